@@ -0,0 +1,43 @@
+package controller
+
+import (
+	"time"
+
+	"github.com/rancher/wrangler/pkg/genericcondition"
+)
+
+// mergeConditions returns the current conditions updated with the desired ones. Update and transition times are
+// only changed when a condition's content changes, so that unchanged conditions do not cause status writes.
+// The returned bool reports whether anything changed.
+func mergeConditions(current, desired []genericcondition.GenericCondition) ([]genericcondition.GenericCondition, bool) {
+	now := time.Now().UTC().Format(time.RFC3339)
+	merged := make([]genericcondition.GenericCondition, 0, len(desired))
+	changed := len(current) != len(desired)
+	for _, condition := range desired {
+		existing := findCondition(current, condition.Type)
+		if existing != nil && existing.Status == condition.Status && existing.Reason == condition.Reason && existing.Message == condition.Message {
+			merged = append(merged, *existing)
+			continue
+		}
+
+		changed = true
+		condition.LastUpdateTime = now
+		condition.LastTransitionTime = now
+		if existing != nil && existing.Status == condition.Status {
+			condition.LastTransitionTime = existing.LastTransitionTime
+		}
+		merged = append(merged, condition)
+	}
+
+	return merged, changed
+}
+
+func findCondition(conditions []genericcondition.GenericCondition, conditionType string) *genericcondition.GenericCondition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+
+	return nil
+}
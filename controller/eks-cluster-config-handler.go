@@ -3,24 +3,23 @@ package controller
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
-	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/eks"
-	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/blang/semver"
 	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
 	awsservices "github.com/rancher/eks-operator/pkg/eks"
 	"github.com/rancher/eks-operator/pkg/eks/services"
 	ekscontrollers "github.com/rancher/eks-operator/pkg/generated/controllers/eks.cattle.io/v1"
-	"github.com/rancher/eks-operator/templates"
 	"github.com/rancher/eks-operator/utils"
 	wranglerv1 "github.com/rancher/wrangler/pkg/generated/controllers/core/v1"
 	"github.com/sirupsen/logrus"
@@ -40,14 +39,45 @@ const (
 	eksConfigUpdatingPhase   = "updating"
 	eksConfigImportingPhase  = "importing"
 	eksClusterConfigKind     = "EKSClusterConfig"
+
+	// logGroupRequeueInterval is how often a cluster with a log retention is checked until its log group exists.
+	logGroupRequeueInterval = time.Minute
+
+	// launchTemplateVersionsToKeep is how many of the most recent versions of the managed launch template are kept
+	// when pruning it, on top of the versions in use by node groups.
+	launchTemplateVersionsToKeep = 5
 )
 
 type Handler struct {
-	eksCC           ekscontrollers.EKSClusterConfigClient
-	eksEnqueueAfter func(namespace, name string, duration time.Duration)
-	eksEnqueue      func(namespace, name string)
-	secrets         wranglerv1.SecretClient
-	secretsCache    wranglerv1.SecretCache
+	ctx              context.Context
+	eksCC            ekscontrollers.EKSClusterConfigClient
+	eksEnqueueAfter  func(namespace, name string, duration time.Duration)
+	eksEnqueue       func(namespace, name string)
+	secrets          wranglerv1.SecretClient
+	secretsCache     wranglerv1.SecretCache
+	options          Options
+	credentialsCache *services.CredentialsCache
+	// nodegroupLaunchIssues holds the last reported launch issues of each node group.
+	nodegroupLaunchIssues sync.Map
+}
+
+// Options configures optional safety checks of the controller.
+type Options struct {
+	// DetectPublicIP detects the public IP of the operator, nil disables the public access lockout check.
+	DetectPublicIP awsservices.PublicIPDetector
+	// RefusePublicAccessLockout refuses public access sources that would lock the operator out instead of
+	// only warning about them.
+	RefusePublicAccessLockout bool
+	// DriftResyncInterval re-enqueues active clusters once they have no pending updates, so that their spec is
+	// compared with the live state of the cluster again even if the config does not change. Changes made outside of
+	// the operator are then reverted within the interval instead of waiting for the next config change. Zero disables
+	// the periodic comparison.
+	DriftResyncInterval time.Duration
+	// ValidateNodeRolePolicies checks that the node roles given for node groups have the node policies attached
+	// before creating the node groups. It needs the iam:ListAttachedRolePolicies permission.
+	ValidateNodeRolePolicies bool
+	// APIMetrics records the EKS, EC2 and CloudFormation requests made by the controller, nil disables the metrics.
+	APIMetrics *services.APIMetrics
 }
 
 type awsServices struct {
@@ -55,18 +85,24 @@ type awsServices struct {
 	eks            services.EKSServiceInterface
 	ec2            services.EC2ServiceInterface
 	iam            services.IAMServiceInterface
+	autoscaling    services.AutoScalingServiceInterface
+	cloudwatchlogs services.CloudWatchLogsServiceInterface
 }
 
 func Register(
 	ctx context.Context,
 	secrets wranglerv1.SecretController,
-	eks ekscontrollers.EKSClusterConfigController) {
+	eks ekscontrollers.EKSClusterConfigController,
+	options Options) {
 	controller := &Handler{
-		eksCC:           eks,
-		eksEnqueue:      eks.Enqueue,
-		eksEnqueueAfter: eks.EnqueueAfter,
-		secretsCache:    secrets.Cache(),
-		secrets:         secrets,
+		ctx:              ctx,
+		eksCC:            eks,
+		eksEnqueue:       eks.Enqueue,
+		eksEnqueueAfter:  eks.EnqueueAfter,
+		secretsCache:     secrets.Cache(),
+		secrets:          secrets,
+		options:          options,
+		credentialsCache: credentialsCache,
 	}
 
 	// Register handlers
@@ -83,7 +119,7 @@ func (h *Handler) OnEksConfigChanged(_ string, config *eksv1.EKSClusterConfig) (
 		return nil, nil
 	}
 
-	awsSVCs, err := newAWSServices(h.secretsCache, config.Spec)
+	awsSVCs, err := newAWSServices(h.secretsCache, h.credentialsCache, h.options.APIMetrics, config.Spec)
 	if err != nil {
 		return config, fmt.Errorf("error creating new AWS services: %w", err)
 	}
@@ -203,10 +239,16 @@ func removeErrorMetadata(message string) (string, error) {
 }
 
 func (h *Handler) OnEksConfigRemoved(_ string, config *eksv1.EKSClusterConfig) (*eksv1.EKSClusterConfig, error) {
-	awsSVCs, err := newAWSServices(h.secretsCache, config.Spec)
+	h.forgetNodegroupLaunchIssues(config)
+
+	awsSVCs, err := newAWSServices(h.secretsCache, h.credentialsCache, h.options.APIMetrics, config.Spec)
 	if err != nil {
 		return config, fmt.Errorf("error creating new AWS services: %w", err)
 	}
+	// the services of the deletion keep the assumed credentials they were created with
+	if err := forgetAssumedRoleCredentials(h.secretsCache, h.credentialsCache, config.Spec); err != nil {
+		logrus.Warnf("error forgetting assumed role credentials of cluster [%s]: %v", config.Name, err)
+	}
 
 	if config.Spec.Imported {
 		logrus.Infof("cluster [%s] is imported, will not delete EKS cluster", config.Name)
@@ -223,7 +265,7 @@ func (h *Handler) OnEksConfigRemoved(_ string, config *eksv1.EKSClusterConfig) (
 	logrus.Infof("starting node group deletion for config [%s]", config.Spec.DisplayName)
 	waitingForNodegroupDeletion := true
 	for waitingForNodegroupDeletion {
-		waitingForNodegroupDeletion, err = deleteNodeGroups(config, config.Spec.NodeGroups, awsSVCs.eks)
+		waitingForNodegroupDeletion, err = deleteNodeGroups(h.ctx, config, config.Spec.NodeGroups, awsSVCs.eks)
 		if err != nil {
 			return config, fmt.Errorf("error deleting nodegroups for config [%s]", config.Spec.DisplayName)
 		}
@@ -233,28 +275,27 @@ func (h *Handler) OnEksConfigRemoved(_ string, config *eksv1.EKSClusterConfig) (
 
 	if config.Status.ManagedLaunchTemplateID != "" {
 		logrus.Infof("deleting common launch template for config [%s]", config.Name)
-		deleteLaunchTemplate(config.Status.ManagedLaunchTemplateID, awsSVCs.ec2)
+		config = config.DeepCopy()
+		if err := awsservices.DeleteLaunchTemplate(h.ctx, &awsservices.DeleteLaunchTemplateOptions{
+			EC2Service: awsSVCs.ec2,
+			Config:     config,
+		}); err != nil {
+			logrus.Warnf("%v, will not retry", err)
+		}
 	}
 
 	logrus.Infof("starting control plane deletion for config [%s]", config.Name)
-	_, err = awsSVCs.eks.DeleteCluster(&eks.DeleteClusterInput{
-		Name: aws.String(config.Spec.DisplayName),
+	err = awsservices.DeleteCluster(h.ctx, &awsservices.DeleteClusterOptions{
+		EKSService: awsSVCs.eks,
+		Config:     config,
 	})
 	if err != nil {
-		if notFound(err) {
-			_, err = awsSVCs.eks.DeleteCluster(&eks.DeleteClusterInput{
-				Name: aws.String(config.Spec.DisplayName),
-			})
-		}
-
-		if err != nil && !notFound(err) {
-			return config, fmt.Errorf("error deleting cluster: %v", err)
-		}
+		return config, err
 	}
 
 	if aws.StringValue(config.Spec.ServiceRole) == "" {
 		logrus.Infof("deleting service role for config [%s]", config.Name)
-		err = deleteStack(awsSVCs.cloudformation, getServiceRoleName(config.Spec.DisplayName), getServiceRoleName(config.Spec.DisplayName))
+		err = deleteStack(h.ctx, awsSVCs.cloudformation, getServiceRoleName(config.Spec.DisplayName), getServiceRoleName(config.Spec.DisplayName))
 		if err != nil {
 			return config, fmt.Errorf("error deleting service role stack: %v", err)
 		}
@@ -262,21 +303,57 @@ func (h *Handler) OnEksConfigRemoved(_ string, config *eksv1.EKSClusterConfig) (
 
 	if len(config.Spec.Subnets) == 0 {
 		logrus.Infof("deleting vpc, subnets, and security groups for config [%s]", config.Name)
-		err = deleteStack(awsSVCs.cloudformation, getVPCStackName(config.Spec.DisplayName), getVPCStackName(config.Spec.DisplayName))
+		err = deleteStack(h.ctx, awsSVCs.cloudformation, awsservices.GetVpcStackName(config.Spec.DisplayName), awsservices.GetVpcStackName(config.Spec.DisplayName))
 		if err != nil {
 			return config, fmt.Errorf("error deleting vpc stack: %v", err)
 		}
 	}
 
 	logrus.Infof("deleting node instance role for config [%s]", config.Name)
-	err = deleteStack(awsSVCs.cloudformation, fmt.Sprintf("%s-node-instance-role", config.Spec.DisplayName), fmt.Sprintf("%s-node-instance-role", config.Spec.DisplayName))
+	sharedWith, err := h.getNodeInstanceRoleUsers(config)
 	if err != nil {
-		return config, fmt.Errorf("error deleting worker node stack: %v", err)
+		return config, err
+	}
+	err = awsservices.DeleteNodeInstanceRole(h.ctx, &awsservices.DeleteNodeInstanceRoleOptions{
+		CloudFormationService: awsSVCs.cloudformation,
+		Config:                config,
+		SharedWith:            sharedWith,
+	})
+	if err != nil {
+		return config, fmt.Errorf("error deleting worker node stack: %w", err)
 	}
 
 	return config, err
 }
 
+// getNodeInstanceRoleUsers returns the names of the other clusters with node groups using the node role generated
+// for the given cluster.
+func (h *Handler) getNodeInstanceRoleUsers(config *eksv1.EKSClusterConfig) ([]string, error) {
+	if config.Status.GeneratedNodeRole == "" {
+		return nil, nil
+	}
+
+	eksConfigs, err := h.eksCC.List("", metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing eksclusterconfigs to check usage of node role [%s]: %w", config.Status.GeneratedNodeRole, err)
+	}
+
+	var users []string
+	for _, c := range eksConfigs.Items {
+		if c.Namespace == config.Namespace && c.Name == config.Name {
+			continue
+		}
+		for _, ng := range c.Spec.NodeGroups {
+			if aws.StringValue(ng.NodeRole) == config.Status.GeneratedNodeRole {
+				users = append(users, c.Name)
+				break
+			}
+		}
+	}
+
+	return users, nil
+}
+
 func (h *Handler) checkAndUpdate(config *eksv1.EKSClusterConfig, awsSVCs *awsServices) (*eksv1.EKSClusterConfig, error) {
 	if awsSVCs == nil {
 		return config, fmt.Errorf("aws services not initialized")
@@ -294,7 +371,7 @@ func (h *Handler) checkAndUpdate(config *eksv1.EKSClusterConfig, awsSVCs *awsSer
 		return config, err
 	}
 
-	clusterState, err := awsservices.GetClusterState(&awsservices.GetClusterStatusOpts{
+	clusterState, err := awsservices.GetClusterState(h.ctx, &awsservices.GetClusterStatusOpts{
 		EKSService: awsSVCs.eks,
 		Config:     config,
 	})
@@ -302,6 +379,29 @@ func (h *Handler) checkAndUpdate(config *eksv1.EKSClusterConfig, awsSVCs *awsSer
 		return config, err
 	}
 
+	if conditions, changed := mergeConditions(config.Status.Conditions, awsservices.GetClusterConditions(clusterState.Cluster)); changed {
+		config = config.DeepCopy()
+		config.Status.Conditions = conditions
+		config, err = h.eksCC.UpdateStatus(config)
+		if err != nil {
+			return config, err
+		}
+	}
+
+	// the cluster security group is listed in the launch template of node groups with additional security groups
+	if id := awsservices.GetClusterSecurityGroupID(clusterState.Cluster); id != "" && id != config.Status.ClusterSecurityGroupID {
+		config = config.DeepCopy()
+		config.Status.ClusterSecurityGroupID = id
+		config, err = h.eksCC.UpdateStatus(config)
+		if err != nil {
+			return config, err
+		}
+	}
+
+	if err := awsservices.ValidateClusterFeatures(config, clusterState.Cluster); err != nil {
+		return config, err
+	}
+
 	if aws.StringValue(clusterState.Cluster.Status) == eks.ClusterStatusUpdating {
 		// upstream cluster is already updating, must wait until sending next update
 		logrus.Infof("waiting for cluster [%s] to finish updating", config.Name)
@@ -314,23 +414,23 @@ func (h *Handler) checkAndUpdate(config *eksv1.EKSClusterConfig, awsSVCs *awsSer
 		return config, nil
 	}
 
-	ngs, err := awsSVCs.eks.ListNodegroups(
-		&eks.ListNodegroupsInput{
-			ClusterName: aws.String(config.Spec.DisplayName),
-		})
+	ngNames, err := awsservices.ListNodegroups(h.ctx, &awsservices.ListNodegroupsOpts{
+		EKSService: awsSVCs.eks,
+		Config:     config,
+	})
 	if err != nil {
 		return config, err
 	}
 
 	// gather upstream node groups states
-	nodeGroupStates := make([]*eks.DescribeNodegroupOutput, 0, len(ngs.Nodegroups))
+	nodeGroupStates := make([]*eks.DescribeNodegroupOutput, 0, len(ngNames))
 	nodegroupARNs := make(map[string]string)
-	for _, ngName := range ngs.Nodegroups {
-		ng, err := awsSVCs.eks.DescribeNodegroup(
-			&eks.DescribeNodegroupInput{
-				ClusterName:   aws.String(config.Spec.DisplayName),
-				NodegroupName: ngName,
-			})
+	for _, ngName := range ngNames {
+		ng, err := awsservices.GetNodegroupState(h.ctx, &awsservices.GetNodegroupStateOpts{
+			EKSService:    awsSVCs.eks,
+			Config:        config,
+			NodegroupName: ngName,
+		})
 		if err != nil {
 			return config, err
 		}
@@ -344,29 +444,64 @@ func (h *Handler) checkAndUpdate(config *eksv1.EKSClusterConfig, awsSVCs *awsSer
 					return config, err
 				}
 			}
-			logrus.Infof("waiting for cluster [%s] to update nodegroups [%s]", config.Name, aws.StringValue(ngName))
+			logrus.Infof("waiting for cluster [%s] to update nodegroups [%s]", config.Name, ngName)
+			if status == eks.NodegroupStatusUpdating {
+				progress, err := awsservices.GetNodegroupUpdateProgress(h.ctx, &awsservices.GetNodegroupUpdateProgressOpts{
+					EKSService:         awsSVCs.eks,
+					AutoScalingService: awsSVCs.autoscaling,
+					Config:             config,
+					NodegroupName:      ngName,
+				})
+				if err != nil {
+					logrus.Warnf("error getting update progress for nodegroup [%s] in cluster [%s]: %v", ngName, config.Name, err)
+				} else {
+					logrus.Infof("cluster [%s]: %s", config.Name, progress.Message())
+				}
+			}
 			h.eksEnqueueAfter(config.Namespace, config.Name, 30*time.Second)
 			return config, nil
 		}
 
+		if health := awsservices.GetNodegroupHealthReport(ng.Nodegroup); health.Degraded {
+			if health.Persistent() {
+				logrus.Warnf("cluster [%s]: %s, the nodegroup will not recover until the issues are fixed", config.Name, health.Message())
+			} else {
+				logrus.Infof("cluster [%s]: %s, waiting for the nodegroup to recover", config.Name, health.Message())
+			}
+		}
+
+		h.reportNodegroupLaunchIssues(config, ng.Nodegroup, awsSVCs)
+
 		nodeGroupStates = append(nodeGroupStates, ng)
-		nodegroupARNs[aws.StringValue(ngName)] = aws.StringValue(ng.Nodegroup.NodegroupArn)
+		nodegroupARNs[ngName] = aws.StringValue(ng.Nodegroup.NodegroupArn)
 	}
 
 	if config.Status.Phase == eksConfigActivePhase && len(config.Status.TemplateVersionsToDelete) != 0 {
 		// If there are any launch template versions that need to be cleaned up, we do it now.
-		awsservices.DeleteLaunchTemplateVersions(awsSVCs.ec2, config.Status.ManagedLaunchTemplateID, aws.StringSlice(config.Status.TemplateVersionsToDelete))
+		awsservices.DeleteLaunchTemplateVersions(h.ctx, awsSVCs.ec2, config.Status.ManagedLaunchTemplateID, aws.StringSlice(config.Status.TemplateVersionsToDelete))
 		config = config.DeepCopy()
 		config.Status.TemplateVersionsToDelete = nil
 		return h.eksCC.UpdateStatus(config)
 	}
 
-	upstreamSpec, clusterARN, err := BuildUpstreamClusterState(config.Spec.DisplayName, config.Status.ManagedLaunchTemplateID, clusterState, nodeGroupStates, awsSVCs.ec2, true)
+	upstreamSpec, clusterARN, err := BuildUpstreamClusterState(h.ctx, config.Spec.DisplayName, config.Status.ManagedLaunchTemplateID, clusterState, nodeGroupStates, awsSVCs.ec2, true)
 	if err != nil {
 		return config, err
 	}
 
-	return h.updateUpstreamClusterState(upstreamSpec, config, awsSVCs, clusterARN, nodegroupARNs)
+	updatedConfig, err := h.updateUpstreamClusterState(upstreamSpec, config, awsSVCs, clusterARN, nodegroupARNs)
+	if awsservices.IsRetriableUpdateConflict(err) {
+		// another update started since the cluster state was described, retry once it is done
+		logrus.Infof("waiting for cluster [%s] to finish updating: %v", config.Name, err)
+		if config.Status.Phase != eksConfigUpdatingPhase {
+			config = config.DeepCopy()
+			config.Status.Phase = eksConfigUpdatingPhase
+			return h.eksCC.UpdateStatus(config)
+		}
+		h.eksEnqueueAfter(config.Namespace, config.Name, 30*time.Second)
+		return config, nil
+	}
+	return updatedConfig, err
 }
 
 func validateUpdate(config *eksv1.EKSClusterConfig) error {
@@ -402,6 +537,32 @@ func validateUpdate(config *eksv1.EKSClusterConfig) error {
 		errs = append(errs, fmt.Sprintf("versions for cluster [%s] and nodegroup [%s] not compatible: all nodegroup kubernetes versions"+
 			"must be equal to or one minor version lower than the cluster kubernetes version", aws.StringValue(config.Spec.KubernetesVersion), aws.StringValue(ng.Version)))
 	}
+	for _, ng := range config.Spec.NodeGroups {
+		if err := awsservices.ValidateNodeBootstrapConfig(ng); err != nil {
+			errs = append(errs, err.Error())
+		}
+		if err := awsservices.ValidateTargetGroupARNs(ng); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if err := awsservices.ValidateTags(config); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if err := awsservices.ValidateAccessConfig(config); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if err := awsservices.ValidateAccessEntries(config); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if err := awsservices.ValidateLoggingTypes(config); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if err := awsservices.ValidateLogRetention(config); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if err := awsservices.ValidateSubnetCapacityCheck(config); err != nil {
+		errs = append(errs, err.Error())
+	}
 	if len(errs) != 0 {
 		return fmt.Errorf(strings.Join(errs, ";"))
 	}
@@ -433,14 +594,12 @@ func (h *Handler) create(config *eksv1.EKSClusterConfig, awsSVCs *awsServices) (
 		return config, fmt.Errorf("error creating or getting service role: %w", err)
 	}
 
-	if err := awsservices.CreateCluster(&awsservices.CreateClusterOptions{
+	if err := awsservices.CreateCluster(h.ctx, &awsservices.CreateClusterOptions{
 		EKSService: awsSVCs.eks,
 		Config:     config,
 		RoleARN:    roleARN,
 	}); err != nil {
-		if !isClusterConflict(err) {
-			return config, fmt.Errorf("error creating cluster: %w", err)
-		}
+		return config, fmt.Errorf("error creating cluster: %w", err)
 	}
 
 	// If a user edits a cluster at the exact right (or wrong) time, then the
@@ -462,6 +621,68 @@ func (h *Handler) create(config *eksv1.EKSClusterConfig, awsSVCs *awsServices) (
 	return config, err
 }
 
+// reportNodegroupLaunchIssues warns about the issues preventing a node group from launching nodes. The same issues are
+// only reported once, as they are seen again on every reconcile until they are fixed.
+func (h *Handler) reportNodegroupLaunchIssues(config *eksv1.EKSClusterConfig, ng *eks.Nodegroup, awsSVCs *awsServices) {
+	key := nodegroupLaunchIssuesKey(config, aws.StringValue(ng.NodegroupName))
+	if ng.Health == nil || len(ng.Health.Issues) == 0 {
+		h.nodegroupLaunchIssues.Delete(key)
+		return
+	}
+
+	report, err := awsservices.GetNodegroupLaunchReport(h.ctx, &awsservices.GetNodegroupLaunchReportOpts{
+		AutoScalingService: awsSVCs.autoscaling,
+		Config:             config,
+		Nodegroup:          ng,
+	})
+	if err != nil {
+		logrus.Warnf("error getting launch report for nodegroup [%s] in cluster [%s]: %v", aws.StringValue(ng.NodegroupName), config.Name, err)
+		return
+	}
+
+	message := report.Message()
+	if previous, ok := h.nodegroupLaunchIssues.Load(key); ok && previous == message {
+		return
+	}
+	h.nodegroupLaunchIssues.Store(key, message)
+	logrus.Warnf("cluster [%s]: %s", config.Name, message)
+}
+
+// forgetNodegroupLaunchIssues drops the launch issues reported for the node groups of a removed cluster.
+func (h *Handler) forgetNodegroupLaunchIssues(config *eksv1.EKSClusterConfig) {
+	prefix := nodegroupLaunchIssuesKey(config, "")
+	h.nodegroupLaunchIssues.Range(func(key, _ interface{}) bool {
+		if strings.HasPrefix(key.(string), prefix) {
+			h.nodegroupLaunchIssues.Delete(key)
+		}
+		return true
+	})
+}
+
+func nodegroupLaunchIssuesKey(config *eksv1.EKSClusterConfig, ngName string) string {
+	return config.Namespace + "/" + config.Name + "/" + ngName
+}
+
+// checkPublicAccessLockout warns about, or refuses if configured, public access sources that would stop the operator
+// from reaching the cluster API. A failure to detect the operator IP does not block the update.
+func (h *Handler) checkPublicAccessLockout(config *eksv1.EKSClusterConfig, upstreamSpec *eksv1.EKSClusterConfigSpec) error {
+	err := awsservices.CheckPublicAccessLockout(h.ctx, &awsservices.CheckPublicAccessLockoutOpts{
+		Config:              config,
+		UpstreamClusterSpec: upstreamSpec,
+		DetectPublicIP:      h.options.DetectPublicIP,
+	})
+	switch {
+	case err == nil:
+	case errors.Is(err, awsservices.ErrPublicAccessLockout) && h.options.RefusePublicAccessLockout:
+		return err
+	case errors.Is(err, awsservices.ErrPublicAccessLockout):
+		logrus.Warnf("%v, the operator may lose access to the cluster API", err)
+	default:
+		logrus.Warnf("skipping public access lockout check for cluster [%s]: %v", config.Name, err)
+	}
+	return nil
+}
+
 func (h *Handler) validateCreate(config *eksv1.EKSClusterConfig, awsSVCs *awsServices) error {
 	if awsSVCs == nil {
 		return fmt.Errorf("aws services not initialized")
@@ -478,15 +699,22 @@ func (h *Handler) validateCreate(config *eksv1.EKSClusterConfig, awsSVCs *awsSer
 		}
 	}
 
+	// the region has to be checked before any stack is created, as the templates depend on its partition
+	if err := awsservices.ValidateRegion(config.Spec.Region); err != nil {
+		return fmt.Errorf("cluster [%s]: %w", config.Name, err)
+	}
+
 	// validate nodegroup version
 	if !config.Spec.Imported {
 		// Check for existing clusters in EKS with the same display name
-		listOutput, err := awsSVCs.eks.ListClusters(&eks.ListClustersInput{})
+		clusters, err := awsservices.ListClusters(h.ctx, &awsservices.ListClustersOpts{
+			EKSService: awsSVCs.eks,
+		})
 		if err != nil {
-			return fmt.Errorf("error listing clusters: %v", err)
+			return err
 		}
-		for _, cluster := range listOutput.Clusters {
-			if aws.StringValue(cluster) == config.Spec.DisplayName {
+		for _, cluster := range clusters {
+			if cluster == config.Spec.DisplayName {
 				return fmt.Errorf("cannot create cluster [%s] because a cluster in EKS exists with the same name", config.Spec.DisplayName)
 			}
 		}
@@ -503,9 +731,38 @@ func (h *Handler) validateCreate(config *eksv1.EKSClusterConfig, awsSVCs *awsSer
 		if config.Spec.SecretsEncryption == nil {
 			return fmt.Errorf(cannotBeNilError, "secretsEncryption", config.Name)
 		}
+		if err := awsservices.ValidateEncryptionConfig(config); err != nil {
+			return err
+		}
+		if err := awsservices.ValidateLaunchTemplatePrefix(config); err != nil {
+			return err
+		}
+		if err := awsservices.ValidateAccessConfig(config); err != nil {
+			return err
+		}
+		if err := awsservices.ValidateAccessEntries(config); err != nil {
+			return err
+		}
+		if err := awsservices.ValidateLoggingTypes(config); err != nil {
+			return err
+		}
+		if err := awsservices.ValidateLogRetention(config); err != nil {
+			return err
+		}
+		if err := awsservices.ValidateSubnetCapacityCheck(config); err != nil {
+			return err
+		}
+		if boundary := aws.StringValue(config.Spec.PermissionsBoundaryARN); boundary != "" {
+			if err := awsservices.ValidatePermissionsBoundaryARN(boundary); err != nil {
+				return fmt.Errorf("cluster [%s]: %w", config.Name, err)
+			}
+		}
 		if config.Spec.Tags == nil {
 			return fmt.Errorf(cannotBeNilError, "tags", config.Name)
 		}
+		if err := awsservices.ValidateTags(config); err != nil {
+			return err
+		}
 		if config.Spec.Subnets == nil {
 			return fmt.Errorf(cannotBeNilError, "subnets", config.Name)
 		}
@@ -573,6 +830,12 @@ func (h *Handler) validateCreate(config *eksv1.EKSClusterConfig, awsSVCs *awsSer
 			if ng.NodeRole == nil {
 				logrus.Warnf("nodeRole is not specified for nodegroup [%s] in cluster [%s], the controller will generate it", *ng.NodegroupName, config.Name)
 			}
+			if err := awsservices.ValidateNodeBootstrapConfig(ng); err != nil {
+				return fmt.Errorf("cluster [%s]: %w", config.Name, err)
+			}
+			if err := awsservices.ValidateTargetGroupARNs(ng); err != nil {
+				return fmt.Errorf("cluster [%s]: %w", config.Name, err)
+			}
 			if aws.BoolValue(ng.RequestSpotInstances) {
 				if len(ng.SpotInstanceTypes) == 0 {
 					return fmt.Errorf("nodegroup [%s] in cluster [%s]: spotInstanceTypes must be specified when requesting spot instances", *ng.NodegroupName, config.Name)
@@ -602,37 +865,32 @@ func (h *Handler) generateAndSetNetworking(config *eksv1.EKSClusterConfig, awsSV
 
 	if len(config.Spec.Subnets) != 0 {
 		logrus.Infof("VPC info provided, skipping vpc/subnet/securitygroup creation")
+		vpcID, err := awsservices.ValidateVpcResources(h.ctx, &awsservices.ValidateVpcResourcesOpts{
+			EC2Service: awsSVCs.ec2,
+			Config:     config,
+		})
+		if err != nil {
+			return config, err
+		}
 		config = config.DeepCopy()
 		// copy networking fields to status
+		config.Status.VirtualNetwork = vpcID
 		config.Status.Subnets = config.Spec.Subnets
 		config.Status.SecurityGroups = config.Spec.SecurityGroups
 		config.Status.NetworkFieldsSource = "provided"
 	} else {
 		logrus.Infof("Bringing up vpc")
-		stack, err := awsservices.CreateStack(&awsservices.CreateStackOptions{
+		status, err := awsservices.CreateVpcStack(h.ctx, &awsservices.CreateVpcStackOptions{
 			CloudFormationService: awsSVCs.cloudformation,
-			StackName:             getVPCStackName(config.Spec.DisplayName),
-			DisplayName:           config.Spec.DisplayName,
-			TemplateBody:          templates.VpcTemplate,
-			Capabilities:          []string{},
-			Parameters:            []*cloudformation.Parameter{},
+			Config:                config,
 		})
 		if err != nil {
-			return config, fmt.Errorf("error creating stack with VPC template: %v", err)
-		}
-
-		virtualNetworkString := getParameterValueFromOutput("VpcId", stack.Stacks[0].Outputs)
-		subnetIdsString := getParameterValueFromOutput("SubnetIds", stack.Stacks[0].Outputs)
-
-		if subnetIdsString == "" {
-			return config, fmt.Errorf("no subnet ids were returned")
+			return config, err
 		}
 
 		config = config.DeepCopy()
-		// copy generated field to status
-		config.Status.VirtualNetwork = virtualNetworkString
-		config.Status.Subnets = strings.Split(subnetIdsString, ",")
-		config.Status.NetworkFieldsSource = "generated"
+		// copy generated fields to status
+		config.Status = *status
 	}
 
 	return h.eksCC.UpdateStatus(config)
@@ -643,52 +901,101 @@ func (h *Handler) createOrGetServiceRole(config *eksv1.EKSClusterConfig, awsSVCs
 	if aws.StringValue(config.Spec.ServiceRole) == "" {
 		logrus.Infof("Creating service role")
 
-		stack, err := awsservices.CreateStack(&awsservices.CreateStackOptions{
+		stack, err := awsservices.CreateStack(h.ctx, &awsservices.CreateStackOptions{
 			CloudFormationService: awsSVCs.cloudformation,
 			StackName:             getServiceRoleName(config.Spec.DisplayName),
 			DisplayName:           config.Spec.DisplayName,
-			TemplateBody:          templates.ServiceRoleTemplate,
+			TemplateBody:          awsservices.GetServiceRoleTemplate(config.Spec.Region),
 			Capabilities:          []string{cloudformation.CapabilityCapabilityIam},
 			Parameters:            nil,
+			Tags:                  awsservices.GetNormalizedTags(config, config.Spec.Tags),
 		})
 		if err != nil {
 			return "", fmt.Errorf("error creating stack with service role template: %v", err)
 		}
 
-		roleARN = getParameterValueFromOutput("RoleArn", stack.Stacks[0].Outputs)
+		roleARN = awsservices.GetOutputsFromStack(stack.Stacks[0])["RoleArn"]
 		if roleARN == "" {
 			return "", fmt.Errorf("no RoleARN was returned")
 		}
 	} else {
 		logrus.Infof("Retrieving existing service role")
-		role, err := awsSVCs.iam.GetRole(&iam.GetRoleInput{
-			RoleName: config.Spec.ServiceRole,
+		role, err := awsservices.GetClusterRole(h.ctx, &awsservices.GetClusterRoleOpts{
+			IAMService: awsSVCs.iam,
+			Config:     config,
 		})
 		if err != nil {
-			return "", fmt.Errorf("error getting role: %w", err)
+			return "", err
 		}
 
-		roleARN = *role.Role.Arn
+		roleARN = aws.StringValue(role.Arn)
 	}
 
 	return roleARN, nil
 }
 
-func newAWSServices(secretsCache wranglerv1.SecretCache, spec eksv1.EKSClusterConfigSpec) (*awsServices, error) {
-	sess, err := newAWSSession(secretsCache, spec)
+// newAWSServices returns the services of the session for the spec. The EKS, EC2 and CloudFormation services record
+// their requests in the metrics if there are any.
+func newAWSServices(secretsCache wranglerv1.SecretCache, credentialsCache *services.CredentialsCache, metrics *services.APIMetrics, spec eksv1.EKSClusterConfigSpec) (*awsServices, error) {
+	sess, err := newAWSSession(secretsCache, credentialsCache, spec)
 	if err != nil {
 		return nil, err
 	}
 
-	return &awsServices{
+	awsSVCs := &awsServices{
 		eks:            services.NewEKSService(sess),
 		cloudformation: services.NewCloudFormationService(sess),
 		iam:            services.NewIAMService(sess),
 		ec2:            services.NewEC2Service(sess),
-	}, nil
+		autoscaling:    services.NewAutoScalingService(sess),
+		cloudwatchlogs: services.NewCloudWatchLogsService(sess),
+	}
+	if metrics != nil {
+		awsSVCs.eks = services.NewInstrumentedEKSService(awsSVCs.eks, metrics)
+		awsSVCs.ec2 = services.NewInstrumentedEC2Service(awsSVCs.ec2, metrics)
+		awsSVCs.cloudformation = services.NewInstrumentedCloudFormationService(awsSVCs.cloudformation, metrics)
+	}
+
+	return awsSVCs, nil
 }
 
-func newAWSSession(secretsCache wranglerv1.SecretCache, spec eksv1.EKSClusterConfigSpec) (*session.Session, error) {
+// newAWSSession returns a session using the credentials of the cloud credential secret of the spec, or the default
+// credentials of the operator if there is none. The role of the spec is assumed with those credentials if it is set,
+// sharing the assumed credentials through the cache.
+func newAWSSession(secretsCache wranglerv1.SecretCache, credentialsCache *services.CredentialsCache, spec eksv1.EKSClusterConfigSpec) (*session.Session, error) {
+	sess, err := newSourceAWSSession(secretsCache, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	if spec.AssumeRoleARN != "" {
+		roleSess, err := credentialsCache.SessionForRole(sess, spec.AssumeRoleARN, spec.ExternalID)
+		if err != nil {
+			return nil, fmt.Errorf("error assuming role [%s]: %w", spec.AssumeRoleARN, err)
+		}
+		return roleSess, nil
+	}
+
+	return sess, nil
+}
+
+// forgetAssumedRoleCredentials evicts the credentials the role of the spec was assumed with from the cache, so that
+// removed clusters do not keep their entries forever.
+func forgetAssumedRoleCredentials(secretsCache wranglerv1.SecretCache, credentialsCache *services.CredentialsCache, spec eksv1.EKSClusterConfigSpec) error {
+	if spec.AssumeRoleARN == "" {
+		return nil
+	}
+
+	sess, err := newSourceAWSSession(secretsCache, spec)
+	if err != nil {
+		return err
+	}
+	return credentialsCache.Forget(sess, spec.AssumeRoleARN, spec.ExternalID)
+}
+
+// newSourceAWSSession returns a session using the credentials of the cloud credential secret of the spec, or the
+// default credentials of the operator if there is none.
+func newSourceAWSSession(secretsCache wranglerv1.SecretCache, spec eksv1.EKSClusterConfigSpec) (*session.Session, error) {
 	awsConfig := &aws.Config{}
 
 	if region := spec.Region; region != "" {
@@ -729,7 +1036,7 @@ func (h *Handler) waitForCreationComplete(config *eksv1.EKSClusterConfig, awsSVC
 
 	var err error
 
-	state, err := awsservices.GetClusterState(&awsservices.GetClusterStatusOpts{
+	state, err := awsservices.GetClusterState(h.ctx, &awsservices.GetClusterStatusOpts{
 		EKSService: awsSVCs.eks,
 		Config:     config,
 	})
@@ -745,19 +1052,52 @@ func (h *Handler) waitForCreationComplete(config *eksv1.EKSClusterConfig, awsSVC
 		return config, fmt.Errorf("no cluster status was returned")
 	}
 
-	status := *state.Cluster.Status
-	if status == eks.ClusterStatusFailed {
+	phase := awsservices.DeriveClusterPhase(state.Cluster)
+	if phase.Phase == awsservices.ClusterPhaseFailed {
+		if len(phase.Issues) != 0 {
+			return config, fmt.Errorf("creation failed for cluster named %q with ARN %q: %s",
+				aws.StringValue(state.Cluster.Name),
+				aws.StringValue(state.Cluster.Arn),
+				strings.Join(phase.Issues, "; "))
+		}
 		return config, fmt.Errorf("creation failed for cluster named %q with ARN %q",
 			aws.StringValue(state.Cluster.Name),
 			aws.StringValue(state.Cluster.Arn))
 	}
 
-	if status == eks.ClusterStatusActive {
-		if err := h.createCASecret(config, state); err != nil {
-			return config, err
+	if phase.Phase == awsservices.ClusterPhaseActive {
+		if err := h.createCASecret(config, aws.StringValue(state.Cluster.Endpoint), aws.StringValue(state.Cluster.CertificateAuthority.Data)); err != nil {
+			if !apierrors.IsAlreadyExists(err) {
+				return config, err
+			}
+		}
+		if config.Spec.FargateProfile != nil {
+			logrus.Infof("creating fargate profile for cluster [%s]", config.Name)
+			if err := awsservices.CreateFargateProfile(h.ctx, &awsservices.CreateFargateProfileOptions{
+				EKSService: awsSVCs.eks,
+				Config:     config,
+			}); err != nil {
+				return config, err
+			}
+		}
+		if len(config.Spec.Addons) != 0 {
+			if err := awsservices.CreateAddons(h.ctx, &awsservices.CreateAddonsOpts{
+				EKSService: awsSVCs.eks,
+				Config:     config,
+			}); err != nil {
+				return config, err
+			}
 		}
-		logrus.Infof("cluster [%s] created successfully", config.Name)
 		config = config.DeepCopy()
+		if len(config.Spec.AccessEntries) != 0 {
+			if err := awsservices.CreateAccessEntries(h.ctx, &awsservices.CreateAccessEntriesOpts{
+				EKSService: awsSVCs.eks,
+				Config:     config,
+			}); err != nil {
+				return config, err
+			}
+		}
+		logrus.Infof("cluster [%s] created successfully", config.Name)
 		config.Status.Phase = eksConfigActivePhase
 		return h.eksCC.UpdateStatus(config)
 	}
@@ -769,7 +1109,7 @@ func (h *Handler) waitForCreationComplete(config *eksv1.EKSClusterConfig, awsSVC
 }
 
 // buildUpstreamClusterState
-func BuildUpstreamClusterState(name, managedTemplateID string, clusterState *eks.DescribeClusterOutput, nodeGroupStates []*eks.DescribeNodegroupOutput, ec2Service services.EC2ServiceInterface, includeManagedLaunchTemplate bool) (*eksv1.EKSClusterConfigSpec, string, error) {
+func BuildUpstreamClusterState(ctx context.Context, name, managedTemplateID string, clusterState *eks.DescribeClusterOutput, nodeGroupStates []*eks.DescribeNodegroupOutput, ec2Service services.EC2ServiceInterface, includeManagedLaunchTemplate bool) (*eksv1.EKSClusterConfigSpec, string, error) {
 	upstreamSpec := &eksv1.EKSClusterConfigSpec{}
 
 	upstreamSpec.Imported = true
@@ -819,6 +1159,13 @@ func BuildUpstreamClusterState(name, managedTemplateID string, clusterState *eks
 		}
 	}
 
+	// set access config
+	if accessConfig := clusterState.Cluster.AccessConfig; accessConfig != nil {
+		upstreamSpec.AccessConfig = &eksv1.AccessConfig{
+			AuthenticationMode: accessConfig.AuthenticationMode,
+		}
+	}
+
 	// set node groups
 	upstreamSpec.NodeGroups = make([]eksv1.NodeGroup, 0, len(nodeGroupStates))
 	for _, ng := range nodeGroupStates {
@@ -837,6 +1184,8 @@ func BuildUpstreamClusterState(name, managedTemplateID string, clusterState *eks
 			Tags:                 ng.Nodegroup.Tags,
 			Version:              ng.Nodegroup.Version,
 			RequestSpotInstances: aws.Bool(aws.StringValue(ng.Nodegroup.CapacityType) == eks.CapacityTypesSpot),
+			Taints:               awsservices.FromEKSTaints(ng.Nodegroup.Taints),
+			UpdateConfig:         awsservices.FromEKSUpdateConfig(ng.Nodegroup.UpdateConfig),
 		}
 
 		if aws.BoolValue(ngToAdd.RequestSpotInstances) {
@@ -858,7 +1207,7 @@ func BuildUpstreamClusterState(name, managedTemplateID string, clusterState *eks
 
 			if managedTemplateID == aws.StringValue(ngToAdd.LaunchTemplate.ID) {
 				// If this is a rancher-managed launch template, then we move the data from the launch template to the node group.
-				launchTemplateRequestOutput, err := awsservices.GetLaunchTemplateVersions(&awsservices.GetLaunchTemplateVersionsOpts{
+				launchTemplateRequestOutput, err := awsservices.GetLaunchTemplateVersions(ctx, &awsservices.GetLaunchTemplateVersionsOpts{
 					EC2Service:       ec2Service,
 					LaunchTemplateID: ngToAdd.LaunchTemplate.ID,
 					Versions:         []*string{ng.Nodegroup.LaunchTemplate.Version},
@@ -882,10 +1231,15 @@ func BuildUpstreamClusterState(name, managedTemplateID string, clusterState *eks
 				launchTemplateData := launchTemplateRequestOutput.LaunchTemplateVersions[0].LaunchTemplateData
 
 				ngToAdd.DiskSize = launchTemplateData.BlockDeviceMappings[0].Ebs.VolumeSize
+				ngToAdd.DiskType = launchTemplateData.BlockDeviceMappings[0].Ebs.VolumeType
+				ngToAdd.Iops = launchTemplateData.BlockDeviceMappings[0].Ebs.Iops
+				ngToAdd.Throughput = launchTemplateData.BlockDeviceMappings[0].Ebs.Throughput
 				ngToAdd.Ec2SshKey = launchTemplateData.KeyName
 				ngToAdd.ImageID = launchTemplateData.ImageId
 				ngToAdd.InstanceType = launchTemplateData.InstanceType
 				ngToAdd.ResourceTags = utils.GetInstanceTags(launchTemplateData.TagSpecifications)
+				ngToAdd.VolumeTags = utils.GetVolumeTags(launchTemplateData.TagSpecifications)
+				ngToAdd.NodeGroupSecurityGroups = aws.StringValueSlice(launchTemplateData.SecurityGroupIds)
 
 				userData := aws.StringValue(launchTemplateData.UserData)
 				if userData != "" {
@@ -910,10 +1264,10 @@ func BuildUpstreamClusterState(name, managedTemplateID string, clusterState *eks
 				ngToAdd.Ec2SshKey = ng.Nodegroup.RemoteAccess.Ec2SshKey
 			}
 		}
-		if aws.StringValue(ng.Nodegroup.AmiType) == eks.AMITypesAl2X8664Gpu {
-			ngToAdd.Gpu = aws.Bool(true)
-		} else if aws.StringValue(ng.Nodegroup.AmiType) == eks.AMITypesAl2X8664 {
-			ngToAdd.Gpu = aws.Bool(false)
+		if family, gpu, arm, ok := awsservices.GetAMITypeSettings(aws.StringValue(ng.Nodegroup.AmiType)); ok {
+			ngToAdd.AMIFamily = aws.String(family)
+			ngToAdd.Gpu = aws.Bool(gpu)
+			ngToAdd.Arm = aws.Bool(arm)
 		}
 		upstreamSpec.NodeGroups = append(upstreamSpec.NodeGroups, ngToAdd)
 	}
@@ -946,7 +1300,7 @@ func (h *Handler) updateUpstreamClusterState(upstreamSpec *eksv1.EKSClusterConfi
 
 	// check kubernetes version for update
 	if config.Spec.KubernetesVersion != nil {
-		updated, err := awsservices.UpdateClusterVersion(&awsservices.UpdateClusterVersionOpts{
+		updated, err := awsservices.UpdateClusterVersion(h.ctx, &awsservices.UpdateClusterVersionOpts{
 			EKSService:          awsSVCs.eks,
 			Config:              config,
 			UpstreamClusterSpec: upstreamSpec,
@@ -961,9 +1315,9 @@ func (h *Handler) updateUpstreamClusterState(upstreamSpec *eksv1.EKSClusterConfi
 
 	// check tags for update
 	if config.Spec.Tags != nil {
-		updated, err := awsservices.UpdateResourceTags(&awsservices.UpdateResourceTagsOpts{
+		updated, err := awsservices.UpdateResourceTags(h.ctx, &awsservices.UpdateResourceTagsOpts{
 			EKSService:   awsSVCs.eks,
-			Tags:         config.Spec.Tags,
+			Tags:         awsservices.GetNormalizedTags(config, config.Spec.Tags),
 			UpstreamTags: upstreamSpec.Tags,
 			ResourceARN:  clusterARN,
 		})
@@ -977,7 +1331,7 @@ func (h *Handler) updateUpstreamClusterState(upstreamSpec *eksv1.EKSClusterConfi
 
 	if config.Spec.LoggingTypes != nil {
 		// check logging for update
-		updated, err := awsservices.UpdateClusterLoggingTypes(&awsservices.UpdateLoggingTypesOpts{
+		updated, err := awsservices.UpdateClusterLoggingTypes(h.ctx, &awsservices.UpdateLoggingTypesOpts{
 			EKSService:          awsSVCs.eks,
 			Config:              config,
 			UpstreamClusterSpec: upstreamSpec,
@@ -990,7 +1344,66 @@ func (h *Handler) updateUpstreamClusterState(upstreamSpec *eksv1.EKSClusterConfi
 		}
 	}
 
-	updated, err := awsservices.UpdateClusterAccess(&awsservices.UpdateClusterAccessOpts{
+	if config.Spec.AccessConfig != nil {
+		updated, err := awsservices.UpdateClusterAccessConfig(h.ctx, &awsservices.UpdateClusterAccessConfigOpts{
+			EKSService:          awsSVCs.eks,
+			Config:              config,
+			UpstreamClusterSpec: upstreamSpec,
+		})
+		if err != nil {
+			return config, fmt.Errorf("error updating access config: %w", err)
+		}
+		if updated {
+			return h.enqueueUpdate(config)
+		}
+	}
+
+	if config.Spec.LogRetentionDays != nil {
+		// retention is applied right away, there is no upstream update to wait for
+		logRetentionConfig := config.DeepCopy()
+		_, err := awsservices.UpdateClusterLogRetention(h.ctx, &awsservices.UpdateClusterLogRetentionOpts{
+			CloudWatchLogsService: awsSVCs.cloudwatchlogs,
+			Config:                logRetentionConfig,
+		})
+		switch {
+		case errors.Is(err, awsservices.ErrLogGroupNotFound):
+			// nothing else changes once the cluster is active, check again until EKS creates the log group
+			logrus.Debugf("%v", err)
+			h.eksEnqueueAfter(config.Namespace, config.Name, logGroupRequeueInterval)
+		case err != nil:
+			return config, fmt.Errorf("error updating log retention: %w", err)
+		}
+		if logRetentionConfig.Status.LogRetentionDays != config.Status.LogRetentionDays {
+			config, err = h.eksCC.UpdateStatus(logRetentionConfig)
+			if err != nil {
+				return config, err
+			}
+		}
+	}
+
+	if aws.BoolValue(config.Spec.AssociateOIDCProvider) && config.Status.OIDCProviderARN == "" {
+		providerARN, err := awsservices.AssociateIAMOIDCProvider(h.ctx, &awsservices.AssociateIAMOIDCProviderOpts{
+			EKSService: awsSVCs.eks,
+			IAMService: awsSVCs.iam,
+			Config:     config,
+		})
+		if err != nil {
+			return config, fmt.Errorf("error associating IAM OIDC provider: %w", err)
+		}
+		config = config.DeepCopy()
+		config.Status.OIDCProviderARN = providerARN
+		config, err = h.eksCC.UpdateStatus(config)
+		if err != nil {
+			return config, err
+		}
+	}
+
+	if config.Spec.PublicAccessSources != nil {
+		if err := h.checkPublicAccessLockout(config, upstreamSpec); err != nil {
+			return config, err
+		}
+	}
+	updated, err := awsservices.UpdateClusterVpcAccess(h.ctx, &awsservices.UpdateClusterVpcAccessOpts{
 		EKSService:          awsSVCs.eks,
 		Config:              config,
 		UpstreamClusterSpec: upstreamSpec,
@@ -1002,14 +1415,46 @@ func (h *Handler) updateUpstreamClusterState(upstreamSpec *eksv1.EKSClusterConfi
 		return h.enqueueUpdate(config)
 	}
 
-	if config.Spec.PublicAccessSources != nil {
-		updated, err := awsservices.UpdateClusterPublicAccessSources(&awsservices.UpdateClusterPublicAccessSourcesOpts{
-			EKSService:          awsSVCs.eks,
-			Config:              config,
-			UpstreamClusterSpec: upstreamSpec,
+	updated, err = awsservices.UpdateClusterVpcConfig(h.ctx, &awsservices.UpdateClusterVpcConfigOpts{
+		EKSService:          awsSVCs.eks,
+		Config:              config,
+		UpstreamClusterSpec: upstreamSpec,
+	})
+	if err != nil {
+		return config, fmt.Errorf("error updating cluster vpc config: %w", err)
+	}
+	if updated {
+		return h.enqueueUpdate(config)
+	}
+
+	if len(config.Spec.Addons) != 0 {
+		updated, err := awsservices.UpdateAddons(h.ctx, &awsservices.UpdateAddonsOpts{
+			EKSService: awsSVCs.eks,
+			Config:     config,
 		})
 		if err != nil {
-			return config, fmt.Errorf("error updating cluster public access sources: %w", err)
+			return config, fmt.Errorf("error updating addons: %w", err)
+		}
+		if updated {
+			return h.enqueueUpdate(config)
+		}
+	}
+
+	if config.Spec.AccessEntries != nil {
+		accessEntriesConfig := config.DeepCopy()
+		updated, err := awsservices.UpdateAccessEntries(h.ctx, &awsservices.UpdateAccessEntriesOpts{
+			EKSService: awsSVCs.eks,
+			Config:     accessEntriesConfig,
+		})
+		if err != nil {
+			return config, fmt.Errorf("error updating access entries: %w", err)
+		}
+		if !utils.CompareStringSliceElements(accessEntriesConfig.Status.AccessEntries, config.Status.AccessEntries) {
+			// the created entries are recorded right away, they are the only ones deleted once removed from the spec
+			config, err = h.eksCC.UpdateStatus(accessEntriesConfig)
+			if err != nil {
+				return config, err
+			}
 		}
 		if updated {
 			return h.enqueueUpdate(config)
@@ -1017,6 +1462,10 @@ func (h *Handler) updateUpstreamClusterState(upstreamSpec *eksv1.EKSClusterConfi
 	}
 
 	if config.Spec.NodeGroups == nil {
+		if config.Status.Phase == eksConfigActivePhase {
+			h.enqueueDriftResync(config)
+			return config, nil
+		}
 		logrus.Infof("cluster [%s] finished updating", config.Name)
 		config = config.DeepCopy()
 		config.Status.Phase = eksConfigActivePhase
@@ -1034,6 +1483,11 @@ func (h *Handler) updateUpstreamClusterState(upstreamSpec *eksv1.EKSClusterConfi
 
 	for _, ng := range config.Spec.NodeGroups {
 		ngs[aws.StringValue(ng.NodegroupName)] = ng
+		if upstreamNg, ok := upstreamNgs[aws.StringValue(ng.NodegroupName)]; ok {
+			if err := awsservices.ValidateNodegroupUpdate(config, ng, upstreamNg); err != nil {
+				return config, err
+			}
+		}
 	}
 
 	// Deep copy the config object here, so it's not copied multiple times for each
@@ -1043,11 +1497,12 @@ func (h *Handler) updateUpstreamClusterState(upstreamSpec *eksv1.EKSClusterConfi
 	// check if node groups need to be created
 	var updatingNodegroups bool
 	templateVersionsToAdd := make(map[string]string)
+	createdDesiredSizes := make(map[string]int64)
 	for _, ng := range config.Spec.NodeGroups {
 		if _, ok := upstreamNgs[aws.StringValue(ng.NodegroupName)]; ok {
 			continue
 		}
-		if err := awsservices.CreateLaunchTemplate(&awsservices.CreateLaunchTemplateOptions{
+		if err := awsservices.CreateLaunchTemplate(h.ctx, &awsservices.CreateLaunchTemplateOptions{
 			EC2Service: awsSVCs.ec2,
 			Config:     config,
 		}); err != nil {
@@ -1063,13 +1518,17 @@ func (h *Handler) updateUpstreamClusterState(upstreamSpec *eksv1.EKSClusterConfi
 			}
 		}
 
-		ltVersion, generatedNodeRole, err := awsservices.CreateNodeGroup(&awsservices.CreateNodeGroupOptions{
+		createNodeGroupOpts := &awsservices.CreateNodeGroupOptions{
 			EC2Service:            awsSVCs.ec2,
 			CloudFormationService: awsSVCs.cloudformation,
 			EKSService:            awsSVCs.eks,
 			Config:                config,
 			NodeGroup:             ng,
-		})
+		}
+		if h.options.ValidateNodeRolePolicies {
+			createNodeGroupOpts.IAMService = awsSVCs.iam
+		}
+		ltVersion, generatedNodeRole, err := awsservices.CreateNodeGroup(h.ctx, createNodeGroupOpts)
 
 		if err != nil {
 			return config, fmt.Errorf("error creating nodegroup: %w", err)
@@ -1084,16 +1543,24 @@ func (h *Handler) updateUpstreamClusterState(upstreamSpec *eksv1.EKSClusterConfi
 			return config, err
 		}
 		templateVersionsToAdd[aws.StringValue(ng.NodegroupName)] = ltVersion
+		if ng.DesiredSize != nil {
+			createdDesiredSizes[aws.StringValue(ng.NodegroupName)] = aws.Int64Value(ng.DesiredSize)
+		}
 		updatingNodegroups = true
 	}
 
 	// check for node groups need to be deleted
 	templateVersionsToDelete := make(map[string]string)
+	var deletedNodegroups []string
 	for _, ng := range upstreamSpec.NodeGroups {
 		if _, ok := ngs[aws.StringValue(ng.NodegroupName)]; ok {
 			continue
 		}
-		templateVersionToDelete, _, err := deleteNodeGroup(config, ng, awsSVCs.eks)
+		templateVersionToDelete, _, err := awsservices.DeleteNodeGroup(h.ctx, &awsservices.DeleteNodeGroupOptions{
+			EKSService: awsSVCs.eks,
+			Config:     config,
+			NodeGroup:  ng,
+		})
 		if err != nil {
 			return config, err
 		}
@@ -1101,14 +1568,31 @@ func (h *Handler) updateUpstreamClusterState(upstreamSpec *eksv1.EKSClusterConfi
 		if templateVersionToDelete != nil {
 			templateVersionsToDelete[aws.StringValue(ng.NodegroupName)] = *templateVersionToDelete
 		}
+		deletedNodegroups = append(deletedNodegroups, aws.StringValue(ng.NodegroupName))
+		h.nodegroupLaunchIssues.Delete(nodegroupLaunchIssuesKey(config, aws.StringValue(ng.NodegroupName)))
 	}
 
 	if updatingNodegroups {
-		if len(templateVersionsToDelete) != 0 || len(templateVersionsToAdd) != 0 {
+		if len(templateVersionsToDelete) != 0 || len(templateVersionsToAdd) != 0 || len(deletedNodegroups) != 0 {
+			config = config.DeepCopy()
+			for _, ngName := range deletedNodegroups {
+				// a node group created again with the same name gets new auto scaling groups to attach the target groups to
+				delete(config.Status.NodegroupTargetGroupARNs, ngName)
+				delete(config.Status.NodegroupDesiredSizes, ngName)
+			}
+			if len(createdDesiredSizes) != 0 && config.Status.NodegroupDesiredSizes == nil {
+				config.Status.NodegroupDesiredSizes = make(map[string]int64, len(createdDesiredSizes))
+			}
+			for ngName, desiredSize := range createdDesiredSizes {
+				config.Status.NodegroupDesiredSizes[ngName] = desiredSize
+			}
 			config.Status.Phase = eksConfigUpdatingPhase
 			config.Status.TemplateVersionsToDelete = append(config.Status.TemplateVersionsToDelete, utils.ValuesFromMap(templateVersionsToDelete)...)
 			config.Status.ManagedLaunchTemplateVersions = utils.SubtractMaps(config.Status.ManagedLaunchTemplateVersions, templateVersionsToDelete)
 			config.Status.ManagedLaunchTemplateVersions = utils.MergeMaps(config.Status.ManagedLaunchTemplateVersions, templateVersionsToAdd)
+			if len(templateVersionsToAdd) != 0 {
+				h.pruneLaunchTemplateVersions(config, awsSVCs)
+			}
 			return h.eksCC.UpdateStatus(config)
 		}
 		return h.enqueueUpdate(config)
@@ -1147,7 +1631,7 @@ func (h *Handler) updateUpstreamClusterState(upstreamSpec *eksv1.EKSClusterConfi
 
 			if lt == nil && config.Status.ManagedLaunchTemplateID == aws.StringValue(upstreamNg.LaunchTemplate.ID) {
 				// In this case, Rancher is managing the launch template, so we check to see if we need a new version.
-				lt, err = newLaunchTemplateVersionIfNeeded(config, upstreamNg, ng, awsSVCs.ec2)
+				lt, err = newLaunchTemplateVersionIfNeeded(h.ctx, config, upstreamNg, ng, awsSVCs.ec2)
 				if err != nil {
 					return config, err
 				}
@@ -1168,49 +1652,139 @@ func (h *Handler) updateUpstreamClusterState(upstreamSpec *eksv1.EKSClusterConfi
 			}
 		}
 
-		if ng.Version != nil {
-			if aws.StringValue(upstreamNg.Version) != desiredNgVersions[aws.StringValue(ng.NodegroupName)] {
-				ngVersionInput.Version = aws.String(desiredNgVersions[aws.StringValue(ng.NodegroupName)])
-			}
+		// A custom AMI rollout only needs the new launch template version, the kubernetes version
+		// is defined by the image itself and cannot be sent along with it.
+		amiRollout := ngVersionInput.LaunchTemplate != nil && aws.StringValue(ng.ImageID) != ""
+		if ng.Version != nil && !amiRollout {
+			ngVersionInput.Version = aws.String(desiredNgVersions[aws.StringValue(ng.NodegroupName)])
 		}
 
-		if ngVersionInput.Version != nil || ngVersionInput.LaunchTemplate != nil {
+		updated, err := awsservices.UpdateNodegroupVersion(h.ctx, &awsservices.UpdateNodegroupVersionOpts{
+			EKSService:      awsSVCs.eks,
+			EC2Service:      awsSVCs.ec2,
+			Config:          config,
+			NodeGroup:       &ng,
+			NGVersionInput:  ngVersionInput,
+			UpstreamVersion: upstreamNg.Version,
+			LTVersions:      templateVersionsToAdd,
+		})
+		if err != nil {
+			return config, err
+		}
+		if updated {
+			updateNodegroupProperties = true
+			continue
+		}
+		updated, err = awsservices.UpdateNodegroupLabels(h.ctx, &awsservices.UpdateNodegroupLabelsOpts{
+			EKSService:     awsSVCs.eks,
+			Config:         config,
+			NodeGroup:      &ng,
+			UpstreamLabels: upstreamNg.Labels,
+		})
+		if err != nil {
+			return config, err
+		}
+		if updated {
 			updateNodegroupProperties = true
-			if err := awsservices.UpdateNodegroupVersion(&awsservices.UpdateNodegroupVersionOpts{
-				EKSService:     awsSVCs.eks,
-				EC2Service:     awsSVCs.ec2,
-				Config:         config,
-				NodeGroup:      &ng,
-				NGVersionInput: ngVersionInput,
-				LTVersions:     templateVersionsToAdd,
+			continue
+		}
+
+		if aws.Int64Value(ng.MaxSize) > aws.Int64Value(upstreamNg.MaxSize) {
+			if err := awsservices.CheckNodegroupSubnetCapacity(h.ctx, &awsservices.CheckNodegroupSubnetCapacityOpts{
+				EC2Service:   awsSVCs.ec2,
+				Config:       config,
+				NodeGroup:    &ng,
+				Subnets:      upstreamNg.Subnets,
+				RunningNodes: aws.Int64Value(upstreamNg.DesiredSize),
 			}); err != nil {
 				return config, err
 			}
+		}
+		scalingConfig := config.DeepCopy()
+		updated, err = awsservices.UpdateNodegroupScaling(h.ctx, &awsservices.UpdateNodegroupScalingOpts{
+			EKSService:        awsSVCs.eks,
+			Config:            scalingConfig,
+			NodeGroup:         &ng,
+			UpstreamNodeGroup: &upstreamNg,
+		})
+		if err != nil {
+			return config, err
+		}
+		if updated {
+			// the applied desired size is recorded right away, drift from it is what ignoreDesiredSizeDrift keeps
+			ngName := aws.StringValue(ng.NodegroupName)
+			appliedDesiredSize, recorded := config.Status.NodegroupDesiredSizes[ngName]
+			if desiredSize, ok := scalingConfig.Status.NodegroupDesiredSizes[ngName]; ok && (!recorded || desiredSize != appliedDesiredSize) {
+				config, err = h.eksCC.UpdateStatus(scalingConfig)
+				if err != nil {
+					return config, err
+				}
+			}
+			updateNodegroupProperties = true
 			continue
 		}
-		updateNodegroupConfig, sendUpdateNodegroupConfig := getNodegroupConfigUpdate(config.Spec.DisplayName, ng, upstreamNg)
 
-		if sendUpdateNodegroupConfig {
+		updated, err = awsservices.UpdateNodegroupUpdateConfig(h.ctx, &awsservices.UpdateNodegroupUpdateConfigOpts{
+			EKSService:           awsSVCs.eks,
+			Config:               config,
+			NodeGroup:            &ng,
+			UpstreamUpdateConfig: upstreamNg.UpdateConfig,
+		})
+		if err != nil {
+			return config, err
+		}
+		if updated {
 			updateNodegroupProperties = true
-			_, err := awsSVCs.eks.UpdateNodegroupConfig(&updateNodegroupConfig)
-			if err != nil {
-				return config, err
-			}
 			continue
 		}
 
-		if ng.Tags != nil {
-			var err error // initialize error here because we assign returned value to updateNodegroupProperties
-			updateNodegroupProperties, err = awsservices.UpdateResourceTags(&awsservices.UpdateResourceTagsOpts{
-				EKSService:   awsSVCs.eks,
-				Tags:         aws.StringValueMap(ng.Tags),
-				UpstreamTags: aws.StringValueMap(upstreamNg.Tags),
-				ResourceARN:  ngARNs[aws.StringValue(ng.NodegroupName)],
-			})
+		updated, err = awsservices.UpdateNodegroupTaints(h.ctx, &awsservices.UpdateNodegroupTaintsOpts{
+			EKSService:     awsSVCs.eks,
+			Config:         config,
+			NodeGroup:      &ng,
+			UpstreamTaints: upstreamNg.Taints,
+		})
+		if err != nil {
+			return config, err
+		}
+		if updated {
+			updateNodegroupProperties = true
+			continue
+		}
+
+		updated, err = awsservices.UpdateNodegroupTags(h.ctx, &awsservices.UpdateNodegroupTagsOpts{
+			EKSService:        awsSVCs.eks,
+			Config:            config,
+			NodeGroup:         &ng,
+			UpstreamNodeGroup: &upstreamNg,
+			NodegroupARN:      ngARNs[aws.StringValue(ng.NodegroupName)],
+		})
+		if err != nil {
+			return config, fmt.Errorf("error updating nodegroup tags: %w", err)
+		}
+		if updated {
+			updateNodegroupProperties = true
+		}
+
+		targetGroupsConfig := config.DeepCopy()
+		updated, err = awsservices.UpdateNodegroupTargetGroups(h.ctx, &awsservices.UpdateNodegroupTargetGroupsOpts{
+			EKSService:         awsSVCs.eks,
+			AutoScalingService: awsSVCs.autoscaling,
+			Config:             targetGroupsConfig,
+			NodeGroup:          &ng,
+		})
+		if err != nil {
+			return config, err
+		}
+		ngName := aws.StringValue(ng.NodegroupName)
+		if !utils.CompareStringSliceElements(targetGroupsConfig.Status.NodegroupTargetGroupARNs[ngName], config.Status.NodegroupTargetGroupARNs[ngName]) {
+			// the attached target groups are recorded right away, they are the only ones detached once removed
+			config, err = h.eksCC.UpdateStatus(targetGroupsConfig)
 			if err != nil {
-				return config, fmt.Errorf("error updating cluster tags: %w", err)
+				return config, err
 			}
 		}
+		updateNodegroupProperties = updateNodegroupProperties || updated
 	}
 
 	if updateNodegroupProperties {
@@ -1223,6 +1797,9 @@ func (h *Handler) updateUpstreamClusterState(upstreamSpec *eksv1.EKSClusterConfi
 			config.Status.ManagedLaunchTemplateVersions = utils.SubtractMaps(config.Status.ManagedLaunchTemplateVersions, templateVersionsToAdd)
 			config.Status.ManagedLaunchTemplateVersions = utils.MergeMaps(config.Status.ManagedLaunchTemplateVersions, templateVersionsToAdd)
 			config.Status.Phase = eksConfigUpdatingPhase
+			if len(templateVersionsToAdd) != 0 {
+				h.pruneLaunchTemplateVersions(config, awsSVCs)
+			}
 			return h.eksCC.UpdateStatus(config)
 		}
 		return h.enqueueUpdate(config)
@@ -1236,10 +1813,35 @@ func (h *Handler) updateUpstreamClusterState(upstreamSpec *eksv1.EKSClusterConfi
 		return h.eksCC.UpdateStatus(config)
 	}
 
-	// check for node groups updates here
+	h.enqueueDriftResync(config)
 	return config, nil
 }
 
+// pruneLaunchTemplateVersions deletes the versions of the managed launch template no node group uses anymore, keeping
+// the versions node groups are being rolled away from until their update finishes. Failing to prune is not fatal, the
+// versions are pruned again after the next node group update.
+func (h *Handler) pruneLaunchTemplateVersions(config *eksv1.EKSClusterConfig, awsSVCs *awsServices) {
+	inUseVersions := append(utils.ValuesFromMap(config.Status.ManagedLaunchTemplateVersions), config.Status.TemplateVersionsToDelete...)
+	if err := awsservices.PruneLaunchTemplateVersions(h.ctx, &awsservices.PruneLaunchTemplateVersionsOptions{
+		EC2Service:    awsSVCs.ec2,
+		Config:        config,
+		InUseVersions: inUseVersions,
+		Keep:          launchTemplateVersionsToKeep,
+	}); err != nil {
+		logrus.Warnf("error pruning launch template versions for cluster [%s]: %v", config.Name, err)
+	}
+}
+
+// enqueueDriftResync schedules the next comparison of an up to date cluster with its live state. Every reconcile
+// describes the cluster and its node groups rather than relying on the last known state, so re-enqueueing is enough
+// for changes made outside of the operator to be detected and reverted.
+func (h *Handler) enqueueDriftResync(config *eksv1.EKSClusterConfig) {
+	if h.options.DriftResyncInterval <= 0 {
+		return
+	}
+	h.eksEnqueueAfter(config.Namespace, config.Name, h.options.DriftResyncInterval)
+}
+
 // importCluster cluster returns a spec representing the upstream state of the cluster matching to the
 // given config's displayName and region.
 func (h *Handler) importCluster(config *eksv1.EKSClusterConfig, awsSVCs *awsServices) (*eksv1.EKSClusterConfig, error) {
@@ -1247,39 +1849,30 @@ func (h *Handler) importCluster(config *eksv1.EKSClusterConfig, awsSVCs *awsServ
 		return config, fmt.Errorf("aws services not initialized")
 	}
 
-	clusterState, err := awsservices.GetClusterState(&awsservices.GetClusterStatusOpts{
+	status, err := awsservices.ImportCluster(h.ctx, &awsservices.ImportClusterOpts{
 		EKSService: awsSVCs.eks,
+		EC2Service: awsSVCs.ec2,
 		Config:     config,
 	})
 	if err != nil {
 		return config, err
 	}
 
-	if err := h.createCASecret(config, clusterState); err != nil {
+	config = config.DeepCopy()
+	config.Status = *status
+	if err := h.createCASecret(config, config.Status.Endpoint, config.Status.CertificateAuthorityData); err != nil {
 		if !apierrors.IsAlreadyExists(err) {
 			return config, err
 		}
 	}
 
-	launchTemplatesOutput, err := awsSVCs.ec2.DescribeLaunchTemplates(&ec2.DescribeLaunchTemplatesInput{
-		LaunchTemplateNames: []*string{aws.String(fmt.Sprintf(awsservices.LaunchTemplateNameFormat, config.Spec.DisplayName))},
-	})
-	if err == nil && len(launchTemplatesOutput.LaunchTemplates) > 0 {
-		config.Status.ManagedLaunchTemplateID = aws.StringValue(launchTemplatesOutput.LaunchTemplates[0].LaunchTemplateId)
-	}
-
-	config.Status.Subnets = aws.StringValueSlice(clusterState.Cluster.ResourcesVpcConfig.SubnetIds)
-	config.Status.SecurityGroups = aws.StringValueSlice(clusterState.Cluster.ResourcesVpcConfig.SecurityGroupIds)
 	config.Status.Phase = eksConfigActivePhase
 	return h.eksCC.UpdateStatus(config)
 }
 
 // createCASecret creates a secret containing ca and endpoint. These can be used to create a kubeconfig via
 // the go sdk
-func (h *Handler) createCASecret(config *eksv1.EKSClusterConfig, clusterState *eks.DescribeClusterOutput) error {
-	endpoint := aws.StringValue(clusterState.Cluster.Endpoint)
-	ca := aws.StringValue(clusterState.Cluster.CertificateAuthority.Data)
-
+func (h *Handler) createCASecret(config *eksv1.EKSClusterConfig, endpoint, ca string) error {
 	_, err := h.secrets.Create(
 		&corev1.Secret{
 			ObjectMeta: metav1.ObjectMeta{
@@ -1315,34 +1908,20 @@ func (h *Handler) enqueueUpdate(config *eksv1.EKSClusterConfig) (*eksv1.EKSClust
 	return h.eksCC.UpdateStatus(config)
 }
 
-func getVPCStackName(name string) string {
-	return name + "-eks-vpc"
-}
-
 func getServiceRoleName(name string) string {
 	return name + "-eks-service-role"
 }
 
-func getParameterValueFromOutput(key string, outputs []*cloudformation.Output) string {
-	for _, output := range outputs {
-		if *output.OutputKey == key {
-			return *output.OutputValue
-		}
-	}
-
-	return ""
-}
-
-func deleteStack(svc services.CloudFormationServiceInterface, newStyleName, oldStyleName string) error {
+func deleteStack(ctx context.Context, svc services.CloudFormationServiceInterface, newStyleName, oldStyleName string) error {
 	name := newStyleName
-	_, err := svc.DescribeStacks(&cloudformation.DescribeStacksInput{
+	_, err := svc.DescribeStacks(ctx, &cloudformation.DescribeStacksInput{
 		StackName: aws.String(name),
 	})
 	if doesNotExist(err) {
 		name = oldStyleName
 	}
 
-	_, err = svc.DeleteStack(&cloudformation.DeleteStackInput{
+	_, err = svc.DeleteStack(ctx, &cloudformation.DeleteStackInput{
 		StackName: aws.String(name),
 	})
 	if err != nil && !doesNotExist(err) {
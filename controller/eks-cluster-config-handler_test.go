@@ -0,0 +1,164 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/golang/mock/gomock"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/pkg/eks/services/mock_services"
+	ekscontrollers "github.com/rancher/eks-operator/pkg/generated/controllers/eks.cattle.io/v1"
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type statusRecorder struct {
+	ekscontrollers.EKSClusterConfigClient
+	updated []*eksv1.EKSClusterConfig
+}
+
+func (s *statusRecorder) UpdateStatus(config *eksv1.EKSClusterConfig) (*eksv1.EKSClusterConfig, error) {
+	s.updated = append(s.updated, config)
+	return config, nil
+}
+
+func TestDriftResync(t *testing.T) {
+	asserts := assert.New(t)
+	eksService := mock_services.NewMockEKSServiceInterface(gomock.NewController(t))
+	statuses := &statusRecorder{}
+	var enqueuedAfter []time.Duration
+	h := &Handler{
+		ctx:   context.Background(),
+		eksCC: statuses,
+		eksEnqueueAfter: func(_, _ string, duration time.Duration) {
+			enqueuedAfter = append(enqueuedAfter, duration)
+		},
+		options: Options{DriftResyncInterval: time.Hour},
+	}
+	config := &eksv1.EKSClusterConfig{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"},
+		Spec: eksv1.EKSClusterConfigSpec{
+			DisplayName:  "test",
+			LoggingTypes: []string{"audit"},
+		},
+		Status: eksv1.EKSClusterConfigStatus{Phase: eksConfigActivePhase},
+	}
+
+	// the live state matches the config, the next comparison is scheduled
+	_, err := h.updateUpstreamClusterState(&eksv1.EKSClusterConfigSpec{LoggingTypes: []string{"audit"}}, config,
+		&awsServices{eks: eksService}, "", nil)
+	asserts.NoError(err)
+	asserts.Equal([]time.Duration{time.Hour}, enqueuedAfter)
+	asserts.Empty(statuses.updated)
+
+	// logging was disabled outside of the operator, the resync reverts it
+	eksService.EXPECT().UpdateClusterConfig(gomock.Any(), &eks.UpdateClusterConfigInput{
+		Name: aws.String("test"),
+		Logging: &eks.Logging{ClusterLogging: []*eks.LogSetup{{
+			Enabled: aws.Bool(true),
+			Types:   aws.StringSlice([]string{"audit"}),
+		}}},
+	}).Return(&eks.UpdateClusterConfigOutput{}, nil)
+	_, err = h.updateUpstreamClusterState(&eksv1.EKSClusterConfigSpec{}, config, &awsServices{eks: eksService}, "", nil)
+	asserts.NoError(err)
+	asserts.Len(statuses.updated, 1)
+	asserts.Equal(eksConfigUpdatingPhase, statuses.updated[0].Status.Phase)
+	asserts.Len(enqueuedAfter, 1)
+}
+
+func TestLogRetentionRequeuedUntilLogGroupExists(t *testing.T) {
+	asserts := assert.New(t)
+	cloudWatchLogsService := mock_services.NewMockCloudWatchLogsServiceInterface(gomock.NewController(t))
+	var enqueuedAfter []time.Duration
+	h := &Handler{
+		ctx:   context.Background(),
+		eksCC: &statusRecorder{},
+		eksEnqueueAfter: func(_, _ string, duration time.Duration) {
+			enqueuedAfter = append(enqueuedAfter, duration)
+		},
+	}
+	config := &eksv1.EKSClusterConfig{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"},
+		Spec: eksv1.EKSClusterConfigSpec{
+			DisplayName:      "test",
+			LoggingTypes:     []string{"audit"},
+			LogRetentionDays: aws.Int64(30),
+		},
+		Status: eksv1.EKSClusterConfigStatus{Phase: eksConfigActivePhase},
+	}
+
+	cloudWatchLogsService.EXPECT().DescribeLogGroups(gomock.Any(), gomock.Any()).Return(&cloudwatchlogs.DescribeLogGroupsOutput{}, nil)
+	_, err := h.updateUpstreamClusterState(&eksv1.EKSClusterConfigSpec{LoggingTypes: []string{"audit"}}, config,
+		&awsServices{cloudwatchlogs: cloudWatchLogsService}, "", nil)
+	asserts.NoError(err)
+	asserts.Equal([]time.Duration{logGroupRequeueInterval}, enqueuedAfter)
+}
+
+func TestDriftResyncDisabled(t *testing.T) {
+	enqueued := false
+	h := &Handler{
+		eksEnqueueAfter: func(_, _ string, _ time.Duration) {
+			enqueued = true
+		},
+	}
+	h.enqueueDriftResync(&eksv1.EKSClusterConfig{})
+	assert.False(t, enqueued)
+}
+
+func TestReportNodegroupLaunchIssuesOnce(t *testing.T) {
+	asserts := assert.New(t)
+	hook := logtest.NewLocal(logrus.StandardLogger())
+	defer hook.Reset()
+
+	h := &Handler{ctx: context.Background()}
+	config := &eksv1.EKSClusterConfig{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	awsSVCs := &awsServices{autoscaling: mock_services.NewMockAutoScalingServiceInterface(gomock.NewController(t))}
+	unhealthy := func(message string) *eks.Nodegroup {
+		return &eks.Nodegroup{
+			NodegroupName: aws.String("ng"),
+			Health: &eks.NodegroupHealth{Issues: []*eks.Issue{{
+				Code:    aws.String(eks.NodegroupIssueCodeAsgInstanceLaunchFailures),
+				Message: aws.String(message),
+			}}},
+		}
+	}
+
+	h.reportNodegroupLaunchIssues(config, unhealthy("launch failed"), awsSVCs)
+	h.reportNodegroupLaunchIssues(config, unhealthy("launch failed"), awsSVCs)
+	asserts.Len(hook.AllEntries(), 1)
+
+	h.reportNodegroupLaunchIssues(config, unhealthy("launch failed again"), awsSVCs)
+	asserts.Len(hook.AllEntries(), 2)
+
+	// the issues are reported again once they come back after the node group recovered
+	h.reportNodegroupLaunchIssues(config, &eks.Nodegroup{NodegroupName: aws.String("ng")}, awsSVCs)
+	h.reportNodegroupLaunchIssues(config, unhealthy("launch failed again"), awsSVCs)
+	if asserts.Len(hook.AllEntries(), 3) {
+		asserts.Equal(logrus.WarnLevel, hook.LastEntry().Level)
+		asserts.Contains(hook.LastEntry().Message, "launch failed again")
+	}
+}
+
+func TestForgetNodegroupLaunchIssues(t *testing.T) {
+	h := &Handler{}
+	config := &eksv1.EKSClusterConfig{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	other := &eksv1.EKSClusterConfig{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-2"}}
+	h.nodegroupLaunchIssues.Store(nodegroupLaunchIssuesKey(config, "ng1"), "launch failed")
+	h.nodegroupLaunchIssues.Store(nodegroupLaunchIssuesKey(config, "ng2"), "launch failed")
+	h.nodegroupLaunchIssues.Store(nodegroupLaunchIssuesKey(other, "ng1"), "launch failed")
+
+	h.forgetNodegroupLaunchIssues(config)
+
+	_, ok := h.nodegroupLaunchIssues.Load(nodegroupLaunchIssuesKey(config, "ng1"))
+	assert.False(t, ok)
+	_, ok = h.nodegroupLaunchIssues.Load(nodegroupLaunchIssuesKey(config, "ng2"))
+	assert.False(t, ok)
+	_, ok = h.nodegroupLaunchIssues.Load(nodegroupLaunchIssuesKey(other, "ng1"))
+	assert.True(t, ok)
+}
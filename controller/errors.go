@@ -7,14 +7,6 @@ import (
 	"github.com/aws/aws-sdk-go/service/eks"
 )
 
-func isClusterConflict(err error) bool {
-	if awsErr, ok := err.(awserr.Error); ok {
-		return awsErr.Code() == eks.ErrCodeResourceInUseException
-	}
-
-	return false
-}
-
 func doesNotExist(err error) bool {
 	// There is no better way of doing this because AWS API does not distinguish between a attempt to delete a stack
 	// (or key pair) that does not exist, and, for example, a malformed delete request, so we have to parse the error
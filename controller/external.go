@@ -6,12 +6,17 @@ import (
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/eks"
 	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/pkg/eks/services"
 	wranglerv1 "github.com/rancher/wrangler/pkg/generated/controllers/core/v1"
 )
 
+// credentialsCache is shared by the handler and the sessions started by other controllers, so that a role is only
+// assumed once for all of them.
+var credentialsCache = services.NewCredentialsCache()
+
 // StartAWSSessions starts AWS sessions.
 func StartAWSSessions(secretsCache wranglerv1.SecretCache, spec eksv1.EKSClusterConfigSpec) (*session.Session, *eks.EKS, error) {
-	sess, err := newAWSSession(secretsCache, spec)
+	sess, err := newAWSSession(secretsCache, credentialsCache, spec)
 	if err != nil {
 		return nil, nil, fmt.Errorf("error getting new aws session: %v", err)
 	}
@@ -1,138 +1,59 @@
 package controller
 
 import (
-	"sort"
+	"context"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/golang/mock/gomock"
 	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/pkg/eks/services/mock_services"
 	"github.com/stretchr/testify/assert"
 )
 
-func TestGetNodegroupConfigUpdate(t *testing.T) {
-	type nodegroupUpdateTestCase struct {
-		clusterName           string
-		ng1                   eksv1.NodeGroup
-		ng2                   eksv1.NodeGroup
-		expectedNgUpdateInput eks.UpdateNodegroupConfigInput
-		expectedNgNeedsUpdate bool
-	}
-	asserts := assert.New(t)
-	testCases := []nodegroupUpdateTestCase{
-		{
-			// test case where there should be no update
-			clusterName: "testcluster1",
-			ng1:         eksv1.NodeGroup{Labels: aws.StringMap(map[string]string{"a": "b"}), MinSize: aws.Int64(1), MaxSize: aws.Int64(1)},
-			ng2:         eksv1.NodeGroup{Labels: aws.StringMap(map[string]string{"a": "b"}), MinSize: aws.Int64(1), MaxSize: aws.Int64(1)},
-			expectedNgUpdateInput: eks.UpdateNodegroupConfigInput{
-				ClusterName: aws.String("testcluster1"),
-				ScalingConfig: &eks.NodegroupScalingConfig{
-					MinSize: aws.Int64(1),
-					MaxSize: aws.Int64(1),
-				},
-			},
-			expectedNgNeedsUpdate: false,
-		},
-		{
-			// test the case where upstream doesn't have scaling fields MinSize or MaxSize size but desired does
-			clusterName: "testcluster2",
-			ng1:         eksv1.NodeGroup{Labels: aws.StringMap(map[string]string{"a": "b"}), MinSize: aws.Int64(1), MaxSize: aws.Int64(1)},
-			ng2:         eksv1.NodeGroup{Labels: aws.StringMap(map[string]string{"a": "b"})},
-			expectedNgUpdateInput: eks.UpdateNodegroupConfigInput{
-				ClusterName: aws.String("testcluster2"),
-				ScalingConfig: &eks.NodegroupScalingConfig{
-					MinSize: aws.Int64(1),
-					MaxSize: aws.Int64(1),
-				}},
-			expectedNgNeedsUpdate: true,
-		},
-		{
-			// test case where scaling field, DesiredSize, should be updated
-			clusterName: "testcluster3",
-			ng1:         eksv1.NodeGroup{Labels: aws.StringMap(map[string]string{"a": "b"}), DesiredSize: aws.Int64(1)},
-			ng2:         eksv1.NodeGroup{Labels: aws.StringMap(map[string]string{"a": "b"}), DesiredSize: aws.Int64(3)},
-			expectedNgUpdateInput: eks.UpdateNodegroupConfigInput{
-				ClusterName: aws.String("testcluster3"),
-				ScalingConfig: &eks.NodegroupScalingConfig{
-					DesiredSize: aws.Int64(1),
-				}},
-			expectedNgNeedsUpdate: true,
-		},
-		{
-			// test case where label should be deleted
-			clusterName: "testcluster4",
-			ng1:         eksv1.NodeGroup{Labels: aws.StringMap(map[string]string{}), MinSize: aws.Int64(1), MaxSize: aws.Int64(1)},
-			ng2:         eksv1.NodeGroup{Labels: aws.StringMap(map[string]string{"a": "b"}), MinSize: aws.Int64(1), MaxSize: aws.Int64(1)},
-			expectedNgUpdateInput: eks.UpdateNodegroupConfigInput{
-				ClusterName: aws.String("testcluster4"),
-				Labels: &eks.UpdateLabelsPayload{
-					RemoveLabels: aws.StringSlice([]string{"a"}),
-				},
-				ScalingConfig: &eks.NodegroupScalingConfig{
-					MinSize: aws.Int64(1),
-					MaxSize: aws.Int64(1),
-				}},
-			expectedNgNeedsUpdate: true,
-		},
-		{
-			// test case where label should be added
-			clusterName: "testcluster5",
-			ng1:         eksv1.NodeGroup{Labels: aws.StringMap(map[string]string{"a": "b"}), MinSize: aws.Int64(1), MaxSize: aws.Int64(1)},
-			ng2:         eksv1.NodeGroup{Labels: aws.StringMap(map[string]string{}), MinSize: aws.Int64(1), MaxSize: aws.Int64(1)},
-			expectedNgUpdateInput: eks.UpdateNodegroupConfigInput{
-				ClusterName: aws.String("testcluster5"),
-				Labels: &eks.UpdateLabelsPayload{
-					AddOrUpdateLabels: aws.StringMap(map[string]string{"a": "b"}),
-				},
-				ScalingConfig: &eks.NodegroupScalingConfig{
-					MinSize: aws.Int64(1),
-					MaxSize: aws.Int64(1),
-				}},
-			expectedNgNeedsUpdate: true,
-		},
-		{
-			// test case where labels should be removed and added
-			clusterName: "testcluster6",
-			ng1:         eksv1.NodeGroup{Labels: aws.StringMap(map[string]string{"a": "b", "g": "h"}), MinSize: aws.Int64(1), MaxSize: aws.Int64(1)},
-			ng2:         eksv1.NodeGroup{Labels: aws.StringMap(map[string]string{"c": "d", "e": "f", "g": "h"}), MinSize: aws.Int64(1), MaxSize: aws.Int64(1)},
-			expectedNgUpdateInput: eks.UpdateNodegroupConfigInput{
-				ClusterName: aws.String("testcluster6"),
-				Labels: &eks.UpdateLabelsPayload{
-					RemoveLabels:      aws.StringSlice([]string{"c", "e"}),
-					AddOrUpdateLabels: aws.StringMap(map[string]string{"a": "b"}),
-				},
-				ScalingConfig: &eks.NodegroupScalingConfig{
-					MinSize: aws.Int64(1),
-					MaxSize: aws.Int64(1),
-				}},
-			expectedNgNeedsUpdate: true,
+func TestNewLaunchTemplateVersionIfNeededWithoutOwnershipTag(t *testing.T) {
+	config := &eksv1.EKSClusterConfig{
+		Spec: eksv1.EKSClusterConfigSpec{DisplayName: "test"},
+		Status: eksv1.EKSClusterConfigStatus{
+			ManagedLaunchTemplateID: "lt-1",
 		},
-		{
-			// test case where label should be updated
-			clusterName: "testcluster7",
-			ng1:         eksv1.NodeGroup{Labels: aws.StringMap(map[string]string{"a": "b", "g": "h"}), MinSize: aws.Int64(1), MaxSize: aws.Int64(1)},
-			ng2:         eksv1.NodeGroup{Labels: aws.StringMap(map[string]string{"a": "b", "g": "i"}), MinSize: aws.Int64(1), MaxSize: aws.Int64(1)},
-			expectedNgUpdateInput: eks.UpdateNodegroupConfigInput{
-				ClusterName: aws.String("testcluster7"),
-				Labels: &eks.UpdateLabelsPayload{
-					AddOrUpdateLabels: aws.StringMap(map[string]string{"g": "h"}),
-				},
-				ScalingConfig: &eks.NodegroupScalingConfig{
-					MinSize: aws.Int64(1),
-					MaxSize: aws.Int64(1),
-				}},
-			expectedNgNeedsUpdate: true,
+	}
+	ng := eksv1.NodeGroup{
+		NodegroupName: aws.String("ng1"),
+		DiskSize:      aws.Int64(20),
+		Ec2SshKey:     aws.String("key"),
+		InstanceType:  aws.String("t3.medium"),
+		ResourceTags:  aws.StringMap(map[string]string{"team": "a"}),
+	}
+	// the launch template of a node group created before the ownership tag was added by default
+	upstreamNg := ng
+
+	// the mock fails the test on any call, such as creating a launch template version
+	ec2Service := mock_services.NewMockEC2ServiceInterface(gomock.NewController(t))
+	lt, err := newLaunchTemplateVersionIfNeeded(context.Background(), config, upstreamNg, ng, ec2Service)
+	assert.NoError(t, err)
+	assert.Nil(t, lt)
+}
+
+func TestNewLaunchTemplateVersionIfNeededWithClusterSecurityGroup(t *testing.T) {
+	config := &eksv1.EKSClusterConfig{
+		Spec: eksv1.EKSClusterConfigSpec{DisplayName: "test"},
+		Status: eksv1.EKSClusterConfigStatus{
+			ManagedLaunchTemplateID: "lt-1",
+			ClusterSecurityGroupID:  "sg-cluster",
 		},
 	}
-	for _, testCase := range testCases {
-		ngUpdateInput, ngNeedsUpdate := getNodegroupConfigUpdate(testCase.clusterName, testCase.ng1, testCase.ng2)
-		if ngUpdateInput.Labels != nil && len(ngUpdateInput.Labels.RemoveLabels) > 0 {
-			sortedRemovedLabels := aws.StringValueSlice(ngUpdateInput.Labels.RemoveLabels)
-			sort.Strings(sortedRemovedLabels)
-			ngUpdateInput.Labels.RemoveLabels = aws.StringSlice(sortedRemovedLabels)
-		}
-		asserts.Equal(testCase.expectedNgUpdateInput, ngUpdateInput)
-		asserts.Equal(testCase.expectedNgNeedsUpdate, ngNeedsUpdate)
+	ng := eksv1.NodeGroup{
+		NodegroupName:           aws.String("ng1"),
+		DiskSize:                aws.Int64(20),
+		InstanceType:            aws.String("t3.medium"),
+		NodeGroupSecurityGroups: []string{"sg-extra"},
 	}
+	upstreamNg := ng
+	upstreamNg.NodeGroupSecurityGroups = []string{"sg-extra", "sg-cluster"}
+
+	ec2Service := mock_services.NewMockEC2ServiceInterface(gomock.NewController(t))
+	lt, err := newLaunchTemplateVersionIfNeeded(context.Background(), config, upstreamNg, ng, ec2Service)
+	assert.NoError(t, err)
+	assert.Nil(t, lt)
 }
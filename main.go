@@ -5,8 +5,14 @@ package main
 
 import (
 	"flag"
+	"net/http"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rancher/eks-operator/controller"
+	awsservices "github.com/rancher/eks-operator/pkg/eks"
+	"github.com/rancher/eks-operator/pkg/eks/services"
 	eksv1 "github.com/rancher/eks-operator/pkg/generated/controllers/eks.cattle.io"
 	"github.com/rancher/wrangler-api/pkg/generated/controllers/apps"
 	core3 "github.com/rancher/wrangler/pkg/generated/controllers/core"
@@ -17,13 +23,28 @@ import (
 )
 
 var (
-	masterURL      string
-	kubeconfigFile string
+	masterURL                 string
+	kubeconfigFile            string
+	publicIPDetectionURL      string
+	refusePublicAccessLockout bool
+	driftResyncInterval       time.Duration
+	validateNodeRolePolicies  bool
+	metricsAddress            string
 )
 
 func init() {
 	flag.StringVar(&kubeconfigFile, "kubeconfig", "", "Path to a kubeconfig. Only required if out-of-cluster.")
 	flag.StringVar(&masterURL, "master", "", "The address of the Kubernetes API server. Overrides any value in kubeconfig. Only required if out-of-cluster.")
+	flag.StringVar(&publicIPDetectionURL, "public-ip-detection-url", "",
+		"URL answering with the public IP of the operator, such as https://checkip.amazonaws.com, used to detect public access sources that would lock the operator out. Empty disables the check.")
+	flag.BoolVar(&refusePublicAccessLockout, "refuse-public-access-lockout", false,
+		"Refuse public access sources that would lock the operator out instead of only logging a warning.")
+	flag.DurationVar(&driftResyncInterval, "drift-resync-interval", 0,
+		"Interval at which active clusters are compared with their live state to revert changes made outside of the operator. Zero disables the periodic comparison.")
+	flag.BoolVar(&validateNodeRolePolicies, "validate-node-role-policies", false,
+		"Check that the node roles given for node groups have the node policies attached before creating the node groups. Needs the iam:ListAttachedRolePolicies permission.")
+	flag.StringVar(&metricsAddress, "metrics-address", "",
+		"Address to serve the AWS API request metrics on, such as :8080. Empty disables the metrics.")
 	flag.Parse()
 }
 
@@ -54,9 +75,38 @@ func main() {
 	// The typical pattern is to build all your controller/clients then just pass to each handler
 	// the bare minimum of what they need.  This will eventually help with writing tests.  So
 	// don't pass in something like kubeClient, apps, or sample
+	options := controller.Options{
+		RefusePublicAccessLockout: refusePublicAccessLockout,
+		DriftResyncInterval:       driftResyncInterval,
+		ValidateNodeRolePolicies:  validateNodeRolePolicies,
+	}
+	if publicIPDetectionURL != "" {
+		options.DetectPublicIP = awsservices.NewHTTPPublicIPDetector(publicIPDetectionURL, &http.Client{Timeout: 10 * time.Second})
+	}
+
+	if metricsAddress != "" {
+		options.APIMetrics, err = services.NewAPIMetrics(prometheus.DefaultRegisterer)
+		if err != nil {
+			logrus.Fatalf("Error registering metrics: %s", err.Error())
+		}
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.Handler())
+		metricsServer := &http.Server{
+			Addr:              metricsAddress,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		go func() {
+			if err := metricsServer.ListenAndServe(); err != nil {
+				logrus.Errorf("Error serving metrics: %s", err.Error())
+			}
+		}()
+	}
+
 	controller.Register(ctx,
 		core.Core().V1().Secret(),
-		eks.Eks().V1().EKSClusterConfig())
+		eks.Eks().V1().EKSClusterConfig(),
+		options)
 
 	// Start all the controllers
 	if err := start.All(ctx, 3, apps, eks, core); err != nil {
@@ -17,6 +17,7 @@ limitations under the License.
 package v1
 
 import (
+	"github.com/rancher/wrangler/pkg/genericcondition"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -34,21 +35,34 @@ type EKSClusterConfig struct {
 // EKSClusterConfigSpec is the spec for a EKSClusterConfig resource
 type EKSClusterConfigSpec struct {
 	AmazonCredentialSecret string            `json:"amazonCredentialSecret"`
+	AssumeRoleARN          string            `json:"assumeRoleArn"`
+	ExternalID             string            `json:"externalId"`
 	DisplayName            string            `json:"displayName" norman:"noupdate"`
 	Region                 string            `json:"region" norman:"noupdate"`
 	Imported               bool              `json:"imported" norman:"noupdate"`
 	KubernetesVersion      *string           `json:"kubernetesVersion" norman:"pointer"`
 	Tags                   map[string]string `json:"tags"`
+	NormalizeTags          *bool             `json:"normalizeTags"`
 	SecretsEncryption      *bool             `json:"secretsEncryption" norman:"noupdate"`
 	KmsKey                 *string           `json:"kmsKey" norman:"noupdate,pointer"`
+	EncryptionResources    []string          `json:"encryptionResources" norman:"noupdate"`
 	PublicAccess           *bool             `json:"publicAccess"`
 	PrivateAccess          *bool             `json:"privateAccess"`
 	PublicAccessSources    []string          `json:"publicAccessSources"`
 	LoggingTypes           []string          `json:"loggingTypes"`
+	LogRetentionDays       *int64            `json:"logRetentionDays"`
 	Subnets                []string          `json:"subnets" norman:"noupdate"`
 	SecurityGroups         []string          `json:"securityGroups" norman:"noupdate"`
 	ServiceRole            *string           `json:"serviceRole" norman:"noupdate,pointer"`
+	PermissionsBoundaryARN *string           `json:"permissionsBoundaryArn" norman:"noupdate,pointer"`
+	LaunchTemplatePrefix   *string           `json:"launchTemplatePrefix" norman:"noupdate,pointer"`
 	NodeGroups             []NodeGroup       `json:"nodeGroups"`
+	FargateProfile         *FargateProfile   `json:"fargateProfile"`
+	Addons                 []Addon           `json:"addons"`
+	SubnetCapacityCheck    *string           `json:"subnetCapacityCheck" norman:"pointer"`
+	AccessConfig           *AccessConfig     `json:"accessConfig"`
+	AccessEntries          []AccessEntry     `json:"accessEntries"`
+	AssociateOIDCProvider  *bool             `json:"associateOidcProvider"`
 }
 
 type EKSClusterConfigStatus struct {
@@ -63,28 +77,131 @@ type EKSClusterConfigStatus struct {
 	NetworkFieldsSource string `json:"networkFieldsSource"`
 	FailureMessage      string `json:"failureMessage"`
 	GeneratedNodeRole   string `json:"generatedNodeRole"`
+	// AccessEntries are the principal ARNs of the access entries created from the spec. Only those are deleted once
+	// they are removed from the spec, so the entries EKS creates for node roles and the cluster creator are kept.
+	AccessEntries []string `json:"accessEntries"`
+	// LogRetentionDays is the retention last set on the control plane log group, it is only set again when the
+	// retention of the spec changes.
+	LogRetentionDays int64 `json:"logRetentionDays"`
+	// NodegroupTargetGroupARNs are the target groups attached to the auto scaling groups of each node group, by node
+	// group name. Only those are detached once removed from the spec of the node group.
+	NodegroupTargetGroupARNs map[string][]string `json:"nodegroupTargetGroupArns"`
+	// NodegroupDesiredSizes are the desired sizes last applied to the node groups, by node group name. With
+	// ignoreDesiredSizeDrift a live desired size differing from the spec is only kept while the desired size of the
+	// spec still matches the one last applied.
+	NodegroupDesiredSizes map[string]int64 `json:"nodegroupDesiredSizes"`
+	// OIDCProviderARN is the IAM OIDC provider registered for the OIDC issuer of the cluster. It may predate the
+	// cluster config and is left in place when the cluster is deleted.
+	OIDCProviderARN string `json:"oidcProviderArn"`
+	// fields below are read from the upstream cluster
+	ClusterARN               string `json:"clusterArn"`
+	Endpoint                 string `json:"endpoint"`
+	CertificateAuthorityData string `json:"certificateAuthorityData"`
+	KmsKeyARN                string `json:"kmsKeyArn"`
+	OIDCIssuer               string `json:"oidcIssuer"`
+	ClusterSecurityGroupID   string `json:"clusterSecurityGroupId"`
+	// conditions describing the health of the upstream control plane
+	Conditions []genericcondition.GenericCondition `json:"conditions"`
 }
 
 type NodeGroup struct {
-	Gpu                  *bool              `json:"gpu"`
-	ImageID              *string            `json:"imageId" norman:"pointer"`
-	NodegroupName        *string            `json:"nodegroupName" norman:"required,pointer" wrangler:"required"`
-	DiskSize             *int64             `json:"diskSize"`
-	InstanceType         *string            `json:"instanceType" norman:"pointer"`
-	Labels               map[string]*string `json:"labels"`
-	Ec2SshKey            *string            `json:"ec2SshKey" norman:"pointer"`
-	DesiredSize          *int64             `json:"desiredSize"`
-	MaxSize              *int64             `json:"maxSize"`
-	MinSize              *int64             `json:"minSize"`
-	Subnets              []string           `json:"subnets"`
-	Tags                 map[string]*string `json:"tags"`
-	ResourceTags         map[string]*string `json:"resourceTags"`
-	UserData             *string            `json:"userData" norman:"pointer"`
-	Version              *string            `json:"version" norman:"pointer"`
-	LaunchTemplate       *LaunchTemplate    `json:"launchTemplate"`
-	RequestSpotInstances *bool              `json:"requestSpotInstances"`
-	SpotInstanceTypes    []*string          `json:"spotInstanceTypes"`
-	NodeRole             *string            `json:"nodeRole" norman:"pointer"`
+	Gpu                        *bool                  `json:"gpu"`
+	Arm                        *bool                  `json:"arm"`
+	AMIFamily                  *string                `json:"amiFamily" norman:"pointer"`
+	ImageID                    *string                `json:"imageId" norman:"pointer"`
+	NodegroupName              *string                `json:"nodegroupName" norman:"required,pointer" wrangler:"required"`
+	DiskSize                   *int64                 `json:"diskSize"`
+	DiskType                   *string                `json:"diskType" norman:"pointer"`
+	Iops                       *int64                 `json:"iops"`
+	Throughput                 *int64                 `json:"throughput"`
+	InstanceType               *string                `json:"instanceType" norman:"pointer"`
+	Labels                     map[string]*string     `json:"labels"`
+	Ec2SshKey                  *string                `json:"ec2SshKey" norman:"pointer"`
+	DesiredSize                *int64                 `json:"desiredSize"`
+	MaxSize                    *int64                 `json:"maxSize"`
+	MinSize                    *int64                 `json:"minSize"`
+	IgnoreDesiredSizeDrift     *bool                  `json:"ignoreDesiredSizeDrift"`
+	Subnets                    []string               `json:"subnets"`
+	Tags                       map[string]*string     `json:"tags"`
+	ResourceTags               map[string]*string     `json:"resourceTags"`
+	VolumeTags                 map[string]*string     `json:"volumeTags"`
+	UserData                   *string                `json:"userData" norman:"pointer"`
+	Version                    *string                `json:"version" norman:"pointer"`
+	LaunchTemplate             *LaunchTemplate        `json:"launchTemplate"`
+	RequestSpotInstances       *bool                  `json:"requestSpotInstances"`
+	SpotInstanceTypes          []*string              `json:"spotInstanceTypes"`
+	NodeRole                   *string                `json:"nodeRole" norman:"pointer"`
+	IamInstanceProfile         *string                `json:"iamInstanceProfile" norman:"pointer"`
+	TargetGroupARNs            []string               `json:"targetGroupArns"`
+	Taints                     []Taint                `json:"taints"`
+	DisableClusterOwnershipTag *bool                  `json:"disableClusterOwnershipTag"`
+	SecurityGroupsForPods      *bool                  `json:"securityGroupsForPods"`
+	UpdateConfig               *NodeGroupUpdateConfig `json:"updateConfig"`
+	UpdateStrategy             *string                `json:"updateStrategy" norman:"pointer"`
+	AvailabilityZone           *string                `json:"availabilityZone" norman:"noupdate,pointer"`
+	NodeTerminationHandler     *bool                  `json:"nodeTerminationHandler"`
+	NodeGroupSecurityGroups    []string               `json:"nodeGroupSecurityGroups"`
+	ExpectedPodsPerNode        *int64                 `json:"expectedPodsPerNode"`
+	BootstrapExtraArgs         *string                `json:"bootstrapExtraArgs" norman:"pointer"`
+	KubeletExtraArgs           *string                `json:"kubeletExtraArgs" norman:"pointer"`
+}
+
+// NodeGroupUpdateConfig limits how many nodes EKS replaces at once when rolling out a node group update. EKS drains
+// the nodes of each batch respecting PodDisruptionBudgets and only moves to the next batch once the new nodes are
+// ready, so a budget that never allows an eviction stalls the rollout until the update fails, unless it is forced.
+type NodeGroupUpdateConfig struct {
+	MaxUnavailable           *int64 `json:"maxUnavailable"`
+	MaxUnavailablePercentage *int64 `json:"maxUnavailablePercentage"`
+}
+
+type Taint struct {
+	Key    *string `json:"key" norman:"pointer"`
+	Value  *string `json:"value" norman:"pointer"`
+	Effect *string `json:"effect" norman:"pointer"`
+}
+
+// AccessConfig sets how the cluster authenticates IAM principals, either with the aws-auth config map, with access
+// entries or with both. The authentication mode can only move from CONFIG_MAP through API_AND_CONFIG_MAP to API.
+type AccessConfig struct {
+	AuthenticationMode *string `json:"authenticationMode" norman:"pointer"`
+}
+
+// AccessEntry grants an IAM principal access to the cluster, through kubernetes groups or access policies, once the
+// authentication mode of the cluster allows access entries.
+type AccessEntry struct {
+	PrincipalArn     string         `json:"principalArn"`
+	KubernetesGroups []string       `json:"kubernetesGroups"`
+	Type             *string        `json:"type" norman:"pointer"`
+	AccessPolicies   []AccessPolicy `json:"accessPolicies"`
+}
+
+type AccessPolicy struct {
+	PolicyArn   string      `json:"policyArn"`
+	AccessScope AccessScope `json:"accessScope"`
+}
+
+type AccessScope struct {
+	Type       string   `json:"type"`
+	Namespaces []string `json:"namespaces"`
+}
+
+type Addon struct {
+	Name                  string  `json:"name"`
+	Version               *string `json:"version" norman:"pointer"`
+	ServiceAccountRoleArn *string `json:"serviceAccountRoleArn" norman:"pointer"`
+	ConfigurationValues   *string `json:"configurationValues" norman:"pointer"`
+}
+
+type FargateProfile struct {
+	FargateProfileName  *string                  `json:"fargateProfileName" norman:"pointer"`
+	PodExecutionRoleArn *string                  `json:"podExecutionRoleArn" norman:"pointer"`
+	Subnets             []string                 `json:"subnets"`
+	Selectors           []FargateProfileSelector `json:"selectors"`
+}
+
+type FargateProfileSelector struct {
+	Namespace *string            `json:"namespace" norman:"pointer"`
+	Labels    map[string]*string `json:"labels"`
 }
 
 type LaunchTemplate struct {
@@ -22,9 +22,133 @@ limitations under the License.
 package v1
 
 import (
+	genericcondition "github.com/rancher/wrangler/pkg/genericcondition"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccessConfig) DeepCopyInto(out *AccessConfig) {
+	*out = *in
+	if in.AuthenticationMode != nil {
+		in, out := &in.AuthenticationMode, &out.AuthenticationMode
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccessConfig.
+func (in *AccessConfig) DeepCopy() *AccessConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AccessConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccessEntry) DeepCopyInto(out *AccessEntry) {
+	*out = *in
+	if in.KubernetesGroups != nil {
+		in, out := &in.KubernetesGroups, &out.KubernetesGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Type != nil {
+		in, out := &in.Type, &out.Type
+		*out = new(string)
+		**out = **in
+	}
+	if in.AccessPolicies != nil {
+		in, out := &in.AccessPolicies, &out.AccessPolicies
+		*out = make([]AccessPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccessEntry.
+func (in *AccessEntry) DeepCopy() *AccessEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(AccessEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccessPolicy) DeepCopyInto(out *AccessPolicy) {
+	*out = *in
+	in.AccessScope.DeepCopyInto(&out.AccessScope)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccessPolicy.
+func (in *AccessPolicy) DeepCopy() *AccessPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(AccessPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccessScope) DeepCopyInto(out *AccessScope) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccessScope.
+func (in *AccessScope) DeepCopy() *AccessScope {
+	if in == nil {
+		return nil
+	}
+	out := new(AccessScope)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Addon) DeepCopyInto(out *Addon) {
+	*out = *in
+	if in.Version != nil {
+		in, out := &in.Version, &out.Version
+		*out = new(string)
+		**out = **in
+	}
+	if in.ServiceAccountRoleArn != nil {
+		in, out := &in.ServiceAccountRoleArn, &out.ServiceAccountRoleArn
+		*out = new(string)
+		**out = **in
+	}
+	if in.ConfigurationValues != nil {
+		in, out := &in.ConfigurationValues, &out.ConfigurationValues
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Addon.
+func (in *Addon) DeepCopy() *Addon {
+	if in == nil {
+		return nil
+	}
+	out := new(Addon)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EKSClusterConfig) DeepCopyInto(out *EKSClusterConfig) {
 	*out = *in
@@ -101,6 +225,11 @@ func (in *EKSClusterConfigSpec) DeepCopyInto(out *EKSClusterConfigSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.NormalizeTags != nil {
+		in, out := &in.NormalizeTags, &out.NormalizeTags
+		*out = new(bool)
+		**out = **in
+	}
 	if in.SecretsEncryption != nil {
 		in, out := &in.SecretsEncryption, &out.SecretsEncryption
 		*out = new(bool)
@@ -111,6 +240,11 @@ func (in *EKSClusterConfigSpec) DeepCopyInto(out *EKSClusterConfigSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.EncryptionResources != nil {
+		in, out := &in.EncryptionResources, &out.EncryptionResources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.PublicAccess != nil {
 		in, out := &in.PublicAccess, &out.PublicAccess
 		*out = new(bool)
@@ -131,6 +265,11 @@ func (in *EKSClusterConfigSpec) DeepCopyInto(out *EKSClusterConfigSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.LogRetentionDays != nil {
+		in, out := &in.LogRetentionDays, &out.LogRetentionDays
+		*out = new(int64)
+		**out = **in
+	}
 	if in.Subnets != nil {
 		in, out := &in.Subnets, &out.Subnets
 		*out = make([]string, len(*in))
@@ -146,6 +285,16 @@ func (in *EKSClusterConfigSpec) DeepCopyInto(out *EKSClusterConfigSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.PermissionsBoundaryARN != nil {
+		in, out := &in.PermissionsBoundaryARN, &out.PermissionsBoundaryARN
+		*out = new(string)
+		**out = **in
+	}
+	if in.LaunchTemplatePrefix != nil {
+		in, out := &in.LaunchTemplatePrefix, &out.LaunchTemplatePrefix
+		*out = new(string)
+		**out = **in
+	}
 	if in.NodeGroups != nil {
 		in, out := &in.NodeGroups, &out.NodeGroups
 		*out = make([]NodeGroup, len(*in))
@@ -153,6 +302,40 @@ func (in *EKSClusterConfigSpec) DeepCopyInto(out *EKSClusterConfigSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.FargateProfile != nil {
+		in, out := &in.FargateProfile, &out.FargateProfile
+		*out = new(FargateProfile)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Addons != nil {
+		in, out := &in.Addons, &out.Addons
+		*out = make([]Addon, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SubnetCapacityCheck != nil {
+		in, out := &in.SubnetCapacityCheck, &out.SubnetCapacityCheck
+		*out = new(string)
+		**out = **in
+	}
+	if in.AccessConfig != nil {
+		in, out := &in.AccessConfig, &out.AccessConfig
+		*out = new(AccessConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AccessEntries != nil {
+		in, out := &in.AccessEntries, &out.AccessEntries
+		*out = make([]AccessEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AssociateOIDCProvider != nil {
+		in, out := &in.AssociateOIDCProvider, &out.AssociateOIDCProvider
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
@@ -191,6 +374,38 @@ func (in *EKSClusterConfigStatus) DeepCopyInto(out *EKSClusterConfigStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.AccessEntries != nil {
+		in, out := &in.AccessEntries, &out.AccessEntries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodegroupTargetGroupARNs != nil {
+		in, out := &in.NodegroupTargetGroupARNs, &out.NodegroupTargetGroupARNs
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.NodegroupDesiredSizes != nil {
+		in, out := &in.NodegroupDesiredSizes, &out.NodegroupDesiredSizes
+		*out = make(map[string]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]genericcondition.GenericCondition, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -204,6 +419,80 @@ func (in *EKSClusterConfigStatus) DeepCopy() *EKSClusterConfigStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FargateProfile) DeepCopyInto(out *FargateProfile) {
+	*out = *in
+	if in.FargateProfileName != nil {
+		in, out := &in.FargateProfileName, &out.FargateProfileName
+		*out = new(string)
+		**out = **in
+	}
+	if in.PodExecutionRoleArn != nil {
+		in, out := &in.PodExecutionRoleArn, &out.PodExecutionRoleArn
+		*out = new(string)
+		**out = **in
+	}
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Selectors != nil {
+		in, out := &in.Selectors, &out.Selectors
+		*out = make([]FargateProfileSelector, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FargateProfile.
+func (in *FargateProfile) DeepCopy() *FargateProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(FargateProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FargateProfileSelector) DeepCopyInto(out *FargateProfileSelector) {
+	*out = *in
+	if in.Namespace != nil {
+		in, out := &in.Namespace, &out.Namespace
+		*out = new(string)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]*string, len(*in))
+		for key, val := range *in {
+			var outVal *string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = new(string)
+				**out = **in
+			}
+			(*out)[key] = outVal
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FargateProfileSelector.
+func (in *FargateProfileSelector) DeepCopy() *FargateProfileSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(FargateProfileSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LaunchTemplate) DeepCopyInto(out *LaunchTemplate) {
 	*out = *in
@@ -243,6 +532,16 @@ func (in *NodeGroup) DeepCopyInto(out *NodeGroup) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Arm != nil {
+		in, out := &in.Arm, &out.Arm
+		*out = new(bool)
+		**out = **in
+	}
+	if in.AMIFamily != nil {
+		in, out := &in.AMIFamily, &out.AMIFamily
+		*out = new(string)
+		**out = **in
+	}
 	if in.ImageID != nil {
 		in, out := &in.ImageID, &out.ImageID
 		*out = new(string)
@@ -258,6 +557,21 @@ func (in *NodeGroup) DeepCopyInto(out *NodeGroup) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.DiskType != nil {
+		in, out := &in.DiskType, &out.DiskType
+		*out = new(string)
+		**out = **in
+	}
+	if in.Iops != nil {
+		in, out := &in.Iops, &out.Iops
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Throughput != nil {
+		in, out := &in.Throughput, &out.Throughput
+		*out = new(int64)
+		**out = **in
+	}
 	if in.InstanceType != nil {
 		in, out := &in.InstanceType, &out.InstanceType
 		*out = new(string)
@@ -298,6 +612,11 @@ func (in *NodeGroup) DeepCopyInto(out *NodeGroup) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.IgnoreDesiredSizeDrift != nil {
+		in, out := &in.IgnoreDesiredSizeDrift, &out.IgnoreDesiredSizeDrift
+		*out = new(bool)
+		**out = **in
+	}
 	if in.Subnets != nil {
 		in, out := &in.Subnets, &out.Subnets
 		*out = make([]string, len(*in))
@@ -333,6 +652,21 @@ func (in *NodeGroup) DeepCopyInto(out *NodeGroup) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.VolumeTags != nil {
+		in, out := &in.VolumeTags, &out.VolumeTags
+		*out = make(map[string]*string, len(*in))
+		for key, val := range *in {
+			var outVal *string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = new(string)
+				**out = **in
+			}
+			(*out)[key] = outVal
+		}
+	}
 	if in.UserData != nil {
 		in, out := &in.UserData, &out.UserData
 		*out = new(string)
@@ -369,6 +703,73 @@ func (in *NodeGroup) DeepCopyInto(out *NodeGroup) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.IamInstanceProfile != nil {
+		in, out := &in.IamInstanceProfile, &out.IamInstanceProfile
+		*out = new(string)
+		**out = **in
+	}
+	if in.TargetGroupARNs != nil {
+		in, out := &in.TargetGroupARNs, &out.TargetGroupARNs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Taints != nil {
+		in, out := &in.Taints, &out.Taints
+		*out = make([]Taint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DisableClusterOwnershipTag != nil {
+		in, out := &in.DisableClusterOwnershipTag, &out.DisableClusterOwnershipTag
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SecurityGroupsForPods != nil {
+		in, out := &in.SecurityGroupsForPods, &out.SecurityGroupsForPods
+		*out = new(bool)
+		**out = **in
+	}
+	if in.UpdateConfig != nil {
+		in, out := &in.UpdateConfig, &out.UpdateConfig
+		*out = new(NodeGroupUpdateConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(string)
+		**out = **in
+	}
+	if in.AvailabilityZone != nil {
+		in, out := &in.AvailabilityZone, &out.AvailabilityZone
+		*out = new(string)
+		**out = **in
+	}
+	if in.NodeTerminationHandler != nil {
+		in, out := &in.NodeTerminationHandler, &out.NodeTerminationHandler
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NodeGroupSecurityGroups != nil {
+		in, out := &in.NodeGroupSecurityGroups, &out.NodeGroupSecurityGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExpectedPodsPerNode != nil {
+		in, out := &in.ExpectedPodsPerNode, &out.ExpectedPodsPerNode
+		*out = new(int64)
+		**out = **in
+	}
+	if in.BootstrapExtraArgs != nil {
+		in, out := &in.BootstrapExtraArgs, &out.BootstrapExtraArgs
+		*out = new(string)
+		**out = **in
+	}
+	if in.KubeletExtraArgs != nil {
+		in, out := &in.KubeletExtraArgs, &out.KubeletExtraArgs
+		*out = new(string)
+		**out = **in
+	}
 	return
 }
 
@@ -381,3 +782,60 @@ func (in *NodeGroup) DeepCopy() *NodeGroup {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeGroupUpdateConfig) DeepCopyInto(out *NodeGroupUpdateConfig) {
+	*out = *in
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxUnavailablePercentage != nil {
+		in, out := &in.MaxUnavailablePercentage, &out.MaxUnavailablePercentage
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeGroupUpdateConfig.
+func (in *NodeGroupUpdateConfig) DeepCopy() *NodeGroupUpdateConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeGroupUpdateConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Taint) DeepCopyInto(out *Taint) {
+	*out = *in
+	if in.Key != nil {
+		in, out := &in.Key, &out.Key
+		*out = new(string)
+		**out = **in
+	}
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = new(string)
+		**out = **in
+	}
+	if in.Effect != nil {
+		in, out := &in.Effect, &out.Effect
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Taint.
+func (in *Taint) DeepCopy() *Taint {
+	if in == nil {
+		return nil
+	}
+	out := new(Taint)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,220 @@
+package eks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/eks"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/pkg/eks/services"
+	"github.com/sirupsen/logrus"
+)
+
+// accessEntryTypes are the access entry types EKS supports, the SDK has no enum for them.
+var accessEntryTypes = []string{"STANDARD", "EC2_LINUX", "EC2_WINDOWS", "FARGATE_LINUX"}
+
+// ValidateAccessEntries catches access entries EKS would reject. Principals and policies have to be given by ARN,
+// namespaces can only be set on namespace scoped policies, and the cluster must not keep the CONFIG_MAP
+// authentication mode which ignores access entries.
+func ValidateAccessEntries(config *eksv1.EKSClusterConfig) error {
+	if len(config.Spec.AccessEntries) == 0 {
+		return nil
+	}
+
+	if config.Spec.AccessConfig != nil && aws.StringValue(config.Spec.AccessConfig.AuthenticationMode) == eks.AuthenticationModeConfigMap {
+		return fmt.Errorf("accessEntries for cluster [%s] need authenticationMode [%s] or [%s]",
+			config.Name, eks.AuthenticationModeApiAndConfigMap, eks.AuthenticationModeApi)
+	}
+
+	principals := make(map[string]bool, len(config.Spec.AccessEntries))
+	for _, entry := range config.Spec.AccessEntries {
+		if _, err := arn.Parse(entry.PrincipalArn); err != nil {
+			return fmt.Errorf("principalArn [%s] of access entry for cluster [%s] is not a valid ARN: %w", entry.PrincipalArn, config.Name, err)
+		}
+		if principals[entry.PrincipalArn] {
+			return fmt.Errorf("principalArn [%s] has more than one access entry for cluster [%s]", entry.PrincipalArn, config.Name)
+		}
+		principals[entry.PrincipalArn] = true
+
+		if entryType := aws.StringValue(entry.Type); entry.Type != nil && !validAccessEntryType(entryType) {
+			return fmt.Errorf("type [%s] of access entry [%s] for cluster [%s] is not supported, must be one of [%s]",
+				entryType, entry.PrincipalArn, config.Name, strings.Join(accessEntryTypes, ", "))
+		}
+
+		for _, policy := range entry.AccessPolicies {
+			if _, err := arn.Parse(policy.PolicyArn); err != nil {
+				return fmt.Errorf("policyArn [%s] of access entry [%s] for cluster [%s] is not a valid ARN: %w",
+					policy.PolicyArn, entry.PrincipalArn, config.Name, err)
+			}
+			switch policy.AccessScope.Type {
+			case eks.AccessScopeTypeCluster:
+				if len(policy.AccessScope.Namespaces) != 0 {
+					return fmt.Errorf("policy [%s] of access entry [%s] for cluster [%s] cannot set namespaces for a cluster scope",
+						policy.PolicyArn, entry.PrincipalArn, config.Name)
+				}
+			case eks.AccessScopeTypeNamespace:
+				if len(policy.AccessScope.Namespaces) == 0 {
+					return fmt.Errorf("policy [%s] of access entry [%s] for cluster [%s] must set namespaces for a namespace scope",
+						policy.PolicyArn, entry.PrincipalArn, config.Name)
+				}
+			default:
+				return fmt.Errorf("access scope type [%s] of policy [%s] of access entry [%s] for cluster [%s] is not supported, must be one of [%s]",
+					policy.AccessScope.Type, policy.PolicyArn, entry.PrincipalArn, config.Name, strings.Join(eks.AccessScopeType_Values(), ", "))
+			}
+		}
+	}
+
+	return nil
+}
+
+type CreateAccessEntriesOpts struct {
+	EKSService services.EKSServiceInterface
+	Config     *eksv1.EKSClusterConfig
+}
+
+// CreateAccessEntries creates the access entries of the cluster and associates their access policies. Entries that
+// already exist are adopted, and the principals of all the entries are recorded in the status of the config so they
+// are deleted once removed from the spec.
+func CreateAccessEntries(ctx context.Context, opts *CreateAccessEntriesOpts) (err error) {
+	ctx, span := startSpan(ctx, "CreateAccessEntries", opts.Config.Spec.DisplayName)
+	defer func() { endSpan(span, err) }()
+
+	for _, entry := range opts.Config.Spec.AccessEntries {
+		if err := createAccessEntry(ctx, opts.EKSService, opts.Config, entry); err != nil {
+			return err
+		}
+	}
+	opts.Config.Status.AccessEntries = getAccessEntryPrincipals(opts.Config.Spec.AccessEntries)
+
+	return nil
+}
+
+type UpdateAccessEntriesOpts struct {
+	EKSService services.EKSServiceInterface
+	Config     *eksv1.EKSClusterConfig
+}
+
+// UpdateAccessEntries creates the access entries of the spec missing upstream and deletes the entries created from
+// the spec that were removed from it since. Entries the operator did not create, such as the ones EKS creates for
+// node roles and the cluster creator, are never deleted. The access policies and kubernetes groups of existing
+// entries are left as they are. The status of the config records the principals of the entries of the spec.
+func UpdateAccessEntries(ctx context.Context, opts *UpdateAccessEntriesOpts) (_ bool, err error) {
+	ctx, span := startSpan(ctx, "UpdateAccessEntries", opts.Config.Spec.DisplayName)
+	defer func() { endSpan(span, err) }()
+
+	upstreamPrincipals, err := listAccessEntries(ctx, opts.EKSService, opts.Config.Spec.DisplayName)
+	if err != nil {
+		return false, fmt.Errorf("error listing access entries for cluster [%s]: %w", opts.Config.Name, err)
+	}
+
+	updated := false
+	principals := make(map[string]bool, len(opts.Config.Spec.AccessEntries))
+	for _, entry := range opts.Config.Spec.AccessEntries {
+		principals[entry.PrincipalArn] = true
+	}
+	for _, entry := range opts.Config.Spec.AccessEntries {
+		if upstreamPrincipals[entry.PrincipalArn] {
+			continue
+		}
+		if err := createAccessEntry(ctx, opts.EKSService, opts.Config, entry); err != nil {
+			return updated, err
+		}
+		updated = true
+	}
+
+	for _, principal := range opts.Config.Status.AccessEntries {
+		if principals[principal] || !upstreamPrincipals[principal] {
+			continue
+		}
+		logrus.Infof("deleting access entry [%s] for cluster [%s]", principal, opts.Config.Name)
+		_, err := opts.EKSService.DeleteAccessEntry(ctx, &eks.DeleteAccessEntryInput{
+			ClusterName:  aws.String(opts.Config.Spec.DisplayName),
+			PrincipalArn: aws.String(principal),
+		})
+		if err != nil && !notFoundInEKSError(err) {
+			return updated, fmt.Errorf("error deleting access entry [%s] for cluster [%s]: %w", principal, opts.Config.Name, err)
+		}
+		updated = true
+	}
+	opts.Config.Status.AccessEntries = getAccessEntryPrincipals(opts.Config.Spec.AccessEntries)
+
+	return updated, nil
+}
+
+// createAccessEntry creates the access entry and associates its access policies. An entry that already exists only
+// gets the policies associated, EKS replaces the scope of a policy already associated.
+func createAccessEntry(ctx context.Context, eksService services.EKSServiceInterface, config *eksv1.EKSClusterConfig, entry eksv1.AccessEntry) error {
+	logrus.Infof("creating access entry [%s] for cluster [%s]", entry.PrincipalArn, config.Name)
+	_, err := eksService.CreateAccessEntry(ctx, &eks.CreateAccessEntryInput{
+		ClusterName:      aws.String(config.Spec.DisplayName),
+		PrincipalArn:     aws.String(entry.PrincipalArn),
+		KubernetesGroups: aws.StringSlice(entry.KubernetesGroups),
+		Type:             entry.Type,
+		Tags:             getTags(GetNormalizedTags(config, config.Spec.Tags)),
+	})
+	if err != nil && !alreadyExistsInEKSError(err) {
+		return fmt.Errorf("error creating access entry [%s] for cluster [%s]: %w", entry.PrincipalArn, config.Name, err)
+	}
+
+	for _, policy := range entry.AccessPolicies {
+		accessScope := &eks.AccessScope{
+			Type: aws.String(policy.AccessScope.Type),
+		}
+		if len(policy.AccessScope.Namespaces) != 0 {
+			accessScope.Namespaces = aws.StringSlice(policy.AccessScope.Namespaces)
+		}
+		_, err := eksService.AssociateAccessPolicy(ctx, &eks.AssociateAccessPolicyInput{
+			ClusterName:  aws.String(config.Spec.DisplayName),
+			PrincipalArn: aws.String(entry.PrincipalArn),
+			PolicyArn:    aws.String(policy.PolicyArn),
+			AccessScope:  accessScope,
+		})
+		if err != nil {
+			return fmt.Errorf("error associating policy [%s] with access entry [%s] for cluster [%s]: %w",
+				policy.PolicyArn, entry.PrincipalArn, config.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// listAccessEntries returns the principal ARNs of all the access entries of the cluster.
+func listAccessEntries(ctx context.Context, eksService services.EKSServiceInterface, clusterName string) (map[string]bool, error) {
+	principals := make(map[string]bool)
+	input := &eks.ListAccessEntriesInput{
+		ClusterName: aws.String(clusterName),
+	}
+	for {
+		output, err := eksService.ListAccessEntries(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		for _, principal := range output.AccessEntries {
+			principals[aws.StringValue(principal)] = true
+		}
+		if output.NextToken == nil {
+			return principals, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+func getAccessEntryPrincipals(entries []eksv1.AccessEntry) []string {
+	principals := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		principals = append(principals, entry.PrincipalArn)
+	}
+	return principals
+}
+
+func validAccessEntryType(entryType string) bool {
+	for _, supportedType := range accessEntryTypes {
+		if entryType == supportedType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,243 @@
+package eks
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/pkg/eks/services/mock_services"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("ValidateAccessEntries", func() {
+	const principalARN = "arn:aws:iam::123456789012:role/admin"
+
+	DescribeTable("should validate the access entries",
+		func(authenticationMode string, entries []eksv1.AccessEntry, expectedError string) {
+			config := &eksv1.EKSClusterConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: eksv1.EKSClusterConfigSpec{
+					AccessEntries: entries,
+				},
+			}
+			if authenticationMode != "" {
+				config.Spec.AccessConfig = &eksv1.AccessConfig{AuthenticationMode: aws.String(authenticationMode)}
+			}
+
+			err := ValidateAccessEntries(config)
+			if expectedError == "" {
+				Expect(err).ToNot(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(ContainSubstring(expectedError)))
+			}
+		},
+		Entry("no entries", "CONFIG_MAP", nil, ""),
+		Entry("entry with groups", "API", []eksv1.AccessEntry{
+			{PrincipalArn: principalARN, KubernetesGroups: []string{"admins"}},
+		}, ""),
+		Entry("entry with policies", "API_AND_CONFIG_MAP", []eksv1.AccessEntry{
+			{PrincipalArn: principalARN, Type: aws.String("STANDARD"), AccessPolicies: []eksv1.AccessPolicy{
+				{
+					PolicyArn:   "arn:aws:eks::aws:cluster-access-policy/AmazonEKSClusterAdminPolicy",
+					AccessScope: eksv1.AccessScope{Type: "cluster"},
+				},
+				{
+					PolicyArn:   "arn:aws:eks::aws:cluster-access-policy/AmazonEKSEditPolicy",
+					AccessScope: eksv1.AccessScope{Type: "namespace", Namespaces: []string{"default"}},
+				},
+			}},
+		}, ""),
+		Entry("CONFIG_MAP authentication mode", "CONFIG_MAP", []eksv1.AccessEntry{
+			{PrincipalArn: principalARN},
+		}, "need authenticationMode [API_AND_CONFIG_MAP] or [API]"),
+		Entry("principal name", "API", []eksv1.AccessEntry{
+			{PrincipalArn: "admin"},
+		}, "principalArn [admin] of access entry for cluster [test] is not a valid ARN"),
+		Entry("duplicate principal", "API", []eksv1.AccessEntry{
+			{PrincipalArn: principalARN},
+			{PrincipalArn: principalARN},
+		}, "has more than one access entry"),
+		Entry("unknown type", "API", []eksv1.AccessEntry{
+			{PrincipalArn: principalARN, Type: aws.String("standard")},
+		}, "type [standard] of access entry"),
+		Entry("policy name", "API", []eksv1.AccessEntry{
+			{PrincipalArn: principalARN, AccessPolicies: []eksv1.AccessPolicy{
+				{PolicyArn: "AmazonEKSClusterAdminPolicy", AccessScope: eksv1.AccessScope{Type: "cluster"}},
+			}},
+		}, "policyArn [AmazonEKSClusterAdminPolicy]"),
+		Entry("cluster scope with namespaces", "API", []eksv1.AccessEntry{
+			{PrincipalArn: principalARN, AccessPolicies: []eksv1.AccessPolicy{
+				{
+					PolicyArn:   "arn:aws:eks::aws:cluster-access-policy/AmazonEKSEditPolicy",
+					AccessScope: eksv1.AccessScope{Type: "cluster", Namespaces: []string{"default"}},
+				},
+			}},
+		}, "cannot set namespaces for a cluster scope"),
+		Entry("namespace scope without namespaces", "API", []eksv1.AccessEntry{
+			{PrincipalArn: principalARN, AccessPolicies: []eksv1.AccessPolicy{
+				{
+					PolicyArn:   "arn:aws:eks::aws:cluster-access-policy/AmazonEKSEditPolicy",
+					AccessScope: eksv1.AccessScope{Type: "namespace"},
+				},
+			}},
+		}, "must set namespaces for a namespace scope"),
+		Entry("unknown scope", "API", []eksv1.AccessEntry{
+			{PrincipalArn: principalARN, AccessPolicies: []eksv1.AccessPolicy{
+				{PolicyArn: "arn:aws:eks::aws:cluster-access-policy/AmazonEKSEditPolicy"},
+			}},
+		}, "access scope type [] of policy"),
+	)
+})
+
+var _ = Describe("UpdateAccessEntries", func() {
+	const (
+		adminARN     = "arn:aws:iam::123456789012:role/admin"
+		developerARN = "arn:aws:iam::123456789012:role/developer"
+		nodeRoleARN  = "arn:aws:iam::123456789012:role/node"
+	)
+
+	var (
+		mockController *gomock.Controller
+		eksServiceMock *mock_services.MockEKSServiceInterface
+		updateOpts     *UpdateAccessEntriesOpts
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+		updateOpts = &UpdateAccessEntriesOpts{
+			EKSService: eksServiceMock,
+			Config: &eksv1.EKSClusterConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: eksv1.EKSClusterConfigSpec{
+					DisplayName: "test",
+					AccessEntries: []eksv1.AccessEntry{
+						{PrincipalArn: adminARN},
+					},
+				},
+				Status: eksv1.EKSClusterConfigStatus{
+					AccessEntries: []string{adminARN},
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should create the access entry of a new principal and associate its policies", func() {
+		updateOpts.Config.Spec.AccessEntries = append(updateOpts.Config.Spec.AccessEntries, eksv1.AccessEntry{
+			PrincipalArn:     developerARN,
+			KubernetesGroups: []string{"developers"},
+			AccessPolicies: []eksv1.AccessPolicy{
+				{
+					PolicyArn:   "arn:aws:eks::aws:cluster-access-policy/AmazonEKSEditPolicy",
+					AccessScope: eksv1.AccessScope{Type: "namespace", Namespaces: []string{"dev"}},
+				},
+			},
+		})
+		eksServiceMock.EXPECT().ListAccessEntries(gomock.Any(), &eks.ListAccessEntriesInput{
+			ClusterName: aws.String("test"),
+		}).Return(&eks.ListAccessEntriesOutput{
+			AccessEntries: aws.StringSlice([]string{adminARN, nodeRoleARN}),
+		}, nil)
+		eksServiceMock.EXPECT().CreateAccessEntry(gomock.Any(), &eks.CreateAccessEntryInput{
+			ClusterName:      aws.String("test"),
+			PrincipalArn:     aws.String(developerARN),
+			KubernetesGroups: aws.StringSlice([]string{"developers"}),
+		}).Return(&eks.CreateAccessEntryOutput{}, nil)
+		eksServiceMock.EXPECT().AssociateAccessPolicy(gomock.Any(), &eks.AssociateAccessPolicyInput{
+			ClusterName:  aws.String("test"),
+			PrincipalArn: aws.String(developerARN),
+			PolicyArn:    aws.String("arn:aws:eks::aws:cluster-access-policy/AmazonEKSEditPolicy"),
+			AccessScope: &eks.AccessScope{
+				Type:       aws.String("namespace"),
+				Namespaces: aws.StringSlice([]string{"dev"}),
+			},
+		}).Return(&eks.AssociateAccessPolicyOutput{}, nil)
+		eksServiceMock.EXPECT().DeleteAccessEntry(gomock.Any(), gomock.Any()).Times(0)
+
+		updated, err := UpdateAccessEntries(context.Background(), updateOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeTrue())
+		Expect(updateOpts.Config.Status.AccessEntries).To(Equal([]string{adminARN, developerARN}))
+	})
+
+	It("should delete the access entry of a principal removed from the spec", func() {
+		updateOpts.Config.Status.AccessEntries = []string{adminARN, developerARN}
+		eksServiceMock.EXPECT().ListAccessEntries(gomock.Any(), gomock.Any()).Return(&eks.ListAccessEntriesOutput{
+			AccessEntries: aws.StringSlice([]string{adminARN, developerARN, nodeRoleARN}),
+		}, nil)
+		eksServiceMock.EXPECT().CreateAccessEntry(gomock.Any(), gomock.Any()).Times(0)
+		eksServiceMock.EXPECT().DeleteAccessEntry(gomock.Any(), &eks.DeleteAccessEntryInput{
+			ClusterName:  aws.String("test"),
+			PrincipalArn: aws.String(developerARN),
+		}).Return(&eks.DeleteAccessEntryOutput{}, nil)
+
+		updated, err := UpdateAccessEntries(context.Background(), updateOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeTrue())
+		Expect(updateOpts.Config.Status.AccessEntries).To(Equal([]string{adminARN}))
+	})
+
+	It("should look for the access entries in all pages", func() {
+		eksServiceMock.EXPECT().ListAccessEntries(gomock.Any(), &eks.ListAccessEntriesInput{
+			ClusterName: aws.String("test"),
+		}).Return(&eks.ListAccessEntriesOutput{
+			AccessEntries: aws.StringSlice([]string{nodeRoleARN}),
+			NextToken:     aws.String("next"),
+		}, nil)
+		eksServiceMock.EXPECT().ListAccessEntries(gomock.Any(), &eks.ListAccessEntriesInput{
+			ClusterName: aws.String("test"),
+			NextToken:   aws.String("next"),
+		}).Return(&eks.ListAccessEntriesOutput{
+			AccessEntries: aws.StringSlice([]string{adminARN}),
+		}, nil)
+		eksServiceMock.EXPECT().CreateAccessEntry(gomock.Any(), gomock.Any()).Times(0)
+		eksServiceMock.EXPECT().DeleteAccessEntry(gomock.Any(), gomock.Any()).Times(0)
+
+		updated, err := UpdateAccessEntries(context.Background(), updateOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+
+	It("should fail if listing the access entries returns error", func() {
+		eksServiceMock.EXPECT().ListAccessEntries(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+		_, err := UpdateAccessEntries(context.Background(), updateOpts)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("CreateAccessEntries", func() {
+	It("should create the access entries and record their principals", func() {
+		mockController := gomock.NewController(GinkgoT())
+		defer mockController.Finish()
+		eksServiceMock := mock_services.NewMockEKSServiceInterface(mockController)
+		config := &eksv1.EKSClusterConfig{
+			Spec: eksv1.EKSClusterConfigSpec{
+				DisplayName: "test",
+				AccessEntries: []eksv1.AccessEntry{
+					{PrincipalArn: "arn:aws:iam::123456789012:role/admin"},
+				},
+			},
+		}
+		eksServiceMock.EXPECT().CreateAccessEntry(gomock.Any(), gomock.Any()).Return(&eks.CreateAccessEntryOutput{}, nil)
+
+		Expect(CreateAccessEntries(context.Background(), &CreateAccessEntriesOpts{
+			EKSService: eksServiceMock,
+			Config:     config,
+		})).To(Succeed())
+		Expect(config.Status.AccessEntries).To(Equal([]string{"arn:aws:iam::123456789012:role/admin"}))
+	})
+})
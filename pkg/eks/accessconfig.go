@@ -0,0 +1,107 @@
+package eks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eks"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/pkg/eks/services"
+)
+
+// authenticationModeOrder ranks the authentication modes in the only order EKS allows moving between them, one step
+// at a time from CONFIG_MAP through API_AND_CONFIG_MAP to API.
+var authenticationModeOrder = map[string]int{
+	eks.AuthenticationModeConfigMap:       0,
+	eks.AuthenticationModeApiAndConfigMap: 1,
+	eks.AuthenticationModeApi:             2,
+}
+
+// ValidateAccessConfig ensures the authentication mode of the cluster, if set, is one EKS supports.
+func ValidateAccessConfig(config *eksv1.EKSClusterConfig) error {
+	if config.Spec.AccessConfig == nil || config.Spec.AccessConfig.AuthenticationMode == nil {
+		return nil
+	}
+
+	mode := aws.StringValue(config.Spec.AccessConfig.AuthenticationMode)
+	if _, ok := authenticationModeOrder[mode]; !ok {
+		return fmt.Errorf("authenticationMode [%s] for cluster [%s] is not supported, must be one of [%s]",
+			mode, config.Name, strings.Join(eks.AuthenticationMode_Values(), ", "))
+	}
+
+	return nil
+}
+
+// validateAuthenticationModeTransition ensures EKS accepts moving the cluster from the upstream authentication mode
+// to the desired one.
+func validateAuthenticationModeTransition(upstreamMode, mode string) error {
+	switch rankDiff := authenticationModeOrder[mode] - authenticationModeOrder[upstreamMode]; {
+	case rankDiff < 0:
+		return fmt.Errorf("authentication mode cannot be changed back from [%s] to [%s]", upstreamMode, mode)
+	case rankDiff > 1:
+		return fmt.Errorf("authentication mode cannot be changed from [%s] to [%s] directly, change it to [%s] first",
+			upstreamMode, mode, eks.AuthenticationModeApiAndConfigMap)
+	}
+
+	return nil
+}
+
+// getCreateAccessConfig returns the access config to create the cluster with, nil to leave the EKS default.
+func getCreateAccessConfig(config *eksv1.EKSClusterConfig) *eks.CreateAccessConfigRequest {
+	if config.Spec.AccessConfig == nil || config.Spec.AccessConfig.AuthenticationMode == nil {
+		return nil
+	}
+
+	return &eks.CreateAccessConfigRequest{
+		AuthenticationMode: config.Spec.AccessConfig.AuthenticationMode,
+	}
+}
+
+type UpdateClusterAccessConfigOpts struct {
+	EKSService          services.EKSServiceInterface
+	Config              *eksv1.EKSClusterConfig
+	UpstreamClusterSpec *eksv1.EKSClusterConfigSpec
+}
+
+// UpdateClusterAccessConfig moves the cluster to the authentication mode of the config when it differs from the
+// upstream mode. Changes EKS would reject, going back towards CONFIG_MAP or skipping API_AND_CONFIG_MAP, are refused
+// before calling EKS.
+func UpdateClusterAccessConfig(ctx context.Context, opts *UpdateClusterAccessConfigOpts) (_ bool, err error) {
+	ctx, span := startSpan(ctx, "UpdateClusterAccessConfig", opts.Config.Spec.DisplayName)
+	defer func() { endSpan(span, err) }()
+
+	if opts.Config.Spec.AccessConfig == nil || opts.Config.Spec.AccessConfig.AuthenticationMode == nil {
+		return false, nil
+	}
+
+	mode := aws.StringValue(opts.Config.Spec.AccessConfig.AuthenticationMode)
+	// clusters created before access entries existed report no mode and use the aws-auth config map
+	upstreamMode := eks.AuthenticationModeConfigMap
+	if opts.UpstreamClusterSpec.AccessConfig != nil && opts.UpstreamClusterSpec.AccessConfig.AuthenticationMode != nil {
+		upstreamMode = aws.StringValue(opts.UpstreamClusterSpec.AccessConfig.AuthenticationMode)
+	}
+	if mode == upstreamMode {
+		return false, nil
+	}
+
+	if err := validateAuthenticationModeTransition(upstreamMode, mode); err != nil {
+		return false, fmt.Errorf("error updating cluster [%s] access config: %w", opts.Config.Name, err)
+	}
+
+	err = retryWithBackoff(ctx, defaultBackoff, func() error {
+		_, err := opts.EKSService.UpdateClusterConfig(ctx, &eks.UpdateClusterConfigInput{
+			Name: aws.String(opts.Config.Spec.DisplayName),
+			AccessConfig: &eks.UpdateAccessConfigRequest{
+				AuthenticationMode: aws.String(mode),
+			},
+		})
+		return AsUpdateConflict(err)
+	})
+	if err != nil {
+		return false, fmt.Errorf("error updating cluster [%s] access config: %w", opts.Config.Name, err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,139 @@
+package eks
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/pkg/eks/services/mock_services"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("ValidateAccessConfig", func() {
+	DescribeTable("should validate the authentication mode",
+		func(accessConfig *eksv1.AccessConfig, expectedError string) {
+			config := &eksv1.EKSClusterConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: eksv1.EKSClusterConfigSpec{
+					AccessConfig: accessConfig,
+				},
+			}
+
+			err := ValidateAccessConfig(config)
+			if expectedError == "" {
+				Expect(err).ToNot(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(ContainSubstring(expectedError)))
+			}
+		},
+		Entry("no access config", nil, ""),
+		Entry("no authentication mode", &eksv1.AccessConfig{}, ""),
+		Entry("CONFIG_MAP", &eksv1.AccessConfig{AuthenticationMode: aws.String("CONFIG_MAP")}, ""),
+		Entry("API_AND_CONFIG_MAP", &eksv1.AccessConfig{AuthenticationMode: aws.String("API_AND_CONFIG_MAP")}, ""),
+		Entry("API", &eksv1.AccessConfig{AuthenticationMode: aws.String("API")}, ""),
+		Entry("unknown mode", &eksv1.AccessConfig{AuthenticationMode: aws.String("api")},
+			"authenticationMode [api] for cluster [test] is not supported"),
+	)
+})
+
+var _ = Describe("UpdateClusterAccessConfig", func() {
+	var (
+		mockController *gomock.Controller
+		eksServiceMock *mock_services.MockEKSServiceInterface
+		updateOpts     *UpdateClusterAccessConfigOpts
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+		updateOpts = &UpdateClusterAccessConfigOpts{
+			EKSService: eksServiceMock,
+			Config: &eksv1.EKSClusterConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: eksv1.EKSClusterConfigSpec{
+					DisplayName: "test",
+				},
+			},
+			UpstreamClusterSpec: &eksv1.EKSClusterConfigSpec{},
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	setModes := func(upstreamMode, mode string) {
+		updateOpts.Config.Spec.AccessConfig = &eksv1.AccessConfig{AuthenticationMode: aws.String(mode)}
+		if upstreamMode != "" {
+			updateOpts.UpstreamClusterSpec.AccessConfig = &eksv1.AccessConfig{AuthenticationMode: aws.String(upstreamMode)}
+		}
+	}
+
+	DescribeTable("should update the authentication mode towards API",
+		func(upstreamMode, mode string) {
+			setModes(upstreamMode, mode)
+			eksServiceMock.EXPECT().UpdateClusterConfig(gomock.Any(), &eks.UpdateClusterConfigInput{
+				Name: aws.String("test"),
+				AccessConfig: &eks.UpdateAccessConfigRequest{
+					AuthenticationMode: aws.String(mode),
+				},
+			}).Return(&eks.UpdateClusterConfigOutput{}, nil)
+
+			updated, err := UpdateClusterAccessConfig(context.Background(), updateOpts)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(updated).To(BeTrue())
+		},
+		Entry("CONFIG_MAP to API_AND_CONFIG_MAP", "CONFIG_MAP", "API_AND_CONFIG_MAP"),
+		Entry("API_AND_CONFIG_MAP to API", "API_AND_CONFIG_MAP", "API"),
+		Entry("no upstream mode to API_AND_CONFIG_MAP", "", "API_AND_CONFIG_MAP"),
+	)
+
+	DescribeTable("should reject authentication mode changes EKS does not allow",
+		func(upstreamMode, mode, expectedError string) {
+			setModes(upstreamMode, mode)
+			eksServiceMock.EXPECT().UpdateClusterConfig(gomock.Any(), gomock.Any()).Times(0)
+
+			updated, err := UpdateClusterAccessConfig(context.Background(), updateOpts)
+			Expect(err).To(MatchError(ContainSubstring(expectedError)))
+			Expect(updated).To(BeFalse())
+		},
+		Entry("API to API_AND_CONFIG_MAP", "API", "API_AND_CONFIG_MAP", "cannot be changed back from [API] to [API_AND_CONFIG_MAP]"),
+		Entry("API to CONFIG_MAP", "API", "CONFIG_MAP", "cannot be changed back from [API] to [CONFIG_MAP]"),
+		Entry("API_AND_CONFIG_MAP to CONFIG_MAP", "API_AND_CONFIG_MAP", "CONFIG_MAP", "cannot be changed back from [API_AND_CONFIG_MAP] to [CONFIG_MAP]"),
+		Entry("CONFIG_MAP to API", "CONFIG_MAP", "API", "cannot be changed from [CONFIG_MAP] to [API] directly"),
+	)
+
+	It("should not update an unchanged authentication mode", func() {
+		setModes("API", "API")
+		eksServiceMock.EXPECT().UpdateClusterConfig(gomock.Any(), gomock.Any()).Times(0)
+
+		updated, err := UpdateClusterAccessConfig(context.Background(), updateOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+
+	It("should not update CONFIG_MAP on a cluster reporting no authentication mode", func() {
+		setModes("", "CONFIG_MAP")
+		eksServiceMock.EXPECT().UpdateClusterConfig(gomock.Any(), gomock.Any()).Times(0)
+
+		updated, err := UpdateClusterAccessConfig(context.Background(), updateOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+
+	It("should not update without an authentication mode", func() {
+		eksServiceMock.EXPECT().UpdateClusterConfig(gomock.Any(), gomock.Any()).Times(0)
+
+		updated, err := UpdateClusterAccessConfig(context.Background(), updateOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+})
@@ -0,0 +1,185 @@
+package eks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eks"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/pkg/eks/services"
+	"github.com/sirupsen/logrus"
+)
+
+type CreateAddonsOpts struct {
+	EKSService services.EKSServiceInterface
+	Config     *eksv1.EKSClusterConfig
+}
+
+// CreateAddons installs the add-ons of the cluster. Add-ons that are already installed are left as they are.
+func CreateAddons(ctx context.Context, opts *CreateAddonsOpts) (err error) {
+	ctx, span := startSpan(ctx, "CreateAddons", opts.Config.Spec.DisplayName)
+	defer func() { endSpan(span, err) }()
+
+	for _, addon := range opts.Config.Spec.Addons {
+		if err := createAddon(ctx, opts.EKSService, opts.Config, addon); err != nil && !alreadyExistsInEKSError(err) {
+			return fmt.Errorf("error creating addon [%s] for cluster [%s]: %w", addon.Name, opts.Config.Name, err)
+		}
+	}
+
+	return nil
+}
+
+type UpdateAddonsOpts struct {
+	EKSService services.EKSServiceInterface
+	Config     *eksv1.EKSClusterConfig
+}
+
+// UpdateAddons installs missing add-ons and updates installed add-ons whose version or service account role differs
+// from the desired one. An unset version or role leaves the installed one untouched.
+func UpdateAddons(ctx context.Context, opts *UpdateAddonsOpts) (_ bool, err error) {
+	ctx, span := startSpan(ctx, "UpdateAddons", opts.Config.Spec.DisplayName)
+	defer func() { endSpan(span, err) }()
+
+	updated := false
+	for _, addon := range opts.Config.Spec.Addons {
+		output, err := opts.EKSService.DescribeAddon(ctx, &eks.DescribeAddonInput{
+			AddonName:   aws.String(addon.Name),
+			ClusterName: aws.String(opts.Config.Spec.DisplayName),
+		})
+		if notFoundInEKSError(err) {
+			if err := createAddon(ctx, opts.EKSService, opts.Config, addon); err != nil {
+				return updated, fmt.Errorf("error creating addon [%s] for cluster [%s]: %w", addon.Name, opts.Config.Name, err)
+			}
+			updated = true
+			continue
+		}
+		if err != nil {
+			return updated, fmt.Errorf("error describing addon [%s] for cluster [%s]: %w", addon.Name, opts.Config.Name, err)
+		}
+
+		if status := aws.StringValue(output.Addon.Status); status == eks.AddonStatusCreating || status == eks.AddonStatusUpdating {
+			// the add-on must finish its current operation before it can be updated again
+			updated = true
+			continue
+		}
+
+		updateAddonInput := &eks.UpdateAddonInput{
+			AddonName:           aws.String(addon.Name),
+			ClusterName:         aws.String(opts.Config.Spec.DisplayName),
+			ConfigurationValues: addon.ConfigurationValues,
+		}
+		if addon.Version != nil && aws.StringValue(output.Addon.AddonVersion) != aws.StringValue(addon.Version) {
+			logrus.Infof("updating addon [%s] version for cluster [%s]", addon.Name, opts.Config.Name)
+			updateAddonInput.AddonVersion = addon.Version
+		}
+		if addon.ServiceAccountRoleArn != nil && aws.StringValue(output.Addon.ServiceAccountRoleArn) != aws.StringValue(addon.ServiceAccountRoleArn) {
+			logrus.Infof("updating addon [%s] service account role for cluster [%s]", addon.Name, opts.Config.Name)
+			updateAddonInput.ServiceAccountRoleArn = addon.ServiceAccountRoleArn
+		}
+		if updateAddonInput.AddonVersion == nil && updateAddonInput.ServiceAccountRoleArn == nil {
+			continue
+		}
+
+		_, err = opts.EKSService.UpdateAddon(ctx, updateAddonInput)
+		if err != nil && !alreadyExistsInEKSError(err) {
+			return updated, fmt.Errorf("error updating addon [%s] for cluster [%s]: %w", addon.Name, opts.Config.Name, err)
+		}
+		// an add-on still busy with an update started since it was described is retried on the next reconcile
+		updated = true
+	}
+
+	return updated, nil
+}
+
+// validateAddonsCompatibility makes sure every add-on of the cluster runs a version that supports the kubernetes
+// version the cluster is upgraded to. Add-ons are only updated once the cluster upgrade is done, so the version set on
+// an add-on is checked, or the installed version if none is set. Add-ons that are not compatible must be given a
+// version supporting the target kubernetes version before the cluster is upgraded.
+func validateAddonsCompatibility(ctx context.Context, eksService services.EKSServiceInterface, config *eksv1.EKSClusterConfig, kubernetesVersion string) error {
+	var incompatible []string
+	for _, addon := range config.Spec.Addons {
+		version := aws.StringValue(addon.Version)
+		if version == "" {
+			output, err := eksService.DescribeAddon(ctx, &eks.DescribeAddonInput{
+				AddonName:   aws.String(addon.Name),
+				ClusterName: aws.String(config.Spec.DisplayName),
+			})
+			if notFoundInEKSError(err) {
+				// the add-on is installed with the default version of the upgraded cluster
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("error describing addon [%s] for cluster [%s]: %w", addon.Name, config.Name, err)
+			}
+			version = aws.StringValue(output.Addon.AddonVersion)
+		}
+
+		versions, defaultVersion, err := getAddonVersions(ctx, eksService, addon.Name, kubernetesVersion)
+		if err != nil {
+			return fmt.Errorf("error describing versions of addon [%s] for cluster [%s]: %w", addon.Name, config.Name, err)
+		}
+		if versions[version] {
+			continue
+		}
+		switch {
+		case len(versions) == 0:
+			incompatible = append(incompatible, fmt.Sprintf("%s [%s] has no compatible version", addon.Name, version))
+		case defaultVersion == "":
+			incompatible = append(incompatible, fmt.Sprintf("%s [%s] must be updated to a compatible version", addon.Name, version))
+		default:
+			incompatible = append(incompatible, fmt.Sprintf("%s [%s] must be updated, default compatible version is [%s]", addon.Name, version, defaultVersion))
+		}
+	}
+
+	if len(incompatible) != 0 {
+		return fmt.Errorf("addons of cluster [%s] are not compatible with kubernetes version [%s]: %s",
+			config.Name, kubernetesVersion, strings.Join(incompatible, "; "))
+	}
+	return nil
+}
+
+// getAddonVersions returns the versions of an add-on that support the kubernetes version, and the one EKS installs
+// by default.
+func getAddonVersions(ctx context.Context, eksService services.EKSServiceInterface, addonName, kubernetesVersion string) (map[string]bool, string, error) {
+	versions := make(map[string]bool)
+	defaultVersion := ""
+	input := &eks.DescribeAddonVersionsInput{
+		AddonName:         aws.String(addonName),
+		KubernetesVersion: aws.String(kubernetesVersion),
+	}
+	for {
+		output, err := eksService.DescribeAddonVersions(ctx, input)
+		if err != nil {
+			return nil, "", err
+		}
+		for _, addon := range output.Addons {
+			for _, version := range addon.AddonVersions {
+				versions[aws.StringValue(version.AddonVersion)] = true
+				for _, compatibility := range version.Compatibilities {
+					if aws.BoolValue(compatibility.DefaultVersion) {
+						defaultVersion = aws.StringValue(version.AddonVersion)
+					}
+				}
+			}
+		}
+		if output.NextToken == nil {
+			return versions, defaultVersion, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+func createAddon(ctx context.Context, eksService services.EKSServiceInterface, config *eksv1.EKSClusterConfig, addon eksv1.Addon) error {
+	logrus.Infof("creating addon [%s] for cluster [%s]", addon.Name, config.Name)
+	_, err := eksService.CreateAddon(ctx, &eks.CreateAddonInput{
+		AddonName:             aws.String(addon.Name),
+		AddonVersion:          addon.Version,
+		ClusterName:           aws.String(config.Spec.DisplayName),
+		ConfigurationValues:   addon.ConfigurationValues,
+		ServiceAccountRoleArn: addon.ServiceAccountRoleArn,
+		Tags:                  getTags(GetNormalizedTags(config, config.Spec.Tags)),
+	})
+	return err
+}
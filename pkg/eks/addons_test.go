@@ -0,0 +1,310 @@
+package eks
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/pkg/eks/services/mock_services"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("CreateAddons", func() {
+	var (
+		mockController      *gomock.Controller
+		eksServiceMock      *mock_services.MockEKSServiceInterface
+		createAddonsOptions *CreateAddonsOpts
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+		createAddonsOptions = &CreateAddonsOpts{
+			EKSService: eksServiceMock,
+			Config: &eksv1.EKSClusterConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-cluster",
+				},
+				Spec: eksv1.EKSClusterConfigSpec{
+					DisplayName: "test-cluster",
+					Addons: []eksv1.Addon{
+						{
+							Name:                  "vpc-cni",
+							Version:               aws.String("v1.12.6-eksbuild.2"),
+							ServiceAccountRoleArn: aws.String("arn:aws:iam::123456789012:role/test"),
+							ConfigurationValues:   aws.String(`{"env":{"ENABLE_PREFIX_DELEGATION":"true"}}`),
+						},
+					},
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should create addons", func() {
+		eksServiceMock.EXPECT().CreateAddon(
+			gomock.Any(),
+			&eks.CreateAddonInput{
+				AddonName:             aws.String("vpc-cni"),
+				AddonVersion:          aws.String("v1.12.6-eksbuild.2"),
+				ClusterName:           aws.String("test-cluster"),
+				ServiceAccountRoleArn: aws.String("arn:aws:iam::123456789012:role/test"),
+				ConfigurationValues:   aws.String(`{"env":{"ENABLE_PREFIX_DELEGATION":"true"}}`),
+			},
+		).Return(nil, nil)
+		Expect(CreateAddons(context.Background(), createAddonsOptions)).To(Succeed())
+	})
+
+	It("should not fail if addon already exists", func() {
+		eksServiceMock.EXPECT().CreateAddon(gomock.Any(), gomock.Any()).Return(nil, awserr.New(eks.ErrCodeResourceInUseException, "already exists", nil))
+		Expect(CreateAddons(context.Background(), createAddonsOptions)).To(Succeed())
+	})
+
+	It("should return error if create addon failed", func() {
+		eksServiceMock.EXPECT().CreateAddon(gomock.Any(), gomock.Any()).Return(nil, errors.New("error creating addon"))
+		Expect(CreateAddons(context.Background(), createAddonsOptions)).ToNot(Succeed())
+	})
+})
+
+var _ = Describe("UpdateAddons", func() {
+	var (
+		mockController      *gomock.Controller
+		eksServiceMock      *mock_services.MockEKSServiceInterface
+		updateAddonsOptions *UpdateAddonsOpts
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+		updateAddonsOptions = &UpdateAddonsOpts{
+			EKSService: eksServiceMock,
+			Config: &eksv1.EKSClusterConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-cluster",
+				},
+				Spec: eksv1.EKSClusterConfigSpec{
+					DisplayName: "test-cluster",
+					Addons: []eksv1.Addon{
+						{
+							Name:    "coredns",
+							Version: aws.String("v1.9.3-eksbuild.3"),
+						},
+					},
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should update addon version", func() {
+		eksServiceMock.EXPECT().DescribeAddon(
+			gomock.Any(),
+			&eks.DescribeAddonInput{
+				AddonName:   aws.String("coredns"),
+				ClusterName: aws.String("test-cluster"),
+			},
+		).Return(&eks.DescribeAddonOutput{
+			Addon: &eks.Addon{AddonVersion: aws.String("v1.8.7-eksbuild.4")},
+		}, nil)
+		eksServiceMock.EXPECT().UpdateAddon(
+			gomock.Any(),
+			&eks.UpdateAddonInput{
+				AddonName:    aws.String("coredns"),
+				AddonVersion: aws.String("v1.9.3-eksbuild.3"),
+				ClusterName:  aws.String("test-cluster"),
+			},
+		).Return(nil, nil)
+		updated, err := UpdateAddons(context.Background(), updateAddonsOptions)
+		Expect(updated).To(BeTrue())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should update addon service account role", func() {
+		updateAddonsOptions.Config.Spec.Addons[0].ServiceAccountRoleArn = aws.String("arn:aws:iam::123456789012:role/new-role")
+		eksServiceMock.EXPECT().DescribeAddon(gomock.Any(), gomock.Any()).Return(&eks.DescribeAddonOutput{
+			Addon: &eks.Addon{
+				AddonVersion:          aws.String("v1.9.3-eksbuild.3"),
+				ServiceAccountRoleArn: aws.String("arn:aws:iam::123456789012:role/old-role"),
+			},
+		}, nil)
+		eksServiceMock.EXPECT().UpdateAddon(
+			gomock.Any(),
+			&eks.UpdateAddonInput{
+				AddonName:             aws.String("coredns"),
+				ClusterName:           aws.String("test-cluster"),
+				ServiceAccountRoleArn: aws.String("arn:aws:iam::123456789012:role/new-role"),
+			},
+		).Return(nil, nil)
+		updated, err := UpdateAddons(context.Background(), updateAddonsOptions)
+		Expect(updated).To(BeTrue())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should wait for addon if an update started in the meantime", func() {
+		updateAddonsOptions.Config.Spec.Addons[0].ServiceAccountRoleArn = aws.String("arn:aws:iam::123456789012:role/new-role")
+		eksServiceMock.EXPECT().DescribeAddon(gomock.Any(), gomock.Any()).Return(&eks.DescribeAddonOutput{
+			Addon: &eks.Addon{AddonVersion: aws.String("v1.9.3-eksbuild.3")},
+		}, nil)
+		eksServiceMock.EXPECT().UpdateAddon(gomock.Any(), gomock.Any()).Return(nil, awserr.New(eks.ErrCodeResourceInUseException, "update in progress", nil))
+		updated, err := UpdateAddons(context.Background(), updateAddonsOptions)
+		Expect(updated).To(BeTrue())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should not update addon version if version didn't change", func() {
+		eksServiceMock.EXPECT().DescribeAddon(gomock.Any(), gomock.Any()).Return(&eks.DescribeAddonOutput{
+			Addon: &eks.Addon{AddonVersion: aws.String("v1.9.3-eksbuild.3")},
+		}, nil)
+		updated, err := UpdateAddons(context.Background(), updateAddonsOptions)
+		Expect(updated).To(BeFalse())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should wait for addon to finish updating", func() {
+		eksServiceMock.EXPECT().DescribeAddon(gomock.Any(), gomock.Any()).Return(&eks.DescribeAddonOutput{
+			Addon: &eks.Addon{
+				AddonVersion: aws.String("v1.8.7-eksbuild.4"),
+				Status:       aws.String(eks.AddonStatusUpdating),
+			},
+		}, nil)
+		updated, err := UpdateAddons(context.Background(), updateAddonsOptions)
+		Expect(updated).To(BeTrue())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should create addon if it is not installed", func() {
+		eksServiceMock.EXPECT().DescribeAddon(gomock.Any(), gomock.Any()).Return(nil, awserr.New(eks.ErrCodeResourceNotFoundException, "not found", nil))
+		eksServiceMock.EXPECT().CreateAddon(gomock.Any(), gomock.Any()).Return(nil, nil)
+		updated, err := UpdateAddons(context.Background(), updateAddonsOptions)
+		Expect(updated).To(BeTrue())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should return error if update addon failed", func() {
+		eksServiceMock.EXPECT().DescribeAddon(gomock.Any(), gomock.Any()).Return(&eks.DescribeAddonOutput{
+			Addon: &eks.Addon{AddonVersion: aws.String("v1.8.7-eksbuild.4")},
+		}, nil)
+		eksServiceMock.EXPECT().UpdateAddon(gomock.Any(), gomock.Any()).Return(nil, errors.New("error updating addon"))
+		updated, err := UpdateAddons(context.Background(), updateAddonsOptions)
+		Expect(updated).To(BeFalse())
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("validateAddonsCompatibility", func() {
+	var (
+		mockController *gomock.Controller
+		eksServiceMock *mock_services.MockEKSServiceInterface
+		config         *eksv1.EKSClusterConfig
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+		config = &eksv1.EKSClusterConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test-cluster",
+			},
+			Spec: eksv1.EKSClusterConfigSpec{
+				DisplayName: "test-cluster",
+				Addons: []eksv1.Addon{
+					{
+						Name: "vpc-cni",
+					},
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	expectAddonVersions := func(versions ...string) {
+		addonVersions := []*eks.AddonVersionInfo{}
+		for i, version := range versions {
+			addonVersions = append(addonVersions, &eks.AddonVersionInfo{
+				AddonVersion: aws.String(version),
+				Compatibilities: []*eks.Compatibility{
+					{ClusterVersion: aws.String("1.27"), DefaultVersion: aws.Bool(i == 0)},
+				},
+			})
+		}
+		eksServiceMock.EXPECT().DescribeAddonVersions(gomock.Any(), &eks.DescribeAddonVersionsInput{
+			AddonName:         aws.String("vpc-cni"),
+			KubernetesVersion: aws.String("1.27"),
+		}).Return(&eks.DescribeAddonVersionsOutput{
+			Addons: []*eks.AddonInfo{
+				{
+					AddonName:     aws.String("vpc-cni"),
+					AddonVersions: addonVersions,
+				},
+			},
+		}, nil)
+	}
+
+	expectInstalledVersion := func(version string) {
+		eksServiceMock.EXPECT().DescribeAddon(gomock.Any(), &eks.DescribeAddonInput{
+			AddonName:   aws.String("vpc-cni"),
+			ClusterName: aws.String("test-cluster"),
+		}).Return(&eks.DescribeAddonOutput{
+			Addon: &eks.Addon{AddonVersion: aws.String(version)},
+		}, nil)
+	}
+
+	It("should accept an installed version compatible with the kubernetes version", func() {
+		expectInstalledVersion("v1.12.6-eksbuild.2")
+		expectAddonVersions("v1.12.6-eksbuild.2", "v1.13.2-eksbuild.1")
+
+		Expect(validateAddonsCompatibility(context.Background(), eksServiceMock, config, "1.27")).To(Succeed())
+	})
+
+	It("should report an installed version incompatible with the kubernetes version", func() {
+		expectInstalledVersion("v1.10.4-eksbuild.1")
+		expectAddonVersions("v1.12.6-eksbuild.2", "v1.13.2-eksbuild.1")
+
+		err := validateAddonsCompatibility(context.Background(), eksServiceMock, config, "1.27")
+		Expect(err).To(MatchError("addons of cluster [test-cluster] are not compatible with kubernetes version [1.27]: " +
+			"vpc-cni [v1.10.4-eksbuild.1] must be updated, default compatible version is [v1.12.6-eksbuild.2]"))
+	})
+
+	It("should check the version set on the add-on instead of the installed one", func() {
+		config.Spec.Addons[0].Version = aws.String("v1.13.2-eksbuild.1")
+		expectAddonVersions("v1.12.6-eksbuild.2", "v1.13.2-eksbuild.1")
+
+		Expect(validateAddonsCompatibility(context.Background(), eksServiceMock, config, "1.27")).To(Succeed())
+	})
+
+	It("should skip add-ons that are not installed yet", func() {
+		eksServiceMock.EXPECT().DescribeAddon(gomock.Any(), gomock.Any()).Return(nil, awserr.New(eks.ErrCodeResourceNotFoundException, "", nil))
+
+		Expect(validateAddonsCompatibility(context.Background(), eksServiceMock, config, "1.27")).To(Succeed())
+	})
+
+	It("should report add-ons without any compatible version", func() {
+		expectInstalledVersion("v1.10.4-eksbuild.1")
+		expectAddonVersions()
+
+		err := validateAddonsCompatibility(context.Background(), eksServiceMock, config, "1.27")
+		Expect(err).To(MatchError(ContainSubstring("vpc-cni [v1.10.4-eksbuild.1] has no compatible version")))
+	})
+
+	It("should fail if describing the add-on versions fails", func() {
+		expectInstalledVersion("v1.12.6-eksbuild.2")
+		eksServiceMock.EXPECT().DescribeAddonVersions(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+		Expect(validateAddonsCompatibility(context.Background(), eksServiceMock, config, "1.27")).ToNot(Succeed())
+	})
+})
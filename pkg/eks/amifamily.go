@@ -0,0 +1,135 @@
+package eks
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eks"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+)
+
+const (
+	// AMIFamilyAmazonLinux2 is the default AMI family of node groups.
+	AMIFamilyAmazonLinux2 = "AmazonLinux2"
+	// AMIFamilyBottlerocket runs the nodes on Bottlerocket, which is configured through TOML user data.
+	AMIFamilyBottlerocket = "Bottlerocket"
+	// The Windows AMI families run x86_64 nodes only and take PowerShell user data.
+	AMIFamilyWindowsCore2019 = "WindowsCore2019"
+	AMIFamilyWindowsFull2019 = "WindowsFull2019"
+	AMIFamilyWindowsCore2022 = "WindowsCore2022"
+	AMIFamilyWindowsFull2022 = "WindowsFull2022"
+
+	// bottlerocketDataDeviceName is the volume Bottlerocket stores container images and pod data on, its root volume
+	// only holds the read-only OS image.
+	bottlerocketDataDeviceName = "/dev/xvdb"
+	// windowsRootDeviceName is the root volume of the EKS optimized Windows AMIs.
+	windowsRootDeviceName = "/dev/sda1"
+)
+
+// amiFamilies are the AMI families node groups can be created with.
+var amiFamilies = []string{
+	AMIFamilyAmazonLinux2,
+	AMIFamilyBottlerocket,
+	AMIFamilyWindowsCore2019,
+	AMIFamilyWindowsFull2019,
+	AMIFamilyWindowsCore2022,
+	AMIFamilyWindowsFull2022,
+}
+
+// nodegroupAMIType is the AMI family and architecture of an EKS AMI type.
+type nodegroupAMIType struct {
+	family string
+	gpu    bool
+	arm    bool
+}
+
+// nodegroupAMITypes are the AMI types node groups that do not use a custom image are created with. There is no
+// Amazon Linux 2 GPU AMI for ARM instances.
+var nodegroupAMITypes = map[string]nodegroupAMIType{
+	eks.AMITypesAl2X8664:                {family: AMIFamilyAmazonLinux2},
+	eks.AMITypesAl2X8664Gpu:             {family: AMIFamilyAmazonLinux2, gpu: true},
+	eks.AMITypesAl2Arm64:                {family: AMIFamilyAmazonLinux2, arm: true},
+	eks.AMITypesBottlerocketX8664:       {family: AMIFamilyBottlerocket},
+	eks.AMITypesBottlerocketX8664Nvidia: {family: AMIFamilyBottlerocket, gpu: true},
+	eks.AMITypesBottlerocketArm64:       {family: AMIFamilyBottlerocket, arm: true},
+	eks.AMITypesBottlerocketArm64Nvidia: {family: AMIFamilyBottlerocket, gpu: true, arm: true},
+	eks.AMITypesWindowsCore2019X8664:    {family: AMIFamilyWindowsCore2019},
+	eks.AMITypesWindowsFull2019X8664:    {family: AMIFamilyWindowsFull2019},
+	eks.AMITypesWindowsCore2022X8664:    {family: AMIFamilyWindowsCore2022},
+	eks.AMITypesWindowsFull2022X8664:    {family: AMIFamilyWindowsFull2022},
+}
+
+// GetAMITypeSettings returns the AMI family and the gpu and arm flags matching an EKS AMI type. It returns false for
+// custom AMIs and AMI types the operator does not create node groups with.
+func GetAMITypeSettings(amiType string) (family string, gpu, arm, ok bool) {
+	settings, ok := nodegroupAMITypes[amiType]
+	return settings.family, settings.gpu, settings.arm, ok
+}
+
+// getNodegroupAMIFamily returns the AMI family of the node group, defaulting to Amazon Linux 2.
+func getNodegroupAMIFamily(group *eksv1.NodeGroup) string {
+	if family := aws.StringValue(group.AMIFamily); family != "" {
+		return family
+	}
+	return AMIFamilyAmazonLinux2
+}
+
+// getNodegroupAMIType returns the EKS AMI type matching the AMI family and the gpu and arm flags of a node group
+// that does not use a custom image.
+func getNodegroupAMIType(group *eksv1.NodeGroup) (string, error) {
+	wanted := nodegroupAMIType{
+		family: getNodegroupAMIFamily(group),
+		gpu:    aws.BoolValue(group.Gpu),
+		arm:    aws.BoolValue(group.Arm),
+	}
+	if !isKnownAMIFamily(wanted.family) {
+		return "", fmt.Errorf("amiFamily [%s] must be one of [%s]", wanted.family, strings.Join(amiFamilies, ", "))
+	}
+	for amiType, settings := range nodegroupAMITypes {
+		if settings == wanted {
+			return amiType, nil
+		}
+	}
+	switch {
+	case wanted.gpu && wanted.arm:
+		return "", fmt.Errorf("gpu and arm cannot both be true for amiFamily [%s]", wanted.family)
+	case wanted.gpu:
+		return "", fmt.Errorf("gpu is not supported for amiFamily [%s]", wanted.family)
+	default:
+		return "", fmt.Errorf("arm is not supported for amiFamily [%s]", wanted.family)
+	}
+}
+
+func isKnownAMIFamily(family string) bool {
+	for _, known := range amiFamilies {
+		if family == known {
+			return true
+		}
+	}
+	return false
+}
+
+func isWindowsAMIFamily(family string) bool {
+	return strings.HasPrefix(family, "Windows")
+}
+
+// usesMultipartUserData returns whether the user data of the node group AMI family is a multipart/mixed MIME
+// document, which EKS merges with its own bootstrap user data. Bottlerocket takes TOML settings and Windows takes
+// PowerShell scripts instead.
+func usesMultipartUserData(group *eksv1.NodeGroup) bool {
+	return getNodegroupAMIFamily(group) == AMIFamilyAmazonLinux2
+}
+
+// getStorageDeviceName returns the device the disk settings of a node group apply to when it does not use a custom
+// image.
+func getStorageDeviceName(group *eksv1.NodeGroup) string {
+	family := getNodegroupAMIFamily(group)
+	switch {
+	case family == AMIFamilyBottlerocket:
+		return bottlerocketDataDeviceName
+	case isWindowsAMIFamily(family):
+		return windowsRootDeviceName
+	}
+	return defaultStorageDeviceName
+}
@@ -0,0 +1,58 @@
+package eks
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eks"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+)
+
+var _ = Describe("getNodegroupAMIType", func() {
+	DescribeTable("should select the AMI type of the AMI family and architecture",
+		func(family *string, gpu, arm bool, expectedAMIType, expectedErr string) {
+			amiType, err := getNodegroupAMIType(&eksv1.NodeGroup{
+				AMIFamily: family,
+				Gpu:       aws.Bool(gpu),
+				Arm:       aws.Bool(arm),
+			})
+			if expectedErr != "" {
+				Expect(err).To(MatchError(ContainSubstring(expectedErr)))
+				return
+			}
+			Expect(err).ToNot(HaveOccurred())
+			Expect(amiType).To(Equal(expectedAMIType))
+		},
+		Entry("default family", nil, false, false, eks.AMITypesAl2X8664, ""),
+		Entry("default family with gpu", nil, true, false, eks.AMITypesAl2X8664Gpu, ""),
+		Entry("default family with arm", nil, false, true, eks.AMITypesAl2Arm64, ""),
+		Entry("amazon linux 2", aws.String(AMIFamilyAmazonLinux2), false, false, eks.AMITypesAl2X8664, ""),
+		Entry("amazon linux 2 with gpu and arm", aws.String(AMIFamilyAmazonLinux2), true, true, "", "gpu and arm cannot both be true"),
+		Entry("bottlerocket", aws.String(AMIFamilyBottlerocket), false, false, eks.AMITypesBottlerocketX8664, ""),
+		Entry("bottlerocket with gpu", aws.String(AMIFamilyBottlerocket), true, false, eks.AMITypesBottlerocketX8664Nvidia, ""),
+		Entry("bottlerocket with arm", aws.String(AMIFamilyBottlerocket), false, true, eks.AMITypesBottlerocketArm64, ""),
+		Entry("bottlerocket with gpu and arm", aws.String(AMIFamilyBottlerocket), true, true, eks.AMITypesBottlerocketArm64Nvidia, ""),
+		Entry("windows core 2019", aws.String(AMIFamilyWindowsCore2019), false, false, eks.AMITypesWindowsCore2019X8664, ""),
+		Entry("windows full 2019", aws.String(AMIFamilyWindowsFull2019), false, false, eks.AMITypesWindowsFull2019X8664, ""),
+		Entry("windows core 2022", aws.String(AMIFamilyWindowsCore2022), false, false, eks.AMITypesWindowsCore2022X8664, ""),
+		Entry("windows full 2022", aws.String(AMIFamilyWindowsFull2022), false, false, eks.AMITypesWindowsFull2022X8664, ""),
+		Entry("windows with gpu", aws.String(AMIFamilyWindowsFull2022), true, false, "", "gpu is not supported for amiFamily [WindowsFull2022]"),
+		Entry("windows with arm", aws.String(AMIFamilyWindowsCore2019), false, true, "", "arm is not supported for amiFamily [WindowsCore2019]"),
+		Entry("unknown family", aws.String("Ubuntu"), false, false, "", "amiFamily [Ubuntu] must be one of"),
+	)
+})
+
+var _ = Describe("GetAMITypeSettings", func() {
+	It("should return the family and architecture of an AMI type", func() {
+		family, gpu, arm, ok := GetAMITypeSettings(eks.AMITypesBottlerocketArm64Nvidia)
+		Expect(ok).To(BeTrue())
+		Expect(family).To(Equal(AMIFamilyBottlerocket))
+		Expect(gpu).To(BeTrue())
+		Expect(arm).To(BeTrue())
+	})
+
+	It("should not return settings for custom AMIs", func() {
+		_, _, _, ok := GetAMITypeSettings(eks.AMITypesCustom)
+		Expect(ok).To(BeFalse())
+	})
+})
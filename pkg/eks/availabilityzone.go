@@ -0,0 +1,57 @@
+package eks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/pkg/eks/services"
+)
+
+// getAvailabilityZoneSubnets returns the subnets a node group placed in a single availability zone is launched in.
+// Subnets given on the node group must all be in that zone, subnets inherited from the cluster are narrowed down to
+// the ones in that zone.
+//
+// Pinning a node group to one zone keeps its nodes next to the EBS volumes of stateful workloads, which cannot be
+// attached across zones. The node group loses its capacity altogether when that zone is impaired, so workloads that
+// need to stay available should be spread over node groups in different zones instead.
+func getAvailabilityZoneSubnets(ctx context.Context, ec2Service services.EC2ServiceInterface, ng *eksv1.NodeGroup, clusterSubnets []string) ([]string, error) {
+	subnets := ng.Subnets
+	if len(subnets) == 0 {
+		subnets = clusterSubnets
+	}
+
+	zone := aws.StringValue(ng.AvailabilityZone)
+	if zone == "" {
+		return subnets, nil
+	}
+
+	ngName := aws.StringValue(ng.NodegroupName)
+	output, err := ec2Service.DescribeSubnets(ctx, &ec2.DescribeSubnetsInput{
+		SubnetIds: aws.StringSlice(subnets),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error describing subnets of nodegroup [%s]: %w", ngName, err)
+	}
+
+	var zoneSubnets, otherSubnets []string
+	for _, subnet := range output.Subnets {
+		if aws.StringValue(subnet.AvailabilityZone) == zone {
+			zoneSubnets = append(zoneSubnets, aws.StringValue(subnet.SubnetId))
+		} else {
+			otherSubnets = append(otherSubnets, aws.StringValue(subnet.SubnetId))
+		}
+	}
+
+	if len(ng.Subnets) != 0 && len(otherSubnets) != 0 {
+		return nil, fmt.Errorf("nodegroup [%s]: subnets [%s] are not in availability zone [%s]", ngName, strings.Join(otherSubnets, ", "), zone)
+	}
+	if len(zoneSubnets) == 0 {
+		return nil, fmt.Errorf("nodegroup [%s]: none of the subnets [%s] is in availability zone [%s]", ngName, strings.Join(subnets, ", "), zone)
+	}
+
+	return zoneSubnets, nil
+}
@@ -0,0 +1,94 @@
+package eks
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/pkg/eks/services/mock_services"
+)
+
+var _ = Describe("getAvailabilityZoneSubnets", func() {
+	var (
+		mockController *gomock.Controller
+		ec2ServiceMock *mock_services.MockEC2ServiceInterface
+		nodeGroup      *eksv1.NodeGroup
+		clusterSubnets []string
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		ec2ServiceMock = mock_services.NewMockEC2ServiceInterface(mockController)
+		nodeGroup = &eksv1.NodeGroup{
+			NodegroupName:    aws.String("test"),
+			AvailabilityZone: aws.String("us-west-2a"),
+		}
+		clusterSubnets = []string{"subnet-a", "subnet-b"}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	describeSubnets := func(subnetIDs []string, subnets ...*ec2.Subnet) {
+		ec2ServiceMock.EXPECT().DescribeSubnets(gomock.Any(), &ec2.DescribeSubnetsInput{
+			SubnetIds: aws.StringSlice(subnetIDs),
+		}).Return(&ec2.DescribeSubnetsOutput{Subnets: subnets}, nil)
+	}
+
+	subnet := func(id, zone string) *ec2.Subnet {
+		return &ec2.Subnet{SubnetId: aws.String(id), AvailabilityZone: aws.String(zone)}
+	}
+
+	It("should return the subnets unchanged if no availability zone is set", func() {
+		nodeGroup.AvailabilityZone = nil
+
+		subnets, err := getAvailabilityZoneSubnets(context.Background(), ec2ServiceMock, nodeGroup, clusterSubnets)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(subnets).To(Equal(clusterSubnets))
+	})
+
+	It("should narrow the cluster subnets down to the availability zone", func() {
+		describeSubnets(clusterSubnets, subnet("subnet-a", "us-west-2a"), subnet("subnet-b", "us-west-2b"))
+
+		subnets, err := getAvailabilityZoneSubnets(context.Background(), ec2ServiceMock, nodeGroup, clusterSubnets)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(subnets).To(Equal([]string{"subnet-a"}))
+	})
+
+	It("should accept node group subnets in the availability zone", func() {
+		nodeGroup.Subnets = []string{"subnet-c"}
+		describeSubnets(nodeGroup.Subnets, subnet("subnet-c", "us-west-2a"))
+
+		subnets, err := getAvailabilityZoneSubnets(context.Background(), ec2ServiceMock, nodeGroup, clusterSubnets)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(subnets).To(Equal([]string{"subnet-c"}))
+	})
+
+	It("should reject node group subnets outside of the availability zone", func() {
+		nodeGroup.Subnets = []string{"subnet-a", "subnet-b"}
+		describeSubnets(nodeGroup.Subnets, subnet("subnet-a", "us-west-2a"), subnet("subnet-b", "us-west-2b"))
+
+		_, err := getAvailabilityZoneSubnets(context.Background(), ec2ServiceMock, nodeGroup, clusterSubnets)
+		Expect(err).To(MatchError(ContainSubstring("subnets [subnet-b] are not in availability zone [us-west-2a]")))
+	})
+
+	It("should fail if no cluster subnet is in the availability zone", func() {
+		describeSubnets(clusterSubnets, subnet("subnet-a", "us-west-2b"), subnet("subnet-b", "us-west-2c"))
+
+		_, err := getAvailabilityZoneSubnets(context.Background(), ec2ServiceMock, nodeGroup, clusterSubnets)
+		Expect(err).To(MatchError(ContainSubstring("none of the subnets [subnet-a, subnet-b] is in availability zone [us-west-2a]")))
+	})
+
+	It("should fail if describing the subnets fails", func() {
+		ec2ServiceMock.EXPECT().DescribeSubnets(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+		_, err := getAvailabilityZoneSubnets(context.Background(), ec2ServiceMock, nodeGroup, clusterSubnets)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,45 @@
+package eks
+
+import (
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/eks"
+)
+
+// ErrUpdateInProgress is matched by errors returned when EKS rejects an update because another update of the cluster
+// or node group is still in progress. The update can be retried once the running one completes.
+var ErrUpdateInProgress = errors.New("another update is in progress")
+
+// updateConflictError keeps the original AWS error while matching ErrUpdateInProgress.
+type updateConflictError struct {
+	err error
+}
+
+func (e *updateConflictError) Error() string {
+	return e.err.Error()
+}
+
+func (e *updateConflictError) Unwrap() error {
+	return e.err
+}
+
+func (e *updateConflictError) Is(target error) bool {
+	return target == ErrUpdateInProgress
+}
+
+// AsUpdateConflict marks a ResourceInUseException returned by an update call as an update conflict, other errors
+// are returned unchanged.
+func AsUpdateConflict(err error) error {
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) && awsErr.Code() == eks.ErrCodeResourceInUseException {
+		return &updateConflictError{err: err}
+	}
+	return err
+}
+
+// IsRetriableUpdateConflict returns whether the error comes from an update rejected because another update is in
+// progress, in which case the update should be requeued rather than reported as a failure.
+func IsRetriableUpdateConflict(err error) bool {
+	return errors.Is(err, ErrUpdateInProgress)
+}
@@ -0,0 +1,37 @@
+package eks
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/eks"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("IsRetriableUpdateConflict", func() {
+	It("should classify a resource in use error as a retriable conflict", func() {
+		err := AsUpdateConflict(awserr.New(eks.ErrCodeResourceInUseException, "update in progress", nil))
+		Expect(IsRetriableUpdateConflict(err)).To(BeTrue())
+		Expect(IsRetriableUpdateConflict(fmt.Errorf("error updating cluster [test]: %w", err))).To(BeTrue())
+	})
+
+	It("should keep the original error", func() {
+		original := awserr.New(eks.ErrCodeResourceInUseException, "update in progress", nil)
+		err := AsUpdateConflict(original)
+		Expect(err.Error()).To(Equal(original.Error()))
+
+		var awsErr awserr.Error
+		Expect(errors.As(err, &awsErr)).To(BeTrue())
+		Expect(awsErr.Code()).To(Equal(eks.ErrCodeResourceInUseException))
+	})
+
+	It("should not classify other errors as retriable conflicts", func() {
+		Expect(IsRetriableUpdateConflict(nil)).To(BeFalse())
+		Expect(IsRetriableUpdateConflict(AsUpdateConflict(nil))).To(BeFalse())
+		Expect(IsRetriableUpdateConflict(AsUpdateConflict(errors.New("update in progress")))).To(BeFalse())
+		Expect(IsRetriableUpdateConflict(AsUpdateConflict(
+			awserr.New(eks.ErrCodeInvalidParameterException, "invalid parameter", nil)))).To(BeFalse())
+	})
+})
@@ -1,22 +1,26 @@
 package eks
 
 import (
+	"context"
 	"encoding/base64"
 	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
-	"github.com/aws/aws-sdk-go/aws/endpoints"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/eks"
 	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
 	"github.com/rancher/eks-operator/pkg/eks/services"
-	"github.com/rancher/eks-operator/templates"
 	"github.com/rancher/eks-operator/utils"
+	"github.com/sirupsen/logrus"
 )
 
 const (
@@ -27,24 +31,118 @@ const (
 	rollbackInProgressStatus = "ROLLBACK_IN_PROGRESS"
 
 	LaunchTemplateNameFormat = "rancher-managed-lt-%s"
+	maxLaunchTemplateName    = 128
 	launchTemplateTagKey     = "rancher-managed-template"
 	launchTemplateTagValue   = "do-not-modify-or-delete"
 	defaultStorageDeviceName = "/dev/xvda"
+
+	// placeholderLaunchTemplateVersion is the default version of the rancher-managed launch template, which only
+	// holds fake userdata and must never be used by a node group.
+	placeholderLaunchTemplateVersion = "1"
+
+	clusterOwnershipTagFormat = "kubernetes.io/cluster/%s"
+	clusterOwnershipTagValue  = "owned"
+
+	// stackDisplayNameTagKey is the tag of the stacks created for a cluster holding the display name of that cluster.
+	stackDisplayNameTagKey = "displayName"
+
+	nodeInstanceRoleStackNameFormat = "%s-node-instance-role"
+	vpcStackNameFormat              = "%s-eks-vpc"
+
+	// nodeTerminationHandlerTagKey is the tag the AWS Node Termination Handler in queue processor mode looks for on
+	// an instance before draining it.
+	nodeTerminationHandlerTagKey   = "aws-node-termination-handler/managed"
+	nodeTerminationHandlerTagValue = "true"
+
+	// defaultStackPollInterval is how often CreateStack describes the stack by default.
+	defaultStackPollInterval = 5 * time.Second
+	// defaultFargateProfilePollInterval is how often CreateFargateProfile describes the fargate profile by default.
+	defaultFargateProfilePollInterval = 5 * time.Second
 )
 
+var launchTemplateNameRegex = regexp.MustCompile(`^[a-zA-Z0-9()./_\-]{3,}$`)
+
 type CreateClusterOptions struct {
 	EKSService services.EKSServiceInterface
 	Config     *eksv1.EKSClusterConfig
 	RoleARN    string
 }
 
-func CreateCluster(opts *CreateClusterOptions) error {
+func CreateCluster(ctx context.Context, opts *CreateClusterOptions) (err error) {
+	ctx, span := startSpan(ctx, "CreateCluster", opts.Config.Spec.DisplayName)
+	defer func() { endSpan(span, err) }()
+
+	if opts.Config.Spec.KubernetesVersion != nil {
+		if err := validateKubernetesVersion(aws.StringValue(opts.Config.Spec.KubernetesVersion)); err != nil {
+			return fmt.Errorf("error creating cluster [%s]: %w", opts.Config.Name, err)
+		}
+	}
+
+	if err := ValidateLoggingTypes(opts.Config); err != nil {
+		return err
+	}
+
 	createClusterInput := newClusterInput(opts.Config, opts.RoleARN)
 
-	_, err := opts.EKSService.CreateCluster(createClusterInput)
+	err = retryWithBackoff(ctx, defaultBackoff, func() error {
+		_, err := opts.EKSService.CreateCluster(ctx, createClusterInput)
+		return err
+	})
+	if err != nil && alreadyExistsInEKSError(err) {
+		// A previous reconcile may have created the cluster without getting to record it, adopt the existing
+		// cluster if it is the one that would have been created.
+		return adoptExistingCluster(ctx, opts, createClusterInput)
+	}
 	return err
 }
 
+func adoptExistingCluster(ctx context.Context, opts *CreateClusterOptions, input *eks.CreateClusterInput) error {
+	output, err := opts.EKSService.DescribeCluster(ctx, &eks.DescribeClusterInput{
+		Name: input.Name,
+	})
+	if err != nil {
+		return fmt.Errorf("error describing existing cluster [%s]: %w", aws.StringValue(input.Name), err)
+	}
+
+	if mismatches := getClusterMismatches(input, output.Cluster); len(mismatches) != 0 {
+		return fmt.Errorf("cluster [%s] already exists and does not match the desired config: %s",
+			aws.StringValue(input.Name), strings.Join(mismatches, "; "))
+	}
+
+	logrus.Infof("adopting existing cluster [%s]", aws.StringValue(input.Name))
+	return nil
+}
+
+// getClusterMismatches compares the settings of an existing cluster that cannot be changed after creation with the
+// desired ones. Settings that were not requested are ignored.
+func getClusterMismatches(input *eks.CreateClusterInput, cluster *eks.Cluster) []string {
+	if cluster == nil {
+		return []string{"cluster has no description"}
+	}
+
+	var mismatches []string
+	if aws.StringValue(input.RoleArn) != aws.StringValue(cluster.RoleArn) {
+		mismatches = append(mismatches, fmt.Sprintf("role is [%s], expected [%s]", aws.StringValue(cluster.RoleArn), aws.StringValue(input.RoleArn)))
+	}
+	if input.Version != nil && aws.StringValue(input.Version) != aws.StringValue(cluster.Version) {
+		mismatches = append(mismatches, fmt.Sprintf("kubernetes version is [%s], expected [%s]", aws.StringValue(cluster.Version), aws.StringValue(input.Version)))
+	}
+	if len(input.ResourcesVpcConfig.SubnetIds) != 0 {
+		var upstreamSubnets []string
+		if cluster.ResourcesVpcConfig != nil {
+			upstreamSubnets = aws.StringValueSlice(cluster.ResourcesVpcConfig.SubnetIds)
+		}
+		subnets := aws.StringValueSlice(input.ResourcesVpcConfig.SubnetIds)
+		sort.Strings(subnets)
+		sort.Strings(upstreamSubnets)
+		if !reflect.DeepEqual(subnets, upstreamSubnets) {
+			mismatches = append(mismatches, fmt.Sprintf("subnets are %v, expected %v", upstreamSubnets, subnets))
+		}
+	}
+
+	return mismatches
+}
+
 func newClusterInput(config *eksv1.EKSClusterConfig, roleARN string) *eks.CreateClusterInput {
 	createClusterInput := &eks.CreateClusterInput{
 		Name:    aws.String(config.Spec.DisplayName),
@@ -56,9 +154,10 @@ func newClusterInput(config *eksv1.EKSClusterConfig, roleARN string) *eks.Create
 			SubnetIds:             aws.StringSlice(config.Status.Subnets),
 			PublicAccessCidrs:     getPublicAccessCidrs(config.Spec.PublicAccessSources),
 		},
-		Tags:    getTags(config.Spec.Tags),
-		Logging: getLogging(config.Spec.LoggingTypes),
-		Version: config.Spec.KubernetesVersion,
+		Tags:         getTags(GetNormalizedTags(config, config.Spec.Tags)),
+		Logging:      getLogging(config.Spec.LoggingTypes),
+		Version:      config.Spec.KubernetesVersion,
+		AccessConfig: getCreateAccessConfig(config),
 	}
 
 	if aws.BoolValue(config.Spec.SecretsEncryption) {
@@ -67,7 +166,7 @@ func newClusterInput(config *eksv1.EKSClusterConfig, roleARN string) *eks.Create
 				Provider: &eks.Provider{
 					KeyArn: config.Spec.KmsKey,
 				},
-				Resources: aws.StringSlice([]string{"secrets"}),
+				Resources: aws.StringSlice(getEncryptionResources(config)),
 			},
 		}
 	}
@@ -75,6 +174,83 @@ func newClusterInput(config *eksv1.EKSClusterConfig, roleARN string) *eks.Create
 	return createClusterInput
 }
 
+type CreateFargateProfileOptions struct {
+	EKSService services.EKSServiceInterface
+	Config     *eksv1.EKSClusterConfig
+	// PollInterval defaults to 5 seconds.
+	PollInterval time.Duration
+}
+
+// CreateFargateProfile creates the fargate profile of the cluster and waits for it to become active.
+func CreateFargateProfile(ctx context.Context, opts *CreateFargateProfileOptions) (err error) {
+	ctx, span := startSpan(ctx, "CreateFargateProfile", opts.Config.Spec.DisplayName)
+	defer func() { endSpan(span, err) }()
+
+	input := newFargateProfileInput(opts.Config)
+	profileName := aws.StringValue(input.FargateProfileName)
+
+	_, err = opts.EKSService.CreateFargateProfile(ctx, input)
+	if err != nil && !alreadyExistsInEKSError(err) {
+		return fmt.Errorf("error creating fargate profile [%s] for cluster [%s]: %w", profileName, opts.Config.Name, err)
+	}
+
+	pollInterval := opts.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = defaultFargateProfilePollInterval
+	}
+	status := eks.FargateProfileStatusCreating
+	for status == eks.FargateProfileStatusCreating {
+		if err := sleepWithContext(ctx, pollInterval); err != nil {
+			return err
+		}
+		output, err := opts.EKSService.DescribeFargateProfile(ctx, &eks.DescribeFargateProfileInput{
+			ClusterName:        input.ClusterName,
+			FargateProfileName: input.FargateProfileName,
+		})
+		if err != nil {
+			return fmt.Errorf("error polling fargate profile [%s] for cluster [%s]: %w", profileName, opts.Config.Name, err)
+		}
+
+		if output == nil || output.FargateProfile == nil {
+			return fmt.Errorf("fargate profile [%s] for cluster [%s] did not have output", profileName, opts.Config.Name)
+		}
+
+		status = aws.StringValue(output.FargateProfile.Status)
+	}
+
+	if status != eks.FargateProfileStatusActive {
+		return fmt.Errorf("fargate profile [%s] for cluster [%s] failed to create: status is %s", profileName, opts.Config.Name, status)
+	}
+
+	return nil
+}
+
+func newFargateProfileInput(config *eksv1.EKSClusterConfig) *eks.CreateFargateProfileInput {
+	profile := config.Spec.FargateProfile
+
+	subnets := profile.Subnets
+	if len(subnets) == 0 {
+		subnets = config.Status.Subnets
+	}
+
+	selectors := make([]*eks.FargateProfileSelector, 0, len(profile.Selectors))
+	for _, selector := range profile.Selectors {
+		selectors = append(selectors, &eks.FargateProfileSelector{
+			Namespace: selector.Namespace,
+			Labels:    selector.Labels,
+		})
+	}
+
+	return &eks.CreateFargateProfileInput{
+		ClusterName:         aws.String(config.Spec.DisplayName),
+		FargateProfileName:  profile.FargateProfileName,
+		PodExecutionRoleArn: profile.PodExecutionRoleArn,
+		Subnets:             aws.StringSlice(subnets),
+		Selectors:           selectors,
+		Tags:                getTags(GetNormalizedTags(config, config.Spec.Tags)),
+	}
+}
+
 type CreateStackOptions struct {
 	CloudFormationService services.CloudFormationServiceInterface
 	StackName             string
@@ -82,47 +258,88 @@ type CreateStackOptions struct {
 	TemplateBody          string
 	Capabilities          []string
 	Parameters            []*cloudformation.Parameter
+	// Tags are applied to the stack and propagated by CloudFormation to the resources it creates.
+	Tags map[string]string
+	// PollInterval defaults to 5 seconds.
+	PollInterval time.Duration
+}
+
+// StackResourceFailure is a failed resource reported by the events of a CloudFormation stack.
+type StackResourceFailure struct {
+	LogicalResourceID string
+	Status            string
+	Reason            string
+}
+
+// StackCreationError is returned when a CloudFormation stack does not reach CREATE_COMPLETE. It carries every
+// CREATE_FAILED and ROLLBACK_IN_PROGRESS event of the stack, so that failures of nested resources are not hidden
+// behind the first one.
+type StackCreationError struct {
+	StackName string
+	Status    string
+	Failures  []StackResourceFailure
+}
+
+func (e *StackCreationError) Error() string {
+	if len(e.Failures) == 0 {
+		return fmt.Sprintf("stack [%s] failed to create with status [%s]: reason unknown", e.StackName, e.Status)
+	}
+	reasons := make([]string, 0, len(e.Failures))
+	for _, failure := range e.Failures {
+		reasons = append(reasons, fmt.Sprintf("%s [%s]: %s", failure.LogicalResourceID, failure.Status, failure.Reason))
+	}
+	return fmt.Sprintf("stack [%s] failed to create with status [%s]: %s", e.StackName, e.Status, strings.Join(reasons, "; "))
 }
 
-func CreateStack(opts *CreateStackOptions) (*cloudformation.DescribeStacksOutput, error) {
-	_, err := opts.CloudFormationService.CreateStack(&cloudformation.CreateStackInput{
+func CreateStack(ctx context.Context, opts *CreateStackOptions) (_ *cloudformation.DescribeStacksOutput, err error) {
+	ctx, span := startSpan(ctx, "CreateStack", opts.DisplayName)
+	defer func() { endSpan(span, err) }()
+
+	_, err = opts.CloudFormationService.CreateStack(ctx, &cloudformation.CreateStackInput{
 		StackName:    aws.String(opts.StackName),
 		TemplateBody: aws.String(opts.TemplateBody),
 		Capabilities: aws.StringSlice(opts.Capabilities),
 		Parameters:   opts.Parameters,
-		Tags: []*cloudformation.Tag{
-			{
-				Key:   aws.String("displayName"),
-				Value: aws.String(opts.DisplayName),
-			},
-		},
+		Tags:         getStackTags(opts.Tags, opts.DisplayName),
 	})
 	if err != nil && !alreadyExistsInCloudFormationError(err) {
 		return nil, fmt.Errorf("error creating master: %v", err)
 	}
 
+	pollInterval := opts.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = defaultStackPollInterval
+	}
 	var stack *cloudformation.DescribeStacksOutput
 	status := createInProgressStatus
 
 	for status == createInProgressStatus {
-		time.Sleep(time.Second * 5)
-		stack, err = opts.CloudFormationService.DescribeStacks(&cloudformation.DescribeStacksInput{
+		if err := sleepWithContext(ctx, pollInterval); err != nil {
+			return nil, err
+		}
+		stack, err = opts.CloudFormationService.DescribeStacks(ctx, &cloudformation.DescribeStacksInput{
 			StackName: aws.String(opts.StackName),
 		})
 		if err != nil {
 			return nil, fmt.Errorf("error polling stack info: %v", err)
 		}
 
-		if stack == nil || stack.Stacks == nil || len(stack.Stacks) == 0 {
-			return nil, fmt.Errorf("stack did not have output: %v", err)
+		// DescribeStacks is eventually consistent, a stack that was just created may not be returned yet
+		if stack == nil || len(stack.Stacks) == 0 || stack.Stacks[0] == nil {
+			return nil, fmt.Errorf("stack [%s] was not returned by DescribeStacks", opts.StackName)
 		}
 
-		status = *stack.Stacks[0].StackStatus
+		if stack.Stacks[0].StackStatus != nil {
+			status = *stack.Stacks[0].StackStatus
+		}
 	}
 
 	if status != createCompleteStatus {
-		reason := "reason unknown"
-		events, err := opts.CloudFormationService.DescribeStackEvents(&cloudformation.DescribeStackEventsInput{
+		stackErr := &StackCreationError{
+			StackName: opts.StackName,
+			Status:    status,
+		}
+		events, err := opts.CloudFormationService.DescribeStackEvents(ctx, &cloudformation.DescribeStackEventsInput{
 			StackName: aws.String(opts.StackName),
 		})
 		if err == nil {
@@ -132,18 +349,16 @@ func CreateStack(opts *CreateStackOptions) (*cloudformation.DescribeStacksOutput
 					continue
 				}
 
-				if *event.ResourceStatus == createFailedStatus {
-					reason = *event.ResourceStatusReason
-					break
-				}
-
-				if *event.ResourceStatus == rollbackInProgressStatus {
-					reason = *event.ResourceStatusReason
-					// do not break so that CREATE_FAILED takes priority
+				if *event.ResourceStatus == createFailedStatus || *event.ResourceStatus == rollbackInProgressStatus {
+					stackErr.Failures = append(stackErr.Failures, StackResourceFailure{
+						LogicalResourceID: *event.LogicalResourceId,
+						Status:            *event.ResourceStatus,
+						Reason:            *event.ResourceStatusReason,
+					})
 				}
 			}
 		}
-		return nil, fmt.Errorf("stack failed to create: %v", reason)
+		return nil, stackErr
 	}
 
 	return stack, nil
@@ -154,44 +369,111 @@ type CreateLaunchTemplateOptions struct {
 	Config     *eksv1.EKSClusterConfig
 }
 
-func CreateLaunchTemplate(opts *CreateLaunchTemplateOptions) error {
-	_, err := opts.EC2Service.DescribeLaunchTemplates(&ec2.DescribeLaunchTemplatesInput{
-		LaunchTemplateIds: []*string{aws.String(opts.Config.Status.ManagedLaunchTemplateID)},
-	})
-	if opts.Config.Status.ManagedLaunchTemplateID == "" || doesNotExist(err) {
-		lt, err := createLaunchTemplate(opts.EC2Service, opts.Config.Spec.DisplayName)
-		if err != nil {
-			return fmt.Errorf("error creating launch template: %w", err)
+// CreateLaunchTemplate makes sure the cluster has a managed launch template and sets its ID in the status of the
+// config. If the template of the status no longer exists, or the status was lost, a managed template with the
+// expected name is adopted before creating a new one, since launch template names are unique within a region.
+func CreateLaunchTemplate(ctx context.Context, opts *CreateLaunchTemplateOptions) error {
+	if id := opts.Config.Status.ManagedLaunchTemplateID; id != "" {
+		_, err := opts.EC2Service.DescribeLaunchTemplates(ctx, &ec2.DescribeLaunchTemplatesInput{
+			LaunchTemplateIds: []*string{aws.String(id)},
+		})
+		if err == nil {
+			return nil
+		}
+		if !doesNotExist(err) {
+			return fmt.Errorf("error checking for existing launch template: %w", err)
 		}
-		opts.Config.Status.ManagedLaunchTemplateID = aws.StringValue(lt.ID)
-	} else if err != nil {
-		return fmt.Errorf("error checking for existing launch template: %w", err)
 	}
 
+	id, err := findManagedLaunchTemplate(ctx, opts.EC2Service, opts.Config)
+	if err != nil {
+		return err
+	}
+	if id != "" {
+		logrus.Infof("adopting existing launch template [%s] for cluster [%s]", id, opts.Config.Name)
+		opts.Config.Status.ManagedLaunchTemplateID = id
+		return nil
+	}
+
+	name := GetManagedLaunchTemplateName(opts.Config)
+	lt, err := createLaunchTemplate(ctx, opts.EC2Service, name, GetNormalizedTags(opts.Config, opts.Config.Spec.Tags))
+	if err != nil {
+		return fmt.Errorf("error creating launch template: %w", err)
+	}
+	opts.Config.Status.ManagedLaunchTemplateID = aws.StringValue(lt.ID)
+
 	return nil
 }
 
-func createLaunchTemplate(ec2Service services.EC2ServiceInterface, clusterDisplayName string) (*eksv1.LaunchTemplate, error) {
+// findManagedLaunchTemplate returns the ID of the launch template with the rancher-managed-template tag and the
+// managed launch template name of the cluster, or an empty ID if there is none. Filtering on the name as well makes
+// sure the template of another cluster is never picked.
+func findManagedLaunchTemplate(ctx context.Context, ec2Service services.EC2ServiceInterface, config *eksv1.EKSClusterConfig) (string, error) {
+	name := GetManagedLaunchTemplateName(config)
+	output, err := ec2Service.DescribeLaunchTemplates(ctx, &ec2.DescribeLaunchTemplatesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("tag:" + launchTemplateTagKey),
+				Values: aws.StringSlice([]string{launchTemplateTagValue}),
+			},
+			{
+				Name:   aws.String("launch-template-name"),
+				Values: aws.StringSlice([]string{name}),
+			},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("error looking up launch template [%s]: %w", name, err)
+	}
+	if len(output.LaunchTemplates) == 0 {
+		return "", nil
+	}
+
+	return aws.StringValue(output.LaunchTemplates[0].LaunchTemplateId), nil
+}
+
+// GetManagedLaunchTemplateName returns the name of the launch template managed by the operator for the cluster. It is
+// the launchTemplatePrefix followed by the display name of the cluster, or rancher-managed-lt- followed by the display
+// name if no prefix is set, so that clusters sharing a display name in different namespaces can use distinct prefixes.
+func GetManagedLaunchTemplateName(config *eksv1.EKSClusterConfig) string {
+	if prefix := aws.StringValue(config.Spec.LaunchTemplatePrefix); prefix != "" {
+		return prefix + config.Spec.DisplayName
+	}
+	return fmt.Sprintf(LaunchTemplateNameFormat, config.Spec.DisplayName)
+}
+
+// ValidateLaunchTemplatePrefix checks that the name of the managed launch template generated from the
+// launchTemplatePrefix is accepted by EC2.
+func ValidateLaunchTemplatePrefix(config *eksv1.EKSClusterConfig) error {
+	if config.Spec.LaunchTemplatePrefix == nil {
+		return nil
+	}
+	name := GetManagedLaunchTemplateName(config)
+	if len(name) > maxLaunchTemplateName {
+		return fmt.Errorf("launch template name [%s] for cluster [%s] is longer than %d characters, shorten launchTemplatePrefix", name, config.Name, maxLaunchTemplateName)
+	}
+	if !launchTemplateNameRegex.MatchString(name) {
+		return fmt.Errorf("launch template name [%s] for cluster [%s] can only contain letters, digits and the characters ( ) . / _ -", name, config.Name)
+	}
+	return nil
+}
+
+func createLaunchTemplate(ctx context.Context, ec2Service services.EC2ServiceInterface, name string, tags map[string]string) (*eksv1.LaunchTemplate, error) {
 	// The first version of the rancher-managed launch template will be the default version.
 	// Since the default version cannot be deleted until the launch template is deleted, it will not be used for any node group.
 	// Also, launch templates cannot be created blank, so fake userdata is added to the first version.
 	launchTemplateCreateInput := &ec2.CreateLaunchTemplateInput{
 		LaunchTemplateData: &ec2.RequestLaunchTemplateData{UserData: aws.String("cGxhY2Vob2xkZXIK")},
-		LaunchTemplateName: aws.String(fmt.Sprintf(LaunchTemplateNameFormat, clusterDisplayName)),
+		LaunchTemplateName: aws.String(name),
 		TagSpecifications: []*ec2.TagSpecification{
 			{
 				ResourceType: aws.String(ec2.ResourceTypeLaunchTemplate),
-				Tags: []*ec2.Tag{
-					{
-						Key:   aws.String(launchTemplateTagKey),
-						Value: aws.String(launchTemplateTagValue),
-					},
-				},
+				Tags:         getLaunchTemplateTags(tags),
 			},
 		},
 	}
 
-	awsLaunchTemplateOutput, err := ec2Service.CreateLaunchTemplate(launchTemplateCreateInput)
+	awsLaunchTemplateOutput, err := ec2Service.CreateLaunchTemplate(ctx, launchTemplateCreateInput)
 	if err != nil {
 		return nil, err
 	}
@@ -207,17 +489,48 @@ type CreateNodeGroupOptions struct {
 	EC2Service            services.EC2ServiceInterface
 	CloudFormationService services.CloudFormationServiceInterface
 	EKSService            services.EKSServiceInterface
+	// IAMService checks the policies of the node role of the node group if set, see ValidateNodeRole.
+	IAMService services.IAMServiceInterface
 
 	Config    *eksv1.EKSClusterConfig
 	NodeGroup eksv1.NodeGroup
 }
 
-func CreateNodeGroup(opts *CreateNodeGroupOptions) (string, string, error) {
-	var err error
-	capacityType := eks.CapacityTypesOnDemand
-	if aws.BoolValue(opts.NodeGroup.RequestSpotInstances) {
-		capacityType = eks.CapacityTypesSpot
+func CreateNodeGroup(ctx context.Context, opts *CreateNodeGroupOptions) (_ string, _ string, err error) {
+	ctx, span := startSpan(ctx, "CreateNodeGroup", opts.Config.Spec.DisplayName)
+	defer func() { endSpan(span, err) }()
+
+	if err := validateNodeGroup(&opts.NodeGroup); err != nil {
+		return "", "", err
+	}
+	if err := validateSecurityGroupsForPods(ctx, opts.EC2Service, &opts.NodeGroup); err != nil {
+		return "", "", err
+	}
+	if nodeRole := aws.StringValue(opts.NodeGroup.NodeRole); nodeRole != "" {
+		if err := ValidateNodeRole(ctx, &ValidateNodeRoleOpts{
+			IAMService: opts.IAMService,
+			Config:     opts.Config,
+			NodeRole:   nodeRole,
+		}); err != nil {
+			return "", "", fmt.Errorf("nodegroup [%s]: %w", aws.StringValue(opts.NodeGroup.NodegroupName), err)
+		}
+	}
+	opts.NodeGroup = GetNormalizedNodeGroup(opts.Config, opts.NodeGroup)
+
+	subnets, err := getAvailabilityZoneSubnets(ctx, opts.EC2Service, &opts.NodeGroup, opts.Config.Status.Subnets)
+	if err != nil {
+		return "", "", err
+	}
+	if err := CheckNodegroupSubnetCapacity(ctx, &CheckNodegroupSubnetCapacityOpts{
+		EC2Service: opts.EC2Service,
+		Config:     opts.Config,
+		NodeGroup:  &opts.NodeGroup,
+		Subnets:    subnets,
+	}); err != nil {
+		return "", "", err
 	}
+
+	capacityType := getCapacityType(opts.NodeGroup.RequestSpotInstances)
 	nodeGroupCreateInput := &eks.CreateNodegroupInput{
 		ClusterName:   aws.String(opts.Config.Spec.DisplayName),
 		NodegroupName: opts.NodeGroup.NodegroupName,
@@ -228,6 +541,8 @@ func CreateNodeGroup(opts *CreateNodeGroupOptions) (string, string, error) {
 			MinSize:     opts.NodeGroup.MinSize,
 		},
 		CapacityType: aws.String(capacityType),
+		Taints:       toEKSTaints(opts.NodeGroup.Taints),
+		UpdateConfig: toEKSUpdateConfig(GetNodegroupUpdateConfig(&opts.NodeGroup)),
 	}
 
 	lt := opts.NodeGroup.LaunchTemplate
@@ -235,7 +550,7 @@ func CreateNodeGroup(opts *CreateNodeGroupOptions) (string, string, error) {
 	if lt == nil {
 		// In this case, the user has not specified their own launch template.
 		// If the cluster doesn't have a launch template associated with it, then we create one.
-		lt, err = CreateNewLaunchTemplateVersion(opts.EC2Service, opts.Config.Status.ManagedLaunchTemplateID, opts.NodeGroup)
+		lt, err = CreateNewLaunchTemplateVersion(ctx, opts.EC2Service, opts.Config, opts.NodeGroup)
 		if err != nil {
 			return "", "", err
 		}
@@ -250,55 +565,52 @@ func CreateNodeGroup(opts *CreateNodeGroupOptions) (string, string, error) {
 		Id:      lt.ID,
 		Version: launchTemplateVersion,
 	}
+	if err := validateManagedLaunchTemplateVersion(opts.Config.Status.ManagedLaunchTemplateID, nodeGroupCreateInput.LaunchTemplate); err != nil {
+		return "", "", fmt.Errorf("error creating nodegroup [%s] in cluster [%s]: %w", aws.StringValue(opts.NodeGroup.NodegroupName), opts.Config.Name, err)
+	}
 
 	if aws.BoolValue(opts.NodeGroup.RequestSpotInstances) {
 		nodeGroupCreateInput.InstanceTypes = opts.NodeGroup.SpotInstanceTypes
 	}
 
 	if aws.StringValue(opts.NodeGroup.ImageID) == "" {
-		if gpu := opts.NodeGroup.Gpu; aws.BoolValue(gpu) {
-			nodeGroupCreateInput.AmiType = aws.String(eks.AMITypesAl2X8664Gpu)
-		} else {
-			nodeGroupCreateInput.AmiType = aws.String(eks.AMITypesAl2X8664)
+		amiType, err := getNodegroupAMIType(&opts.NodeGroup)
+		if err != nil {
+			return "", "", fmt.Errorf("nodegroup [%s]: %w", aws.StringValue(opts.NodeGroup.NodegroupName), err)
 		}
-	}
-
-	if len(opts.NodeGroup.Subnets) != 0 {
-		nodeGroupCreateInput.Subnets = aws.StringSlice(opts.NodeGroup.Subnets)
+		nodeGroupCreateInput.AmiType = aws.String(amiType)
 	} else {
-		nodeGroupCreateInput.Subnets = aws.StringSlice(opts.Config.Status.Subnets)
+		// The AMI of the managed launch template is not an EKS optimized one, so EKS leaves bootstrapping the nodes
+		// to the user data of the launch template.
+		nodeGroupCreateInput.AmiType = aws.String(eks.AMITypesCustom)
 	}
 
+	nodeGroupCreateInput.Subnets = aws.StringSlice(subnets)
+
 	generatedNodeRole := opts.Config.Status.GeneratedNodeRole
 
 	if aws.StringValue(opts.NodeGroup.NodeRole) == "" {
 		if opts.Config.Status.GeneratedNodeRole == "" {
-			finalTemplate := fmt.Sprintf(templates.NodeInstanceRoleTemplate, getEC2ServiceEndpoint(opts.Config.Spec.Region))
-			output, err := CreateStack(&CreateStackOptions{
-				CloudFormationService: opts.CloudFormationService,
-				StackName:             fmt.Sprintf("%s-node-instance-role", opts.Config.Spec.DisplayName),
-				DisplayName:           opts.Config.Spec.DisplayName,
-				TemplateBody:          finalTemplate,
-				Capabilities:          []string{cloudformation.CapabilityCapabilityIam},
-				Parameters:            []*cloudformation.Parameter{},
-			})
+			generatedNodeRole, err = createNodeInstanceRole(ctx, opts)
 			if err != nil {
 				// If there was an error creating the node role stack, return an empty launch template
 				// version and the error.
 				return "", "", err
 			}
-			generatedNodeRole = getParameterValueFromOutput("NodeInstanceRole", output.Stacks[0].Outputs)
 		}
 		nodeGroupCreateInput.NodeRole = aws.String(generatedNodeRole)
 	} else {
 		nodeGroupCreateInput.NodeRole = opts.NodeGroup.NodeRole
 	}
 
-	_, err = opts.EKSService.CreateNodegroup(nodeGroupCreateInput)
+	err = retryWithBackoff(ctx, defaultBackoff, func() error {
+		_, err := opts.EKSService.CreateNodegroup(ctx, nodeGroupCreateInput)
+		return err
+	})
 	if err != nil {
 		// If there was an error creating the node group, then the template version should be deleted
 		// to prevent many launch template versions from being created before the issue is fixed.
-		DeleteLaunchTemplateVersions(opts.EC2Service, *lt.ID, []*string{launchTemplateVersion})
+		DeleteLaunchTemplateVersions(ctx, opts.EC2Service, *lt.ID, []*string{launchTemplateVersion})
 	}
 
 	// Return the launch template version and generated node role to the calling function so they can
@@ -306,18 +618,153 @@ func CreateNodeGroup(opts *CreateNodeGroupOptions) (string, string, error) {
 	return aws.StringValue(launchTemplateVersion), generatedNodeRole, err
 }
 
-func CreateNewLaunchTemplateVersion(ec2Service services.EC2ServiceInterface, launchTemplateID string, group eksv1.NodeGroup) (*eksv1.LaunchTemplate, error) {
-	launchTemplate, err := buildLaunchTemplateData(ec2Service, group)
+// validateScalingConfig makes sure the sizes of the node group are consistent, EKS rejects them with an error that
+// does not name the node group otherwise. Sizes that are not set are left for EKS to default.
+func validateScalingConfig(ng *eksv1.NodeGroup) error {
+	minSize, maxSize, desiredSize := ng.MinSize, ng.MaxSize, ng.DesiredSize
+	if minSize != nil && maxSize != nil && *minSize > *maxSize {
+		return fmt.Errorf("minSize [%d] cannot be greater than maxSize [%d]", *minSize, *maxSize)
+	}
+	if desiredSize == nil {
+		return nil
+	}
+	if minSize != nil && *desiredSize < *minSize {
+		return fmt.Errorf("desiredSize [%d] cannot be less than minSize [%d]", *desiredSize, *minSize)
+	}
+	if maxSize != nil && *desiredSize > *maxSize {
+		return fmt.Errorf("desiredSize [%d] cannot be greater than maxSize [%d]", *desiredSize, *maxSize)
+	}
+	return nil
+}
+
+// validateNodeGroup enforces the EKS rules on node group fields that cannot be combined. When a node group uses
+// its own launch template, the AMI, instance type, disk size and remote access must be configured in that launch
+// template rather than on the node group.
+func validateNodeGroup(ng *eksv1.NodeGroup) error {
+	ngName := aws.StringValue(ng.NodegroupName)
+	if err := validateTaints(ng.Taints); err != nil {
+		return fmt.Errorf("nodegroup [%s]: %w", ngName, err)
+	}
+	if err := validateUpdateConfig(ng); err != nil {
+		return fmt.Errorf("nodegroup [%s]: %w", ngName, err)
+	}
+	if err := validateScalingConfig(ng); err != nil {
+		return fmt.Errorf("nodegroup [%s]: %w", ngName, err)
+	}
+	if _, err := getNodegroupAMIType(ng); err != nil {
+		return fmt.Errorf("nodegroup [%s]: %w", ngName, err)
+	}
+	if aws.StringValue(ng.IamInstanceProfile) != "" {
+		// EKS rejects the launch template of a managed node group setting an instance profile, it creates the instance
+		// profile of the nodes from the node role.
+		return fmt.Errorf("nodegroup [%s]: iamInstanceProfile cannot be specified for a managed nodegroup, set nodeRole instead", ngName)
+	}
+
+	for _, securityGroup := range ng.NodeGroupSecurityGroups {
+		if strings.TrimSpace(securityGroup) == "" {
+			return fmt.Errorf("nodegroup [%s]: nodeGroupSecurityGroups cannot contain empty security group IDs", ngName)
+		}
+	}
+
+	if ng.LaunchTemplate == nil {
+		if aws.StringValue(ng.ImageID) != "" && aws.StringValue(ng.UserData) == "" && !hasBootstrapFields(*ng) {
+			return fmt.Errorf("nodegroup [%s]: userData, or bootstrapExtraArgs and kubeletExtraArgs, must be specified along with a custom imageId to bootstrap the nodes", ngName)
+		}
+		return nil
+	}
+
+	if len(ng.NodeGroupSecurityGroups) != 0 {
+		return fmt.Errorf("nodegroup [%s]: nodeGroupSecurityGroups cannot be specified along with a custom launch template, set the security groups in the launch template instead", ngName)
+	}
+	if aws.StringValue(ng.ImageID) != "" {
+		return fmt.Errorf("nodegroup [%s]: imageId cannot be specified along with a custom launch template, set the AMI in the launch template instead", ngName)
+	}
+	if aws.StringValue(ng.InstanceType) != "" {
+		return fmt.Errorf("nodegroup [%s]: instanceType cannot be specified along with a custom launch template, set the instance type in the launch template instead", ngName)
+	}
+	if aws.BoolValue(ng.NodeTerminationHandler) {
+		return fmt.Errorf("nodegroup [%s]: nodeTerminationHandler cannot be specified along with a custom launch template, tag the instances in the launch template instead", ngName)
+	}
+	if aws.Int64Value(ng.DiskSize) != 0 {
+		return fmt.Errorf("nodegroup [%s]: diskSize cannot be specified along with a custom launch template, set the block device mappings in the launch template instead", ngName)
+	}
+	if aws.StringValue(ng.DiskType) != "" || aws.Int64Value(ng.Iops) != 0 || aws.Int64Value(ng.Throughput) != 0 {
+		return fmt.Errorf("nodegroup [%s]: diskType, iops and throughput cannot be specified along with a custom launch template, set the block device mappings in the launch template instead", ngName)
+	}
+	if aws.StringValue(ng.Ec2SshKey) != "" {
+		return fmt.Errorf("nodegroup [%s]: ec2SshKey cannot be specified along with a custom launch template, set the key pair in the launch template instead", ngName)
+	}
+
+	return nil
+}
+
+// validateManagedLaunchTemplateVersion ensures a node group is never pointed at the placeholder version of the
+// rancher-managed launch template, otherwise its nodes would come up with the placeholder userdata.
+func validateManagedLaunchTemplateVersion(managedTemplateID string, lt *eks.LaunchTemplateSpecification) error {
+	if lt == nil || managedTemplateID == "" || aws.StringValue(lt.Id) != managedTemplateID {
+		return nil
+	}
+
+	if aws.StringValue(lt.Version) == placeholderLaunchTemplateVersion {
+		return fmt.Errorf("launch template [%s] version [%s] is the placeholder version and cannot be used by a nodegroup",
+			managedTemplateID, placeholderLaunchTemplateVersion)
+	}
+
+	return nil
+}
+
+// stackLocks holds a mutex per stack name, so that node groups of the same cluster created at the same time
+// share the node instance role stack instead of racing to create it.
+var stackLocks sync.Map
+
+func createNodeInstanceRole(ctx context.Context, opts *CreateNodeGroupOptions) (string, error) {
+	stackName := fmt.Sprintf(nodeInstanceRoleStackNameFormat, opts.Config.Spec.DisplayName)
+	lock, _ := stackLocks.LoadOrStore(stackName, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
+	parameters := []*cloudformation.Parameter{}
+	if boundary := aws.StringValue(opts.Config.Spec.PermissionsBoundaryARN); boundary != "" {
+		if err := ValidatePermissionsBoundaryARN(boundary); err != nil {
+			return "", err
+		}
+		parameters = append(parameters, &cloudformation.Parameter{
+			ParameterKey:   aws.String("PermissionsBoundary"),
+			ParameterValue: aws.String(boundary),
+		})
+	}
+
+	// CreateStack tolerates a stack that already exists and waits for it to complete, so a node group created
+	// after another one returns the role of the existing stack.
+	output, err := CreateStack(ctx, &CreateStackOptions{
+		CloudFormationService: opts.CloudFormationService,
+		StackName:             stackName,
+		DisplayName:           opts.Config.Spec.DisplayName,
+		TemplateBody:          getNodeInstanceRoleTemplate(opts.Config.Spec.Region),
+		Capabilities:          []string{cloudformation.CapabilityCapabilityIam},
+		Parameters:            parameters,
+		Tags:                  GetNormalizedTags(opts.Config, opts.Config.Spec.Tags),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return GetOutputsFromStack(output.Stacks[0])["NodeInstanceRole"], nil
+}
+
+// CreateNewLaunchTemplateVersion creates a version of the managed launch template of the cluster for the node group.
+func CreateNewLaunchTemplateVersion(ctx context.Context, ec2Service services.EC2ServiceInterface, config *eksv1.EKSClusterConfig, group eksv1.NodeGroup) (*eksv1.LaunchTemplate, error) {
+	launchTemplate, err := buildLaunchTemplateData(ctx, ec2Service, config, group)
 	if err != nil {
 		return nil, err
 	}
 
 	launchTemplateVersionInput := &ec2.CreateLaunchTemplateVersionInput{
 		LaunchTemplateData: launchTemplate,
-		LaunchTemplateId:   aws.String(launchTemplateID),
+		LaunchTemplateId:   aws.String(config.Status.ManagedLaunchTemplateID),
 	}
 
-	awsLaunchTemplateOutput, err := ec2Service.CreateLaunchTemplateVersion(launchTemplateVersionInput)
+	awsLaunchTemplateOutput, err := ec2Service.CreateLaunchTemplateVersion(ctx, launchTemplateVersionInput)
 	if err != nil {
 		return nil, err
 	}
@@ -329,23 +776,25 @@ func CreateNewLaunchTemplateVersion(ec2Service services.EC2ServiceInterface, lau
 	}, nil
 }
 
-func buildLaunchTemplateData(ec2Service services.EC2ServiceInterface, group eksv1.NodeGroup) (*ec2.RequestLaunchTemplateData, error) {
+func buildLaunchTemplateData(ctx context.Context, ec2Service services.EC2ServiceInterface, config *eksv1.EKSClusterConfig, group eksv1.NodeGroup) (*ec2.RequestLaunchTemplateData, error) {
+	clusterName := config.Spec.DisplayName
 	var imageID *string
 	if aws.StringValue(group.ImageID) != "" {
 		imageID = group.ImageID
 	}
 
-	userdata := group.UserData
-	if aws.StringValue(userdata) != "" {
-		if !strings.Contains(*userdata, "Content-Type: multipart/mixed") {
-			return nil, fmt.Errorf("userdata for nodegroup [%s] is not of mime time multipart/mixed", aws.StringValue(group.NodegroupName))
-		}
-		*userdata = base64.StdEncoding.EncodeToString([]byte(*userdata))
+	nodeUserData, err := GetNodeUserData(clusterName, group)
+	if err != nil {
+		return nil, err
+	}
+	var userdata *string
+	if nodeUserData != "" {
+		userdata = aws.String(base64.StdEncoding.EncodeToString([]byte(nodeUserData)))
 	}
 
-	deviceName := aws.String(defaultStorageDeviceName)
+	deviceName := aws.String(getStorageDeviceName(&group))
 	if aws.StringValue(group.ImageID) != "" {
-		if rootDeviceName, err := getImageRootDeviceName(ec2Service, group.ImageID); err != nil {
+		if rootDeviceName, err := getImageRootDeviceName(ctx, ec2Service, group.ImageID); err != nil {
 			return nil, err
 		} else if rootDeviceName != nil {
 			deviceName = rootDeviceName
@@ -359,25 +808,124 @@ func buildLaunchTemplateData(ec2Service services.EC2ServiceInterface, group eksv
 		BlockDeviceMappings: []*ec2.LaunchTemplateBlockDeviceMappingRequest{
 			{
 				DeviceName: deviceName,
-				Ebs: &ec2.LaunchTemplateEbsBlockDeviceRequest{
-					VolumeSize: group.DiskSize,
-				},
+				Ebs:        getRootVolumeRequest(group),
 			},
 		},
-		TagSpecifications: utils.CreateTagSpecs(group.ResourceTags),
+		TagSpecifications: append(utils.CreateTagSpecs(GetNodegroupInstanceTags(clusterName, group)), utils.CreateVolumeTagSpecs(group.VolumeTags)...),
 	}
 	if !aws.BoolValue(group.RequestSpotInstances) {
 		launchTemplateData.InstanceType = group.InstanceType
 	}
+	if securityGroups := GetLaunchTemplateSecurityGroups(config, group); len(securityGroups) != 0 {
+		launchTemplateData.SecurityGroupIds = aws.StringSlice(securityGroups)
+	}
 
 	return launchTemplateData, nil
 }
 
-func getImageRootDeviceName(ec2Service services.EC2ServiceInterface, imageID *string) (*string, error) {
+// GetLaunchTemplateSecurityGroups returns the security groups of the managed launch template for the node group, if it
+// has additional security groups. The security groups are set on the instance rather than on a network interface, the
+// launch template of a managed node group cannot specify both. EKS only attaches the cluster security group to the
+// nodes if the launch template has no security groups, so it is listed along with the additional ones for the nodes
+// to reach the control plane.
+func GetLaunchTemplateSecurityGroups(config *eksv1.EKSClusterConfig, group eksv1.NodeGroup) []string {
+	if len(group.NodeGroupSecurityGroups) == 0 {
+		return nil
+	}
+
+	securityGroups := append([]string{}, group.NodeGroupSecurityGroups...)
+	clusterSecurityGroupID := config.Status.ClusterSecurityGroupID
+	if clusterSecurityGroupID == "" {
+		return securityGroups
+	}
+	for _, securityGroup := range securityGroups {
+		if securityGroup == clusterSecurityGroupID {
+			return securityGroups
+		}
+	}
+
+	return append(securityGroups, clusterSecurityGroupID)
+}
+
+// getRootVolumeRequest returns the root volume of the node group instances. The volume type is only set when the
+// node group asks for it, or defaults to gp3 when IOPS or throughput are requested, so the volume of existing node
+// groups keeps the type of the AMI.
+func getRootVolumeRequest(group eksv1.NodeGroup) *ec2.LaunchTemplateEbsBlockDeviceRequest {
+	volume := &ec2.LaunchTemplateEbsBlockDeviceRequest{
+		VolumeSize: group.DiskSize,
+	}
+	if iops := aws.Int64Value(group.Iops); iops != 0 {
+		volume.Iops = aws.Int64(iops)
+	}
+	if throughput := aws.Int64Value(group.Throughput); throughput != 0 {
+		volume.Throughput = aws.Int64(throughput)
+	}
+
+	if diskType := aws.StringValue(group.DiskType); diskType != "" {
+		volume.VolumeType = aws.String(diskType)
+	} else if volume.Iops != nil || volume.Throughput != nil {
+		volume.VolumeType = aws.String(ec2.VolumeTypeGp3)
+	}
+
+	return volume
+}
+
+// GetNodegroupInstanceTags returns the tags of the node group instances. Unless disabled, they include the
+// cluster ownership tag the in-tree cloud provider uses to find the nodes and subnets of the cluster. The instances
+// of a node group placed in a single availability zone are tagged with that zone.
+//
+// Managed node groups already drain spot nodes on a rebalance recommendation, as EKS enables capacity rebalancing
+// on their auto scaling groups and owns their termination lifecycle hooks. Node groups opting in to the AWS Node
+// Termination Handler get their instances tagged for it, so it also handles the interruption notices, scheduled
+// events and termination of their nodes.
+func GetNodegroupInstanceTags(clusterName string, group eksv1.NodeGroup) map[string]*string {
+	tags := group.ResourceTags
+	if !aws.BoolValue(group.DisableClusterOwnershipTag) {
+		tags = withDefaultTag(tags, fmt.Sprintf(clusterOwnershipTagFormat, clusterName), clusterOwnershipTagValue)
+	}
+	if aws.BoolValue(group.NodeTerminationHandler) {
+		tags = withDefaultTag(tags, nodeTerminationHandlerTagKey, nodeTerminationHandlerTagValue)
+	}
+
+	return tags
+}
+
+// NodegroupInstanceTagsChanged reports whether the instance tags of a node group differ from the upstream ones. The
+// cluster ownership tag added by default is left out unless it is set in the resource tags: EKS already tags the
+// instances with it, and node groups created without it would otherwise all get a new launch template version
+// replacing their nodes. It is added along with the next change of the launch template instead.
+func NodegroupInstanceTagsChanged(clusterName string, upstreamTags map[string]*string, group eksv1.NodeGroup) bool {
+	tags := aws.StringValueMap(GetNodegroupInstanceTags(clusterName, group))
+	upstream := aws.StringValueMap(upstreamTags)
+	ownershipTag := fmt.Sprintf(clusterOwnershipTagFormat, clusterName)
+	if _, ok := group.ResourceTags[ownershipTag]; !ok {
+		delete(tags, ownershipTag)
+		delete(upstream, ownershipTag)
+	}
+
+	return !utils.CompareStringMaps(upstream, tags)
+}
+
+// withDefaultTag returns the tags with the given tag added, unless it is already set. The given tags are not modified.
+func withDefaultTag(tags map[string]*string, key, value string) map[string]*string {
+	if _, ok := tags[key]; ok {
+		return tags
+	}
+
+	withTag := make(map[string]*string, len(tags)+1)
+	for k, v := range tags {
+		withTag[k] = v
+	}
+	withTag[key] = aws.String(value)
+
+	return withTag
+}
+
+func getImageRootDeviceName(ctx context.Context, ec2Service services.EC2ServiceInterface, imageID *string) (*string, error) {
 	if imageID == nil {
 		return nil, fmt.Errorf("imageID is nil")
 	}
-	describeOutput, err := ec2Service.DescribeImages(&ec2.DescribeImagesInput{ImageIds: []*string{imageID}})
+	describeOutput, err := ec2Service.DescribeImages(ctx, &ec2.DescribeImagesInput{ImageIds: []*string{imageID}})
 	if err != nil {
 		return nil, err
 	}
@@ -385,7 +933,81 @@ func getImageRootDeviceName(ec2Service services.EC2ServiceInterface, imageID *st
 		return nil, fmt.Errorf("no images returned for id %v", aws.StringValue(imageID))
 	}
 
-	return describeOutput.Images[0].RootDeviceName, nil
+	image := describeOutput.Images[0]
+	if err := checkImageLaunchable(image); err != nil {
+		return nil, err
+	}
+
+	if image.RootDeviceName != nil {
+		return image.RootDeviceName, nil
+	}
+	// some images, such as the ones registered from instance store volumes, have no root device name, the device of
+	// their first block device mapping is the root one then
+	for _, mapping := range image.BlockDeviceMappings {
+		if aws.StringValue(mapping.DeviceName) != "" {
+			return mapping.DeviceName, nil
+		}
+	}
+	logrus.Warnf("image [%s] has no root device name nor block device mappings, using [%s] as root device",
+		aws.StringValue(imageID), defaultStorageDeviceName)
+
+	return nil, nil
+}
+
+// checkImageLaunchable surfaces AMI problems that would otherwise only show up as instances failing to launch.
+// Marketplace AMIs require a subscription that cannot be confirmed through the EC2 API, so those only log a warning.
+func checkImageLaunchable(image *ec2.Image) error {
+	imageID := aws.StringValue(image.ImageId)
+	if state := aws.StringValue(image.State); state != "" && state != ec2.ImageStateAvailable {
+		return fmt.Errorf("image [%s] is in state [%s] and cannot be used to launch nodes", imageID, state)
+	}
+
+	var productCodes []string
+	for _, productCode := range image.ProductCodes {
+		if aws.StringValue(productCode.ProductCodeType) == ec2.ProductCodeValuesMarketplace {
+			productCodes = append(productCodes, aws.StringValue(productCode.ProductCodeId))
+		}
+	}
+	if len(productCodes) != 0 {
+		logrus.Warnf("image [%s] is a marketplace image with product codes [%s], nodes will fail to launch unless the account is subscribed to it",
+			imageID, strings.Join(productCodes, ", "))
+	}
+
+	return nil
+}
+
+// sleepWithContext waits for the given duration and returns the context error if the context is done first.
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
+
+// getLaunchTemplateTags returns the cluster tags along with the tag identifying the launch template managed by the
+// operator, which cannot be overridden by the cluster tags.
+func getLaunchTemplateTags(tags map[string]string) []*ec2.Tag {
+	launchTemplateTags := make([]*ec2.Tag, 0, len(tags)+1)
+	for _, key := range sortedKeys(tags) {
+		if key != launchTemplateTagKey {
+			launchTemplateTags = append(launchTemplateTags, &ec2.Tag{Key: aws.String(key), Value: aws.String(tags[key])})
+		}
+	}
+	return append(launchTemplateTags, &ec2.Tag{Key: aws.String(launchTemplateTagKey), Value: aws.String(launchTemplateTagValue)})
+}
+
+// getStackTags returns the given tags along with the display name tag the operator finds its stacks by, which
+// cannot be overridden by the given tags.
+func getStackTags(tags map[string]string, displayName string) []*cloudformation.Tag {
+	stackTags := make([]*cloudformation.Tag, 0, len(tags)+1)
+	for _, key := range sortedKeys(tags) {
+		if key != stackDisplayNameTagKey {
+			stackTags = append(stackTags, &cloudformation.Tag{Key: aws.String(key), Value: aws.String(tags[key])})
+		}
+	}
+	return append(stackTags, &cloudformation.Tag{Key: aws.String(stackDisplayNameTagKey), Value: aws.String(displayName)})
 }
 
 func getTags(tags map[string]string) map[string]*string {
@@ -396,6 +1018,27 @@ func getTags(tags map[string]string) map[string]*string {
 	return aws.StringMap(tags)
 }
 
+// ValidateLoggingTypes checks that the logging types of the cluster are control plane log types supported by EKS. EKS
+// does not reject unknown types, a misspelled type would silently not be logged.
+func ValidateLoggingTypes(config *eksv1.EKSClusterConfig) error {
+	valid := make(map[string]bool, len(eks.LogType_Values()))
+	for _, logType := range eks.LogType_Values() {
+		valid[logType] = true
+	}
+
+	var invalid []string
+	for _, loggingType := range config.Spec.LoggingTypes {
+		if !valid[loggingType] {
+			invalid = append(invalid, loggingType)
+		}
+	}
+	if len(invalid) != 0 {
+		return fmt.Errorf("invalid logging types %v for cluster [%s], must be one of %v", invalid, config.Name, eks.LogType_Values())
+	}
+
+	return nil
+}
+
 func getLogging(loggingTypes []string) *eks.Logging {
 	if len(loggingTypes) == 0 {
 		return &eks.Logging{
@@ -425,6 +1068,14 @@ func getPublicAccessCidrs(publicAccessCidrs []string) []*string {
 	return aws.StringSlice(publicAccessCidrs)
 }
 
+// getCapacityType returns the EKS capacity type of a node group requesting spot instances or not.
+func getCapacityType(requestSpotInstances *bool) string {
+	if aws.BoolValue(requestSpotInstances) {
+		return eks.CapacityTypesSpot
+	}
+	return eks.CapacityTypesOnDemand
+}
+
 func alreadyExistsInCloudFormationError(err error) bool {
 	if aerr, ok := err.(awserr.Error); ok {
 		switch aerr.Code() {
@@ -436,30 +1087,35 @@ func alreadyExistsInCloudFormationError(err error) bool {
 	return false
 }
 
-func doesNotExist(err error) bool {
-	// There is no better way of doing this because AWS API does not distinguish between a attempt to delete a stack
-	// (or key pair) that does not exist, and, for example, a malformed delete request, so we have to parse the error
-	// message
-	if err != nil {
-		return strings.Contains(err.Error(), "does not exist")
+func alreadyExistsInEKSError(err error) bool {
+	if aerr, ok := err.(awserr.Error); ok {
+		switch aerr.Code() {
+		case eks.ErrCodeResourceInUseException:
+			return true
+		}
 	}
 
 	return false
 }
 
-func getEC2ServiceEndpoint(region string) string {
-	if p, ok := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), region); ok {
-		return fmt.Sprintf("%s.%s", ec2.ServiceName, p.DNSSuffix())
+func notFoundInEKSError(err error) bool {
+	if aerr, ok := err.(awserr.Error); ok {
+		switch aerr.Code() {
+		case eks.ErrCodeResourceNotFoundException:
+			return true
+		}
 	}
-	return "ec2.amazonaws.com"
+
+	return false
 }
 
-func getParameterValueFromOutput(key string, outputs []*cloudformation.Output) string {
-	for _, output := range outputs {
-		if *output.OutputKey == key {
-			return *output.OutputValue
-		}
+func doesNotExist(err error) bool {
+	// There is no better way of doing this because AWS API does not distinguish between a attempt to delete a stack
+	// (or key pair) that does not exist, and, for example, a malformed delete request, so we have to parse the error
+	// message
+	if err != nil {
+		return strings.Contains(err.Error(), "does not exist")
 	}
 
-	return ""
+	return false
 }
@@ -1,20 +1,28 @@
 package eks
 
 import (
+	"context"
+	"encoding/base64"
 	"errors"
-	"fmt"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
 	"github.com/rancher/eks-operator/pkg/eks/services/mock_services"
 	"github.com/rancher/eks-operator/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 var _ = Describe("CreateCluster", func() {
@@ -30,7 +38,11 @@ var _ = Describe("CreateCluster", func() {
 		clustercCreateOptions = &CreateClusterOptions{
 			EKSService: eksServiceMock,
 			RoleARN:    "test",
-			Config:     &eksv1.EKSClusterConfig{},
+			Config: &eksv1.EKSClusterConfig{
+				Spec: eksv1.EKSClusterConfigSpec{
+					Region: "us-west-2",
+				},
+			},
 		}
 	})
 
@@ -39,16 +51,98 @@ var _ = Describe("CreateCluster", func() {
 	})
 
 	It("should successfully create a cluster", func() {
-		eksServiceMock.EXPECT().CreateCluster(gomock.Any()).Return(nil, nil)
-		Expect(CreateCluster(clustercCreateOptions)).To(Succeed())
+		eksServiceMock.EXPECT().CreateCluster(gomock.Any(), gomock.Any()).Return(nil, nil)
+		Expect(CreateCluster(context.Background(), clustercCreateOptions)).To(Succeed())
 	})
 
 	It("should fail to create a cluster", func() {
-		eksServiceMock.EXPECT().CreateCluster(gomock.Any()).Return(nil, errors.New("error creating cluster"))
-		Expect(CreateCluster(clustercCreateOptions)).ToNot(Succeed())
+		eksServiceMock.EXPECT().CreateCluster(gomock.Any(), gomock.Any()).Return(nil, errors.New("error creating cluster"))
+		Expect(CreateCluster(context.Background(), clustercCreateOptions)).ToNot(Succeed())
+	})
+
+	It("should fail to create a cluster with a malformed kubernetes version", func() {
+		clustercCreateOptions.Config.Spec.KubernetesVersion = aws.String("v1.27")
+		Expect(CreateCluster(context.Background(), clustercCreateOptions)).To(MatchError(ContainSubstring("invalid kubernetes version [v1.27]")))
+	})
+
+	It("should create a cluster with valid logging types", func() {
+		clustercCreateOptions.Config.Spec.LoggingTypes = []string{"api", "audit", "authenticator", "controllerManager", "scheduler"}
+		eksServiceMock.EXPECT().CreateCluster(gomock.Any(), gomock.Any()).Return(nil, nil)
+		Expect(CreateCluster(context.Background(), clustercCreateOptions)).To(Succeed())
+	})
+
+	It("should fail to create a cluster with invalid logging types", func() {
+		clustercCreateOptions.Config.Spec.LoggingTypes = []string{"apiserver", "audit"}
+		eksServiceMock.EXPECT().CreateCluster(gomock.Any(), gomock.Any()).Times(0)
+		Expect(CreateCluster(context.Background(), clustercCreateOptions)).To(MatchError(ContainSubstring("invalid logging types [apiserver]")))
+	})
+
+	It("should adopt an existing cluster that matches the config", func() {
+		clustercCreateOptions.Config.Spec.DisplayName = "test"
+		clustercCreateOptions.Config.Spec.KubernetesVersion = aws.String("1.27")
+		clustercCreateOptions.Config.Status.Subnets = []string{"subnet-1", "subnet-2"}
+		eksServiceMock.EXPECT().CreateCluster(gomock.Any(), gomock.Any()).Return(nil, awserr.New(eks.ErrCodeResourceInUseException, "cluster already exists", nil))
+		eksServiceMock.EXPECT().DescribeCluster(gomock.Any(), &eks.DescribeClusterInput{Name: aws.String("test")}).Return(&eks.DescribeClusterOutput{
+			Cluster: &eks.Cluster{
+				Name:    aws.String("test"),
+				RoleArn: aws.String("test"),
+				Version: aws.String("1.27"),
+				ResourcesVpcConfig: &eks.VpcConfigResponse{
+					SubnetIds: aws.StringSlice([]string{"subnet-2", "subnet-1"}),
+				},
+			},
+		}, nil)
+		Expect(CreateCluster(context.Background(), clustercCreateOptions)).To(Succeed())
+	})
+
+	It("should fail to adopt an existing cluster that does not match the config", func() {
+		clustercCreateOptions.Config.Spec.DisplayName = "test"
+		clustercCreateOptions.Config.Spec.KubernetesVersion = aws.String("1.27")
+		clustercCreateOptions.Config.Status.Subnets = []string{"subnet-1", "subnet-2"}
+		eksServiceMock.EXPECT().CreateCluster(gomock.Any(), gomock.Any()).Return(nil, awserr.New(eks.ErrCodeResourceInUseException, "cluster already exists", nil))
+		eksServiceMock.EXPECT().DescribeCluster(gomock.Any(), &eks.DescribeClusterInput{Name: aws.String("test")}).Return(&eks.DescribeClusterOutput{
+			Cluster: &eks.Cluster{
+				Name:    aws.String("test"),
+				RoleArn: aws.String("other"),
+				Version: aws.String("1.26"),
+				ResourcesVpcConfig: &eks.VpcConfigResponse{
+					SubnetIds: aws.StringSlice([]string{"subnet-3"}),
+				},
+			},
+		}, nil)
+		err := CreateCluster(context.Background(), clustercCreateOptions)
+		Expect(err).To(MatchError(ContainSubstring("cluster [test] already exists and does not match the desired config")))
+		Expect(err).To(MatchError(ContainSubstring("role is [other], expected [test]")))
+		Expect(err).To(MatchError(ContainSubstring("kubernetes version is [1.26], expected [1.27]")))
+		Expect(err).To(MatchError(ContainSubstring("subnets are [subnet-3], expected [subnet-1 subnet-2]")))
 	})
 })
 
+var _ = Describe("ValidateLoggingTypes", func() {
+	DescribeTable("should validate the logging types",
+		func(loggingTypes []string, expectedError string) {
+			config := &eksv1.EKSClusterConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: eksv1.EKSClusterConfigSpec{
+					LoggingTypes: loggingTypes,
+				},
+			}
+
+			err := ValidateLoggingTypes(config)
+			if expectedError == "" {
+				Expect(err).ToNot(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(ContainSubstring(expectedError)))
+			}
+		},
+		Entry("no logging types", nil, ""),
+		Entry("supported logging types", []string{"api", "audit", "authenticator"}, ""),
+		Entry("misspelled logging type", []string{"api", "schedular"}, "invalid logging types [schedular] for cluster [test]"),
+	)
+})
+
 var _ = Describe("newClusterInput", func() {
 	var (
 		roleARN string
@@ -64,7 +158,7 @@ var _ = Describe("newClusterInput", func() {
 				PublicAccess:        aws.Bool(true),
 				PublicAccessSources: []string{"test"},
 				Tags:                map[string]string{"test": "test"},
-				LoggingTypes:        []string{"test"},
+				LoggingTypes:        []string{"api"},
 				KubernetesVersion:   aws.String("test"),
 				SecretsEncryption:   aws.Bool(true),
 				KmsKey:              aws.String("test"),
@@ -125,6 +219,15 @@ var _ = Describe("newClusterInput", func() {
 		Expect(clusterInput.Logging.ClusterLogging[0].Types).To(Equal(aws.StringSlice(config.Spec.LoggingTypes)))
 	})
 
+	It("should pass the encryption resources set on the config", func() {
+		config.Spec.EncryptionResources = []string{"secrets", "future-resource"}
+		clusterInput := newClusterInput(config, roleARN)
+		Expect(clusterInput).ToNot(BeNil())
+
+		Expect(clusterInput.EncryptionConfig).To(HaveLen(1))
+		Expect(clusterInput.EncryptionConfig[0].Resources).To(Equal(aws.StringSlice([]string{"secrets", "future-resource"})))
+	})
+
 	It("should successfully create a cluster with no secrets encryption set", func() {
 		config.Spec.SecretsEncryption = aws.Bool(false)
 		clusterInput := newClusterInput(config, roleARN)
@@ -132,6 +235,113 @@ var _ = Describe("newClusterInput", func() {
 
 		Expect(clusterInput.EncryptionConfig).To(BeNil())
 	})
+
+	It("should set the authentication mode set on the config", func() {
+		config.Spec.AccessConfig = &eksv1.AccessConfig{AuthenticationMode: aws.String("API_AND_CONFIG_MAP")}
+		clusterInput := newClusterInput(config, roleARN)
+		Expect(clusterInput).ToNot(BeNil())
+
+		Expect(clusterInput.AccessConfig).To(Equal(&eks.CreateAccessConfigRequest{AuthenticationMode: aws.String("API_AND_CONFIG_MAP")}))
+	})
+
+	It("should leave the authentication mode to EKS if not set", func() {
+		clusterInput := newClusterInput(config, roleARN)
+		Expect(clusterInput).ToNot(BeNil())
+
+		Expect(clusterInput.AccessConfig).To(BeNil())
+	})
+})
+
+var _ = Describe("CreateFargateProfile", func() {
+	var (
+		mockController              *gomock.Controller
+		eksServiceMock              *mock_services.MockEKSServiceInterface
+		createFargateProfileOptions *CreateFargateProfileOptions
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+		createFargateProfileOptions = &CreateFargateProfileOptions{
+			EKSService:   eksServiceMock,
+			PollInterval: time.Millisecond,
+			Config: &eksv1.EKSClusterConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-cluster",
+				},
+				Spec: eksv1.EKSClusterConfigSpec{
+					DisplayName: "test",
+					Tags:        map[string]string{"test": "test"},
+					FargateProfile: &eksv1.FargateProfile{
+						FargateProfileName:  aws.String("test-profile"),
+						PodExecutionRoleArn: aws.String("arn:aws:iam::123456789012:role/test"),
+						Selectors: []eksv1.FargateProfileSelector{
+							{
+								Namespace: aws.String("test"),
+								Labels:    aws.StringMap(map[string]string{"app": "test"}),
+							},
+						},
+					},
+				},
+				Status: eksv1.EKSClusterConfigStatus{
+					Subnets: []string{"subnet-1", "subnet-2"},
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should create a fargate profile", func() {
+		eksServiceMock.EXPECT().CreateFargateProfile(gomock.Any(), &eks.CreateFargateProfileInput{
+			ClusterName:         aws.String("test"),
+			FargateProfileName:  aws.String("test-profile"),
+			PodExecutionRoleArn: aws.String("arn:aws:iam::123456789012:role/test"),
+			Subnets:             aws.StringSlice([]string{"subnet-1", "subnet-2"}),
+			Selectors: []*eks.FargateProfileSelector{
+				{
+					Namespace: aws.String("test"),
+					Labels:    aws.StringMap(map[string]string{"app": "test"}),
+				},
+			},
+			Tags: aws.StringMap(map[string]string{"test": "test"}),
+		}).Return(nil, nil)
+		eksServiceMock.EXPECT().DescribeFargateProfile(gomock.Any(), &eks.DescribeFargateProfileInput{
+			ClusterName:        aws.String("test"),
+			FargateProfileName: aws.String("test-profile"),
+		}).Return(&eks.DescribeFargateProfileOutput{
+			FargateProfile: &eks.FargateProfile{
+				Status: aws.String(eks.FargateProfileStatusActive),
+			},
+		}, nil)
+
+		Expect(CreateFargateProfile(context.Background(), createFargateProfileOptions)).To(Succeed())
+	})
+
+	It("should use the fargate profile subnets if set", func() {
+		createFargateProfileOptions.Config.Spec.FargateProfile.Subnets = []string{"subnet-3"}
+		input := newFargateProfileInput(createFargateProfileOptions.Config)
+		Expect(input.Subnets).To(Equal(aws.StringSlice([]string{"subnet-3"})))
+	})
+
+	It("should fail if the fargate profile fails to create", func() {
+		eksServiceMock.EXPECT().CreateFargateProfile(gomock.Any(), gomock.Any()).Return(nil, nil)
+		eksServiceMock.EXPECT().DescribeFargateProfile(gomock.Any(), gomock.Any()).Return(&eks.DescribeFargateProfileOutput{
+			FargateProfile: &eks.FargateProfile{
+				Status: aws.String(eks.FargateProfileStatusCreateFailed),
+			},
+		}, nil)
+
+		err := CreateFargateProfile(context.Background(), createFargateProfileOptions)
+		Expect(err).To(MatchError(ContainSubstring(eks.FargateProfileStatusCreateFailed)))
+	})
+
+	It("should fail if creating the fargate profile returns an error", func() {
+		eksServiceMock.EXPECT().CreateFargateProfile(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+		Expect(CreateFargateProfile(context.Background(), createFargateProfileOptions)).ToNot(Succeed())
+	})
 })
 
 var _ = Describe("CreateStack", func() {
@@ -151,6 +361,7 @@ var _ = Describe("CreateStack", func() {
 			TemplateBody:          "test-body",
 			Capabilities:          []string{"test"},
 			Parameters:            []*cloudformation.Parameter{{ParameterKey: aws.String("test"), ParameterValue: aws.String("test")}},
+			PollInterval:          time.Millisecond,
 		}
 	})
 
@@ -159,7 +370,7 @@ var _ = Describe("CreateStack", func() {
 	})
 
 	It("should successfully create a stack", func() {
-		cloudFormationsServiceMock.EXPECT().CreateStack(&cloudformation.CreateStackInput{
+		cloudFormationsServiceMock.EXPECT().CreateStack(gomock.Any(), &cloudformation.CreateStackInput{
 			StackName:    &stackCreationOptions.StackName,
 			TemplateBody: &stackCreationOptions.TemplateBody,
 			Capabilities: aws.StringSlice(stackCreationOptions.Capabilities),
@@ -173,6 +384,7 @@ var _ = Describe("CreateStack", func() {
 		}).Return(nil, nil)
 
 		cloudFormationsServiceMock.EXPECT().DescribeStacks(
+			gomock.Any(),
 			&cloudformation.DescribeStacksInput{
 				StackName: &stackCreationOptions.StackName,
 			},
@@ -185,35 +397,74 @@ var _ = Describe("CreateStack", func() {
 				},
 			}, nil)
 
-		describeStacksOutput, err := CreateStack(stackCreationOptions)
+		describeStacksOutput, err := CreateStack(context.Background(), stackCreationOptions)
 		Expect(err).ToNot(HaveOccurred())
 
 		Expect(describeStacksOutput).ToNot(BeNil())
 	})
 
+	It("should tag the stack with the given tags", func() {
+		stackCreationOptions.Tags = map[string]string{"team": "platform", "displayName": "overridden"}
+		cloudFormationsServiceMock.EXPECT().CreateStack(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, input *cloudformation.CreateStackInput) (*cloudformation.CreateStackOutput, error) {
+				Expect(input.Tags).To(Equal([]*cloudformation.Tag{
+					{Key: aws.String("team"), Value: aws.String("platform")},
+					{Key: aws.String("displayName"), Value: aws.String("test")},
+				}))
+				return nil, nil
+			})
+		cloudFormationsServiceMock.EXPECT().DescribeStacks(gomock.Any(), gomock.Any()).Return(&cloudformation.DescribeStacksOutput{
+			Stacks: []*cloudformation.Stack{{StackStatus: aws.String(createCompleteStatus)}},
+		}, nil)
+
+		_, err := CreateStack(context.Background(), stackCreationOptions)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should stop polling the stack if the context is done", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		cloudFormationsServiceMock.EXPECT().CreateStack(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+		_, err := CreateStack(ctx, stackCreationOptions)
+		Expect(err).To(MatchError(context.Canceled))
+	})
+
 	It("should fail to create a stack if CreateStack returns error", func() {
-		cloudFormationsServiceMock.EXPECT().CreateStack(gomock.Any()).Return(nil, errors.New("error"))
+		cloudFormationsServiceMock.EXPECT().CreateStack(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
 
-		_, err := CreateStack(stackCreationOptions)
+		_, err := CreateStack(context.Background(), stackCreationOptions)
 		Expect(err).To(HaveOccurred())
 	})
 
 	It("should fail to create a stack if DescribeStacks returns no stacks", func() {
-		cloudFormationsServiceMock.EXPECT().CreateStack(gomock.Any()).Return(nil, nil)
+		cloudFormationsServiceMock.EXPECT().CreateStack(gomock.Any(), gomock.Any()).Return(nil, nil)
 
 		cloudFormationsServiceMock.EXPECT().DescribeStacks(
+			gomock.Any(),
 			&cloudformation.DescribeStacksInput{
 				StackName: &stackCreationOptions.StackName,
 			},
 		).Return(&cloudformation.DescribeStacksOutput{}, nil)
 
-		_, err := CreateStack(stackCreationOptions)
+		_, err := CreateStack(context.Background(), stackCreationOptions)
 		Expect(err).To(HaveOccurred())
 	})
 
+	It("should fail to create a stack if DescribeStacks returns an empty list of stacks", func() {
+		cloudFormationsServiceMock.EXPECT().CreateStack(gomock.Any(), gomock.Any()).Return(nil, nil)
+		cloudFormationsServiceMock.EXPECT().DescribeStacks(gomock.Any(), gomock.Any()).Return(&cloudformation.DescribeStacksOutput{
+			Stacks: []*cloudformation.Stack{},
+		}, nil)
+
+		var err error
+		Expect(func() { _, err = CreateStack(context.Background(), stackCreationOptions) }).ToNot(Panic())
+		Expect(err).To(MatchError("stack [test] was not returned by DescribeStacks"))
+	})
+
 	It("should fail to create a stack if stack already exists", func() {
-		cloudFormationsServiceMock.EXPECT().CreateStack(gomock.Any()).Return(nil, awserr.New(cloudformation.ErrCodeAlreadyExistsException, "", nil))
-		cloudFormationsServiceMock.EXPECT().DescribeStacks(gomock.Any()).Return(
+		cloudFormationsServiceMock.EXPECT().CreateStack(gomock.Any(), gomock.Any()).Return(nil, awserr.New(cloudformation.ErrCodeAlreadyExistsException, "", nil))
+		cloudFormationsServiceMock.EXPECT().DescribeStacks(gomock.Any(), gomock.Any()).Return(
 			&cloudformation.DescribeStacksOutput{
 				Stacks: []*cloudformation.Stack{
 					{
@@ -222,21 +473,21 @@ var _ = Describe("CreateStack", func() {
 				},
 			}, nil)
 
-		_, err := CreateStack(stackCreationOptions)
+		_, err := CreateStack(context.Background(), stackCreationOptions)
 		Expect(err).ToNot(HaveOccurred())
 	})
 
 	It("should fail to create a stack if DescribeStack return errors", func() {
-		cloudFormationsServiceMock.EXPECT().CreateStack(gomock.Any()).Return(nil, nil)
-		cloudFormationsServiceMock.EXPECT().DescribeStacks(gomock.Any()).Return(nil, errors.New("error"))
+		cloudFormationsServiceMock.EXPECT().CreateStack(gomock.Any(), gomock.Any()).Return(nil, nil)
+		cloudFormationsServiceMock.EXPECT().DescribeStacks(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
 
-		_, err := CreateStack(stackCreationOptions)
+		_, err := CreateStack(context.Background(), stackCreationOptions)
 		Expect(err).To(HaveOccurred())
 	})
 
 	It("should fail to create a stack if stack status is CREATE_FAILED", func() {
-		cloudFormationsServiceMock.EXPECT().CreateStack(gomock.Any()).Return(nil, nil)
-		cloudFormationsServiceMock.EXPECT().DescribeStacks(gomock.Any()).Return(
+		cloudFormationsServiceMock.EXPECT().CreateStack(gomock.Any(), gomock.Any()).Return(nil, nil)
+		cloudFormationsServiceMock.EXPECT().DescribeStacks(gomock.Any(), gomock.Any()).Return(
 			&cloudformation.DescribeStacksOutput{
 				Stacks: []*cloudformation.Stack{
 					{
@@ -245,6 +496,7 @@ var _ = Describe("CreateStack", func() {
 				},
 			}, nil)
 		cloudFormationsServiceMock.EXPECT().DescribeStackEvents(
+			gomock.Any(),
 			&cloudformation.DescribeStackEventsInput{
 				StackName: &stackCreationOptions.StackName,
 			},
@@ -259,14 +511,14 @@ var _ = Describe("CreateStack", func() {
 				},
 			}, nil)
 
-		_, err := CreateStack(stackCreationOptions)
+		_, err := CreateStack(context.Background(), stackCreationOptions)
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(ContainSubstring(createFailedStatus))
 	})
 
 	It("should fail to create a stack if stack status is ROLLBACK_IN_PROGRESS", func() {
-		cloudFormationsServiceMock.EXPECT().CreateStack(gomock.Any()).Return(nil, nil)
-		cloudFormationsServiceMock.EXPECT().DescribeStacks(gomock.Any()).Return(
+		cloudFormationsServiceMock.EXPECT().CreateStack(gomock.Any(), gomock.Any()).Return(nil, nil)
+		cloudFormationsServiceMock.EXPECT().DescribeStacks(gomock.Any(), gomock.Any()).Return(
 			&cloudformation.DescribeStacksOutput{
 				Stacks: []*cloudformation.Stack{
 					{
@@ -275,6 +527,7 @@ var _ = Describe("CreateStack", func() {
 				},
 			}, nil)
 		cloudFormationsServiceMock.EXPECT().DescribeStackEvents(
+			gomock.Any(),
 			&cloudformation.DescribeStackEventsInput{
 				StackName: &stackCreationOptions.StackName,
 			},
@@ -289,17 +542,78 @@ var _ = Describe("CreateStack", func() {
 				},
 			}, nil)
 
-		_, err := CreateStack(stackCreationOptions)
+		_, err := CreateStack(context.Background(), stackCreationOptions)
 		Expect(err).To(HaveOccurred())
 		Expect(err.Error()).To(ContainSubstring(rollbackInProgressStatus))
 	})
+
+	It("should return every failed resource of the stack", func() {
+		cloudFormationsServiceMock.EXPECT().CreateStack(gomock.Any(), gomock.Any()).Return(nil, nil)
+		cloudFormationsServiceMock.EXPECT().DescribeStacks(gomock.Any(), gomock.Any()).Return(
+			&cloudformation.DescribeStacksOutput{
+				Stacks: []*cloudformation.Stack{
+					{
+						StackStatus: aws.String(rollbackInProgressStatus),
+					},
+				},
+			}, nil)
+		cloudFormationsServiceMock.EXPECT().DescribeStackEvents(gomock.Any(), gomock.Any()).Return(
+			&cloudformation.DescribeStackEventsOutput{
+				StackEvents: []*cloudformation.StackEvent{
+					{
+						ResourceStatus:       aws.String(rollbackInProgressStatus),
+						ResourceStatusReason: aws.String("The following resource(s) failed to create: [VPC, NodeInstanceRole]."),
+						LogicalResourceId:    aws.String("test"),
+					},
+					{
+						ResourceStatus:       aws.String(createFailedStatus),
+						ResourceStatusReason: aws.String("Resource creation cancelled"),
+						LogicalResourceId:    aws.String("VPC"),
+					},
+					{
+						ResourceStatus:       aws.String("CREATE_IN_PROGRESS"),
+						ResourceStatusReason: aws.String("Resource creation Initiated"),
+						LogicalResourceId:    aws.String("InternetGateway"),
+					},
+					{
+						ResourceStatus:       aws.String(createFailedStatus),
+						ResourceStatusReason: aws.String("API: iam:CreateRole User is not authorized"),
+						LogicalResourceId:    aws.String("NodeInstanceRole"),
+					},
+				},
+			}, nil)
+
+		_, err := CreateStack(context.Background(), stackCreationOptions)
+		var stackErr *StackCreationError
+		Expect(errors.As(err, &stackErr)).To(BeTrue())
+		Expect(stackErr.StackName).To(Equal("test"))
+		Expect(stackErr.Status).To(Equal(rollbackInProgressStatus))
+		Expect(stackErr.Failures).To(Equal([]StackResourceFailure{
+			{
+				LogicalResourceID: "test",
+				Status:            rollbackInProgressStatus,
+				Reason:            "The following resource(s) failed to create: [VPC, NodeInstanceRole].",
+			},
+			{
+				LogicalResourceID: "VPC",
+				Status:            createFailedStatus,
+				Reason:            "Resource creation cancelled",
+			},
+			{
+				LogicalResourceID: "NodeInstanceRole",
+				Status:            createFailedStatus,
+				Reason:            "API: iam:CreateRole User is not authorized",
+			},
+		}))
+		Expect(err.Error()).To(ContainSubstring("NodeInstanceRole [CREATE_FAILED]: API: iam:CreateRole User is not authorized"))
+	})
 })
 
 var _ = Describe("createLaunchTemplate", func() {
 	var (
 		mockController     *gomock.Controller
 		ec2ServiceMock     *mock_services.MockEC2ServiceInterface
-		clusterDisplayName = "testName"
+		launchTemplateName = "rancher-managed-lt-testName"
 	)
 
 	BeforeEach(func() {
@@ -320,9 +634,10 @@ var _ = Describe("createLaunchTemplate", func() {
 			},
 		}
 		ec2ServiceMock.EXPECT().CreateLaunchTemplate(
+			gomock.Any(),
 			&ec2.CreateLaunchTemplateInput{
 				LaunchTemplateData: &ec2.RequestLaunchTemplateData{UserData: aws.String("cGxhY2Vob2xkZXIK")},
-				LaunchTemplateName: aws.String(fmt.Sprintf(LaunchTemplateNameFormat, clusterDisplayName)),
+				LaunchTemplateName: aws.String(launchTemplateName),
 				TagSpecifications: []*ec2.TagSpecification{
 					{
 						ResourceType: aws.String(ec2.ResourceTypeLaunchTemplate),
@@ -336,7 +651,7 @@ var _ = Describe("createLaunchTemplate", func() {
 				},
 			},
 		).Return(expectedOutput, nil)
-		launchTemplate, err := createLaunchTemplate(ec2ServiceMock, clusterDisplayName)
+		launchTemplate, err := createLaunchTemplate(context.Background(), ec2ServiceMock, launchTemplateName, nil)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(launchTemplate).ToNot(BeNil())
 
@@ -345,18 +660,79 @@ var _ = Describe("createLaunchTemplate", func() {
 		Expect(launchTemplate.Version).To(Equal(expectedOutput.LaunchTemplate.LatestVersionNumber))
 	})
 
+	It("should tag the launch template with the cluster tags", func() {
+		ec2ServiceMock.EXPECT().CreateLaunchTemplate(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, input *ec2.CreateLaunchTemplateInput) (*ec2.CreateLaunchTemplateOutput, error) {
+				Expect(input.TagSpecifications).To(Equal([]*ec2.TagSpecification{
+					{
+						ResourceType: aws.String(ec2.ResourceTypeLaunchTemplate),
+						Tags: []*ec2.Tag{
+							{Key: aws.String("owner"), Value: aws.String("team")},
+							{Key: aws.String("team"), Value: aws.String("platform")},
+							{Key: aws.String(launchTemplateTagKey), Value: aws.String(launchTemplateTagValue)},
+						},
+					},
+				}))
+				return &ec2.CreateLaunchTemplateOutput{LaunchTemplate: &ec2.LaunchTemplate{}}, nil
+			})
+
+		_, err := createLaunchTemplate(context.Background(), ec2ServiceMock, launchTemplateName, map[string]string{
+			"team":               "platform",
+			"owner":              "team",
+			launchTemplateTagKey: "overridden",
+		})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
 	It("should fail to create a launch template", func() {
-		ec2ServiceMock.EXPECT().CreateLaunchTemplate(gomock.Any()).Return(nil, errors.New("error"))
-		_, err := createLaunchTemplate(ec2ServiceMock, clusterDisplayName)
+		ec2ServiceMock.EXPECT().CreateLaunchTemplate(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+		_, err := createLaunchTemplate(context.Background(), ec2ServiceMock, launchTemplateName, nil)
 		Expect(err).To(HaveOccurred())
 	})
 })
 
+var _ = Describe("GetManagedLaunchTemplateName", func() {
+	var config *eksv1.EKSClusterConfig
+
+	BeforeEach(func() {
+		config = &eksv1.EKSClusterConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test",
+			},
+			Spec: eksv1.EKSClusterConfigSpec{
+				DisplayName: "my-cluster",
+			},
+		}
+	})
+
+	It("should default to the rancher-managed prefix", func() {
+		Expect(GetManagedLaunchTemplateName(config)).To(Equal("rancher-managed-lt-my-cluster"))
+		config.Spec.LaunchTemplatePrefix = aws.String("")
+		Expect(GetManagedLaunchTemplateName(config)).To(Equal("rancher-managed-lt-my-cluster"))
+		Expect(ValidateLaunchTemplatePrefix(config)).To(Succeed())
+	})
+
+	It("should use the launch template prefix", func() {
+		config.Spec.LaunchTemplatePrefix = aws.String("fleet-a/")
+		Expect(GetManagedLaunchTemplateName(config)).To(Equal("fleet-a/my-cluster"))
+		Expect(ValidateLaunchTemplatePrefix(config)).To(Succeed())
+	})
+
+	It("should refuse a prefix generating an invalid name", func() {
+		config.Spec.LaunchTemplatePrefix = aws.String("fleet a:")
+		Expect(ValidateLaunchTemplatePrefix(config)).To(MatchError(ContainSubstring("can only contain")))
+
+		config.Spec.LaunchTemplatePrefix = aws.String(strings.Repeat("a", 120))
+		Expect(ValidateLaunchTemplatePrefix(config)).To(MatchError(ContainSubstring("longer than 128 characters")))
+	})
+})
+
 var _ = Describe("CreateLaunchTemplate", func() {
 	var (
 		mockController           *gomock.Controller
 		ec2ServiceMock           *mock_services.MockEC2ServiceInterface
 		createLaunchTemplateOpts *CreateLaunchTemplateOptions
+		lookupInput              *ec2.DescribeLaunchTemplatesInput
 	)
 
 	BeforeEach(func() {
@@ -373,6 +749,18 @@ var _ = Describe("CreateLaunchTemplate", func() {
 				},
 			},
 		}
+		lookupInput = &ec2.DescribeLaunchTemplatesInput{
+			Filters: []*ec2.Filter{
+				{
+					Name:   aws.String("tag:rancher-managed-template"),
+					Values: aws.StringSlice([]string{"do-not-modify-or-delete"}),
+				},
+				{
+					Name:   aws.String("launch-template-name"),
+					Values: aws.StringSlice([]string{"rancher-managed-lt-test"}),
+				},
+			},
+		}
 	})
 
 	AfterEach(func() {
@@ -381,7 +769,8 @@ var _ = Describe("CreateLaunchTemplate", func() {
 
 	It("should create a launch template if managed launch template ID is not set", func() {
 		createLaunchTemplateOpts.Config.Status.ManagedLaunchTemplateID = ""
-		ec2ServiceMock.EXPECT().CreateLaunchTemplate(gomock.Any()).Return(&ec2.CreateLaunchTemplateOutput{
+		ec2ServiceMock.EXPECT().DescribeLaunchTemplates(gomock.Any(), lookupInput).Return(&ec2.DescribeLaunchTemplatesOutput{}, nil)
+		ec2ServiceMock.EXPECT().CreateLaunchTemplate(gomock.Any(), gomock.Any()).Return(&ec2.CreateLaunchTemplateOutput{
 			LaunchTemplate: &ec2.LaunchTemplate{
 				LaunchTemplateName:   aws.String("testName"),
 				LaunchTemplateId:     aws.String("testID"),
@@ -389,57 +778,88 @@ var _ = Describe("CreateLaunchTemplate", func() {
 			},
 		}, nil)
 
-		ec2ServiceMock.EXPECT().DescribeLaunchTemplates(
-			&ec2.DescribeLaunchTemplatesInput{
-				LaunchTemplateIds: []*string{aws.String(createLaunchTemplateOpts.Config.Status.ManagedLaunchTemplateID)},
-			},
-		).Return(nil, nil)
-
-		Expect(CreateLaunchTemplate(createLaunchTemplateOpts)).To(Succeed())
+		Expect(CreateLaunchTemplate(context.Background(), createLaunchTemplateOpts)).To(Succeed())
 		Expect(createLaunchTemplateOpts.Config.Status.ManagedLaunchTemplateID).To(Equal("testID"))
 	})
 
-	It("should create a launch template if managed launch template doesn't exist", func() {
-		ec2ServiceMock.EXPECT().CreateLaunchTemplate(gomock.Any()).Return(&ec2.CreateLaunchTemplateOutput{
-			LaunchTemplate: &ec2.LaunchTemplate{
-				LaunchTemplateName:   aws.String("testName"),
-				LaunchTemplateId:     aws.String("testID"),
-				DefaultVersionNumber: aws.Int64(1),
+	It("should adopt an existing managed launch template if managed launch template ID is not set", func() {
+		createLaunchTemplateOpts.Config.Status.ManagedLaunchTemplateID = ""
+		ec2ServiceMock.EXPECT().DescribeLaunchTemplates(gomock.Any(), lookupInput).Return(&ec2.DescribeLaunchTemplatesOutput{
+			LaunchTemplates: []*ec2.LaunchTemplate{
+				{
+					LaunchTemplateName: aws.String("rancher-managed-lt-test"),
+					LaunchTemplateId:   aws.String("existingID"),
+				},
 			},
 		}, nil)
 
+		Expect(CreateLaunchTemplate(context.Background(), createLaunchTemplateOpts)).To(Succeed())
+		Expect(createLaunchTemplateOpts.Config.Status.ManagedLaunchTemplateID).To(Equal("existingID"))
+	})
+
+	It("should name the launch template with the launch template prefix", func() {
+		createLaunchTemplateOpts.Config.Status.ManagedLaunchTemplateID = ""
+		createLaunchTemplateOpts.Config.Spec.LaunchTemplatePrefix = aws.String("team-a-")
+		lookupInput.Filters[1].Values = aws.StringSlice([]string{"team-a-test"})
+		ec2ServiceMock.EXPECT().DescribeLaunchTemplates(gomock.Any(), lookupInput).Return(&ec2.DescribeLaunchTemplatesOutput{}, nil)
+		ec2ServiceMock.EXPECT().CreateLaunchTemplate(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, input *ec2.CreateLaunchTemplateInput) (*ec2.CreateLaunchTemplateOutput, error) {
+				Expect(input.LaunchTemplateName).To(Equal(aws.String("team-a-test")))
+				return &ec2.CreateLaunchTemplateOutput{LaunchTemplate: &ec2.LaunchTemplate{LaunchTemplateId: aws.String("testID")}}, nil
+			})
+
+		Expect(CreateLaunchTemplate(context.Background(), createLaunchTemplateOpts)).To(Succeed())
+	})
+
+	It("should create a launch template if managed launch template doesn't exist", func() {
 		ec2ServiceMock.EXPECT().DescribeLaunchTemplates(
+			gomock.Any(),
 			&ec2.DescribeLaunchTemplatesInput{
 				LaunchTemplateIds: []*string{aws.String(createLaunchTemplateOpts.Config.Status.ManagedLaunchTemplateID)},
 			},
 		).Return(nil, errors.New("does not exist"))
+		ec2ServiceMock.EXPECT().DescribeLaunchTemplates(gomock.Any(), lookupInput).Return(&ec2.DescribeLaunchTemplatesOutput{}, nil)
+		ec2ServiceMock.EXPECT().CreateLaunchTemplate(gomock.Any(), gomock.Any()).Return(&ec2.CreateLaunchTemplateOutput{
+			LaunchTemplate: &ec2.LaunchTemplate{
+				LaunchTemplateName:   aws.String("testName"),
+				LaunchTemplateId:     aws.String("testID"),
+				DefaultVersionNumber: aws.Int64(1),
+			},
+		}, nil)
 
-		Expect(CreateLaunchTemplate(createLaunchTemplateOpts)).To(Succeed())
+		Expect(CreateLaunchTemplate(context.Background(), createLaunchTemplateOpts)).To(Succeed())
 		Expect(createLaunchTemplateOpts.Config.Status.ManagedLaunchTemplateID).To(Equal("testID"))
 	})
 
 	It("should not create a launch template if managed launch template exists", func() {
 		ec2ServiceMock.EXPECT().DescribeLaunchTemplates(
+			gomock.Any(),
 			&ec2.DescribeLaunchTemplatesInput{
 				LaunchTemplateIds: []*string{aws.String(createLaunchTemplateOpts.Config.Status.ManagedLaunchTemplateID)},
 			},
 		).Return(nil, nil)
 
-		Expect(CreateLaunchTemplate(createLaunchTemplateOpts)).To(Succeed())
+		Expect(CreateLaunchTemplate(context.Background(), createLaunchTemplateOpts)).To(Succeed())
 	})
 
 	It("should fail to create a launch template if DescribeLaunchTemplates returns error", func() {
-		ec2ServiceMock.EXPECT().DescribeLaunchTemplates(gomock.Any()).Return(nil, errors.New("error"))
-		Expect(CreateLaunchTemplate(createLaunchTemplateOpts)).ToNot(Succeed())
+		ec2ServiceMock.EXPECT().DescribeLaunchTemplates(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+		Expect(CreateLaunchTemplate(context.Background(), createLaunchTemplateOpts)).ToNot(Succeed())
+	})
+
+	It("should fail to create a launch template if looking up an existing launch template returns error", func() {
+		createLaunchTemplateOpts.Config.Status.ManagedLaunchTemplateID = ""
+		ec2ServiceMock.EXPECT().DescribeLaunchTemplates(gomock.Any(), lookupInput).Return(nil, errors.New("error"))
+		Expect(CreateLaunchTemplate(context.Background(), createLaunchTemplateOpts)).ToNot(Succeed())
 	})
 
 	It("should fail to create a launch template if CreateLaunchTemplate return error", func() {
 		createLaunchTemplateOpts.Config.Status.ManagedLaunchTemplateID = ""
-		ec2ServiceMock.EXPECT().DescribeLaunchTemplates(gomock.Any()).Return(nil, nil)
+		ec2ServiceMock.EXPECT().DescribeLaunchTemplates(gomock.Any(), gomock.Any()).Return(&ec2.DescribeLaunchTemplatesOutput{}, nil)
 
-		ec2ServiceMock.EXPECT().CreateLaunchTemplate(gomock.Any()).Return(nil, errors.New("error"))
+		ec2ServiceMock.EXPECT().CreateLaunchTemplate(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
 
-		Expect(CreateLaunchTemplate(createLaunchTemplateOpts)).ToNot(Succeed())
+		Expect(CreateLaunchTemplate(context.Background(), createLaunchTemplateOpts)).ToNot(Succeed())
 	})
 })
 
@@ -462,6 +882,7 @@ var _ = Describe("getImageRootDeviceName", func() {
 	It("should get the root device name", func() {
 		exptectedRootDeviceName := "test-root-device-name"
 		ec2ServiceMock.EXPECT().DescribeImages(
+			gomock.Any(),
 			&ec2.DescribeImagesInput{
 				ImageIds: []*string{&imageID},
 			},
@@ -475,20 +896,108 @@ var _ = Describe("getImageRootDeviceName", func() {
 			},
 			nil)
 
-		rootDeviceName, err := getImageRootDeviceName(ec2ServiceMock, &imageID)
+		rootDeviceName, err := getImageRootDeviceName(context.Background(), ec2ServiceMock, &imageID)
 		Expect(err).ToNot(HaveOccurred())
 
 		Expect(rootDeviceName).To(Equal(&exptectedRootDeviceName))
 	})
 
+	It("should get the root device name of a marketplace image and warn about its subscription", func() {
+		hook := test.NewLocal(logrus.StandardLogger())
+		defer logrus.StandardLogger().ReplaceHooks(make(logrus.LevelHooks))
+		ec2ServiceMock.EXPECT().DescribeImages(gomock.Any(), gomock.Any()).Return(
+			&ec2.DescribeImagesOutput{
+				Images: []*ec2.Image{
+					{
+						ImageId:        &imageID,
+						State:          aws.String(ec2.ImageStateAvailable),
+						RootDeviceName: aws.String("test-root-device-name"),
+						ProductCodes: []*ec2.ProductCode{
+							{
+								ProductCodeId:   aws.String("test-product-code"),
+								ProductCodeType: aws.String(ec2.ProductCodeValuesMarketplace),
+							},
+						},
+					},
+				},
+			},
+			nil)
+
+		rootDeviceName, err := getImageRootDeviceName(context.Background(), ec2ServiceMock, &imageID)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rootDeviceName).To(Equal(aws.String("test-root-device-name")))
+
+		Expect(hook.Entries).To(HaveLen(1))
+		Expect(hook.LastEntry().Level).To(Equal(logrus.WarnLevel))
+		Expect(hook.LastEntry().Message).To(ContainSubstring("image [test-image-id] is a marketplace image with product codes [test-product-code]"))
+	})
+
+	It("should get the device name of the first block device mapping if the image has no root device name", func() {
+		ec2ServiceMock.EXPECT().DescribeImages(gomock.Any(), gomock.Any()).Return(
+			&ec2.DescribeImagesOutput{
+				Images: []*ec2.Image{
+					{
+						ImageId: &imageID,
+						BlockDeviceMappings: []*ec2.BlockDeviceMapping{
+							{
+								DeviceName:  aws.String("/dev/sda1"),
+								VirtualName: aws.String("ephemeral0"),
+							},
+							{
+								DeviceName: aws.String("/dev/sdb"),
+							},
+						},
+					},
+				},
+			},
+			nil)
+
+		rootDeviceName, err := getImageRootDeviceName(context.Background(), ec2ServiceMock, &imageID)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rootDeviceName).To(Equal(aws.String("/dev/sda1")))
+	})
+
+	It("should return no root device name if the image has neither a root device name nor block device mappings", func() {
+		ec2ServiceMock.EXPECT().DescribeImages(gomock.Any(), gomock.Any()).Return(
+			&ec2.DescribeImagesOutput{
+				Images: []*ec2.Image{
+					{
+						ImageId: &imageID,
+					},
+				},
+			},
+			nil)
+
+		rootDeviceName, err := getImageRootDeviceName(context.Background(), ec2ServiceMock, &imageID)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rootDeviceName).To(BeNil())
+	})
+
+	It("should fail to get the root device name if the image is not available", func() {
+		ec2ServiceMock.EXPECT().DescribeImages(gomock.Any(), gomock.Any()).Return(
+			&ec2.DescribeImagesOutput{
+				Images: []*ec2.Image{
+					{
+						ImageId:        &imageID,
+						State:          aws.String(ec2.ImageStateDeregistered),
+						RootDeviceName: aws.String("test-root-device-name"),
+					},
+				},
+			},
+			nil)
+
+		_, err := getImageRootDeviceName(context.Background(), ec2ServiceMock, &imageID)
+		Expect(err).To(HaveOccurred())
+	})
+
 	It("should fail to get the root device name if image is nil", func() {
-		_, err := getImageRootDeviceName(ec2ServiceMock, nil)
+		_, err := getImageRootDeviceName(context.Background(), ec2ServiceMock, nil)
 		Expect(err).To(HaveOccurred())
 	})
 
 	It("should fail to get the root device name if error is return by ec2", func() {
-		ec2ServiceMock.EXPECT().DescribeImages(gomock.Any()).Return(nil, errors.New("error"))
-		_, err := getImageRootDeviceName(ec2ServiceMock, &imageID)
+		ec2ServiceMock.EXPECT().DescribeImages(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+		_, err := getImageRootDeviceName(context.Background(), ec2ServiceMock, &imageID)
 		Expect(err).To(HaveOccurred())
 	})
 })
@@ -497,12 +1006,16 @@ var _ = Describe("buildLaunchTemplateData", func() {
 	var (
 		mockController *gomock.Controller
 		ec2ServiceMock *mock_services.MockEC2ServiceInterface
+		config         *eksv1.EKSClusterConfig
 		group          *eksv1.NodeGroup
 	)
 
 	BeforeEach(func() {
 		mockController = gomock.NewController(GinkgoT())
 		ec2ServiceMock = mock_services.NewMockEC2ServiceInterface(mockController)
+		config = &eksv1.EKSClusterConfig{
+			Spec: eksv1.EKSClusterConfigSpec{DisplayName: "test"},
+		}
 		group = &eksv1.NodeGroup{
 			ImageID:      aws.String("test-ami"),
 			UserData:     aws.String("Content-Type: multipart/mixed ..."),
@@ -520,6 +1033,7 @@ var _ = Describe("buildLaunchTemplateData", func() {
 	It("should build a launch template data", func() {
 		exptectedRootDeviceName := "test-root-device-name"
 		ec2ServiceMock.EXPECT().DescribeImages(
+			gomock.Any(),
 			&ec2.DescribeImagesInput{
 				ImageIds: []*string{group.ImageID},
 			},
@@ -533,37 +1047,233 @@ var _ = Describe("buildLaunchTemplateData", func() {
 			},
 			nil)
 
-		launchTemplateData, err := buildLaunchTemplateData(ec2ServiceMock, *group)
+		launchTemplateData, err := buildLaunchTemplateData(context.Background(), ec2ServiceMock, config, *group)
 		Expect(err).ToNot(HaveOccurred())
 
 		Expect(launchTemplateData).ToNot(BeNil())
 		Expect(launchTemplateData.ImageId).To(Equal(group.ImageID))
 		Expect(launchTemplateData.KeyName).To(Equal(group.Ec2SshKey))
-		Expect(launchTemplateData.UserData).To(Equal(group.UserData))
+		Expect(launchTemplateData.UserData).To(Equal(aws.String(base64.StdEncoding.EncodeToString([]byte(aws.StringValue(group.UserData))))))
 		Expect(launchTemplateData.BlockDeviceMappings).To(HaveLen(1))
 		Expect(launchTemplateData.BlockDeviceMappings[0].DeviceName).To(Equal(&exptectedRootDeviceName))
 		Expect(launchTemplateData.BlockDeviceMappings[0].Ebs.VolumeSize).To(Equal(group.DiskSize))
-		Expect(launchTemplateData.TagSpecifications).To(Equal(utils.CreateTagSpecs(group.ResourceTags)))
+		Expect(launchTemplateData.TagSpecifications).To(Equal(utils.CreateTagSpecs(aws.StringMap(map[string]string{
+			"test":                       "test",
+			"kubernetes.io/cluster/test": "owned",
+		}))))
 		Expect(launchTemplateData.InstanceType).To(Equal(group.InstanceType))
 	})
 
-	It("should fail to build a launch template data if userdata is invalid", func() {
-		group.UserData = aws.String("invalid-user-data")
-		_, err := buildLaunchTemplateData(ec2ServiceMock, *group)
-		Expect(err).To(HaveOccurred())
-	})
+	It("should apply the disk settings to the data volume of bottlerocket node groups", func() {
+		group.ImageID = nil
+		group.AMIFamily = aws.String(AMIFamilyBottlerocket)
+		group.UserData = aws.String("[settings.kubernetes]")
 
-	It("should fail to build a launch template data if error is return by ec2", func() {
-		ec2ServiceMock.EXPECT().DescribeImages(gomock.Any()).Return(nil, errors.New("error"))
-		_, err := buildLaunchTemplateData(ec2ServiceMock, *group)
-		Expect(err).To(HaveOccurred())
+		launchTemplateData, err := buildLaunchTemplateData(context.Background(), ec2ServiceMock, config, *group)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(launchTemplateData.UserData).To(Equal(aws.String(base64.StdEncoding.EncodeToString([]byte("[settings.kubernetes]")))))
+		Expect(launchTemplateData.BlockDeviceMappings).To(HaveLen(1))
+		Expect(launchTemplateData.BlockDeviceMappings[0].DeviceName).To(Equal(aws.String("/dev/xvdb")))
+		Expect(launchTemplateData.BlockDeviceMappings[0].Ebs.VolumeSize).To(Equal(group.DiskSize))
 	})
-})
 
-var _ = Describe("createNewLaunchTemplateVersion", func() {
-	var (
-		mockController *gomock.Controller
+	It("should apply the disk settings to the root volume of windows node groups", func() {
+		group.ImageID = nil
+		group.AMIFamily = aws.String(AMIFamilyWindowsFull2022)
+		group.UserData = aws.String("<powershell>Write-Output ready</powershell>")
+
+		launchTemplateData, err := buildLaunchTemplateData(context.Background(), ec2ServiceMock, config, *group)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(launchTemplateData.UserData).To(Equal(aws.String(base64.StdEncoding.EncodeToString([]byte("<powershell>Write-Output ready</powershell>")))))
+		Expect(launchTemplateData.BlockDeviceMappings).To(HaveLen(1))
+		Expect(launchTemplateData.BlockDeviceMappings[0].DeviceName).To(Equal(aws.String("/dev/sda1")))
+	})
+
+	It("should tag the node volumes with the volume tags", func() {
+		group.ImageID = nil
+		group.VolumeTags = aws.StringMap(map[string]string{"backup": "daily"})
+
+		launchTemplateData, err := buildLaunchTemplateData(context.Background(), ec2ServiceMock, config, *group)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(launchTemplateData.TagSpecifications).To(HaveLen(2))
+		Expect(launchTemplateData.TagSpecifications).To(ContainElement(&ec2.LaunchTemplateTagSpecificationRequest{
+			ResourceType: aws.String(ec2.ResourceTypeInstance),
+			Tags: []*ec2.Tag{
+				{Key: aws.String("kubernetes.io/cluster/test"), Value: aws.String("owned")},
+				{Key: aws.String("test"), Value: aws.String("test")},
+			},
+		}))
+		Expect(launchTemplateData.TagSpecifications).To(ContainElement(&ec2.LaunchTemplateTagSpecificationRequest{
+			ResourceType: aws.String(ec2.ResourceTypeVolume),
+			Tags:         []*ec2.Tag{{Key: aws.String("backup"), Value: aws.String("daily")}},
+		}))
+	})
+
+	It("should tag the instances with the cluster ownership tag", func() {
+		group.ImageID = nil
+		group.ResourceTags = nil
+
+		config.Spec.DisplayName = "my-cluster"
+
+		launchTemplateData, err := buildLaunchTemplateData(context.Background(), ec2ServiceMock, config, *group)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(launchTemplateData.TagSpecifications).To(Equal([]*ec2.LaunchTemplateTagSpecificationRequest{
+			{
+				ResourceType: aws.String(ec2.ResourceTypeInstance),
+				Tags:         []*ec2.Tag{{Key: aws.String("kubernetes.io/cluster/my-cluster"), Value: aws.String("owned")}},
+			},
+		}))
+	})
+
+	It("should not override an ownership tag set in the resource tags", func() {
+		group.ResourceTags = aws.StringMap(map[string]string{"kubernetes.io/cluster/test": "shared"})
+
+		Expect(GetNodegroupInstanceTags("test", *group)).To(Equal(group.ResourceTags))
+	})
+
+	It("should not add the ownership tag if it is disabled", func() {
+		group.DisableClusterOwnershipTag = aws.Bool(true)
+
+		Expect(GetNodegroupInstanceTags("test", *group)).To(Equal(group.ResourceTags))
+	})
+
+	It("should leave the default ownership tag out of the instance tag comparison", func() {
+		Expect(NodegroupInstanceTagsChanged("test", group.ResourceTags, *group)).To(BeFalse())
+
+		upstreamTags := aws.StringMap(map[string]string{"test": "test", "kubernetes.io/cluster/test": "owned"})
+		group.DisableClusterOwnershipTag = aws.Bool(true)
+		Expect(NodegroupInstanceTagsChanged("test", upstreamTags, *group)).To(BeFalse())
+
+		upstreamTags["test"] = aws.String("changed")
+		Expect(NodegroupInstanceTagsChanged("test", upstreamTags, *group)).To(BeTrue())
+	})
+
+	It("should compare an ownership tag set in the resource tags", func() {
+		group.ResourceTags = aws.StringMap(map[string]string{"kubernetes.io/cluster/test": "shared"})
+
+		Expect(NodegroupInstanceTagsChanged("test", nil, *group)).To(BeTrue())
+		Expect(NodegroupInstanceTagsChanged("test", group.ResourceTags, *group)).To(BeFalse())
+	})
+
+	It("should not tag the instances with the availability zone of the node group", func() {
+		// the kubelet labels the nodes with their zone, a tag on the instances is not used for scheduling
+		group.AvailabilityZone = aws.String("us-west-2a")
+
+		tags := GetNodegroupInstanceTags("test", *group)
+		Expect(tags).ToNot(HaveKey("topology.kubernetes.io/zone"))
+		Expect(tags).To(HaveKeyWithValue("kubernetes.io/cluster/test", aws.String("owned")))
+	})
+
+	DescribeTable("should configure the root volume",
+		func(diskType *string, iops, throughput *int64, expected *ec2.LaunchTemplateEbsBlockDeviceRequest) {
+			group.ImageID = nil
+			group.DiskType = diskType
+			group.Iops = iops
+			group.Throughput = throughput
+
+			launchTemplateData, err := buildLaunchTemplateData(context.Background(), ec2ServiceMock, config, *group)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(launchTemplateData.BlockDeviceMappings).To(HaveLen(1))
+			Expect(launchTemplateData.BlockDeviceMappings[0].Ebs).To(Equal(expected))
+		},
+		Entry("disk size only", nil, nil, nil,
+			&ec2.LaunchTemplateEbsBlockDeviceRequest{VolumeSize: aws.Int64(20)}),
+		Entry("disk type", aws.String(ec2.VolumeTypeIo2), aws.Int64(5000), nil,
+			&ec2.LaunchTemplateEbsBlockDeviceRequest{VolumeSize: aws.Int64(20), VolumeType: aws.String(ec2.VolumeTypeIo2), Iops: aws.Int64(5000)}),
+		Entry("throughput defaults to gp3", nil, aws.Int64(4000), aws.Int64(250),
+			&ec2.LaunchTemplateEbsBlockDeviceRequest{VolumeSize: aws.Int64(20), VolumeType: aws.String(ec2.VolumeTypeGp3), Iops: aws.Int64(4000), Throughput: aws.Int64(250)}),
+		Entry("zero values are omitted", aws.String(""), aws.Int64(0), aws.Int64(0),
+			&ec2.LaunchTemplateEbsBlockDeviceRequest{VolumeSize: aws.Int64(20)}),
+	)
+
+	It("should tag the instances for the node termination handler", func() {
+		group.RequestSpotInstances = aws.Bool(true)
+		group.NodeTerminationHandler = aws.Bool(true)
+
+		Expect(GetNodegroupInstanceTags("test", *group)).To(Equal(aws.StringMap(map[string]string{
+			"test":                                 "test",
+			"kubernetes.io/cluster/test":           "owned",
+			"aws-node-termination-handler/managed": "true",
+		})))
+	})
+
+	It("should set the security groups of the node group along with the cluster security group", func() {
+		group.ImageID = nil
+		group.UserData = nil
+		group.NodeGroupSecurityGroups = []string{"sg-extra"}
+		config.Status.ClusterSecurityGroupID = "sg-cluster"
+
+		launchTemplateData, err := buildLaunchTemplateData(context.Background(), ec2ServiceMock, config, *group)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(launchTemplateData.SecurityGroupIds).To(Equal(aws.StringSlice([]string{"sg-extra", "sg-cluster"})))
+		Expect(launchTemplateData.NetworkInterfaces).To(BeNil())
+
+		group.NodeGroupSecurityGroups = []string{"sg-cluster", "sg-extra"}
+
+		launchTemplateData, err = buildLaunchTemplateData(context.Background(), ec2ServiceMock, config, *group)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(launchTemplateData.SecurityGroupIds).To(Equal(aws.StringSlice([]string{"sg-cluster", "sg-extra"})))
+
+		group.NodeGroupSecurityGroups = []string{}
+
+		launchTemplateData, err = buildLaunchTemplateData(context.Background(), ec2ServiceMock, config, *group)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(launchTemplateData.SecurityGroupIds).To(BeNil())
+	})
+
+	It("should not set an instance profile", func() {
+		group.ImageID = nil
+		group.UserData = nil
+		group.IamInstanceProfile = aws.String("test-profile")
+
+		launchTemplateData, err := buildLaunchTemplateData(context.Background(), ec2ServiceMock, config, *group)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(launchTemplateData.IamInstanceProfile).To(BeNil())
+	})
+
+	It("should fail to build a launch template data if userdata is invalid", func() {
+		group.UserData = aws.String("invalid-user-data")
+		_, err := buildLaunchTemplateData(context.Background(), ec2ServiceMock, config, *group)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should render the user data from the bootstrap fields", func() {
+		ec2ServiceMock.EXPECT().DescribeImages(gomock.Any(), gomock.Any()).Return(&ec2.DescribeImagesOutput{Images: []*ec2.Image{{}}}, nil)
+		group.UserData = nil
+		group.KubeletExtraArgs = aws.String("--max-pods=58")
+
+		launchTemplateData, err := buildLaunchTemplateData(context.Background(), ec2ServiceMock, config, *group)
+		Expect(err).ToNot(HaveOccurred())
+		userData, err := base64.StdEncoding.DecodeString(aws.StringValue(launchTemplateData.UserData))
+		Expect(err).ToNot(HaveOccurred())
+		_, script := parseShellScriptPart(string(userData))
+		Expect(script).To(ContainSubstring("/etc/eks/bootstrap.sh 'test' --kubelet-extra-args"))
+	})
+
+	It("should not encode the user data of the node group in place", func() {
+		ec2ServiceMock.EXPECT().DescribeImages(gomock.Any(), gomock.Any()).Return(&ec2.DescribeImagesOutput{Images: []*ec2.Image{{}}}, nil)
+		userData := aws.StringValue(group.UserData)
+
+		_, err := buildLaunchTemplateData(context.Background(), ec2ServiceMock, config, *group)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(aws.StringValue(group.UserData)).To(Equal(userData))
+	})
+
+	It("should fail to build a launch template data if error is return by ec2", func() {
+		ec2ServiceMock.EXPECT().DescribeImages(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+		_, err := buildLaunchTemplateData(context.Background(), ec2ServiceMock, config, *group)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("createNewLaunchTemplateVersion", func() {
+	var (
+		mockController *gomock.Controller
 		ec2ServiceMock *mock_services.MockEC2ServiceInterface
+		config         *eksv1.EKSClusterConfig
 		group          *eksv1.NodeGroup
 		templateID     = "test-launch-template"
 	)
@@ -571,6 +1281,10 @@ var _ = Describe("createNewLaunchTemplateVersion", func() {
 	BeforeEach(func() {
 		mockController = gomock.NewController(GinkgoT())
 		ec2ServiceMock = mock_services.NewMockEC2ServiceInterface(mockController)
+		config = &eksv1.EKSClusterConfig{
+			Spec:   eksv1.EKSClusterConfigSpec{DisplayName: "test"},
+			Status: eksv1.EKSClusterConfigStatus{ManagedLaunchTemplateID: templateID},
+		}
 		group = &eksv1.NodeGroup{
 			DiskSize:     aws.Int64(20),
 			ResourceTags: aws.StringMap(map[string]string{"test": "test"}),
@@ -584,7 +1298,7 @@ var _ = Describe("createNewLaunchTemplateVersion", func() {
 	})
 
 	It("should create a new launch template", func() {
-		input, err := buildLaunchTemplateData(ec2ServiceMock, *group)
+		input, err := buildLaunchTemplateData(context.Background(), ec2ServiceMock, config, *group)
 		Expect(err).ToNot(HaveOccurred())
 
 		output := &ec2.CreateLaunchTemplateVersionOutput{
@@ -595,12 +1309,12 @@ var _ = Describe("createNewLaunchTemplateVersion", func() {
 			},
 		}
 
-		ec2ServiceMock.EXPECT().CreateLaunchTemplateVersion(&ec2.CreateLaunchTemplateVersionInput{
+		ec2ServiceMock.EXPECT().CreateLaunchTemplateVersion(gomock.Any(), &ec2.CreateLaunchTemplateVersionInput{
 			LaunchTemplateData: input,
 			LaunchTemplateId:   aws.String(templateID),
 		}).Return(output, nil)
 
-		launchTemplate, err := CreateNewLaunchTemplateVersion(ec2ServiceMock, templateID, *group)
+		launchTemplate, err := CreateNewLaunchTemplateVersion(context.Background(), ec2ServiceMock, config, *group)
 		Expect(err).ToNot(HaveOccurred())
 
 		Expect(launchTemplate.Name).To(Equal(output.LaunchTemplateVersion.LaunchTemplateName))
@@ -609,8 +1323,8 @@ var _ = Describe("createNewLaunchTemplateVersion", func() {
 	})
 
 	It("should fail to create a new launch template if error is returned by ec2", func() {
-		ec2ServiceMock.EXPECT().CreateLaunchTemplateVersion(gomock.Any()).Return(nil, errors.New("error"))
-		_, err := CreateNewLaunchTemplateVersion(ec2ServiceMock, templateID, *group)
+		ec2ServiceMock.EXPECT().CreateLaunchTemplateVersion(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+		_, err := CreateNewLaunchTemplateVersion(context.Background(), ec2ServiceMock, config, *group)
 		Expect(err).To(HaveOccurred())
 	})
 })
@@ -651,10 +1365,14 @@ var _ = Describe("CreateNodeGroup", func() {
 				MinSize:              aws.Int64(1),
 				Subnets:              []string{"test"},
 				ImageID:              aws.String("test"),
+				UserData:             aws.String("Content-Type: multipart/mixed ..."),
 				Ec2SshKey:            aws.String("test"),
 				SpotInstanceTypes:    aws.StringSlice([]string{"test"}),
 			},
 		}
+		ec2ServiceMock.EXPECT().DescribeSubnets(gomock.Any(), gomock.Any()).Return(&ec2.DescribeSubnetsOutput{
+			Subnets: []*ec2.Subnet{{SubnetId: aws.String("test"), AvailableIpAddressCount: aws.Int64(250)}},
+		}, nil).AnyTimes()
 	})
 
 	AfterEach(func() {
@@ -662,10 +1380,11 @@ var _ = Describe("CreateNodeGroup", func() {
 	})
 
 	It("should create a node group", func() {
-		ec2ServiceMock.EXPECT().CreateLaunchTemplateVersion(&ec2.CreateLaunchTemplateVersionInput{
+		ec2ServiceMock.EXPECT().CreateLaunchTemplateVersion(gomock.Any(), &ec2.CreateLaunchTemplateVersionInput{
 			LaunchTemplateData: &ec2.RequestLaunchTemplateData{
-				ImageId: createNodeGroupOpts.NodeGroup.ImageID,
-				KeyName: createNodeGroupOpts.NodeGroup.Ec2SshKey,
+				ImageId:  createNodeGroupOpts.NodeGroup.ImageID,
+				KeyName:  createNodeGroupOpts.NodeGroup.Ec2SshKey,
+				UserData: aws.String(base64.StdEncoding.EncodeToString([]byte("Content-Type: multipart/mixed ..."))),
 				BlockDeviceMappings: []*ec2.LaunchTemplateBlockDeviceMappingRequest{
 					{
 						DeviceName: aws.String("test"),
@@ -674,18 +1393,18 @@ var _ = Describe("CreateNodeGroup", func() {
 						},
 					},
 				},
-				TagSpecifications: utils.CreateTagSpecs(createNodeGroupOpts.NodeGroup.ResourceTags),
+				TagSpecifications: utils.CreateTagSpecs(aws.StringMap(map[string]string{"kubernetes.io/cluster/test": "owned"})),
 			},
 			LaunchTemplateId: aws.String(createNodeGroupOpts.Config.Status.ManagedLaunchTemplateID),
 		}).Return(&ec2.CreateLaunchTemplateVersionOutput{
 			LaunchTemplateVersion: &ec2.LaunchTemplateVersion{
 				LaunchTemplateName: aws.String("test"),
 				LaunchTemplateId:   aws.String("test"),
-				VersionNumber:      aws.Int64(1),
+				VersionNumber:      aws.Int64(2),
 			},
 		}, nil)
 
-		ec2ServiceMock.EXPECT().DescribeImages(&ec2.DescribeImagesInput{ImageIds: []*string{createNodeGroupOpts.NodeGroup.ImageID}}).Return(&ec2.DescribeImagesOutput{
+		ec2ServiceMock.EXPECT().DescribeImages(gomock.Any(), &ec2.DescribeImagesInput{ImageIds: []*string{createNodeGroupOpts.NodeGroup.ImageID}}).Return(&ec2.DescribeImagesOutput{
 			Images: []*ec2.Image{
 				{
 					RootDeviceName: aws.String("test"),
@@ -693,9 +1412,9 @@ var _ = Describe("CreateNodeGroup", func() {
 			},
 		}, nil)
 
-		cloudFormationsServiceMock.EXPECT().CreateStack(gomock.Any()).Return(nil, nil)
+		cloudFormationsServiceMock.EXPECT().CreateStack(gomock.Any(), gomock.Any()).Return(nil, nil)
 
-		cloudFormationsServiceMock.EXPECT().DescribeStacks(gomock.Any()).Return(
+		cloudFormationsServiceMock.EXPECT().DescribeStacks(gomock.Any(), gomock.Any()).Return(
 			&cloudformation.DescribeStacksOutput{
 				Stacks: []*cloudformation.Stack{
 					{
@@ -710,7 +1429,7 @@ var _ = Describe("CreateNodeGroup", func() {
 				},
 			}, nil)
 
-		eksServiceMock.EXPECT().CreateNodegroup(&eks.CreateNodegroupInput{
+		eksServiceMock.EXPECT().CreateNodegroup(gomock.Any(), &eks.CreateNodegroupInput{
 			ClusterName:   aws.String(createNodeGroupOpts.Config.Spec.DisplayName),
 			NodegroupName: createNodeGroupOpts.NodeGroup.NodegroupName,
 			Labels:        createNodeGroupOpts.NodeGroup.Labels,
@@ -720,32 +1439,42 @@ var _ = Describe("CreateNodeGroup", func() {
 				MinSize:     createNodeGroupOpts.NodeGroup.MinSize,
 			},
 			CapacityType: aws.String(eks.CapacityTypesSpot),
+			AmiType:      aws.String(eks.AMITypesCustom),
 			LaunchTemplate: &eks.LaunchTemplateSpecification{
 				Id:      aws.String("test"),
-				Version: aws.String("1"),
+				Version: aws.String("2"),
 			},
 			InstanceTypes: createNodeGroupOpts.NodeGroup.SpotInstanceTypes,
 			Subnets:       aws.StringSlice(createNodeGroupOpts.NodeGroup.Subnets),
 			NodeRole:      aws.String("test"),
 		}).Return(nil, nil)
 
-		launchTemplateVersion, generatedNodeRole, err := CreateNodeGroup(createNodeGroupOpts)
+		launchTemplateVersion, generatedNodeRole, err := CreateNodeGroup(context.Background(), createNodeGroupOpts)
 		Expect(err).ToNot(HaveOccurred())
 
-		Expect(launchTemplateVersion).To(Equal("1"))
+		Expect(launchTemplateVersion).To(Equal("2"))
 		Expect(generatedNodeRole).To(Equal("test"))
 	})
 
+	It("should fail to create a node group with a custom image and no user data", func() {
+		createNodeGroupOpts.NodeGroup.UserData = nil
+
+		_, _, err := CreateNodeGroup(context.Background(), createNodeGroupOpts)
+		Expect(err).To(MatchError(ContainSubstring("must be specified along with a custom imageId")))
+	})
+
 	It("shouldn't create launch template if it exists", func() {
 		createNodeGroupOpts.NodeGroup.LaunchTemplate = &eksv1.LaunchTemplate{
 			ID:      aws.String("test"),
-			Version: aws.Int64(1),
+			Version: aws.Int64(2),
 			Name:    aws.String("test"),
 		}
+		createNodeGroupOpts.NodeGroup.ImageID = nil
+		createNodeGroupOpts.NodeGroup.Ec2SshKey = nil
 
-		cloudFormationsServiceMock.EXPECT().CreateStack(gomock.Any()).Return(nil, nil)
+		cloudFormationsServiceMock.EXPECT().CreateStack(gomock.Any(), gomock.Any()).Return(nil, nil)
 
-		cloudFormationsServiceMock.EXPECT().DescribeStacks(gomock.Any()).Return(
+		cloudFormationsServiceMock.EXPECT().DescribeStacks(gomock.Any(), gomock.Any()).Return(
 			&cloudformation.DescribeStacksOutput{
 				Stacks: []*cloudformation.Stack{
 					{
@@ -760,26 +1489,66 @@ var _ = Describe("CreateNodeGroup", func() {
 				},
 			}, nil)
 
-		eksServiceMock.EXPECT().CreateNodegroup(gomock.Any()).Return(nil, nil)
+		eksServiceMock.EXPECT().CreateNodegroup(gomock.Any(), gomock.Any()).Return(nil, nil)
 
-		launchTemplateVersion, generatedNodeRole, err := CreateNodeGroup(createNodeGroupOpts)
+		launchTemplateVersion, generatedNodeRole, err := CreateNodeGroup(context.Background(), createNodeGroupOpts)
 		Expect(err).ToNot(HaveOccurred())
 
-		Expect(launchTemplateVersion).To(Equal("1"))
+		Expect(launchTemplateVersion).To(Equal("2"))
 		Expect(generatedNodeRole).To(Equal("test"))
 	})
 
+	It("should fail to create node group with the placeholder version of the managed launch template", func() {
+		ec2ServiceMock.EXPECT().CreateLaunchTemplateVersion(gomock.Any(), gomock.Any()).Return(&ec2.CreateLaunchTemplateVersionOutput{
+			LaunchTemplateVersion: &ec2.LaunchTemplateVersion{
+				LaunchTemplateName: aws.String("test"),
+				LaunchTemplateId:   aws.String("test"),
+				VersionNumber:      aws.Int64(1),
+			},
+		}, nil)
+		ec2ServiceMock.EXPECT().DescribeImages(gomock.Any(), gomock.Any()).Return(&ec2.DescribeImagesOutput{
+			Images: []*ec2.Image{
+				{
+					RootDeviceName: aws.String("test"),
+				},
+			},
+		}, nil)
+
+		_, _, err := CreateNodeGroup(context.Background(), createNodeGroupOpts)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should fail to create node group with a custom launch template and node group level fields", func() {
+		createNodeGroupOpts.NodeGroup.LaunchTemplate = &eksv1.LaunchTemplate{
+			ID:      aws.String("test"),
+			Version: aws.Int64(2),
+			Name:    aws.String("test"),
+		}
+
+		_, _, err := CreateNodeGroup(context.Background(), createNodeGroupOpts)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should fail to create node group with a desired size above max size before creating a launch template version", func() {
+		createNodeGroupOpts.NodeGroup.DesiredSize = aws.Int64(10)
+		createNodeGroupOpts.NodeGroup.MaxSize = aws.Int64(5)
+		ec2ServiceMock.EXPECT().CreateLaunchTemplateVersion(gomock.Any(), gomock.Any()).Times(0)
+
+		_, _, err := CreateNodeGroup(context.Background(), createNodeGroupOpts)
+		Expect(err).To(MatchError("nodegroup [test]: desiredSize [10] cannot be greater than maxSize [5]"))
+	})
+
 	It("shouldn't create node role if it exists", func() {
 		createNodeGroupOpts.Config.Status.GeneratedNodeRole = "test"
-		ec2ServiceMock.EXPECT().CreateLaunchTemplateVersion(gomock.Any()).Return(&ec2.CreateLaunchTemplateVersionOutput{
+		ec2ServiceMock.EXPECT().CreateLaunchTemplateVersion(gomock.Any(), gomock.Any()).Return(&ec2.CreateLaunchTemplateVersionOutput{
 			LaunchTemplateVersion: &ec2.LaunchTemplateVersion{
 				LaunchTemplateName: aws.String("test"),
 				LaunchTemplateId:   aws.String("test"),
-				VersionNumber:      aws.Int64(1),
+				VersionNumber:      aws.Int64(2),
 			},
 		}, nil)
 
-		ec2ServiceMock.EXPECT().DescribeImages(gomock.Any()).Return(&ec2.DescribeImagesOutput{
+		ec2ServiceMock.EXPECT().DescribeImages(gomock.Any(), gomock.Any()).Return(&ec2.DescribeImagesOutput{
 			Images: []*ec2.Image{
 				{
 					RootDeviceName: aws.String("test"),
@@ -787,32 +1556,109 @@ var _ = Describe("CreateNodeGroup", func() {
 			},
 		}, nil)
 
-		eksServiceMock.EXPECT().CreateNodegroup(gomock.Any()).Return(nil, nil)
+		eksServiceMock.EXPECT().CreateNodegroup(gomock.Any(), gomock.Any()).Return(nil, nil)
 
-		launchTemplateVersion, generatedNodeRole, err := CreateNodeGroup(createNodeGroupOpts)
+		launchTemplateVersion, generatedNodeRole, err := CreateNodeGroup(context.Background(), createNodeGroupOpts)
 		Expect(err).ToNot(HaveOccurred())
 
-		Expect(launchTemplateVersion).To(Equal("1"))
+		Expect(launchTemplateVersion).To(Equal("2"))
 		Expect(generatedNodeRole).To(Equal("test"))
 	})
 
+	It("should share the node role stack between node groups created concurrently", func() {
+		var (
+			mu                sync.Mutex
+			stacksInFlight    int
+			maxStacksInFlight int
+			stackCreated      bool
+		)
+		ec2ServiceMock.EXPECT().CreateLaunchTemplateVersion(gomock.Any(), gomock.Any()).Return(&ec2.CreateLaunchTemplateVersionOutput{
+			LaunchTemplateVersion: &ec2.LaunchTemplateVersion{
+				LaunchTemplateName: aws.String("test"),
+				LaunchTemplateId:   aws.String("test"),
+				VersionNumber:      aws.Int64(2),
+			},
+		}, nil).Times(2)
+		ec2ServiceMock.EXPECT().DescribeImages(gomock.Any(), gomock.Any()).Return(&ec2.DescribeImagesOutput{
+			Images: []*ec2.Image{
+				{
+					RootDeviceName: aws.String("test"),
+				},
+			},
+		}, nil).Times(2)
+		cloudFormationsServiceMock.EXPECT().CreateStack(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(context.Context, *cloudformation.CreateStackInput) (*cloudformation.CreateStackOutput, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				stacksInFlight++
+				if stacksInFlight > maxStacksInFlight {
+					maxStacksInFlight = stacksInFlight
+				}
+				if stackCreated {
+					return nil, awserr.New(cloudformation.ErrCodeAlreadyExistsException, "", nil)
+				}
+				stackCreated = true
+				return nil, nil
+			}).Times(2)
+		cloudFormationsServiceMock.EXPECT().DescribeStacks(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(context.Context, *cloudformation.DescribeStacksInput) (*cloudformation.DescribeStacksOutput, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				stacksInFlight--
+				return &cloudformation.DescribeStacksOutput{
+					Stacks: []*cloudformation.Stack{
+						{
+							StackStatus: aws.String(createCompleteStatus),
+							Outputs: []*cloudformation.Output{
+								{
+									OutputKey:   aws.String("NodeInstanceRole"),
+									OutputValue: aws.String("test"),
+								},
+							},
+						},
+					},
+				}, nil
+			}).Times(2)
+		eksServiceMock.EXPECT().CreateNodegroup(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+
+		secondNodeGroupOpts := *createNodeGroupOpts
+		secondNodeGroupOpts.NodeGroup.NodegroupName = aws.String("test2")
+
+		var wg sync.WaitGroup
+		generatedNodeRoles := make([]string, 2)
+		for i, opts := range []*CreateNodeGroupOptions{createNodeGroupOpts, &secondNodeGroupOpts} {
+			wg.Add(1)
+			go func(i int, opts *CreateNodeGroupOptions) {
+				defer GinkgoRecover()
+				defer wg.Done()
+				_, generatedNodeRole, err := CreateNodeGroup(context.Background(), opts)
+				Expect(err).ToNot(HaveOccurred())
+				generatedNodeRoles[i] = generatedNodeRole
+			}(i, opts)
+		}
+		wg.Wait()
+
+		Expect(maxStacksInFlight).To(Equal(1))
+		Expect(generatedNodeRoles).To(Equal([]string{"test", "test"}))
+	})
+
 	It("delete launch template versions if creating node group fails", func() {
-		ec2ServiceMock.EXPECT().CreateLaunchTemplateVersion(gomock.Any()).Return(&ec2.CreateLaunchTemplateVersionOutput{
+		ec2ServiceMock.EXPECT().CreateLaunchTemplateVersion(gomock.Any(), gomock.Any()).Return(&ec2.CreateLaunchTemplateVersionOutput{
 			LaunchTemplateVersion: &ec2.LaunchTemplateVersion{
 				LaunchTemplateName: aws.String("test"),
 				LaunchTemplateId:   aws.String("test"),
-				VersionNumber:      aws.Int64(1),
+				VersionNumber:      aws.Int64(2),
 			},
 		}, nil)
-		ec2ServiceMock.EXPECT().DescribeImages(gomock.Any()).Return(&ec2.DescribeImagesOutput{
+		ec2ServiceMock.EXPECT().DescribeImages(gomock.Any(), gomock.Any()).Return(&ec2.DescribeImagesOutput{
 			Images: []*ec2.Image{
 				{
 					RootDeviceName: aws.String("test"),
 				},
 			},
 		}, nil)
-		cloudFormationsServiceMock.EXPECT().CreateStack(gomock.Any()).Return(nil, nil)
-		cloudFormationsServiceMock.EXPECT().DescribeStacks(gomock.Any()).Return(
+		cloudFormationsServiceMock.EXPECT().CreateStack(gomock.Any(), gomock.Any()).Return(nil, nil)
+		cloudFormationsServiceMock.EXPECT().DescribeStacks(gomock.Any(), gomock.Any()).Return(
 			&cloudformation.DescribeStacksOutput{
 				Stacks: []*cloudformation.Stack{
 					{
@@ -826,20 +1672,20 @@ var _ = Describe("CreateNodeGroup", func() {
 					},
 				},
 			}, nil)
-		eksServiceMock.EXPECT().CreateNodegroup(gomock.Any()).Return(nil, errors.New("error"))
-		ec2ServiceMock.EXPECT().DeleteLaunchTemplateVersions(gomock.Any()).Return(nil, nil)
+		eksServiceMock.EXPECT().CreateNodegroup(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+		ec2ServiceMock.EXPECT().DeleteLaunchTemplateVersions(gomock.Any(), gomock.Any()).Return(nil, nil)
 
-		launchTemplateVersion, generatedNodeRole, err := CreateNodeGroup(createNodeGroupOpts)
+		launchTemplateVersion, generatedNodeRole, err := CreateNodeGroup(context.Background(), createNodeGroupOpts)
 		Expect(err).To(HaveOccurred())
 
-		Expect(launchTemplateVersion).To(Equal("1"))
+		Expect(launchTemplateVersion).To(Equal("2"))
 		Expect(generatedNodeRole).To(Equal("test"))
 	})
 
 	It("should fail to create node group if creating launch template return error", func() {
-		ec2ServiceMock.EXPECT().CreateLaunchTemplateVersion(gomock.Any()).Return(nil, errors.New("error"))
+		ec2ServiceMock.EXPECT().CreateLaunchTemplateVersion(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
 
-		ec2ServiceMock.EXPECT().DescribeImages(gomock.Any()).Return(&ec2.DescribeImagesOutput{
+		ec2ServiceMock.EXPECT().DescribeImages(gomock.Any(), gomock.Any()).Return(&ec2.DescribeImagesOutput{
 			Images: []*ec2.Image{
 				{
 					RootDeviceName: aws.String("test"),
@@ -847,22 +1693,22 @@ var _ = Describe("CreateNodeGroup", func() {
 			},
 		}, nil)
 
-		_, _, err := CreateNodeGroup(createNodeGroupOpts)
+		_, _, err := CreateNodeGroup(context.Background(), createNodeGroupOpts)
 		Expect(err).To(HaveOccurred())
 	})
 
 	It("get subnets from status if not set", func() {
 		createNodeGroupOpts.NodeGroup.Subnets = nil
 		createNodeGroupOpts.Config.Status.Subnets = []string{"from", "status"}
-		ec2ServiceMock.EXPECT().CreateLaunchTemplateVersion(gomock.Any()).Return(&ec2.CreateLaunchTemplateVersionOutput{
+		ec2ServiceMock.EXPECT().CreateLaunchTemplateVersion(gomock.Any(), gomock.Any()).Return(&ec2.CreateLaunchTemplateVersionOutput{
 			LaunchTemplateVersion: &ec2.LaunchTemplateVersion{
 				LaunchTemplateName: aws.String("test"),
 				LaunchTemplateId:   aws.String("test"),
-				VersionNumber:      aws.Int64(1),
+				VersionNumber:      aws.Int64(2),
 			},
 		}, nil)
 
-		ec2ServiceMock.EXPECT().DescribeImages(gomock.Any()).Return(&ec2.DescribeImagesOutput{
+		ec2ServiceMock.EXPECT().DescribeImages(gomock.Any(), gomock.Any()).Return(&ec2.DescribeImagesOutput{
 			Images: []*ec2.Image{
 				{
 					RootDeviceName: aws.String("test"),
@@ -870,9 +1716,9 @@ var _ = Describe("CreateNodeGroup", func() {
 			},
 		}, nil)
 
-		cloudFormationsServiceMock.EXPECT().CreateStack(gomock.Any()).Return(nil, nil)
+		cloudFormationsServiceMock.EXPECT().CreateStack(gomock.Any(), gomock.Any()).Return(nil, nil)
 
-		cloudFormationsServiceMock.EXPECT().DescribeStacks(gomock.Any()).Return(
+		cloudFormationsServiceMock.EXPECT().DescribeStacks(gomock.Any(), gomock.Any()).Return(
 			&cloudformation.DescribeStacksOutput{
 				Stacks: []*cloudformation.Stack{
 					{
@@ -887,7 +1733,7 @@ var _ = Describe("CreateNodeGroup", func() {
 				},
 			}, nil)
 
-		eksServiceMock.EXPECT().CreateNodegroup(&eks.CreateNodegroupInput{
+		eksServiceMock.EXPECT().CreateNodegroup(gomock.Any(), &eks.CreateNodegroupInput{
 			ClusterName:   aws.String(createNodeGroupOpts.Config.Spec.DisplayName),
 			NodegroupName: createNodeGroupOpts.NodeGroup.NodegroupName,
 			Labels:        createNodeGroupOpts.NodeGroup.Labels,
@@ -897,19 +1743,20 @@ var _ = Describe("CreateNodeGroup", func() {
 				MinSize:     createNodeGroupOpts.NodeGroup.MinSize,
 			},
 			CapacityType: aws.String(eks.CapacityTypesSpot),
+			AmiType:      aws.String(eks.AMITypesCustom),
 			LaunchTemplate: &eks.LaunchTemplateSpecification{
 				Id:      aws.String("test"),
-				Version: aws.String("1"),
+				Version: aws.String("2"),
 			},
 			InstanceTypes: createNodeGroupOpts.NodeGroup.SpotInstanceTypes,
 			Subnets:       aws.StringSlice(createNodeGroupOpts.Config.Status.Subnets),
 			NodeRole:      aws.String("test"),
 		}).Return(nil, nil)
 
-		launchTemplateVersion, generatedNodeRole, err := CreateNodeGroup(createNodeGroupOpts)
+		launchTemplateVersion, generatedNodeRole, err := CreateNodeGroup(context.Background(), createNodeGroupOpts)
 		Expect(err).ToNot(HaveOccurred())
 
-		Expect(launchTemplateVersion).To(Equal("1"))
+		Expect(launchTemplateVersion).To(Equal("2"))
 		Expect(generatedNodeRole).To(Equal("test"))
 	})
 
@@ -917,17 +1764,17 @@ var _ = Describe("CreateNodeGroup", func() {
 		createNodeGroupOpts.NodeGroup.Gpu = aws.Bool(true)
 		createNodeGroupOpts.NodeGroup.ImageID = nil
 
-		ec2ServiceMock.EXPECT().CreateLaunchTemplateVersion(gomock.Any()).Return(&ec2.CreateLaunchTemplateVersionOutput{
+		ec2ServiceMock.EXPECT().CreateLaunchTemplateVersion(gomock.Any(), gomock.Any()).Return(&ec2.CreateLaunchTemplateVersionOutput{
 			LaunchTemplateVersion: &ec2.LaunchTemplateVersion{
 				LaunchTemplateName: aws.String("test"),
 				LaunchTemplateId:   aws.String("test"),
-				VersionNumber:      aws.Int64(1),
+				VersionNumber:      aws.Int64(2),
 			},
 		}, nil)
 
-		cloudFormationsServiceMock.EXPECT().CreateStack(gomock.Any()).Return(nil, nil)
+		cloudFormationsServiceMock.EXPECT().CreateStack(gomock.Any(), gomock.Any()).Return(nil, nil)
 
-		cloudFormationsServiceMock.EXPECT().DescribeStacks(gomock.Any()).Return(
+		cloudFormationsServiceMock.EXPECT().DescribeStacks(gomock.Any(), gomock.Any()).Return(
 			&cloudformation.DescribeStacksOutput{
 				Stacks: []*cloudformation.Stack{
 					{
@@ -942,7 +1789,7 @@ var _ = Describe("CreateNodeGroup", func() {
 				},
 			}, nil)
 
-		eksServiceMock.EXPECT().CreateNodegroup(&eks.CreateNodegroupInput{
+		eksServiceMock.EXPECT().CreateNodegroup(gomock.Any(), &eks.CreateNodegroupInput{
 			ClusterName:   aws.String(createNodeGroupOpts.Config.Spec.DisplayName),
 			NodegroupName: createNodeGroupOpts.NodeGroup.NodegroupName,
 			Labels:        createNodeGroupOpts.NodeGroup.Labels,
@@ -954,7 +1801,7 @@ var _ = Describe("CreateNodeGroup", func() {
 			CapacityType: aws.String(eks.CapacityTypesSpot),
 			LaunchTemplate: &eks.LaunchTemplateSpecification{
 				Id:      aws.String("test"),
-				Version: aws.String("1"),
+				Version: aws.String("2"),
 			},
 			InstanceTypes: createNodeGroupOpts.NodeGroup.SpotInstanceTypes,
 			Subnets:       aws.StringSlice(createNodeGroupOpts.NodeGroup.Subnets),
@@ -962,26 +1809,213 @@ var _ = Describe("CreateNodeGroup", func() {
 			AmiType:       aws.String(eks.AMITypesAl2X8664Gpu),
 		}).Return(nil, nil)
 
-		launchTemplateVersion, generatedNodeRole, err := CreateNodeGroup(createNodeGroupOpts)
+		launchTemplateVersion, generatedNodeRole, err := CreateNodeGroup(context.Background(), createNodeGroupOpts)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(launchTemplateVersion).To(Equal("2"))
+		Expect(generatedNodeRole).To(Equal("test"))
+	})
+
+	It("set arm ami type", func() {
+		createNodeGroupOpts.NodeGroup.Arm = aws.Bool(true)
+		createNodeGroupOpts.NodeGroup.ImageID = nil
+
+		ec2ServiceMock.EXPECT().CreateLaunchTemplateVersion(gomock.Any(), gomock.Any()).Return(&ec2.CreateLaunchTemplateVersionOutput{
+			LaunchTemplateVersion: &ec2.LaunchTemplateVersion{
+				LaunchTemplateName: aws.String("test"),
+				LaunchTemplateId:   aws.String("test"),
+				VersionNumber:      aws.Int64(2),
+			},
+		}, nil)
+
+		cloudFormationsServiceMock.EXPECT().CreateStack(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+		cloudFormationsServiceMock.EXPECT().DescribeStacks(gomock.Any(), gomock.Any()).Return(
+			&cloudformation.DescribeStacksOutput{
+				Stacks: []*cloudformation.Stack{
+					{
+						StackStatus: aws.String(createCompleteStatus),
+						Outputs: []*cloudformation.Output{
+							{
+								OutputKey:   aws.String("NodeInstanceRole"),
+								OutputValue: aws.String("test"),
+							},
+						},
+					},
+				},
+			}, nil)
+
+		eksServiceMock.EXPECT().CreateNodegroup(gomock.Any(), &eks.CreateNodegroupInput{
+			ClusterName:   aws.String(createNodeGroupOpts.Config.Spec.DisplayName),
+			NodegroupName: createNodeGroupOpts.NodeGroup.NodegroupName,
+			Labels:        createNodeGroupOpts.NodeGroup.Labels,
+			ScalingConfig: &eks.NodegroupScalingConfig{
+				DesiredSize: createNodeGroupOpts.NodeGroup.DesiredSize,
+				MaxSize:     createNodeGroupOpts.NodeGroup.MaxSize,
+				MinSize:     createNodeGroupOpts.NodeGroup.MinSize,
+			},
+			CapacityType: aws.String(eks.CapacityTypesSpot),
+			LaunchTemplate: &eks.LaunchTemplateSpecification{
+				Id:      aws.String("test"),
+				Version: aws.String("2"),
+			},
+			InstanceTypes: createNodeGroupOpts.NodeGroup.SpotInstanceTypes,
+			Subnets:       aws.StringSlice(createNodeGroupOpts.NodeGroup.Subnets),
+			NodeRole:      aws.String("test"),
+			AmiType:       aws.String(eks.AMITypesAl2Arm64),
+		}).Return(nil, nil)
+
+		launchTemplateVersion, generatedNodeRole, err := CreateNodeGroup(context.Background(), createNodeGroupOpts)
 		Expect(err).ToNot(HaveOccurred())
 
-		Expect(launchTemplateVersion).To(Equal("1"))
+		Expect(launchTemplateVersion).To(Equal("2"))
 		Expect(generatedNodeRole).To(Equal("test"))
 	})
 
+	It("set windows ami type", func() {
+		createNodeGroupOpts.NodeGroup.AMIFamily = aws.String(AMIFamilyWindowsCore2022)
+		createNodeGroupOpts.NodeGroup.ImageID = nil
+
+		ec2ServiceMock.EXPECT().CreateLaunchTemplateVersion(gomock.Any(), gomock.Any()).Return(&ec2.CreateLaunchTemplateVersionOutput{
+			LaunchTemplateVersion: &ec2.LaunchTemplateVersion{
+				LaunchTemplateName: aws.String("test"),
+				LaunchTemplateId:   aws.String("test"),
+				VersionNumber:      aws.Int64(2),
+			},
+		}, nil)
+
+		cloudFormationsServiceMock.EXPECT().CreateStack(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+		cloudFormationsServiceMock.EXPECT().DescribeStacks(gomock.Any(), gomock.Any()).Return(
+			&cloudformation.DescribeStacksOutput{
+				Stacks: []*cloudformation.Stack{
+					{
+						StackStatus: aws.String(createCompleteStatus),
+						Outputs: []*cloudformation.Output{
+							{
+								OutputKey:   aws.String("NodeInstanceRole"),
+								OutputValue: aws.String("test"),
+							},
+						},
+					},
+				},
+			}, nil)
+
+		eksServiceMock.EXPECT().CreateNodegroup(gomock.Any(), &eks.CreateNodegroupInput{
+			ClusterName:   aws.String(createNodeGroupOpts.Config.Spec.DisplayName),
+			NodegroupName: createNodeGroupOpts.NodeGroup.NodegroupName,
+			Labels:        createNodeGroupOpts.NodeGroup.Labels,
+			ScalingConfig: &eks.NodegroupScalingConfig{
+				DesiredSize: createNodeGroupOpts.NodeGroup.DesiredSize,
+				MaxSize:     createNodeGroupOpts.NodeGroup.MaxSize,
+				MinSize:     createNodeGroupOpts.NodeGroup.MinSize,
+			},
+			CapacityType: aws.String(eks.CapacityTypesSpot),
+			LaunchTemplate: &eks.LaunchTemplateSpecification{
+				Id:      aws.String("test"),
+				Version: aws.String("2"),
+			},
+			InstanceTypes: createNodeGroupOpts.NodeGroup.SpotInstanceTypes,
+			Subnets:       aws.StringSlice(createNodeGroupOpts.NodeGroup.Subnets),
+			NodeRole:      aws.String("test"),
+			AmiType:       aws.String(eks.AMITypesWindowsCore2022X8664),
+		}).Return(nil, nil)
+
+		launchTemplateVersion, generatedNodeRole, err := CreateNodeGroup(context.Background(), createNodeGroupOpts)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(launchTemplateVersion).To(Equal("2"))
+		Expect(generatedNodeRole).To(Equal("test"))
+	})
+
+	It("should create a node group with an update config", func() {
+		createNodeGroupOpts.NodeGroup.UpdateConfig = &eksv1.NodeGroupUpdateConfig{MaxUnavailablePercentage: aws.Int64(25)}
+
+		ec2ServiceMock.EXPECT().CreateLaunchTemplateVersion(gomock.Any(), gomock.Any()).Return(&ec2.CreateLaunchTemplateVersionOutput{
+			LaunchTemplateVersion: &ec2.LaunchTemplateVersion{
+				LaunchTemplateId: aws.String("test"),
+				VersionNumber:    aws.Int64(2),
+			},
+		}, nil)
+		ec2ServiceMock.EXPECT().DescribeImages(gomock.Any(), gomock.Any()).Return(&ec2.DescribeImagesOutput{
+			Images: []*ec2.Image{{RootDeviceName: aws.String("test")}},
+		}, nil)
+		cloudFormationsServiceMock.EXPECT().CreateStack(gomock.Any(), gomock.Any()).Return(nil, nil)
+		cloudFormationsServiceMock.EXPECT().DescribeStacks(gomock.Any(), gomock.Any()).Return(
+			&cloudformation.DescribeStacksOutput{
+				Stacks: []*cloudformation.Stack{
+					{
+						StackStatus: aws.String(createCompleteStatus),
+						Outputs: []*cloudformation.Output{
+							{
+								OutputKey:   aws.String("NodeInstanceRole"),
+								OutputValue: aws.String("test"),
+							},
+						},
+					},
+				},
+			}, nil)
+		eksServiceMock.EXPECT().CreateNodegroup(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, input *eks.CreateNodegroupInput) (*eks.CreateNodegroupOutput, error) {
+				Expect(input.UpdateConfig).To(Equal(&eks.NodegroupUpdateConfig{MaxUnavailablePercentage: aws.Int64(25)}))
+				return nil, nil
+			})
+
+		_, _, err := CreateNodeGroup(context.Background(), createNodeGroupOpts)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should fail to create node group if the update config sets both fields", func() {
+		createNodeGroupOpts.NodeGroup.UpdateConfig = &eksv1.NodeGroupUpdateConfig{
+			MaxUnavailable:           aws.Int64(1),
+			MaxUnavailablePercentage: aws.Int64(25),
+		}
+
+		ec2ServiceMock.EXPECT().CreateLaunchTemplateVersion(gomock.Any(), gomock.Any()).Times(0)
+		eksServiceMock.EXPECT().CreateNodegroup(gomock.Any(), gomock.Any()).Times(0)
+
+		_, _, err := CreateNodeGroup(context.Background(), createNodeGroupOpts)
+		Expect(err).To(MatchError(ContainSubstring("cannot both be specified")))
+	})
+
+	It("should fail to create node group if the node role is missing policies", func() {
+		iamServiceMock := mock_services.NewMockIAMServiceInterface(mockController)
+		createNodeGroupOpts.IAMService = iamServiceMock
+		createNodeGroupOpts.NodeGroup.NodeRole = aws.String("arn:aws:iam::123456789012:role/test-role")
+
+		iamServiceMock.EXPECT().ListAttachedRolePolicies(gomock.Any(), gomock.Any()).Return(&iam.ListAttachedRolePoliciesOutput{}, nil)
+		ec2ServiceMock.EXPECT().CreateLaunchTemplateVersion(gomock.Any(), gomock.Any()).Times(0)
+		eksServiceMock.EXPECT().CreateNodegroup(gomock.Any(), gomock.Any()).Times(0)
+
+		_, _, err := CreateNodeGroup(context.Background(), createNodeGroupOpts)
+		Expect(err).To(MatchError(ContainSubstring("nodegroup [test]: node role [test-role] for cluster [] does not have policies")))
+	})
+
+	It("should fail to create node group if gpu and arm are both set", func() {
+		createNodeGroupOpts.NodeGroup.Gpu = aws.Bool(true)
+		createNodeGroupOpts.NodeGroup.Arm = aws.Bool(true)
+		createNodeGroupOpts.NodeGroup.ImageID = nil
+
+		ec2ServiceMock.EXPECT().CreateLaunchTemplateVersion(gomock.Any(), gomock.Any()).Times(0)
+		eksServiceMock.EXPECT().CreateNodegroup(gomock.Any(), gomock.Any()).Times(0)
+
+		_, _, err := CreateNodeGroup(context.Background(), createNodeGroupOpts)
+		Expect(err).To(MatchError(ContainSubstring("gpu and arm cannot both be true")))
+	})
+
 	It("set ami type if image id not set", func() {
 		createNodeGroupOpts.NodeGroup.ImageID = nil
-		ec2ServiceMock.EXPECT().CreateLaunchTemplateVersion(gomock.Any()).Return(&ec2.CreateLaunchTemplateVersionOutput{
+		ec2ServiceMock.EXPECT().CreateLaunchTemplateVersion(gomock.Any(), gomock.Any()).Return(&ec2.CreateLaunchTemplateVersionOutput{
 			LaunchTemplateVersion: &ec2.LaunchTemplateVersion{
 				LaunchTemplateName: aws.String("test"),
 				LaunchTemplateId:   aws.String("test"),
-				VersionNumber:      aws.Int64(1),
+				VersionNumber:      aws.Int64(2),
 			},
 		}, nil)
 
-		cloudFormationsServiceMock.EXPECT().CreateStack(gomock.Any()).Return(nil, nil)
+		cloudFormationsServiceMock.EXPECT().CreateStack(gomock.Any(), gomock.Any()).Return(nil, nil)
 
-		cloudFormationsServiceMock.EXPECT().DescribeStacks(gomock.Any()).Return(
+		cloudFormationsServiceMock.EXPECT().DescribeStacks(gomock.Any(), gomock.Any()).Return(
 			&cloudformation.DescribeStacksOutput{
 				Stacks: []*cloudformation.Stack{
 					{
@@ -996,7 +2030,7 @@ var _ = Describe("CreateNodeGroup", func() {
 				},
 			}, nil)
 
-		eksServiceMock.EXPECT().CreateNodegroup(&eks.CreateNodegroupInput{
+		eksServiceMock.EXPECT().CreateNodegroup(gomock.Any(), &eks.CreateNodegroupInput{
 			ClusterName:   aws.String(createNodeGroupOpts.Config.Spec.DisplayName),
 			NodegroupName: createNodeGroupOpts.NodeGroup.NodegroupName,
 			Labels:        createNodeGroupOpts.NodeGroup.Labels,
@@ -1008,7 +2042,7 @@ var _ = Describe("CreateNodeGroup", func() {
 			CapacityType: aws.String(eks.CapacityTypesSpot),
 			LaunchTemplate: &eks.LaunchTemplateSpecification{
 				Id:      aws.String("test"),
-				Version: aws.String("1"),
+				Version: aws.String("2"),
 			},
 			InstanceTypes: createNodeGroupOpts.NodeGroup.SpotInstanceTypes,
 			Subnets:       aws.StringSlice(createNodeGroupOpts.NodeGroup.Subnets),
@@ -1016,10 +2050,220 @@ var _ = Describe("CreateNodeGroup", func() {
 			AmiType:       aws.String(eks.AMITypesAl2X8664),
 		}).Return(nil, nil)
 
-		launchTemplateVersion, generatedNodeRole, err := CreateNodeGroup(createNodeGroupOpts)
+		launchTemplateVersion, generatedNodeRole, err := CreateNodeGroup(context.Background(), createNodeGroupOpts)
 		Expect(err).ToNot(HaveOccurred())
 
-		Expect(launchTemplateVersion).To(Equal("1"))
+		Expect(launchTemplateVersion).To(Equal("2"))
 		Expect(generatedNodeRole).To(Equal("test"))
 	})
 })
+
+var _ = Describe("createNodeInstanceRole", func() {
+	var (
+		mockController             *gomock.Controller
+		cloudFormationsServiceMock *mock_services.MockCloudFormationServiceInterface
+		createNodeGroupOpts        *CreateNodeGroupOptions
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		cloudFormationsServiceMock = mock_services.NewMockCloudFormationServiceInterface(mockController)
+		createNodeGroupOpts = &CreateNodeGroupOptions{
+			CloudFormationService: cloudFormationsServiceMock,
+			Config: &eksv1.EKSClusterConfig{
+				Spec: eksv1.EKSClusterConfigSpec{
+					DisplayName:            "test",
+					Region:                 "us-west-2",
+					PermissionsBoundaryARN: aws.String("arn:aws:iam::123456789012:policy/boundary"),
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should set the permissions boundary of the node instance role", func() {
+		cloudFormationsServiceMock.EXPECT().CreateStack(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, input *cloudformation.CreateStackInput) (*cloudformation.CreateStackOutput, error) {
+				Expect(input.Parameters).To(Equal([]*cloudformation.Parameter{
+					{
+						ParameterKey:   aws.String("PermissionsBoundary"),
+						ParameterValue: aws.String("arn:aws:iam::123456789012:policy/boundary"),
+					},
+				}))
+				Expect(aws.StringValue(input.TemplateBody)).To(ContainSubstring(
+					`PermissionsBoundary: !If [HasPermissionsBoundary, !Ref PermissionsBoundary, !Ref "AWS::NoValue"]`))
+				return &cloudformation.CreateStackOutput{}, nil
+			})
+		cloudFormationsServiceMock.EXPECT().DescribeStacks(gomock.Any(), gomock.Any()).Return(
+			&cloudformation.DescribeStacksOutput{
+				Stacks: []*cloudformation.Stack{
+					{
+						StackStatus: aws.String(createCompleteStatus),
+						Outputs: []*cloudformation.Output{
+							{
+								OutputKey:   aws.String("NodeInstanceRole"),
+								OutputValue: aws.String("test"),
+							},
+						},
+					},
+				},
+			}, nil)
+
+		nodeRole, err := createNodeInstanceRole(context.Background(), createNodeGroupOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(nodeRole).To(Equal("test"))
+	})
+
+	It("should use the managed policies of the partition of the region", func() {
+		createNodeGroupOpts.Config.Spec.Region = "us-gov-west-1"
+		createNodeGroupOpts.Config.Spec.PermissionsBoundaryARN = nil
+		cloudFormationsServiceMock.EXPECT().CreateStack(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, input *cloudformation.CreateStackInput) (*cloudformation.CreateStackOutput, error) {
+				Expect(aws.StringValue(input.TemplateBody)).To(ContainSubstring("- arn:aws-us-gov:iam::aws:policy/AmazonEKSWorkerNodePolicy"))
+				Expect(aws.StringValue(input.TemplateBody)).ToNot(ContainSubstring("arn:aws:"))
+				return &cloudformation.CreateStackOutput{}, nil
+			})
+		cloudFormationsServiceMock.EXPECT().DescribeStacks(gomock.Any(), gomock.Any()).Return(
+			&cloudformation.DescribeStacksOutput{
+				Stacks: []*cloudformation.Stack{
+					{
+						StackStatus: aws.String(createCompleteStatus),
+						Outputs: []*cloudformation.Output{
+							{
+								OutputKey:   aws.String("NodeInstanceRole"),
+								OutputValue: aws.String("test"),
+							},
+						},
+					},
+				},
+			}, nil)
+
+		_, err := createNodeInstanceRole(context.Background(), createNodeGroupOpts)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should fail to create the node instance role with an invalid permissions boundary", func() {
+		createNodeGroupOpts.Config.Spec.PermissionsBoundaryARN = aws.String("boundary")
+
+		_, err := createNodeInstanceRole(context.Background(), createNodeGroupOpts)
+		Expect(err).To(MatchError(ContainSubstring("permissions boundary [boundary] is not a valid ARN")))
+	})
+})
+
+var _ = Describe("validateNodeGroup", func() {
+	launchTemplate := &eksv1.LaunchTemplate{
+		ID:      aws.String("test"),
+		Version: aws.Int64(2),
+	}
+
+	DescribeTable("should enforce mutually exclusive node group fields",
+		func(ng *eksv1.NodeGroup, expectedErr string) {
+			ng.NodegroupName = aws.String("test")
+			err := validateNodeGroup(ng)
+			if expectedErr == "" {
+				Expect(err).ToNot(HaveOccurred())
+				return
+			}
+			Expect(err).To(MatchError(ContainSubstring(expectedErr)))
+		},
+		Entry("managed launch template with node group fields", &eksv1.NodeGroup{
+			ImageID:      aws.String("ami-test"),
+			UserData:     aws.String("Content-Type: multipart/mixed ..."),
+			InstanceType: aws.String("t3.medium"),
+			DiskSize:     aws.Int64(20),
+			Ec2SshKey:    aws.String("test"),
+		}, ""),
+		Entry("custom image with user data", &eksv1.NodeGroup{
+			ImageID:  aws.String("ami-test"),
+			UserData: aws.String("Content-Type: multipart/mixed ..."),
+		}, ""),
+		Entry("custom image with bootstrap arguments", &eksv1.NodeGroup{
+			ImageID:          aws.String("ami-test"),
+			KubeletExtraArgs: aws.String("--max-pods=110"),
+		}, ""),
+		Entry("custom image without user data", &eksv1.NodeGroup{
+			ImageID: aws.String("ami-test"),
+		}, "userData, or bootstrapExtraArgs and kubeletExtraArgs, must be specified along with a custom imageId"),
+		Entry("custom launch template only", &eksv1.NodeGroup{
+			LaunchTemplate: launchTemplate,
+		}, ""),
+		Entry("gpu and arm", &eksv1.NodeGroup{
+			Gpu: aws.Bool(true),
+			Arm: aws.Bool(true),
+		}, "gpu and arm"),
+		Entry("arm only", &eksv1.NodeGroup{
+			Gpu: aws.Bool(false),
+			Arm: aws.Bool(true),
+		}, ""),
+		Entry("custom launch template with image id", &eksv1.NodeGroup{
+			LaunchTemplate: launchTemplate,
+			ImageID:        aws.String("ami-test"),
+		}, "imageId"),
+		Entry("custom launch template with instance type", &eksv1.NodeGroup{
+			LaunchTemplate: launchTemplate,
+			InstanceType:   aws.String("t3.medium"),
+		}, "instanceType"),
+		Entry("custom launch template with disk size", &eksv1.NodeGroup{
+			LaunchTemplate: launchTemplate,
+			DiskSize:       aws.Int64(20),
+		}, "diskSize"),
+		Entry("custom launch template with disk type", &eksv1.NodeGroup{
+			LaunchTemplate: launchTemplate,
+			DiskType:       aws.String(ec2.VolumeTypeGp3),
+		}, "diskType"),
+		Entry("custom launch template with node termination handler", &eksv1.NodeGroup{
+			LaunchTemplate:         launchTemplate,
+			NodeTerminationHandler: aws.Bool(true),
+		}, "nodeTerminationHandler"),
+		Entry("custom launch template with security groups", &eksv1.NodeGroup{
+			LaunchTemplate:          launchTemplate,
+			NodeGroupSecurityGroups: []string{"sg-extra"},
+		}, "nodeGroupSecurityGroups"),
+		Entry("empty security group", &eksv1.NodeGroup{
+			NodeGroupSecurityGroups: []string{"sg-extra", " "},
+		}, "empty security group"),
+		Entry("custom launch template with remote access", &eksv1.NodeGroup{
+			LaunchTemplate: launchTemplate,
+			Ec2SshKey:      aws.String("test"),
+		}, "ec2SshKey"),
+		Entry("taint with a valid effect", &eksv1.NodeGroup{
+			Taints: []eksv1.Taint{{Key: aws.String("test"), Effect: aws.String(eks.TaintEffectPreferNoSchedule)}},
+		}, ""),
+		Entry("taint with an invalid effect", &eksv1.NodeGroup{
+			Taints: []eksv1.Taint{{Key: aws.String("test"), Effect: aws.String("PreferNoSchedule")}},
+		}, "invalid effect"),
+		Entry("instance profile with node role", &eksv1.NodeGroup{
+			IamInstanceProfile: aws.String("test"),
+			NodeRole:           aws.String("test"),
+		}, "iamInstanceProfile cannot be specified for a managed nodegroup"),
+		Entry("instance profile without node role", &eksv1.NodeGroup{
+			IamInstanceProfile: aws.String("test"),
+		}, "iamInstanceProfile cannot be specified for a managed nodegroup"),
+		Entry("custom launch template with instance profile", &eksv1.NodeGroup{
+			LaunchTemplate:     launchTemplate,
+			IamInstanceProfile: aws.String("test"),
+		}, "iamInstanceProfile cannot be specified for a managed nodegroup"),
+		Entry("desired size within min and max size", &eksv1.NodeGroup{
+			MinSize:     aws.Int64(1),
+			DesiredSize: aws.Int64(2),
+			MaxSize:     aws.Int64(3),
+		}, ""),
+		Entry("desired size below min size", &eksv1.NodeGroup{
+			MinSize:     aws.Int64(2),
+			DesiredSize: aws.Int64(1),
+			MaxSize:     aws.Int64(3),
+		}, "desiredSize [1] cannot be less than minSize [2]"),
+		Entry("desired size above max size", &eksv1.NodeGroup{
+			MinSize:     aws.Int64(1),
+			DesiredSize: aws.Int64(10),
+			MaxSize:     aws.Int64(5),
+		}, "desiredSize [10] cannot be greater than maxSize [5]"),
+		Entry("min size above max size", &eksv1.NodeGroup{
+			MinSize: aws.Int64(6),
+			MaxSize: aws.Int64(5),
+		}, "minSize [6] cannot be greater than maxSize [5]"),
+	)
+})
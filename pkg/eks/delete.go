@@ -1,16 +1,209 @@
 package eks
 
 import (
+	"context"
+	"fmt"
 	"strconv"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/eks"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
 	"github.com/rancher/eks-operator/pkg/eks/services"
 	"github.com/sirupsen/logrus"
 )
 
-func DeleteLaunchTemplateVersions(ec2Service services.EC2ServiceInterface, templateID string, templateVersions []*string) {
+type DeleteClusterOptions struct {
+	EKSService services.EKSServiceInterface
+	Config     *eksv1.EKSClusterConfig
+}
+
+// DeleteCluster deletes the EKS cluster. A cluster that is already gone is not an error, so deletion can be retried.
+func DeleteCluster(ctx context.Context, opts *DeleteClusterOptions) (err error) {
+	ctx, span := startSpan(ctx, "DeleteCluster", opts.Config.Spec.DisplayName)
+	defer func() { endSpan(span, err) }()
+
+	_, err = opts.EKSService.DeleteCluster(ctx, &eks.DeleteClusterInput{
+		Name: aws.String(opts.Config.Spec.DisplayName),
+	})
+	if err != nil && !notFoundInEKSError(err) {
+		return fmt.Errorf("error deleting cluster [%s]: %w", opts.Config.Spec.DisplayName, err)
+	}
+
+	return nil
+}
+
+const (
+	launchTemplateDeletionAttempts = 5
+	// defaultLaunchTemplateDeletionRetryInterval is how long DeleteLaunchTemplate waits between attempts by default.
+	defaultLaunchTemplateDeletionRetryInterval = 10 * time.Second
+)
+
+type DeleteLaunchTemplateOptions struct {
+	EC2Service services.EC2ServiceInterface
+	Config     *eksv1.EKSClusterConfig
+	// RetryInterval defaults to 10 seconds.
+	RetryInterval time.Duration
+}
+
+// DeleteLaunchTemplate deletes the launch template managed by the operator for the cluster, along with all of its
+// versions, and clears its ID from the status of the config. A launch template that is already gone is not an error,
+// so deletion can be retried. Any other error is retried a few times, as the template can still be in use by the node
+// groups being deleted along with the cluster.
+func DeleteLaunchTemplate(ctx context.Context, opts *DeleteLaunchTemplateOptions) (err error) {
+	ctx, span := startSpan(ctx, "DeleteLaunchTemplate", opts.Config.Spec.DisplayName)
+	defer func() { endSpan(span, err) }()
+
+	templateID := opts.Config.Status.ManagedLaunchTemplateID
+	if templateID == "" {
+		return nil
+	}
+
+	retryInterval := opts.RetryInterval
+	if retryInterval <= 0 {
+		retryInterval = defaultLaunchTemplateDeletionRetryInterval
+	}
+	err = retryWithBackoff(ctx, backoff{
+		maxAttempts:    launchTemplateDeletionAttempts,
+		initialDelay:   retryInterval,
+		maxDelay:       retryInterval,
+		retryAllErrors: true,
+	}, func() error {
+		_, err := opts.EC2Service.DeleteLaunchTemplate(ctx, &ec2.DeleteLaunchTemplateInput{
+			LaunchTemplateId: aws.String(templateID),
+		})
+		if doesNotExist(err) {
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting launch template [%s] of cluster [%s]: %w", templateID, opts.Config.Name, err)
+	}
+	opts.Config.Status.ManagedLaunchTemplateID = ""
+
+	return nil
+}
+
+type DeleteNodeInstanceRoleOptions struct {
+	CloudFormationService services.CloudFormationServiceInterface
+	Config                *eksv1.EKSClusterConfig
+	// SharedWith holds the names of the other clusters whose node groups use the generated node role.
+	SharedWith []string
+}
+
+// DeleteNodeInstanceRole deletes the stack of the node role generated for the cluster once its node groups are gone.
+// The stack is kept while other clusters still use the role, and it is only deleted if it is tagged with the display
+// name of the cluster, so a stack of the same name created outside of the operator is left alone. A stack that is
+// already gone is not an error, so deletion can be retried.
+func DeleteNodeInstanceRole(ctx context.Context, opts *DeleteNodeInstanceRoleOptions) (err error) {
+	ctx, span := startSpan(ctx, "DeleteNodeInstanceRole", opts.Config.Spec.DisplayName)
+	defer func() { endSpan(span, err) }()
+
+	stackName := fmt.Sprintf(nodeInstanceRoleStackNameFormat, opts.Config.Spec.DisplayName)
+	defer func() {
+		// the cluster is gone and will not create node groups sharing the stack anymore
+		if err == nil {
+			stackLocks.Delete(stackName)
+		}
+	}()
+
+	if len(opts.SharedWith) != 0 {
+		logrus.Infof("node instance role stack [%s] of cluster [%s] is still used by clusters %v, will not delete it",
+			stackName, opts.Config.Name, opts.SharedWith)
+		return nil
+	}
+
+	output, err := opts.CloudFormationService.DescribeStacks(ctx, &cloudformation.DescribeStacksInput{
+		StackName: aws.String(stackName),
+	})
+	if err != nil {
+		if doesNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("error describing node instance role stack [%s] of cluster [%s]: %w", stackName, opts.Config.Name, err)
+	}
+	if len(output.Stacks) == 0 || !isStackOwnedBy(output.Stacks[0], opts.Config.Spec.DisplayName) {
+		logrus.Infof("node instance role stack [%s] was not created for cluster [%s], will not delete it", stackName, opts.Config.Name)
+		return nil
+	}
+
+	_, err = opts.CloudFormationService.DeleteStack(ctx, &cloudformation.DeleteStackInput{
+		StackName: aws.String(stackName),
+	})
+	if err != nil && !doesNotExist(err) {
+		return fmt.Errorf("error deleting node instance role stack [%s] of cluster [%s]: %w", stackName, opts.Config.Name, err)
+	}
+
+	return nil
+}
+
+func isStackOwnedBy(stack *cloudformation.Stack, displayName string) bool {
+	for _, tag := range stack.Tags {
+		if aws.StringValue(tag.Key) == stackDisplayNameTagKey {
+			return aws.StringValue(tag.Value) == displayName
+		}
+	}
+
+	return false
+}
+
+type DeleteNodeGroupOptions struct {
+	EKSService services.EKSServiceInterface
+	Config     *eksv1.EKSClusterConfig
+	NodeGroup  eksv1.NodeGroup
+}
+
+// DeleteNodeGroup deletes the node group, and returns whether its deletion is in progress along with the version of
+// the rancher-managed launch template it used. The version cannot be deleted until the instances of the node group
+// are gone, so it is left to the caller to delete it once the node group is deleted. A node group that is already
+// gone is not an error, so deletion can be retried.
+func DeleteNodeGroup(ctx context.Context, opts *DeleteNodeGroupOptions) (templateVersionToDelete *string, deleting bool, err error) {
+	ctx, span := startSpan(ctx, "DeleteNodeGroup", opts.Config.Spec.DisplayName)
+	defer func() { endSpan(span, err) }()
+
+	ngName := aws.StringValue(opts.NodeGroup.NodegroupName)
+	ngState, err := opts.EKSService.DescribeNodegroup(ctx, &eks.DescribeNodegroupInput{
+		ClusterName:   aws.String(opts.Config.Spec.DisplayName),
+		NodegroupName: opts.NodeGroup.NodegroupName,
+	})
+	if err != nil {
+		if notFoundInEKSError(err) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("error describing nodegroup [%s] in cluster [%s]: %w", ngName, opts.Config.Spec.DisplayName, err)
+	}
+
+	if aws.StringValue(ngState.Nodegroup.Status) == eks.NodegroupStatusDeleting {
+		return nil, true, nil
+	}
+
+	_, err = opts.EKSService.DeleteNodegroup(ctx, &eks.DeleteNodegroupInput{
+		ClusterName:   aws.String(opts.Config.Spec.DisplayName),
+		NodegroupName: opts.NodeGroup.NodegroupName,
+	})
+	if err != nil {
+		if notFoundInEKSError(err) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("error deleting nodegroup [%s] in cluster [%s]: %w", ngName, opts.Config.Spec.DisplayName, err)
+	}
+
+	lt := ngState.Nodegroup.LaunchTemplate
+	// The placeholder version is the default version of the managed launch template and cannot be deleted
+	// until the template itself is deleted.
+	if lt != nil && opts.Config.Status.ManagedLaunchTemplateID != "" &&
+		aws.StringValue(lt.Id) == opts.Config.Status.ManagedLaunchTemplateID &&
+		aws.StringValue(lt.Version) != placeholderLaunchTemplateVersion {
+		templateVersionToDelete = lt.Version
+	}
+
+	return templateVersionToDelete, true, nil
+}
+
+func DeleteLaunchTemplateVersions(ctx context.Context, ec2Service services.EC2ServiceInterface, templateID string, templateVersions []*string) {
 	launchTemplateDeleteVersionInput := &ec2.DeleteLaunchTemplateVersionsInput{
 		LaunchTemplateId: aws.String(templateID),
 		Versions:         templateVersions,
@@ -19,7 +212,7 @@ func DeleteLaunchTemplateVersions(ec2Service services.EC2ServiceInterface, templ
 	var err error
 	var deleteVersionsOutput *ec2.DeleteLaunchTemplateVersionsOutput
 	for i := 0; i < 5; i++ {
-		deleteVersionsOutput, err = ec2Service.DeleteLaunchTemplateVersions(launchTemplateDeleteVersionInput)
+		deleteVersionsOutput, err = ec2Service.DeleteLaunchTemplateVersions(ctx, launchTemplateDeleteVersionInput)
 
 		if deleteVersionsOutput != nil {
 			templateVersions = templateVersions[:0]
@@ -35,7 +228,10 @@ func DeleteLaunchTemplateVersions(ec2Service services.EC2ServiceInterface, templ
 		}
 
 		launchTemplateDeleteVersionInput.Versions = templateVersions
-		time.Sleep(10 * time.Second)
+		if sleepErr := sleepWithContext(ctx, 10*time.Second); sleepErr != nil {
+			err = sleepErr
+			break
+		}
 	}
 
 	logrus.Warnf("could not delete versions [%v] of launch template [%s]: %v, will not retry",
@@ -45,6 +241,71 @@ func DeleteLaunchTemplateVersions(ec2Service services.EC2ServiceInterface, templ
 	)
 }
 
+// maxLaunchTemplateVersionsPerDelete is the maximum number of versions EC2 deletes in a single
+// DeleteLaunchTemplateVersions request.
+const maxLaunchTemplateVersionsPerDelete = 200
+
+type PruneLaunchTemplateVersionsOptions struct {
+	EC2Service services.EC2ServiceInterface
+	Config     *eksv1.EKSClusterConfig
+	// InUseVersions are the versions of the managed launch template referenced by live node groups.
+	InUseVersions []string
+	// Keep is the number of most recent versions kept on top of the default and in use versions.
+	Keep int
+}
+
+// PruneLaunchTemplateVersions deletes the versions of the launch template managed by the operator for the cluster
+// that are no longer needed, as every node group update creates a new version and EC2 limits the number of versions
+// of a launch template. The default version, the placeholder version, the versions in use by node groups and the
+// most recent versions are kept.
+func PruneLaunchTemplateVersions(ctx context.Context, opts *PruneLaunchTemplateVersionsOptions) (err error) {
+	ctx, span := startSpan(ctx, "PruneLaunchTemplateVersions", opts.Config.Spec.DisplayName)
+	defer func() { endSpan(span, err) }()
+
+	templateID := opts.Config.Status.ManagedLaunchTemplateID
+	if templateID == "" {
+		return nil
+	}
+
+	keep := map[int64]bool{}
+	for _, version := range opts.InUseVersions {
+		versionNumber, err := strconv.ParseInt(version, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid in use version [%s] of launch template [%s]: %w", version, templateID, err)
+		}
+		keep[versionNumber] = true
+	}
+
+	versions, defaultVersion, err := GetLaunchTemplateVersionNumbers(ctx, &GetLaunchTemplateVersionNumbersOpts{
+		EC2Service:       opts.EC2Service,
+		LaunchTemplateID: templateID,
+	})
+	if err != nil {
+		return fmt.Errorf("error listing versions of launch template of cluster [%s]: %w", opts.Config.Name, err)
+	}
+	keep[defaultVersion] = true
+
+	var toDelete []*string
+	for i, version := range versions {
+		if i < opts.Keep || keep[version] || strconv.FormatInt(version, 10) == placeholderLaunchTemplateVersion {
+			continue
+		}
+		toDelete = append(toDelete, aws.String(strconv.FormatInt(version, 10)))
+	}
+
+	for len(toDelete) != 0 {
+		n := len(toDelete)
+		if n > maxLaunchTemplateVersionsPerDelete {
+			n = maxLaunchTemplateVersionsPerDelete
+		}
+		batch := toDelete[:n:n]
+		toDelete = toDelete[n:]
+		DeleteLaunchTemplateVersions(ctx, opts.EC2Service, templateID, batch)
+	}
+
+	return nil
+}
+
 func launchTemplateVersionDoesNotExist(errorCode string) bool {
 	return errorCode == ec2.LaunchTemplateErrorCodeLaunchTemplateVersionDoesNotExist ||
 		errorCode == ec2.LaunchTemplateErrorCodeLaunchTemplateIdDoesNotExist
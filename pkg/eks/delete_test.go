@@ -1,13 +1,268 @@
 package eks
 
 import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/eks"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
 	"github.com/rancher/eks-operator/pkg/eks/services/mock_services"
 )
 
+var _ = Describe("DeleteCluster", func() {
+	var (
+		mockController       *gomock.Controller
+		eksServiceMock       *mock_services.MockEKSServiceInterface
+		deleteClusterOptions *DeleteClusterOptions
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+		deleteClusterOptions = &DeleteClusterOptions{
+			EKSService: eksServiceMock,
+			Config: &eksv1.EKSClusterConfig{
+				Spec: eksv1.EKSClusterConfigSpec{
+					DisplayName: "test",
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should delete the cluster", func() {
+		eksServiceMock.EXPECT().DeleteCluster(gomock.Any(), &eks.DeleteClusterInput{
+			Name: aws.String("test"),
+		}).Return(&eks.DeleteClusterOutput{}, nil)
+
+		Expect(DeleteCluster(context.Background(), deleteClusterOptions)).To(Succeed())
+	})
+
+	It("should succeed if the cluster is already deleted", func() {
+		eksServiceMock.EXPECT().DeleteCluster(gomock.Any(), gomock.Any()).Return(nil, awserr.New(eks.ErrCodeResourceNotFoundException, "", nil))
+
+		Expect(DeleteCluster(context.Background(), deleteClusterOptions)).To(Succeed())
+	})
+
+	It("should fail to delete the cluster if DeleteCluster returns error", func() {
+		eksServiceMock.EXPECT().DeleteCluster(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+		Expect(DeleteCluster(context.Background(), deleteClusterOptions)).ToNot(Succeed())
+	})
+})
+
+var _ = Describe("DeleteNodeInstanceRole", func() {
+	var (
+		mockController            *gomock.Controller
+		cloudFormationServiceMock *mock_services.MockCloudFormationServiceInterface
+		deleteRoleOptions         *DeleteNodeInstanceRoleOptions
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		cloudFormationServiceMock = mock_services.NewMockCloudFormationServiceInterface(mockController)
+		deleteRoleOptions = &DeleteNodeInstanceRoleOptions{
+			CloudFormationService: cloudFormationServiceMock,
+			Config: &eksv1.EKSClusterConfig{
+				Spec: eksv1.EKSClusterConfigSpec{
+					DisplayName: "test",
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	describeStack := func(displayName string) {
+		cloudFormationServiceMock.EXPECT().DescribeStacks(gomock.Any(), &cloudformation.DescribeStacksInput{
+			StackName: aws.String("test-node-instance-role"),
+		}).Return(&cloudformation.DescribeStacksOutput{
+			Stacks: []*cloudformation.Stack{
+				{
+					StackName: aws.String("test-node-instance-role"),
+					Tags:      []*cloudformation.Tag{{Key: aws.String("displayName"), Value: aws.String(displayName)}},
+				},
+			},
+		}, nil)
+	}
+
+	It("should delete the stack owned by the cluster", func() {
+		describeStack("test")
+		cloudFormationServiceMock.EXPECT().DeleteStack(gomock.Any(), &cloudformation.DeleteStackInput{
+			StackName: aws.String("test-node-instance-role"),
+		}).Return(&cloudformation.DeleteStackOutput{}, nil)
+
+		Expect(DeleteNodeInstanceRole(context.Background(), deleteRoleOptions)).To(Succeed())
+	})
+
+	It("should drop the lock of the deleted stack", func() {
+		stackLocks.Store("test-node-instance-role", &sync.Mutex{})
+		describeStack("test")
+		cloudFormationServiceMock.EXPECT().DeleteStack(gomock.Any(), gomock.Any()).Return(&cloudformation.DeleteStackOutput{}, nil)
+
+		Expect(DeleteNodeInstanceRole(context.Background(), deleteRoleOptions)).To(Succeed())
+		_, ok := stackLocks.Load("test-node-instance-role")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should keep the lock of the stack if DeleteStack returns error", func() {
+		stackLocks.Store("test-node-instance-role", &sync.Mutex{})
+		defer stackLocks.Delete("test-node-instance-role")
+		describeStack("test")
+		cloudFormationServiceMock.EXPECT().DeleteStack(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+		Expect(DeleteNodeInstanceRole(context.Background(), deleteRoleOptions)).ToNot(Succeed())
+		_, ok := stackLocks.Load("test-node-instance-role")
+		Expect(ok).To(BeTrue())
+	})
+
+	It("should not delete the stack if the role is shared with other clusters", func() {
+		deleteRoleOptions.SharedWith = []string{"other"}
+
+		Expect(DeleteNodeInstanceRole(context.Background(), deleteRoleOptions)).To(Succeed())
+	})
+
+	It("should not delete a stack not created for the cluster", func() {
+		describeStack("other")
+
+		Expect(DeleteNodeInstanceRole(context.Background(), deleteRoleOptions)).To(Succeed())
+	})
+
+	It("should succeed if the stack is already deleted", func() {
+		cloudFormationServiceMock.EXPECT().DescribeStacks(gomock.Any(), gomock.Any()).Return(nil,
+			awserr.New("ValidationError", "Stack with id test-node-instance-role does not exist", nil))
+
+		Expect(DeleteNodeInstanceRole(context.Background(), deleteRoleOptions)).To(Succeed())
+	})
+
+	It("should fail to delete the stack if DeleteStack returns error", func() {
+		describeStack("test")
+		cloudFormationServiceMock.EXPECT().DeleteStack(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+		Expect(DeleteNodeInstanceRole(context.Background(), deleteRoleOptions)).ToNot(Succeed())
+	})
+})
+
+var _ = Describe("DeleteNodeGroup", func() {
+	var (
+		mockController         *gomock.Controller
+		eksServiceMock         *mock_services.MockEKSServiceInterface
+		deleteNodeGroupOptions *DeleteNodeGroupOptions
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+		deleteNodeGroupOptions = &DeleteNodeGroupOptions{
+			EKSService: eksServiceMock,
+			Config: &eksv1.EKSClusterConfig{
+				Spec: eksv1.EKSClusterConfigSpec{
+					DisplayName: "test",
+				},
+				Status: eksv1.EKSClusterConfigStatus{
+					ManagedLaunchTemplateID: "managed",
+				},
+			},
+			NodeGroup: eksv1.NodeGroup{
+				NodegroupName: aws.String("test"),
+			},
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	expectNodegroup := func(launchTemplate *eks.LaunchTemplateSpecification) {
+		eksServiceMock.EXPECT().DescribeNodegroup(gomock.Any(), &eks.DescribeNodegroupInput{
+			ClusterName:   aws.String("test"),
+			NodegroupName: aws.String("test"),
+		}).Return(&eks.DescribeNodegroupOutput{
+			Nodegroup: &eks.Nodegroup{
+				Status:         aws.String(eks.NodegroupStatusActive),
+				LaunchTemplate: launchTemplate,
+			},
+		}, nil)
+		eksServiceMock.EXPECT().DeleteNodegroup(gomock.Any(), &eks.DeleteNodegroupInput{
+			ClusterName:   aws.String("test"),
+			NodegroupName: aws.String("test"),
+		}).Return(&eks.DeleteNodegroupOutput{}, nil)
+	}
+
+	It("should delete the node group and return its managed launch template version", func() {
+		expectNodegroup(&eks.LaunchTemplateSpecification{Id: aws.String("managed"), Version: aws.String("3")})
+
+		version, deleting, err := DeleteNodeGroup(context.Background(), deleteNodeGroupOptions)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleting).To(BeTrue())
+		Expect(version).To(Equal(aws.String("3")))
+	})
+
+	It("should not return the version of a custom launch template", func() {
+		expectNodegroup(&eks.LaunchTemplateSpecification{Id: aws.String("custom"), Version: aws.String("3")})
+
+		version, deleting, err := DeleteNodeGroup(context.Background(), deleteNodeGroupOptions)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleting).To(BeTrue())
+		Expect(version).To(BeNil())
+	})
+
+	It("should not return the default version of the managed launch template", func() {
+		expectNodegroup(&eks.LaunchTemplateSpecification{Id: aws.String("managed"), Version: aws.String("1")})
+
+		version, _, err := DeleteNodeGroup(context.Background(), deleteNodeGroupOptions)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(version).To(BeNil())
+	})
+
+	It("should succeed if the node group is already deleted", func() {
+		eksServiceMock.EXPECT().DescribeNodegroup(gomock.Any(), gomock.Any()).Return(nil, awserr.New(eks.ErrCodeResourceNotFoundException, "", nil))
+
+		_, deleting, err := DeleteNodeGroup(context.Background(), deleteNodeGroupOptions)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleting).To(BeFalse())
+	})
+
+	It("should not delete the node group again if it is already deleting", func() {
+		eksServiceMock.EXPECT().DescribeNodegroup(gomock.Any(), gomock.Any()).Return(&eks.DescribeNodegroupOutput{
+			Nodegroup: &eks.Nodegroup{
+				Status: aws.String(eks.NodegroupStatusDeleting),
+			},
+		}, nil)
+
+		version, deleting, err := DeleteNodeGroup(context.Background(), deleteNodeGroupOptions)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deleting).To(BeTrue())
+		Expect(version).To(BeNil())
+	})
+
+	It("should fail to delete the node group if DeleteNodegroup returns error", func() {
+		eksServiceMock.EXPECT().DescribeNodegroup(gomock.Any(), gomock.Any()).Return(&eks.DescribeNodegroupOutput{
+			Nodegroup: &eks.Nodegroup{
+				Status: aws.String(eks.NodegroupStatusActive),
+			},
+		}, nil)
+		eksServiceMock.EXPECT().DeleteNodegroup(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+		_, _, err := DeleteNodeGroup(context.Background(), deleteNodeGroupOptions)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
 var _ = Describe("deleteLaunchTemplateVersions", func() {
 	var (
 		mockController *gomock.Controller
@@ -27,11 +282,174 @@ var _ = Describe("deleteLaunchTemplateVersions", func() {
 		templateID := "templateID"
 		templateVersions := []*string{aws.String("1"), aws.String("2")}
 
-		ec2ServiceMock.EXPECT().DeleteLaunchTemplateVersions(&ec2.DeleteLaunchTemplateVersionsInput{
+		ec2ServiceMock.EXPECT().DeleteLaunchTemplateVersions(gomock.Any(), &ec2.DeleteLaunchTemplateVersionsInput{
 			LaunchTemplateId: aws.String(templateID),
 			Versions:         templateVersions,
 		}).Return(nil, nil)
 
-		DeleteLaunchTemplateVersions(ec2ServiceMock, templateID, templateVersions)
+		DeleteLaunchTemplateVersions(context.Background(), ec2ServiceMock, templateID, templateVersions)
+	})
+})
+
+var _ = Describe("DeleteLaunchTemplate", func() {
+	var (
+		mockController              *gomock.Controller
+		ec2ServiceMock              *mock_services.MockEC2ServiceInterface
+		deleteLaunchTemplateOptions *DeleteLaunchTemplateOptions
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		ec2ServiceMock = mock_services.NewMockEC2ServiceInterface(mockController)
+		deleteLaunchTemplateOptions = &DeleteLaunchTemplateOptions{
+			EC2Service: ec2ServiceMock,
+			Config: &eksv1.EKSClusterConfig{
+				Spec: eksv1.EKSClusterConfigSpec{
+					DisplayName: "test",
+				},
+				Status: eksv1.EKSClusterConfigStatus{
+					ManagedLaunchTemplateID: "templateID",
+				},
+			},
+			RetryInterval: time.Millisecond,
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should delete the launch template and clear its ID", func() {
+		ec2ServiceMock.EXPECT().DeleteLaunchTemplate(gomock.Any(), &ec2.DeleteLaunchTemplateInput{
+			LaunchTemplateId: aws.String("templateID"),
+		}).Return(&ec2.DeleteLaunchTemplateOutput{}, nil)
+
+		Expect(DeleteLaunchTemplate(context.Background(), deleteLaunchTemplateOptions)).To(Succeed())
+		Expect(deleteLaunchTemplateOptions.Config.Status.ManagedLaunchTemplateID).To(BeEmpty())
+	})
+
+	It("should succeed if the launch template is already deleted", func() {
+		ec2ServiceMock.EXPECT().DeleteLaunchTemplate(gomock.Any(), gomock.Any()).Return(nil,
+			awserr.New("InvalidLaunchTemplateId.NotFound", "The specified launch template, with template ID templateID, does not exist.", nil))
+
+		Expect(DeleteLaunchTemplate(context.Background(), deleteLaunchTemplateOptions)).To(Succeed())
+		Expect(deleteLaunchTemplateOptions.Config.Status.ManagedLaunchTemplateID).To(BeEmpty())
+	})
+
+	It("should not call EC2 if there is no managed launch template", func() {
+		deleteLaunchTemplateOptions.Config.Status.ManagedLaunchTemplateID = ""
+		ec2ServiceMock.EXPECT().DeleteLaunchTemplate(gomock.Any(), gomock.Any()).Times(0)
+
+		Expect(DeleteLaunchTemplate(context.Background(), deleteLaunchTemplateOptions)).To(Succeed())
+	})
+
+	It("should retry deleting the launch template while it is still in use", func() {
+		gomock.InOrder(
+			ec2ServiceMock.EXPECT().DeleteLaunchTemplate(gomock.Any(), gomock.Any()).Return(nil,
+				awserr.New("DependencyViolation", "The launch template is in use.", nil)),
+			ec2ServiceMock.EXPECT().DeleteLaunchTemplate(gomock.Any(), gomock.Any()).Return(&ec2.DeleteLaunchTemplateOutput{}, nil),
+		)
+
+		Expect(DeleteLaunchTemplate(context.Background(), deleteLaunchTemplateOptions)).To(Succeed())
+		Expect(deleteLaunchTemplateOptions.Config.Status.ManagedLaunchTemplateID).To(BeEmpty())
+	})
+
+	It("should fail to delete the launch template if DeleteLaunchTemplate returns error", func() {
+		ec2ServiceMock.EXPECT().DeleteLaunchTemplate(gomock.Any(), gomock.Any()).Return(nil, errors.New("error")).Times(5)
+
+		Expect(DeleteLaunchTemplate(context.Background(), deleteLaunchTemplateOptions)).ToNot(Succeed())
+		Expect(deleteLaunchTemplateOptions.Config.Status.ManagedLaunchTemplateID).To(Equal("templateID"))
+	})
+})
+
+var _ = Describe("PruneLaunchTemplateVersions", func() {
+	var (
+		mockController *gomock.Controller
+		ec2ServiceMock *mock_services.MockEC2ServiceInterface
+		pruneOptions   *PruneLaunchTemplateVersionsOptions
+	)
+
+	launchTemplateVersions := func(defaultVersion int64, versions ...int64) *ec2.DescribeLaunchTemplateVersionsOutput {
+		output := &ec2.DescribeLaunchTemplateVersionsOutput{}
+		for _, version := range versions {
+			output.LaunchTemplateVersions = append(output.LaunchTemplateVersions, &ec2.LaunchTemplateVersion{
+				VersionNumber:  aws.Int64(version),
+				DefaultVersion: aws.Bool(version == defaultVersion),
+			})
+		}
+		return output
+	}
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		ec2ServiceMock = mock_services.NewMockEC2ServiceInterface(mockController)
+		pruneOptions = &PruneLaunchTemplateVersionsOptions{
+			EC2Service: ec2ServiceMock,
+			Config: &eksv1.EKSClusterConfig{
+				Spec: eksv1.EKSClusterConfigSpec{
+					DisplayName: "test",
+				},
+				Status: eksv1.EKSClusterConfigStatus{
+					ManagedLaunchTemplateID: "templateID",
+				},
+			},
+			InUseVersions: []string{"3"},
+			Keep:          2,
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should keep the default, in use and most recent versions", func() {
+		ec2ServiceMock.EXPECT().DescribeLaunchTemplateVersions(gomock.Any(), &ec2.DescribeLaunchTemplateVersionsInput{
+			LaunchTemplateId: aws.String("templateID"),
+		}).Return(launchTemplateVersions(5, 1, 2, 3, 4, 5, 6, 7, 8), nil)
+		ec2ServiceMock.EXPECT().DeleteLaunchTemplateVersions(gomock.Any(), &ec2.DeleteLaunchTemplateVersionsInput{
+			LaunchTemplateId: aws.String("templateID"),
+			Versions:         aws.StringSlice([]string{"6", "4", "2"}),
+		}).Return(&ec2.DeleteLaunchTemplateVersionsOutput{}, nil)
+
+		Expect(PruneLaunchTemplateVersions(context.Background(), pruneOptions)).To(Succeed())
+	})
+
+	It("should list the versions across all pages", func() {
+		firstPage := launchTemplateVersions(1, 4, 3)
+		firstPage.NextToken = aws.String("token")
+		ec2ServiceMock.EXPECT().DescribeLaunchTemplateVersions(gomock.Any(), &ec2.DescribeLaunchTemplateVersionsInput{
+			LaunchTemplateId: aws.String("templateID"),
+		}).Return(firstPage, nil)
+		ec2ServiceMock.EXPECT().DescribeLaunchTemplateVersions(gomock.Any(), &ec2.DescribeLaunchTemplateVersionsInput{
+			LaunchTemplateId: aws.String("templateID"),
+			NextToken:        aws.String("token"),
+		}).Return(launchTemplateVersions(1, 2, 1), nil)
+		ec2ServiceMock.EXPECT().DeleteLaunchTemplateVersions(gomock.Any(), &ec2.DeleteLaunchTemplateVersionsInput{
+			LaunchTemplateId: aws.String("templateID"),
+			Versions:         aws.StringSlice([]string{"2"}),
+		}).Return(&ec2.DeleteLaunchTemplateVersionsOutput{}, nil)
+
+		Expect(PruneLaunchTemplateVersions(context.Background(), pruneOptions)).To(Succeed())
+	})
+
+	It("should not delete anything if every version is kept", func() {
+		ec2ServiceMock.EXPECT().DescribeLaunchTemplateVersions(gomock.Any(), gomock.Any()).Return(launchTemplateVersions(1, 1, 2, 3), nil)
+		ec2ServiceMock.EXPECT().DeleteLaunchTemplateVersions(gomock.Any(), gomock.Any()).Times(0)
+
+		Expect(PruneLaunchTemplateVersions(context.Background(), pruneOptions)).To(Succeed())
+	})
+
+	It("should not call EC2 if there is no managed launch template", func() {
+		pruneOptions.Config.Status.ManagedLaunchTemplateID = ""
+		ec2ServiceMock.EXPECT().DescribeLaunchTemplateVersions(gomock.Any(), gomock.Any()).Times(0)
+
+		Expect(PruneLaunchTemplateVersions(context.Background(), pruneOptions)).To(Succeed())
+	})
+
+	It("should fail if DescribeLaunchTemplateVersions returns error", func() {
+		ec2ServiceMock.EXPECT().DescribeLaunchTemplateVersions(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+		ec2ServiceMock.EXPECT().DeleteLaunchTemplateVersions(gomock.Any(), gomock.Any()).Times(0)
+
+		Expect(PruneLaunchTemplateVersions(context.Background(), pruneOptions)).ToNot(Succeed())
 	})
 })
@@ -0,0 +1,64 @@
+package eks
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+)
+
+const (
+	kmsServiceName = "kms"
+
+	secretsEncryptionResource = "secrets"
+)
+
+// supportedEncryptionResources are the resources EKS can encrypt with the KMS key of the cluster.
+var supportedEncryptionResources = map[string]bool{
+	secretsEncryptionResource: true,
+}
+
+// ValidateEncryptionConfig catches secrets encryption settings EKS would reject only after the cluster creation has
+// started. The KMS key has to be given by its key or alias ARN and live in the region of the cluster. Node volumes
+// are encrypted through the launch template of a node group and are not checked against the cluster key.
+func ValidateEncryptionConfig(config *eksv1.EKSClusterConfig) error {
+	if !aws.BoolValue(config.Spec.SecretsEncryption) {
+		return nil
+	}
+
+	kmsKey := aws.StringValue(config.Spec.KmsKey)
+	if kmsKey == "" {
+		return fmt.Errorf("kmsKey must be specified for cluster [%s] when secretsEncryption is enabled", config.Name)
+	}
+
+	keyARN, err := arn.Parse(kmsKey)
+	if err != nil {
+		return fmt.Errorf("kmsKey [%s] for cluster [%s] is not a valid ARN: %w", kmsKey, config.Name, err)
+	}
+	if keyARN.Service != kmsServiceName || (!strings.HasPrefix(keyARN.Resource, "key/") && !strings.HasPrefix(keyARN.Resource, "alias/")) {
+		return fmt.Errorf("kmsKey [%s] for cluster [%s] must be the ARN of a KMS key or alias", kmsKey, config.Name)
+	}
+	if config.Spec.Region != "" && keyARN.Region != config.Spec.Region {
+		return fmt.Errorf("kmsKey [%s] for cluster [%s] is in region [%s], it must be in the region of the cluster [%s]",
+			kmsKey, config.Name, keyARN.Region, config.Spec.Region)
+	}
+
+	for _, resource := range config.Spec.EncryptionResources {
+		if !supportedEncryptionResources[resource] {
+			return fmt.Errorf("encryptionResources for cluster [%s] contains [%s], only [%s] can be encrypted",
+				config.Name, resource, secretsEncryptionResource)
+		}
+	}
+
+	return nil
+}
+
+// getEncryptionResources returns the resources encrypted with the KMS key of the cluster, secrets unless set.
+func getEncryptionResources(config *eksv1.EKSClusterConfig) []string {
+	if len(config.Spec.EncryptionResources) == 0 {
+		return []string{secretsEncryptionResource}
+	}
+	return config.Spec.EncryptionResources
+}
@@ -0,0 +1,69 @@
+package eks
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("ValidateEncryptionConfig", func() {
+	DescribeTable("should validate the secrets encryption config",
+		func(secretsEncryption bool, kmsKey *string, expectedError string) {
+			config := &eksv1.EKSClusterConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: eksv1.EKSClusterConfigSpec{
+					Region:            "us-west-2",
+					SecretsEncryption: aws.Bool(secretsEncryption),
+					KmsKey:            kmsKey,
+				},
+			}
+
+			err := ValidateEncryptionConfig(config)
+			if expectedError == "" {
+				Expect(err).ToNot(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(ContainSubstring(expectedError)))
+			}
+		},
+		Entry("encryption disabled", false, nil, ""),
+		Entry("key ARN", true, aws.String("arn:aws:kms:us-west-2:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"), ""),
+		Entry("alias ARN", true, aws.String("arn:aws:kms:us-west-2:123456789012:alias/eks"), ""),
+		Entry("nil key", true, nil, "kmsKey must be specified for cluster [test]"),
+		Entry("empty key", true, aws.String(""), "kmsKey must be specified for cluster [test]"),
+		Entry("key ID instead of ARN", true, aws.String("1234abcd-12ab-34cd-56ef-1234567890ab"), "is not a valid ARN"),
+		Entry("ARN of another service", true, aws.String("arn:aws:iam::123456789012:role/eks"), "must be the ARN of a KMS key or alias"),
+		Entry("key in another region", true, aws.String("arn:aws:kms:eu-west-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"),
+			"is in region [eu-west-1], it must be in the region of the cluster [us-west-2]"),
+	)
+
+	DescribeTable("should validate the encryption resources",
+		func(resources []string, expectedError string) {
+			config := &eksv1.EKSClusterConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: eksv1.EKSClusterConfigSpec{
+					Region:              "us-west-2",
+					SecretsEncryption:   aws.Bool(true),
+					KmsKey:              aws.String("arn:aws:kms:us-west-2:123456789012:alias/eks"),
+					EncryptionResources: resources,
+				},
+			}
+
+			err := ValidateEncryptionConfig(config)
+			if expectedError == "" {
+				Expect(err).ToNot(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(expectedError))
+			}
+		},
+		Entry("default", nil, ""),
+		Entry("secrets", []string{"secrets"}, ""),
+		Entry("unsupported resource", []string{"secrets", "configmaps"},
+			"encryptionResources for cluster [test] contains [configmaps], only [secrets] can be encrypted"),
+	)
+})
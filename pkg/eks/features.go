@@ -0,0 +1,80 @@
+package eks
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/blang/semver"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+)
+
+// clusterFeature is a config option that is only available starting with a kubernetes version and, optionally,
+// a platform version of that kubernetes version.
+type clusterFeature struct {
+	name                 string
+	minKubernetesVersion string
+	minPlatformVersion   int
+	requested            func(config *eksv1.EKSClusterConfig) bool
+}
+
+var clusterFeatures = []clusterFeature{
+	{
+		name:                 "nodegroup taints",
+		minKubernetesVersion: "1.19",
+		requested: func(config *eksv1.EKSClusterConfig) bool {
+			for _, ng := range config.Spec.NodeGroups {
+				if len(ng.Taints) != 0 {
+					return true
+				}
+			}
+			return false
+		},
+	},
+}
+
+// ValidateClusterFeatures returns an error if the config requests a feature that is not available on the
+// kubernetes and platform version of the upstream cluster.
+func ValidateClusterFeatures(config *eksv1.EKSClusterConfig, cluster *eks.Cluster) error {
+	if cluster == nil {
+		return nil
+	}
+
+	return validateClusterFeatures(clusterFeatures, config, aws.StringValue(cluster.Version), aws.StringValue(cluster.PlatformVersion))
+}
+
+func validateClusterFeatures(features []clusterFeature, config *eksv1.EKSClusterConfig, version, platformVersion string) error {
+	errs := make([]string, 0)
+	for _, feature := range features {
+		if !feature.requested(config) {
+			continue
+		}
+
+		clusterVersion, err := semver.New(fmt.Sprintf("%s.0", version))
+		if err != nil {
+			return fmt.Errorf("improper version format for cluster [%s]: %s", config.Name, version)
+		}
+		minVersion := semver.MustParse(fmt.Sprintf("%s.0", feature.minKubernetesVersion))
+		if clusterVersion.LT(minVersion) {
+			errs = append(errs, fmt.Sprintf("%s requires kubernetes version %s or later, cluster [%s] is running %s",
+				feature.name, feature.minKubernetesVersion, config.Name, version))
+			continue
+		}
+
+		// Platform versions are numbered per kubernetes version, so they only gate the minimum kubernetes version.
+		if feature.minPlatformVersion == 0 || !clusterVersion.EQ(minVersion) {
+			continue
+		}
+		if platform, err := strconv.Atoi(strings.TrimPrefix(platformVersion, "eks.")); err != nil || platform < feature.minPlatformVersion {
+			errs = append(errs, fmt.Sprintf("%s requires platform version eks.%d or later on kubernetes version %s, cluster [%s] is running %s",
+				feature.name, feature.minPlatformVersion, feature.minKubernetesVersion, config.Name, platformVersion))
+		}
+	}
+	if len(errs) != 0 {
+		return fmt.Errorf(strings.Join(errs, ";"))
+	}
+
+	return nil
+}
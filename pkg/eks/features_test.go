@@ -0,0 +1,76 @@
+package eks
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eks"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("ValidateClusterFeatures", func() {
+	var config *eksv1.EKSClusterConfig
+
+	BeforeEach(func() {
+		config = &eksv1.EKSClusterConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test-cluster",
+			},
+			Spec: eksv1.EKSClusterConfigSpec{
+				NodeGroups: []eksv1.NodeGroup{
+					{
+						NodegroupName: aws.String("test"),
+						Taints: []eksv1.Taint{
+							{Key: aws.String("test"), Effect: aws.String(eks.TaintEffectNoSchedule)},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	It("should allow node group taints on a supported version", func() {
+		Expect(ValidateClusterFeatures(config, &eks.Cluster{
+			Version:         aws.String("1.19"),
+			PlatformVersion: aws.String("eks.1"),
+		})).To(Succeed())
+	})
+
+	It("should reject node group taints on an older version", func() {
+		err := ValidateClusterFeatures(config, &eks.Cluster{
+			Version:         aws.String("1.18"),
+			PlatformVersion: aws.String("eks.3"),
+		})
+		Expect(err).To(MatchError(ContainSubstring("nodegroup taints")))
+	})
+
+	It("should not validate features that are not requested", func() {
+		config.Spec.NodeGroups[0].Taints = nil
+		Expect(ValidateClusterFeatures(config, &eks.Cluster{
+			Version: aws.String("1.18"),
+		})).To(Succeed())
+	})
+
+	DescribeTable("should gate features on the platform version",
+		func(version, platformVersion string, valid bool) {
+			features := []clusterFeature{
+				{
+					name:                 "test",
+					minKubernetesVersion: "1.23",
+					minPlatformVersion:   3,
+					requested:            func(*eksv1.EKSClusterConfig) bool { return true },
+				},
+			}
+			err := validateClusterFeatures(features, config, version, platformVersion)
+			if valid {
+				Expect(err).ToNot(HaveOccurred())
+				return
+			}
+			Expect(err).To(MatchError(ContainSubstring("platform version eks.3")))
+		},
+		Entry("older platform version", "1.23", "eks.2", false),
+		Entry("minimum platform version", "1.23", "eks.3", true),
+		Entry("newer kubernetes version", "1.24", "eks.1", true),
+	)
+})
@@ -1,34 +1,539 @@
 package eks
 
 import (
+	"context"
 	"fmt"
+	"regexp"
+	"sort"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/eks"
 	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
 	"github.com/rancher/eks-operator/pkg/eks/services"
+	"github.com/rancher/wrangler/pkg/genericcondition"
+	corev1 "k8s.io/api/core/v1"
 )
 
+const (
+	// ClusterConditionReady is true when the control plane is active and reports no health issues.
+	ClusterConditionReady = "Ready"
+	// ClusterConditionDegraded is true when the control plane reports health issues or has failed.
+	ClusterConditionDegraded = "Degraded"
+)
+
+// clusterIssueReasons maps EKS cluster health issue codes to condition reasons.
+var clusterIssueReasons = map[string]string{
+	eks.ClusterIssueCodeAccessDenied:          "PermissionsMissing",
+	eks.ClusterIssueCodeClusterUnreachable:    "ControlPlaneUnreachable",
+	eks.ClusterIssueCodeConfigurationConflict: "ConfigurationConflict",
+	eks.ClusterIssueCodeInternalFailure:       "InternalFailure",
+	eks.ClusterIssueCodeResourceLimitExceeded: "ResourceLimitExceeded",
+	eks.ClusterIssueCodeResourceNotFound:      "ResourceNotFound",
+}
+
+// clusterStatusReasons maps EKS cluster statuses other than active to condition reasons.
+var clusterStatusReasons = map[string]string{
+	eks.ClusterStatusCreating: "Provisioning",
+	eks.ClusterStatusUpdating: "Updating",
+	eks.ClusterStatusDeleting: "Deleting",
+	eks.ClusterStatusFailed:   "Failed",
+	eks.ClusterStatusPending:  "Pending",
+}
+
 type GetClusterStatusOpts struct {
 	EKSService services.EKSServiceInterface
 	Config     *eksv1.EKSClusterConfig
 }
 
-func GetClusterState(opts *GetClusterStatusOpts) (*eks.DescribeClusterOutput, error) {
+func GetClusterState(ctx context.Context, opts *GetClusterStatusOpts) (*eks.DescribeClusterOutput, error) {
 	return opts.EKSService.DescribeCluster(
+		ctx,
 		&eks.DescribeClusterInput{
 			Name: aws.String(opts.Config.Spec.DisplayName),
 		})
 }
 
+type GetNodegroupStateOpts struct {
+	EKSService    services.EKSServiceInterface
+	Config        *eksv1.EKSClusterConfig
+	NodegroupName string
+}
+
+func GetNodegroupState(ctx context.Context, opts *GetNodegroupStateOpts) (*eks.DescribeNodegroupOutput, error) {
+	return opts.EKSService.DescribeNodegroup(
+		ctx,
+		&eks.DescribeNodegroupInput{
+			ClusterName:   aws.String(opts.Config.Spec.DisplayName),
+			NodegroupName: aws.String(opts.NodegroupName),
+		})
+}
+
+type ListNodegroupsOpts struct {
+	EKSService services.EKSServiceInterface
+	Config     *eksv1.EKSClusterConfig
+}
+
+// ListNodegroups returns the names of all node groups of the cluster, following the pages of the results.
+func ListNodegroups(ctx context.Context, opts *ListNodegroupsOpts) ([]string, error) {
+	var nodegroups []string
+	input := &eks.ListNodegroupsInput{
+		ClusterName: aws.String(opts.Config.Spec.DisplayName),
+	}
+	for {
+		output, err := opts.EKSService.ListNodegroups(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("error listing nodegroups for cluster [%s]: %w", opts.Config.Name, err)
+		}
+		nodegroups = append(nodegroups, aws.StringValueSlice(output.Nodegroups)...)
+
+		if output.NextToken == nil {
+			return nodegroups, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+type ListClustersOpts struct {
+	EKSService services.EKSServiceInterface
+}
+
+// ListClusters returns the names of the clusters in the region of the EKS service across all the pages of results.
+func ListClusters(ctx context.Context, opts *ListClustersOpts) ([]string, error) {
+	var names []string
+	err := opts.EKSService.ListClustersPages(ctx, &eks.ListClustersInput{}, func(page *eks.ListClustersOutput, _ bool) bool {
+		names = append(names, aws.StringValueSlice(page.Clusters)...)
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing clusters: %w", err)
+	}
+
+	return names, nil
+}
+
+type RefreshClusterStatusOpts struct {
+	EKSService services.EKSServiceInterface
+	Config     *eksv1.EKSClusterConfig
+}
+
+// RefreshClusterStatus describes the cluster and returns the config's status populated with the upstream
+// cluster's networking, cluster security group, endpoint, certificate authority, ARN, encryption key and OIDC
+// issuer. Fields that are not managed upstream, such as the phase or the managed launch template, are carried over
+// from the config. While the cluster is still creating some of the upstream data may not be available yet, in which
+// case the corresponding fields are left as they were.
+func RefreshClusterStatus(ctx context.Context, opts *RefreshClusterStatusOpts) (*eksv1.EKSClusterConfigStatus, error) {
+	clusterState, err := GetClusterState(ctx, &GetClusterStatusOpts{
+		EKSService: opts.EKSService,
+		Config:     opts.Config,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error describing cluster [%s]: %w", opts.Config.Name, err)
+	}
+
+	status := opts.Config.Status.DeepCopy()
+	cluster := clusterState.Cluster
+	if cluster == nil {
+		return status, nil
+	}
+
+	if cluster.Arn != nil {
+		status.ClusterARN = aws.StringValue(cluster.Arn)
+	}
+	if cluster.Endpoint != nil {
+		status.Endpoint = aws.StringValue(cluster.Endpoint)
+	}
+	if cluster.CertificateAuthority != nil && cluster.CertificateAuthority.Data != nil {
+		status.CertificateAuthorityData = aws.StringValue(cluster.CertificateAuthority.Data)
+	}
+	if vpcConfig := cluster.ResourcesVpcConfig; vpcConfig != nil {
+		if vpcConfig.VpcId != nil {
+			status.VirtualNetwork = aws.StringValue(vpcConfig.VpcId)
+		}
+		if vpcConfig.SubnetIds != nil {
+			status.Subnets = aws.StringValueSlice(vpcConfig.SubnetIds)
+		}
+		if vpcConfig.SecurityGroupIds != nil {
+			status.SecurityGroups = aws.StringValueSlice(vpcConfig.SecurityGroupIds)
+		}
+	}
+	if clusterSecurityGroupID := GetClusterSecurityGroupID(cluster); clusterSecurityGroupID != "" {
+		status.ClusterSecurityGroupID = clusterSecurityGroupID
+	}
+	for _, encryptionConfig := range cluster.EncryptionConfig {
+		if encryptionConfig.Provider != nil && encryptionConfig.Provider.KeyArn != nil {
+			status.KmsKeyARN = aws.StringValue(encryptionConfig.Provider.KeyArn)
+			break
+		}
+	}
+	if issuer := getClusterOIDCIssuer(cluster); issuer != "" {
+		status.OIDCIssuer = issuer
+	}
+
+	return status, nil
+}
+
+// GetClusterSecurityGroupID returns the ID of the security group EKS creates for the control plane and managed
+// nodes of the cluster, as opposed to the security groups given on creation. It is empty until EKS created it.
+func GetClusterSecurityGroupID(cluster *eks.Cluster) string {
+	if cluster == nil || cluster.ResourcesVpcConfig == nil {
+		return ""
+	}
+
+	return aws.StringValue(cluster.ResourcesVpcConfig.ClusterSecurityGroupId)
+}
+
+// GetClusterOIDCIssuer describes the cluster and returns the URL of its OIDC issuer, which IAM roles for service
+// accounts are trusted through. It returns an error if the cluster has no OIDC identity yet, as is the case while it
+// is creating.
+func GetClusterOIDCIssuer(ctx context.Context, opts *GetClusterStatusOpts) (string, error) {
+	clusterState, err := GetClusterState(ctx, opts)
+	if err != nil {
+		return "", fmt.Errorf("error describing cluster [%s]: %w", opts.Config.Name, err)
+	}
+
+	issuer := getClusterOIDCIssuer(clusterState.Cluster)
+	if issuer == "" {
+		return "", fmt.Errorf("cluster [%s] has no OIDC issuer yet", opts.Config.Name)
+	}
+	return issuer, nil
+}
+
+func getClusterOIDCIssuer(cluster *eks.Cluster) string {
+	if cluster == nil || cluster.Identity == nil || cluster.Identity.Oidc == nil {
+		return ""
+	}
+
+	return aws.StringValue(cluster.Identity.Oidc.Issuer)
+}
+
+// GetClusterConditions translates the status and health issues of a described cluster into the Ready and
+// Degraded conditions. Timestamps are left empty for the caller to set.
+func GetClusterConditions(cluster *eks.Cluster) []genericcondition.GenericCondition {
+	ready := genericcondition.GenericCondition{
+		Type:   ClusterConditionReady,
+		Status: corev1.ConditionTrue,
+	}
+	degraded := genericcondition.GenericCondition{
+		Type:   ClusterConditionDegraded,
+		Status: corev1.ConditionFalse,
+	}
+	if cluster == nil {
+		ready.Status = corev1.ConditionUnknown
+		degraded.Status = corev1.ConditionUnknown
+		return []genericcondition.GenericCondition{ready, degraded}
+	}
+
+	status := aws.StringValue(cluster.Status)
+	if status != eks.ClusterStatusActive {
+		ready.Status = corev1.ConditionFalse
+		ready.Reason = clusterStatusReasons[status]
+		if ready.Reason == "" {
+			ready.Reason = "Unknown"
+		}
+		ready.Message = fmt.Sprintf("cluster status is %s", status)
+	}
+	if status == eks.ClusterStatusFailed {
+		degraded.Status = corev1.ConditionTrue
+		degraded.Reason = ready.Reason
+		degraded.Message = ready.Message
+	}
+
+	var issues []*eks.ClusterIssue
+	if cluster.Health != nil {
+		issues = cluster.Health.Issues
+	}
+	if len(issues) != 0 {
+		reason := clusterIssueReasons[aws.StringValue(issues[0].Code)]
+		if reason == "" {
+			reason = "Unknown"
+		}
+
+		degraded.Status = corev1.ConditionTrue
+		degraded.Reason = reason
+		degraded.Message = strings.Join(clusterIssueMessages(issues), "; ")
+		if ready.Status == corev1.ConditionTrue {
+			ready.Status = corev1.ConditionFalse
+			ready.Reason = reason
+			ready.Message = degraded.Message
+		}
+	}
+
+	return []genericcondition.GenericCondition{ready, degraded}
+}
+
+func clusterIssueMessages(issues []*eks.ClusterIssue) []string {
+	messages := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		message := fmt.Sprintf("%s: %s", aws.StringValue(issue.Code), aws.StringValue(issue.Message))
+		if len(issue.ResourceIds) != 0 {
+			message = fmt.Sprintf("%s (%s)", message, strings.Join(aws.StringValueSlice(issue.ResourceIds), ", "))
+		}
+		messages = append(messages, message)
+	}
+
+	return messages
+}
+
+// ClusterPhase is the phase of an EKS cluster as seen by the operator.
+type ClusterPhase string
+
+const (
+	ClusterPhaseCreating ClusterPhase = "Creating"
+	ClusterPhaseActive   ClusterPhase = "Active"
+	ClusterPhaseUpdating ClusterPhase = "Updating"
+	ClusterPhaseDeleting ClusterPhase = "Deleting"
+	ClusterPhaseFailed   ClusterPhase = "Failed"
+	ClusterPhaseUnknown  ClusterPhase = "Unknown"
+)
+
+// clusterStatusPhases maps EKS cluster statuses to cluster phases. A pending cluster has not started creating yet.
+var clusterStatusPhases = map[string]ClusterPhase{
+	eks.ClusterStatusPending:  ClusterPhaseCreating,
+	eks.ClusterStatusCreating: ClusterPhaseCreating,
+	eks.ClusterStatusActive:   ClusterPhaseActive,
+	eks.ClusterStatusUpdating: ClusterPhaseUpdating,
+	eks.ClusterStatusDeleting: ClusterPhaseDeleting,
+	eks.ClusterStatusFailed:   ClusterPhaseFailed,
+}
+
+// ClusterPhaseState is the phase of a described cluster along with its health issues.
+type ClusterPhaseState struct {
+	Phase ClusterPhase
+	// Ready is true when the cluster is active and reports no health issues.
+	Ready  bool
+	Issues []string
+}
+
+// DeriveClusterPhase maps the status of a described cluster to a cluster phase and collects its health issues.
+// Statuses unknown to the operator map to ClusterPhaseUnknown.
+func DeriveClusterPhase(cluster *eks.Cluster) ClusterPhaseState {
+	if cluster == nil {
+		return ClusterPhaseState{Phase: ClusterPhaseUnknown}
+	}
+
+	phase, ok := clusterStatusPhases[aws.StringValue(cluster.Status)]
+	if !ok {
+		phase = ClusterPhaseUnknown
+	}
+
+	state := ClusterPhaseState{Phase: phase}
+	if cluster.Health != nil && len(cluster.Health.Issues) != 0 {
+		state.Issues = clusterIssueMessages(cluster.Health.Issues)
+	}
+	state.Ready = phase == ClusterPhaseActive && len(state.Issues) == 0
+
+	return state
+}
+
+const insufficientInstanceCapacityCode = "InsufficientInstanceCapacity"
+
+// insufficientCapacityMessage matches the scaling activity message EC2 returns when an instance type has no
+// capacity left in an availability zone, capturing the instance type and the availability zone.
+var insufficientCapacityMessage = regexp.MustCompile(`sufficient (\S+) capacity in the Availability Zone you requested \(([^)]+)\)`)
+
+// nodegroupIssueRemediations suggests fixes for node group health issues that prevent nodes from launching.
+var nodegroupIssueRemediations = map[string]string{
+	eks.NodegroupIssueCodeInsufficientFreeAddresses: "add subnets with free IP addresses to the node group",
+	eks.NodegroupIssueCodeInstanceLimitExceeded:     "request an EC2 instance limit increase or use a different instance type",
+	eks.NodegroupIssueCodeEc2subnetNotFound:         "update the node group subnets to existing subnets",
+	eks.NodegroupIssueCodeIamNodeRoleNotFound:       "recreate the node role or update the node group node role",
+}
+
+type GetNodegroupLaunchReportOpts struct {
+	AutoScalingService services.AutoScalingServiceInterface
+	Config             *eksv1.EKSClusterConfig
+	Nodegroup          *eks.Nodegroup
+}
+
+// NodegroupLaunchIssue is a problem preventing a node group from reaching its desired size.
+type NodegroupLaunchIssue struct {
+	Code             string
+	Message          string
+	InstanceType     string
+	AvailabilityZone string
+	Remediation      string
+}
+
+// NodegroupLaunchReport combines the health issues of a node group with the failed launches of its auto
+// scaling groups.
+type NodegroupLaunchReport struct {
+	NodegroupName string
+	Issues        []NodegroupLaunchIssue
+}
+
+// Message returns a single actionable message describing all the issues in the report.
+func (r *NodegroupLaunchReport) Message() string {
+	messages := make([]string, 0, len(r.Issues))
+	for _, issue := range r.Issues {
+		message := fmt.Sprintf("%s: %s", issue.Code, issue.Message)
+		if issue.Remediation != "" {
+			message = fmt.Sprintf("%s, %s", message, issue.Remediation)
+		}
+		messages = append(messages, message)
+	}
+
+	return fmt.Sprintf("nodegroup [%s] cannot launch nodes: %s", r.NodegroupName, strings.Join(messages, "; "))
+}
+
+// GetNodegroupLaunchReport describes the scaling activities of the auto scaling groups of a described node group to
+// explain why nodes are failing to launch. Insufficient capacity failures are reported per instance type and
+// availability zone along with a suggested remediation.
+func GetNodegroupLaunchReport(ctx context.Context, opts *GetNodegroupLaunchReportOpts) (*NodegroupLaunchReport, error) {
+	ng := opts.Nodegroup
+	if ng == nil {
+		return &NodegroupLaunchReport{}, nil
+	}
+	ngName := aws.StringValue(ng.NodegroupName)
+	report := &NodegroupLaunchReport{NodegroupName: ngName}
+
+	if ng.Health != nil {
+		for _, issue := range ng.Health.Issues {
+			code := aws.StringValue(issue.Code)
+			report.Issues = append(report.Issues, NodegroupLaunchIssue{
+				Code:        code,
+				Message:     aws.StringValue(issue.Message),
+				Remediation: nodegroupIssueRemediations[code],
+			})
+		}
+	}
+
+	if ng.Resources == nil {
+		return report, nil
+	}
+
+	seen := make(map[string]bool)
+	for _, asg := range ng.Resources.AutoScalingGroups {
+		activities, err := opts.AutoScalingService.DescribeScalingActivities(ctx, &autoscaling.DescribeScalingActivitiesInput{
+			AutoScalingGroupName: asg.Name,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error describing scaling activities of nodegroup [%s] in cluster [%s]: %w", ngName, opts.Config.Name, err)
+		}
+
+		for _, activity := range activities.Activities {
+			if aws.StringValue(activity.StatusCode) != autoscaling.ScalingActivityStatusCodeFailed {
+				continue
+			}
+
+			match := insufficientCapacityMessage.FindStringSubmatch(aws.StringValue(activity.StatusMessage))
+			if match == nil {
+				continue
+			}
+
+			instanceType, zone := match[1], match[2]
+			if seen[instanceType+"/"+zone] {
+				continue
+			}
+			seen[instanceType+"/"+zone] = true
+
+			report.Issues = append(report.Issues, NodegroupLaunchIssue{
+				Code:             insufficientInstanceCapacityCode,
+				Message:          fmt.Sprintf("no %s capacity available in %s", instanceType, zone),
+				InstanceType:     instanceType,
+				AvailabilityZone: zone,
+				Remediation: fmt.Sprintf("add subnets in availability zones other than %s, diversify the instance types or use spot instances",
+					zone),
+			})
+		}
+	}
+
+	return report, nil
+}
+
+// transientNodegroupIssueCodes are node group health issues that can clear up without changing the node group, as
+// opposed to issues caused by its configuration or missing resources that persist until they are fixed.
+var transientNodegroupIssueCodes = map[string]bool{
+	eks.NodegroupIssueCodeAsgInstanceLaunchFailures: true,
+	eks.NodegroupIssueCodeClusterUnreachable:        true,
+	eks.NodegroupIssueCodeInternalFailure:           true,
+}
+
+// NodegroupHealthIssue is a health issue reported on a node group.
+type NodegroupHealthIssue struct {
+	Code        string
+	Message     string
+	ResourceIDs []string
+	Transient   bool
+}
+
+// NodegroupHealthReport describes the health of a node group. A degraded node group only needs to be waited on while
+// all of its issues are transient, otherwise it has to be remediated.
+type NodegroupHealthReport struct {
+	NodegroupName string
+	Degraded      bool
+	Issues        []NodegroupHealthIssue
+}
+
+// Persistent returns true if the node group has an issue that does not clear up on its own.
+func (r *NodegroupHealthReport) Persistent() bool {
+	for _, issue := range r.Issues {
+		if !issue.Transient {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Message returns a single message describing the health issues in the report.
+func (r *NodegroupHealthReport) Message() string {
+	messages := make([]string, 0, len(r.Issues))
+	for _, issue := range r.Issues {
+		message := fmt.Sprintf("%s: %s", issue.Code, issue.Message)
+		if len(issue.ResourceIDs) != 0 {
+			message = fmt.Sprintf("%s (%s)", message, strings.Join(issue.ResourceIDs, ", "))
+		}
+		messages = append(messages, message)
+	}
+
+	state := "healthy"
+	if r.Degraded {
+		state = "degraded"
+	}
+	if len(messages) == 0 {
+		return fmt.Sprintf("nodegroup [%s] is %s", r.NodegroupName, state)
+	}
+
+	return fmt.Sprintf("nodegroup [%s] is %s: %s", r.NodegroupName, state, strings.Join(messages, "; "))
+}
+
+// GetNodegroupHealthReport maps the status and health issues of an upstream node group to a report.
+func GetNodegroupHealthReport(ng *eks.Nodegroup) *NodegroupHealthReport {
+	report := &NodegroupHealthReport{}
+	if ng == nil {
+		return report
+	}
+
+	report.NodegroupName = aws.StringValue(ng.NodegroupName)
+	report.Degraded = aws.StringValue(ng.Status) == eks.NodegroupStatusDegraded
+	if ng.Health == nil {
+		return report
+	}
+
+	for _, issue := range ng.Health.Issues {
+		code := aws.StringValue(issue.Code)
+		report.Issues = append(report.Issues, NodegroupHealthIssue{
+			Code:        code,
+			Message:     aws.StringValue(issue.Message),
+			ResourceIDs: aws.StringValueSlice(issue.ResourceIds),
+			Transient:   transientNodegroupIssueCodes[code],
+		})
+	}
+
+	return report
+}
+
 type GetLaunchTemplateVersionsOpts struct {
 	EC2Service       services.EC2ServiceInterface
 	LaunchTemplateID *string
 	Versions         []*string
 }
 
-func GetLaunchTemplateVersions(opts *GetLaunchTemplateVersionsOpts) (*ec2.DescribeLaunchTemplateVersionsOutput, error) {
+func GetLaunchTemplateVersions(ctx context.Context, opts *GetLaunchTemplateVersionsOpts) (*ec2.DescribeLaunchTemplateVersionsOutput, error) {
 	if opts.LaunchTemplateID == nil {
 		return nil, fmt.Errorf("launch template ID is nil")
 	}
@@ -38,8 +543,86 @@ func GetLaunchTemplateVersions(opts *GetLaunchTemplateVersionsOpts) (*ec2.Descri
 	}
 
 	return opts.EC2Service.DescribeLaunchTemplateVersions(
+		ctx,
 		&ec2.DescribeLaunchTemplateVersionsInput{
 			LaunchTemplateId: opts.LaunchTemplateID,
 			Versions:         opts.Versions,
 		})
 }
+
+type GetLaunchTemplateVersionNumbersOpts struct {
+	EC2Service       services.EC2ServiceInterface
+	LaunchTemplateID string
+}
+
+// GetLaunchTemplateVersionNumbers returns the numbers of all the versions of a launch template, latest first, across
+// all the pages of results, along with the number of its default version.
+func GetLaunchTemplateVersionNumbers(ctx context.Context, opts *GetLaunchTemplateVersionNumbersOpts) ([]int64, int64, error) {
+	if opts.LaunchTemplateID == "" {
+		return nil, 0, fmt.Errorf("launch template ID is empty")
+	}
+
+	var versions []int64
+	var defaultVersion int64
+	input := &ec2.DescribeLaunchTemplateVersionsInput{
+		LaunchTemplateId: aws.String(opts.LaunchTemplateID),
+	}
+	for {
+		output, err := opts.EC2Service.DescribeLaunchTemplateVersions(ctx, input)
+		if err != nil {
+			return nil, 0, fmt.Errorf("error describing versions of launch template [%s]: %w", opts.LaunchTemplateID, err)
+		}
+		for _, version := range output.LaunchTemplateVersions {
+			if aws.BoolValue(version.DefaultVersion) {
+				defaultVersion = aws.Int64Value(version.VersionNumber)
+			}
+			versions = append(versions, aws.Int64Value(version.VersionNumber))
+		}
+
+		if output.NextToken == nil {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i] > versions[j]
+	})
+	return versions, defaultVersion, nil
+}
+
+type GetStackOutputsOpts struct {
+	CloudFormationService services.CloudFormationServiceInterface
+	StackName             string
+}
+
+// GetStackOutputs describes a CloudFormation stack and returns its outputs keyed by output key. A stack without
+// outputs returns an empty map.
+func GetStackOutputs(ctx context.Context, opts *GetStackOutputsOpts) (map[string]string, error) {
+	output, err := opts.CloudFormationService.DescribeStacks(ctx, &cloudformation.DescribeStacksInput{
+		StackName: aws.String(opts.StackName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error describing stack [%s]: %w", opts.StackName, err)
+	}
+	if output == nil || len(output.Stacks) == 0 {
+		return nil, fmt.Errorf("stack [%s] not found", opts.StackName)
+	}
+
+	return GetOutputsFromStack(output.Stacks[0]), nil
+}
+
+// GetOutputsFromStack returns the outputs of a stack already described keyed by output key.
+func GetOutputsFromStack(stack *cloudformation.Stack) map[string]string {
+	outputs := make(map[string]string)
+	if stack == nil {
+		return outputs
+	}
+	for _, output := range stack.Outputs {
+		if output == nil || output.OutputKey == nil {
+			continue
+		}
+		outputs[*output.OutputKey] = aws.StringValue(output.OutputValue)
+	}
+	return outputs
+}
@@ -1,9 +1,12 @@
 package eks
 
 import (
+	"context"
 	"errors"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/eks"
 	"github.com/golang/mock/gomock"
@@ -11,6 +14,9 @@ import (
 	. "github.com/onsi/gomega"
 	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
 	"github.com/rancher/eks-operator/pkg/eks/services/mock_services"
+	"github.com/rancher/wrangler/pkg/genericcondition"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 var _ = Describe("GetClusterState", func() {
@@ -39,18 +45,597 @@ var _ = Describe("GetClusterState", func() {
 
 	It("should successfully get cluster state", func() {
 		eksServiceMock.EXPECT().DescribeCluster(
+			gomock.Any(),
 			&eks.DescribeClusterInput{
 				Name: aws.String(getClusterStatusOptions.Config.Spec.DisplayName),
 			},
 		).Return(&eks.DescribeClusterOutput{}, nil)
-		clusterState, err := GetClusterState(getClusterStatusOptions)
+		clusterState, err := GetClusterState(context.Background(), getClusterStatusOptions)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(clusterState).ToNot(BeNil())
 	})
 
 	It("should fail to get cluster state", func() {
-		eksServiceMock.EXPECT().DescribeCluster(gomock.Any()).Return(nil, errors.New("error getting cluster state"))
-		_, err := GetClusterState(getClusterStatusOptions)
+		eksServiceMock.EXPECT().DescribeCluster(gomock.Any(), gomock.Any()).Return(nil, errors.New("error getting cluster state"))
+		_, err := GetClusterState(context.Background(), getClusterStatusOptions)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("GetNodegroupState", func() {
+	var (
+		mockController        *gomock.Controller
+		eksServiceMock        *mock_services.MockEKSServiceInterface
+		getNodegroupStateOpts *GetNodegroupStateOpts
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+		getNodegroupStateOpts = &GetNodegroupStateOpts{
+			EKSService: eksServiceMock,
+			Config: &eksv1.EKSClusterConfig{
+				Spec: eksv1.EKSClusterConfigSpec{
+					DisplayName: "test-cluster",
+				},
+			},
+			NodegroupName: "test-nodegroup",
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should successfully get node group state", func() {
+		eksServiceMock.EXPECT().DescribeNodegroup(
+			gomock.Any(),
+			&eks.DescribeNodegroupInput{
+				ClusterName:   aws.String(getNodegroupStateOpts.Config.Spec.DisplayName),
+				NodegroupName: aws.String(getNodegroupStateOpts.NodegroupName),
+			},
+		).Return(&eks.DescribeNodegroupOutput{}, nil)
+		nodegroupState, err := GetNodegroupState(context.Background(), getNodegroupStateOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(nodegroupState).ToNot(BeNil())
+	})
+
+	It("should fail to get node group state", func() {
+		eksServiceMock.EXPECT().DescribeNodegroup(gomock.Any(), gomock.Any()).Return(nil, errors.New("error getting node group state"))
+		_, err := GetNodegroupState(context.Background(), getNodegroupStateOpts)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("ListNodegroups", func() {
+	var (
+		mockController     *gomock.Controller
+		eksServiceMock     *mock_services.MockEKSServiceInterface
+		listNodegroupsOpts *ListNodegroupsOpts
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+		listNodegroupsOpts = &ListNodegroupsOpts{
+			EKSService: eksServiceMock,
+			Config: &eksv1.EKSClusterConfig{
+				Spec: eksv1.EKSClusterConfigSpec{
+					DisplayName: "test-cluster",
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should list the node groups of all pages", func() {
+		eksServiceMock.EXPECT().ListNodegroups(gomock.Any(), &eks.ListNodegroupsInput{
+			ClusterName: aws.String("test-cluster"),
+		}).Return(&eks.ListNodegroupsOutput{
+			Nodegroups: aws.StringSlice([]string{"ng1", "ng2"}),
+			NextToken:  aws.String("next"),
+		}, nil)
+		eksServiceMock.EXPECT().ListNodegroups(gomock.Any(), &eks.ListNodegroupsInput{
+			ClusterName: aws.String("test-cluster"),
+			NextToken:   aws.String("next"),
+		}).Return(&eks.ListNodegroupsOutput{
+			Nodegroups: aws.StringSlice([]string{"ng3"}),
+		}, nil)
+
+		nodegroups, err := ListNodegroups(context.Background(), listNodegroupsOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(nodegroups).To(Equal([]string{"ng1", "ng2", "ng3"}))
+	})
+
+	It("should fail to list node groups", func() {
+		eksServiceMock.EXPECT().ListNodegroups(gomock.Any(), gomock.Any()).Return(nil, errors.New("error listing node groups"))
+
+		_, err := ListNodegroups(context.Background(), listNodegroupsOpts)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("ListClusters", func() {
+	var (
+		mockController   *gomock.Controller
+		eksServiceMock   *mock_services.MockEKSServiceInterface
+		listClustersOpts *ListClustersOpts
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+		listClustersOpts = &ListClustersOpts{
+			EKSService: eksServiceMock,
+		}
+	})
+
+	expectClusterPages := func() {
+		eksServiceMock.EXPECT().ListClustersPages(gomock.Any(), &eks.ListClustersInput{}, gomock.Any()).DoAndReturn(
+			func(_ context.Context, _ *eks.ListClustersInput, fn func(*eks.ListClustersOutput, bool) bool) error {
+				if fn(&eks.ListClustersOutput{Clusters: aws.StringSlice([]string{"cluster1", "cluster2"}), NextToken: aws.String("next")}, false) {
+					fn(&eks.ListClustersOutput{Clusters: aws.StringSlice([]string{"cluster3"})}, true)
+				}
+				return nil
+			})
+	}
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should list the clusters of all pages", func() {
+		expectClusterPages()
+		clusters, err := ListClusters(context.Background(), listClustersOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(clusters).To(Equal([]string{"cluster1", "cluster2", "cluster3"}))
+	})
+
+	It("should fail to list clusters", func() {
+		eksServiceMock.EXPECT().ListClustersPages(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("error listing clusters"))
+
+		_, err := ListClusters(context.Background(), listClustersOpts)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("RefreshClusterStatus", func() {
+	var (
+		mockController              *gomock.Controller
+		eksServiceMock              *mock_services.MockEKSServiceInterface
+		refreshClusterStatusOptions *RefreshClusterStatusOpts
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+		refreshClusterStatusOptions = &RefreshClusterStatusOpts{
+			EKSService: eksServiceMock,
+			Config: &eksv1.EKSClusterConfig{
+				Spec: eksv1.EKSClusterConfigSpec{
+					DisplayName: "test-cluster",
+				},
+				Status: eksv1.EKSClusterConfigStatus{
+					Phase:                   "active",
+					ManagedLaunchTemplateID: "lt-test",
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should populate the status from a complete cluster description", func() {
+		eksServiceMock.EXPECT().DescribeCluster(
+			gomock.Any(),
+			&eks.DescribeClusterInput{
+				Name: aws.String("test-cluster"),
+			},
+		).Return(&eks.DescribeClusterOutput{
+			Cluster: &eks.Cluster{
+				Arn:      aws.String("arn:aws:eks:us-east-1:123456789012:cluster/test-cluster"),
+				Endpoint: aws.String("https://test.eks.amazonaws.com"),
+				CertificateAuthority: &eks.Certificate{
+					Data: aws.String("ca-data"),
+				},
+				ResourcesVpcConfig: &eks.VpcConfigResponse{
+					VpcId:                  aws.String("vpc-test"),
+					SubnetIds:              aws.StringSlice([]string{"subnet-1", "subnet-2"}),
+					SecurityGroupIds:       aws.StringSlice([]string{"sg-1"}),
+					ClusterSecurityGroupId: aws.String("sg-cluster"),
+				},
+				EncryptionConfig: []*eks.EncryptionConfig{
+					{
+						Provider: &eks.Provider{KeyArn: aws.String("arn:aws:kms:us-east-1:123456789012:key/test")},
+					},
+				},
+				Identity: &eks.Identity{
+					Oidc: &eks.OIDC{Issuer: aws.String("https://oidc.eks.amazonaws.com/id/test")},
+				},
+			},
+		}, nil)
+
+		status, err := RefreshClusterStatus(context.Background(), refreshClusterStatusOptions)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status).To(Equal(&eksv1.EKSClusterConfigStatus{
+			Phase:                    "active",
+			ManagedLaunchTemplateID:  "lt-test",
+			VirtualNetwork:           "vpc-test",
+			Subnets:                  []string{"subnet-1", "subnet-2"},
+			SecurityGroups:           []string{"sg-1"},
+			ClusterARN:               "arn:aws:eks:us-east-1:123456789012:cluster/test-cluster",
+			Endpoint:                 "https://test.eks.amazonaws.com",
+			CertificateAuthorityData: "ca-data",
+			KmsKeyARN:                "arn:aws:kms:us-east-1:123456789012:key/test",
+			OIDCIssuer:               "https://oidc.eks.amazonaws.com/id/test",
+			ClusterSecurityGroupID:   "sg-cluster",
+		}))
+		Expect(refreshClusterStatusOptions.Config.Status.Endpoint).To(BeEmpty())
+	})
+
+	It("should handle partial data while the cluster is creating", func() {
+		eksServiceMock.EXPECT().DescribeCluster(gomock.Any(), gomock.Any()).Return(&eks.DescribeClusterOutput{
+			Cluster: &eks.Cluster{
+				Arn: aws.String("arn:aws:eks:us-east-1:123456789012:cluster/test-cluster"),
+				ResourcesVpcConfig: &eks.VpcConfigResponse{
+					SubnetIds: aws.StringSlice([]string{"subnet-1"}),
+				},
+			},
+		}, nil)
+
+		status, err := RefreshClusterStatus(context.Background(), refreshClusterStatusOptions)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status.ClusterARN).To(Equal("arn:aws:eks:us-east-1:123456789012:cluster/test-cluster"))
+		Expect(status.Subnets).To(Equal([]string{"subnet-1"}))
+		Expect(status.Endpoint).To(BeEmpty())
+		Expect(status.CertificateAuthorityData).To(BeEmpty())
+		Expect(status.OIDCIssuer).To(BeEmpty())
+		Expect(status.ClusterSecurityGroupID).To(BeEmpty())
+	})
+
+	It("should fail to refresh the cluster status", func() {
+		eksServiceMock.EXPECT().DescribeCluster(gomock.Any(), gomock.Any()).Return(nil, errors.New("error getting cluster state"))
+		_, err := RefreshClusterStatus(context.Background(), refreshClusterStatusOptions)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("GetClusterSecurityGroupID", func() {
+	It("should return the cluster security group ID", func() {
+		Expect(GetClusterSecurityGroupID(&eks.Cluster{
+			ResourcesVpcConfig: &eks.VpcConfigResponse{
+				SecurityGroupIds:       aws.StringSlice([]string{"sg-1"}),
+				ClusterSecurityGroupId: aws.String("sg-cluster"),
+			},
+		})).To(Equal("sg-cluster"))
+	})
+
+	It("should return an empty ID while the cluster is creating", func() {
+		Expect(GetClusterSecurityGroupID(nil)).To(BeEmpty())
+		Expect(GetClusterSecurityGroupID(&eks.Cluster{})).To(BeEmpty())
+		Expect(GetClusterSecurityGroupID(&eks.Cluster{ResourcesVpcConfig: &eks.VpcConfigResponse{}})).To(BeEmpty())
+	})
+})
+
+var _ = Describe("GetClusterOIDCIssuer", func() {
+	var (
+		mockController          *gomock.Controller
+		eksServiceMock          *mock_services.MockEKSServiceInterface
+		getClusterStatusOptions *GetClusterStatusOpts
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+		getClusterStatusOptions = &GetClusterStatusOpts{
+			EKSService: eksServiceMock,
+			Config: &eksv1.EKSClusterConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: eksv1.EKSClusterConfigSpec{
+					DisplayName: "test-cluster",
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should return the OIDC issuer of the cluster", func() {
+		eksServiceMock.EXPECT().DescribeCluster(gomock.Any(), &eks.DescribeClusterInput{
+			Name: aws.String("test-cluster"),
+		}).Return(&eks.DescribeClusterOutput{
+			Cluster: &eks.Cluster{
+				Identity: &eks.Identity{
+					Oidc: &eks.OIDC{Issuer: aws.String("https://oidc.eks.amazonaws.com/id/test")},
+				},
+			},
+		}, nil)
+
+		issuer, err := GetClusterOIDCIssuer(context.Background(), getClusterStatusOptions)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(issuer).To(Equal("https://oidc.eks.amazonaws.com/id/test"))
+	})
+
+	It("should fail if the cluster has no OIDC identity", func() {
+		eksServiceMock.EXPECT().DescribeCluster(gomock.Any(), gomock.Any()).Return(&eks.DescribeClusterOutput{
+			Cluster: &eks.Cluster{},
+		}, nil)
+
+		_, err := GetClusterOIDCIssuer(context.Background(), getClusterStatusOptions)
+		Expect(err).To(MatchError("cluster [test] has no OIDC issuer yet"))
+	})
+
+	It("should fail if the cluster cannot be described", func() {
+		eksServiceMock.EXPECT().DescribeCluster(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+		_, err := GetClusterOIDCIssuer(context.Background(), getClusterStatusOptions)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("GetClusterConditions", func() {
+	It("should report a ready cluster without health issues", func() {
+		conditions := GetClusterConditions(&eks.Cluster{
+			Status: aws.String(eks.ClusterStatusActive),
+		})
+		Expect(conditions).To(Equal([]genericcondition.GenericCondition{
+			{Type: ClusterConditionReady, Status: corev1.ConditionTrue},
+			{Type: ClusterConditionDegraded, Status: corev1.ConditionFalse},
+		}))
+	})
+
+	It("should report a degraded cluster with health issues", func() {
+		conditions := GetClusterConditions(&eks.Cluster{
+			Status: aws.String(eks.ClusterStatusActive),
+			Health: &eks.ClusterHealth{
+				Issues: []*eks.ClusterIssue{
+					{
+						Code:        aws.String(eks.ClusterIssueCodeClusterUnreachable),
+						Message:     aws.String("control plane cannot be reached"),
+						ResourceIds: aws.StringSlice([]string{"i-1", "i-2"}),
+					},
+					{
+						Code:    aws.String(eks.ClusterIssueCodeAccessDenied),
+						Message: aws.String("role is missing permissions"),
+					},
+				},
+			},
+		})
+
+		message := "ClusterUnreachable: control plane cannot be reached (i-1, i-2); AccessDenied: role is missing permissions"
+		Expect(conditions).To(Equal([]genericcondition.GenericCondition{
+			{Type: ClusterConditionReady, Status: corev1.ConditionFalse, Reason: "ControlPlaneUnreachable", Message: message},
+			{Type: ClusterConditionDegraded, Status: corev1.ConditionTrue, Reason: "ControlPlaneUnreachable", Message: message},
+		}))
+	})
+
+	It("should report a provisioning cluster as not ready", func() {
+		conditions := GetClusterConditions(&eks.Cluster{
+			Status: aws.String(eks.ClusterStatusCreating),
+		})
+		Expect(conditions).To(Equal([]genericcondition.GenericCondition{
+			{Type: ClusterConditionReady, Status: corev1.ConditionFalse, Reason: "Provisioning", Message: "cluster status is CREATING"},
+			{Type: ClusterConditionDegraded, Status: corev1.ConditionFalse},
+		}))
+	})
+
+	It("should report a failed cluster as degraded", func() {
+		conditions := GetClusterConditions(&eks.Cluster{
+			Status: aws.String(eks.ClusterStatusFailed),
+		})
+		Expect(conditions).To(Equal([]genericcondition.GenericCondition{
+			{Type: ClusterConditionReady, Status: corev1.ConditionFalse, Reason: "Failed", Message: "cluster status is FAILED"},
+			{Type: ClusterConditionDegraded, Status: corev1.ConditionTrue, Reason: "Failed", Message: "cluster status is FAILED"},
+		}))
+	})
+})
+
+var _ = Describe("DeriveClusterPhase", func() {
+	DescribeTable("should map the cluster status to a phase",
+		func(status string, phase ClusterPhase, ready bool) {
+			state := DeriveClusterPhase(&eks.Cluster{Status: aws.String(status)})
+			Expect(state.Phase).To(Equal(phase))
+			Expect(state.Ready).To(Equal(ready))
+			Expect(state.Issues).To(BeEmpty())
+		},
+		Entry("pending", eks.ClusterStatusPending, ClusterPhaseCreating, false),
+		Entry("creating", eks.ClusterStatusCreating, ClusterPhaseCreating, false),
+		Entry("active", eks.ClusterStatusActive, ClusterPhaseActive, true),
+		Entry("updating", eks.ClusterStatusUpdating, ClusterPhaseUpdating, false),
+		Entry("deleting", eks.ClusterStatusDeleting, ClusterPhaseDeleting, false),
+		Entry("failed", eks.ClusterStatusFailed, ClusterPhaseFailed, false),
+		Entry("unknown", "RESTORING", ClusterPhaseUnknown, false),
+	)
+
+	It("should report the health issues of a failed cluster", func() {
+		state := DeriveClusterPhase(&eks.Cluster{
+			Status: aws.String(eks.ClusterStatusFailed),
+			Health: &eks.ClusterHealth{
+				Issues: []*eks.ClusterIssue{
+					{
+						Code:        aws.String(eks.ClusterIssueCodeResourceNotFound),
+						Message:     aws.String("subnet does not exist"),
+						ResourceIds: aws.StringSlice([]string{"subnet-1"}),
+					},
+					{
+						Code:    aws.String(eks.ClusterIssueCodeAccessDenied),
+						Message: aws.String("role is missing permissions"),
+					},
+				},
+			},
+		})
+		Expect(state).To(Equal(ClusterPhaseState{
+			Phase: ClusterPhaseFailed,
+			Issues: []string{
+				"ResourceNotFound: subnet does not exist (subnet-1)",
+				"AccessDenied: role is missing permissions",
+			},
+		}))
+	})
+
+	It("should not report an active cluster with health issues as ready", func() {
+		state := DeriveClusterPhase(&eks.Cluster{
+			Status: aws.String(eks.ClusterStatusActive),
+			Health: &eks.ClusterHealth{
+				Issues: []*eks.ClusterIssue{
+					{Code: aws.String(eks.ClusterIssueCodeClusterUnreachable), Message: aws.String("control plane cannot be reached")},
+				},
+			},
+		})
+		Expect(state.Phase).To(Equal(ClusterPhaseActive))
+		Expect(state.Ready).To(BeFalse())
+		Expect(state.Issues).To(Equal([]string{"ClusterUnreachable: control plane cannot be reached"}))
+	})
+
+	It("should report an unknown phase without a cluster", func() {
+		Expect(DeriveClusterPhase(nil)).To(Equal(ClusterPhaseState{Phase: ClusterPhaseUnknown}))
+	})
+})
+var _ = Describe("GetNodegroupHealthReport", func() {
+	It("should report the issues of a degraded node group", func() {
+		report := GetNodegroupHealthReport(&eks.Nodegroup{
+			NodegroupName: aws.String("ng"),
+			Status:        aws.String(eks.NodegroupStatusDegraded),
+			Health: &eks.NodegroupHealth{
+				Issues: []*eks.Issue{
+					{
+						Code:        aws.String(eks.NodegroupIssueCodeAsgInstanceLaunchFailures),
+						Message:     aws.String("could not launch on-demand instances"),
+						ResourceIds: aws.StringSlice([]string{"asg-1"}),
+					},
+					{
+						Code:    aws.String(eks.NodegroupIssueCodeIamNodeRoleNotFound),
+						Message: aws.String("node role not found"),
+					},
+				},
+			},
+		})
+
+		Expect(report.Degraded).To(BeTrue())
+		Expect(report.Issues).To(Equal([]NodegroupHealthIssue{
+			{
+				Code:        eks.NodegroupIssueCodeAsgInstanceLaunchFailures,
+				Message:     "could not launch on-demand instances",
+				ResourceIDs: []string{"asg-1"},
+				Transient:   true,
+			},
+			{
+				Code:        eks.NodegroupIssueCodeIamNodeRoleNotFound,
+				Message:     "node role not found",
+				ResourceIDs: []string{},
+			},
+		}))
+		Expect(report.Persistent()).To(BeTrue())
+		Expect(report.Message()).To(Equal("nodegroup [ng] is degraded: AsgInstanceLaunchFailures: could not launch on-demand instances (asg-1); " +
+			"IamNodeRoleNotFound: node role not found"))
+	})
+
+	It("should report a degraded node group with only transient issues as not persistent", func() {
+		report := GetNodegroupHealthReport(&eks.Nodegroup{
+			NodegroupName: aws.String("ng"),
+			Status:        aws.String(eks.NodegroupStatusDegraded),
+			Health: &eks.NodegroupHealth{
+				Issues: []*eks.Issue{
+					{Code: aws.String(eks.NodegroupIssueCodeClusterUnreachable)},
+				},
+			},
+		})
+
+		Expect(report.Degraded).To(BeTrue())
+		Expect(report.Persistent()).To(BeFalse())
+	})
+
+	It("should report an active node group as not degraded", func() {
+		report := GetNodegroupHealthReport(&eks.Nodegroup{
+			NodegroupName: aws.String("ng"),
+			Status:        aws.String(eks.NodegroupStatusActive),
+		})
+
+		Expect(report.Degraded).To(BeFalse())
+		Expect(report.Issues).To(BeEmpty())
+		Expect(report.Message()).To(Equal("nodegroup [ng] is healthy"))
+	})
+})
+
+var _ = Describe("GetNodegroupLaunchReport", func() {
+	var (
+		mockController                  *gomock.Controller
+		autoScalingServiceMock          *mock_services.MockAutoScalingServiceInterface
+		getNodegroupLaunchReportOptions *GetNodegroupLaunchReportOpts
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		autoScalingServiceMock = mock_services.NewMockAutoScalingServiceInterface(mockController)
+		getNodegroupLaunchReportOptions = &GetNodegroupLaunchReportOpts{
+			AutoScalingService: autoScalingServiceMock,
+			Config: &eksv1.EKSClusterConfig{
+				Spec: eksv1.EKSClusterConfigSpec{
+					DisplayName: "test-cluster",
+				},
+			},
+			Nodegroup: &eks.Nodegroup{
+				NodegroupName: aws.String("test"),
+				Health: &eks.NodegroupHealth{
+					Issues: []*eks.Issue{
+						{
+							Code:    aws.String(eks.NodegroupIssueCodeAsgInstanceLaunchFailures),
+							Message: aws.String("Could not launch On-Demand Instances."),
+						},
+					},
+				},
+				Resources: &eks.NodegroupResources{
+					AutoScalingGroups: []*eks.AutoScalingGroup{{Name: aws.String("test-asg")}},
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should report insufficient capacity with remediation", func() {
+		capacityMessage := "We currently do not have sufficient t3.large capacity in the Availability Zone you requested (us-east-1a). " +
+			"Our system will be working on provisioning additional capacity."
+		autoScalingServiceMock.EXPECT().DescribeScalingActivities(gomock.Any(), &autoscaling.DescribeScalingActivitiesInput{
+			AutoScalingGroupName: aws.String("test-asg"),
+		}).Return(&autoscaling.DescribeScalingActivitiesOutput{
+			Activities: []*autoscaling.Activity{
+				{StatusCode: aws.String(autoscaling.ScalingActivityStatusCodeFailed), StatusMessage: aws.String(capacityMessage)},
+				{StatusCode: aws.String(autoscaling.ScalingActivityStatusCodeFailed), StatusMessage: aws.String(capacityMessage)},
+				{StatusCode: aws.String(autoscaling.ScalingActivityStatusCodeSuccessful), StatusMessage: aws.String("")},
+			},
+		}, nil)
+
+		report, err := GetNodegroupLaunchReport(context.Background(), getNodegroupLaunchReportOptions)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Issues).To(HaveLen(2))
+		Expect(report.Issues[1]).To(Equal(NodegroupLaunchIssue{
+			Code:             "InsufficientInstanceCapacity",
+			Message:          "no t3.large capacity available in us-east-1a",
+			InstanceType:     "t3.large",
+			AvailabilityZone: "us-east-1a",
+			Remediation:      "add subnets in availability zones other than us-east-1a, diversify the instance types or use spot instances",
+		}))
+		Expect(report.Message()).To(Equal("nodegroup [test] cannot launch nodes: " +
+			"AsgInstanceLaunchFailures: Could not launch On-Demand Instances.; " +
+			"InsufficientInstanceCapacity: no t3.large capacity available in us-east-1a, " +
+			"add subnets in availability zones other than us-east-1a, diversify the instance types or use spot instances"))
+	})
+
+	It("should fail to get the launch report", func() {
+		autoScalingServiceMock.EXPECT().DescribeScalingActivities(gomock.Any(), gomock.Any()).Return(nil, errors.New("error describing scaling activities"))
+		_, err := GetNodegroupLaunchReport(context.Background(), getNodegroupLaunchReportOptions)
 		Expect(err).To(HaveOccurred())
 	})
 })
@@ -78,31 +663,163 @@ var _ = Describe("GetLaunchTemplateVersions", func() {
 
 	It("should successfully get launch template versions", func() {
 		ec2ServiceMock.EXPECT().DescribeLaunchTemplateVersions(
+			gomock.Any(),
 			&ec2.DescribeLaunchTemplateVersionsInput{
 				LaunchTemplateId: getLaunchTemplateOptions.LaunchTemplateID,
 				Versions:         getLaunchTemplateOptions.Versions,
 			},
 		).Return(&ec2.DescribeLaunchTemplateVersionsOutput{}, nil)
-		ltVersion, err := GetLaunchTemplateVersions(getLaunchTemplateOptions)
+		ltVersion, err := GetLaunchTemplateVersions(context.Background(), getLaunchTemplateOptions)
 		Expect(err).ToNot(HaveOccurred())
 		Expect(ltVersion).ToNot(BeNil())
 	})
 
 	It("should fail to get launch template versions", func() {
-		ec2ServiceMock.EXPECT().DescribeLaunchTemplateVersions(gomock.Any()).Return(nil, errors.New("error getting launch template versions"))
-		_, err := GetLaunchTemplateVersions(getLaunchTemplateOptions)
+		ec2ServiceMock.EXPECT().DescribeLaunchTemplateVersions(gomock.Any(), gomock.Any()).Return(nil, errors.New("error getting launch template versions"))
+		_, err := GetLaunchTemplateVersions(context.Background(), getLaunchTemplateOptions)
 		Expect(err).To(HaveOccurred())
 	})
 
 	It("should fail to get launch template versions when template id is missing", func() {
 		getLaunchTemplateOptions.LaunchTemplateID = nil
-		_, err := GetLaunchTemplateVersions(getLaunchTemplateOptions)
+		_, err := GetLaunchTemplateVersions(context.Background(), getLaunchTemplateOptions)
 		Expect(err).To(HaveOccurred())
 	})
 
 	It("should fail to get launch template versions when versions are missing", func() {
 		getLaunchTemplateOptions.Versions = nil
-		_, err := GetLaunchTemplateVersions(getLaunchTemplateOptions)
+		_, err := GetLaunchTemplateVersions(context.Background(), getLaunchTemplateOptions)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("GetLaunchTemplateVersionNumbers", func() {
+	var (
+		mockController *gomock.Controller
+		ec2ServiceMock *mock_services.MockEC2ServiceInterface
+		opts           *GetLaunchTemplateVersionNumbersOpts
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		ec2ServiceMock = mock_services.NewMockEC2ServiceInterface(mockController)
+		opts = &GetLaunchTemplateVersionNumbersOpts{
+			EC2Service:       ec2ServiceMock,
+			LaunchTemplateID: "test-launch-template-id",
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should return the versions of all pages latest first and the default version", func() {
+		ec2ServiceMock.EXPECT().DescribeLaunchTemplateVersions(gomock.Any(), &ec2.DescribeLaunchTemplateVersionsInput{
+			LaunchTemplateId: aws.String("test-launch-template-id"),
+		}).Return(&ec2.DescribeLaunchTemplateVersionsOutput{
+			LaunchTemplateVersions: []*ec2.LaunchTemplateVersion{
+				{VersionNumber: aws.Int64(1), DefaultVersion: aws.Bool(true)},
+				{VersionNumber: aws.Int64(3)},
+			},
+			NextToken: aws.String("next"),
+		}, nil)
+		ec2ServiceMock.EXPECT().DescribeLaunchTemplateVersions(gomock.Any(), &ec2.DescribeLaunchTemplateVersionsInput{
+			LaunchTemplateId: aws.String("test-launch-template-id"),
+			NextToken:        aws.String("next"),
+		}).Return(&ec2.DescribeLaunchTemplateVersionsOutput{
+			LaunchTemplateVersions: []*ec2.LaunchTemplateVersion{
+				{VersionNumber: aws.Int64(2)},
+			},
+		}, nil)
+
+		versions, defaultVersion, err := GetLaunchTemplateVersionNumbers(context.Background(), opts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(versions).To(Equal([]int64{3, 2, 1}))
+		Expect(defaultVersion).To(Equal(int64(1)))
+	})
+
+	It("should fail to get the versions", func() {
+		ec2ServiceMock.EXPECT().DescribeLaunchTemplateVersions(gomock.Any(), gomock.Any()).Return(nil, errors.New("error describing launch template versions"))
+
+		_, _, err := GetLaunchTemplateVersionNumbers(context.Background(), opts)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should fail to get the versions when template id is missing", func() {
+		opts.LaunchTemplateID = ""
+
+		_, _, err := GetLaunchTemplateVersionNumbers(context.Background(), opts)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("GetStackOutputs", func() {
+	var (
+		mockController             *gomock.Controller
+		cloudFormationsServiceMock *mock_services.MockCloudFormationServiceInterface
+		opts                       *GetStackOutputsOpts
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		cloudFormationsServiceMock = mock_services.NewMockCloudFormationServiceInterface(mockController)
+		opts = &GetStackOutputsOpts{
+			CloudFormationService: cloudFormationsServiceMock,
+			StackName:             "test-eks-vpc",
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should map all outputs of the stack", func() {
+		cloudFormationsServiceMock.EXPECT().DescribeStacks(gomock.Any(), &cloudformation.DescribeStacksInput{
+			StackName: aws.String("test-eks-vpc"),
+		}).Return(&cloudformation.DescribeStacksOutput{
+			Stacks: []*cloudformation.Stack{
+				{
+					Outputs: []*cloudformation.Output{
+						{OutputKey: aws.String("VpcId"), OutputValue: aws.String("vpc-1")},
+						{OutputKey: aws.String("SubnetIds"), OutputValue: aws.String("subnet-1,subnet-2")},
+						{OutputKey: aws.String("SecurityGroups"), OutputValue: aws.String("sg-1")},
+					},
+				},
+			},
+		}, nil)
+
+		outputs, err := GetStackOutputs(context.Background(), opts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(outputs).To(Equal(map[string]string{
+			"VpcId":          "vpc-1",
+			"SubnetIds":      "subnet-1,subnet-2",
+			"SecurityGroups": "sg-1",
+		}))
+		Expect(outputs["NodeInstanceRole"]).To(BeEmpty())
+	})
+
+	It("should return an empty map if the stack has no outputs", func() {
+		cloudFormationsServiceMock.EXPECT().DescribeStacks(gomock.Any(), gomock.Any()).Return(&cloudformation.DescribeStacksOutput{
+			Stacks: []*cloudformation.Stack{{}},
+		}, nil)
+
+		outputs, err := GetStackOutputs(context.Background(), opts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(outputs).ToNot(BeNil())
+		Expect(outputs).To(BeEmpty())
+	})
+
+	It("should fail if the stack does not exist", func() {
+		cloudFormationsServiceMock.EXPECT().DescribeStacks(gomock.Any(), gomock.Any()).Return(&cloudformation.DescribeStacksOutput{}, nil)
+
+		_, err := GetStackOutputs(context.Background(), opts)
+		Expect(err).To(MatchError("stack [test-eks-vpc] not found"))
+	})
+
+	It("should fail if describing the stack fails", func() {
+		cloudFormationsServiceMock.EXPECT().DescribeStacks(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+		_, err := GetStackOutputs(context.Background(), opts)
 		Expect(err).To(HaveOccurred())
 	})
 })
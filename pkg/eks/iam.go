@@ -0,0 +1,268 @@
+package eks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/aws/aws-sdk-go/service/iam"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/pkg/eks/services"
+	"github.com/rancher/eks-operator/templates"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	registryReadOnlyPolicyName = "AmazonEC2ContainerRegistryReadOnly"
+	workerNodePolicyName       = "AmazonEKSWorkerNodePolicy"
+	cniPolicyName              = "AmazonEKS_CNI_Policy"
+	managedPolicyARNFormat     = "arn:%s:iam::aws:policy/%s"
+
+	vpcCNIAddonName = "vpc-cni"
+
+	assumeRoleAction = "sts:AssumeRole"
+)
+
+type ValidateNodeRoleOpts struct {
+	// IAMService checks that the node role has the node policies attached if set. Listing them needs the
+	// iam:ListAttachedRolePolicies permission, so the check is opt-in.
+	IAMService services.IAMServiceInterface
+	Config     *eksv1.EKSClusterConfig
+	NodeRole   string
+}
+
+// ValidateNodeRole ensures a node role given for a node group is the ARN of an IAM role. If an IAM service is given,
+// it also ensures the role has the managed policies nodes need to join the cluster attached, for the partition of the
+// cluster region. Nodes of a role missing one of them fail to join without EKS reporting why, and nodes missing the
+// ECR read only policy of their partition fail to pull the images of the EKS add-ons. The CNI policy is not required
+// when the vpc-cni add-on uses its own service account role.
+func ValidateNodeRole(ctx context.Context, opts *ValidateNodeRoleOpts) error {
+	roleARN, err := arn.Parse(opts.NodeRole)
+	if err != nil || roleARN.Service != iam.ServiceName || !strings.HasPrefix(roleARN.Resource, "role/") {
+		return fmt.Errorf("node role [%s] for cluster [%s] must be the ARN of an IAM role", opts.NodeRole, opts.Config.Name)
+	}
+	if opts.IAMService == nil {
+		return nil
+	}
+	roleName := getRoleName(opts.NodeRole)
+
+	// attached maps the names of the attached policies to their ARNs, to tell policies of another partition apart
+	// from missing ones
+	attached := make(map[string]string)
+	input := &iam.ListAttachedRolePoliciesInput{
+		RoleName: aws.String(roleName),
+	}
+	for {
+		output, err := opts.IAMService.ListAttachedRolePolicies(ctx, input)
+		if accessDeniedInIAMError(err) {
+			logrus.Warnf("not allowed to list policies of node role [%s] for cluster [%s], will not check them: %v", roleName, opts.Config.Name, err)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error listing policies of node role [%s] for cluster [%s]: %w", roleName, opts.Config.Name, err)
+		}
+		for _, policy := range output.AttachedPolicies {
+			attached[aws.StringValue(policy.PolicyName)] = aws.StringValue(policy.PolicyArn)
+		}
+		if !aws.BoolValue(output.IsTruncated) {
+			break
+		}
+		input.Marker = output.Marker
+	}
+
+	var missing, wrongPartition []string
+	for _, policyName := range getNodeRolePolicyNames(opts.Config) {
+		expectedARN := getManagedPolicyARN(opts.Config.Spec.Region, policyName)
+		switch policyARN, ok := attached[policyName]; {
+		case !ok:
+			missing = append(missing, expectedARN)
+		case policyARN != expectedARN:
+			wrongPartition = append(wrongPartition, policyARN)
+		}
+	}
+	if len(wrongPartition) != 0 {
+		return fmt.Errorf("node role [%s] for cluster [%s] has policies [%s] attached which belong to another partition than region [%s]",
+			roleName, opts.Config.Name, strings.Join(wrongPartition, ", "), opts.Config.Spec.Region)
+	}
+	if len(missing) != 0 {
+		return fmt.Errorf("node role [%s] for cluster [%s] does not have policies [%s] attached", roleName, opts.Config.Name, strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// getNodeRolePolicyNames returns the names of the managed policies the node role of the cluster needs.
+func getNodeRolePolicyNames(config *eksv1.EKSClusterConfig) []string {
+	policyNames := []string{workerNodePolicyName, registryReadOnlyPolicyName}
+	if !hasVPCCNIServiceAccountRole(config) {
+		policyNames = append(policyNames, cniPolicyName)
+	}
+	return policyNames
+}
+
+func hasVPCCNIServiceAccountRole(config *eksv1.EKSClusterConfig) bool {
+	for _, addon := range config.Spec.Addons {
+		if addon.Name == vpcCNIAddonName && aws.StringValue(addon.ServiceAccountRoleArn) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+type GetClusterRoleOpts struct {
+	IAMService services.IAMServiceInterface
+	Config     *eksv1.EKSClusterConfig
+}
+
+// GetClusterRole describes the service role of the cluster and ensures its trust policy allows the EKS service
+// principal to assume it. A role trusting another principal, such as an EKS principal made up from the DNS suffix of
+// the China partition, is otherwise only rejected by EKS once the cluster creation has started.
+func GetClusterRole(ctx context.Context, opts *GetClusterRoleOpts) (*iam.Role, error) {
+	roleName := getRoleName(aws.StringValue(opts.Config.Spec.ServiceRole))
+	output, err := opts.IAMService.GetRole(ctx, &iam.GetRoleInput{
+		RoleName: aws.String(roleName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting service role [%s] for cluster [%s]: %w", roleName, opts.Config.Name, err)
+	}
+
+	if err := validateClusterRoleTrust(output.Role, opts.Config.Spec.Region); err != nil {
+		return nil, fmt.Errorf("service role [%s] for cluster [%s]: %w", roleName, opts.Config.Name, err)
+	}
+
+	return output.Role, nil
+}
+
+// trustPolicy is the subset of an assume role policy document needed to find the trusted service principals.
+type trustPolicy struct {
+	Statement []struct {
+		Effect    string
+		Action    policyValues
+		Principal struct {
+			Service policyValues
+		}
+	}
+}
+
+// policyValues is a policy element that is either a single string or a list of strings.
+type policyValues []string
+
+func (v *policyValues) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err == nil {
+		*v = policyValues{value}
+		return nil
+	}
+
+	var values []string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	*v = values
+
+	return nil
+}
+
+func (v policyValues) contains(value string) bool {
+	for _, s := range v {
+		if s == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func validateClusterRoleTrust(role *iam.Role, region string) error {
+	expectedPrincipal := getServicePrincipal(eks.ServiceName, region)
+
+	document, err := url.QueryUnescape(aws.StringValue(role.AssumeRolePolicyDocument))
+	if err != nil {
+		return fmt.Errorf("error decoding trust policy: %w", err)
+	}
+	var policy trustPolicy
+	if err := json.Unmarshal([]byte(document), &policy); err != nil {
+		return fmt.Errorf("error parsing trust policy: %w", err)
+	}
+
+	var otherPrincipal string
+	for _, statement := range policy.Statement {
+		if statement.Effect != "Allow" || (!statement.Action.contains(assumeRoleAction) && !statement.Action.contains("sts:*")) {
+			continue
+		}
+		for _, principal := range statement.Principal.Service {
+			if principal == expectedPrincipal {
+				return nil
+			}
+			if strings.HasPrefix(principal, eks.ServiceName+".") {
+				otherPrincipal = principal
+			}
+		}
+	}
+
+	if otherPrincipal != "" {
+		return fmt.Errorf("trust policy allows principal [%s] which is not an EKS service principal, expected [%s] for region [%s]",
+			otherPrincipal, expectedPrincipal, region)
+	}
+
+	return fmt.Errorf("trust policy does not allow principal [%s] to assume the role", expectedPrincipal)
+}
+
+// GetServiceRoleTemplate returns the template of the stack creating the service role of a cluster in the region,
+// trusting the EKS service principal and attaching the managed policies of the partition of the region.
+func GetServiceRoleTemplate(region string) string {
+	return fmt.Sprintf(templates.ServiceRoleTemplate, getServicePrincipal(eks.ServiceName, region), partitionForRegion(region))
+}
+
+// getNodeInstanceRoleTemplate returns the template of the stack creating the node instance role of a cluster in the
+// region, trusting the EC2 service principal and attaching the managed policies of the partition of the region.
+func getNodeInstanceRoleTemplate(region string) string {
+	return fmt.Sprintf(templates.NodeInstanceRoleTemplate, getServicePrincipal(ec2.ServiceName, region), partitionForRegion(region))
+}
+
+// ValidatePermissionsBoundaryARN ensures the permissions boundary of the generated roles is the ARN of an IAM policy.
+func ValidatePermissionsBoundaryARN(boundary string) error {
+	boundaryARN, err := arn.Parse(boundary)
+	if err != nil {
+		return fmt.Errorf("permissions boundary [%s] is not a valid ARN: %w", boundary, err)
+	}
+	if boundaryARN.Service != iam.ServiceName || !strings.HasPrefix(boundaryARN.Resource, "policy/") {
+		return fmt.Errorf("permissions boundary [%s] must be the ARN of an IAM policy", boundary)
+	}
+
+	return nil
+}
+
+// accessDeniedInIAMError returns true if the caller is not allowed to perform the IAM action.
+func accessDeniedInIAMError(err error) bool {
+	if aerr, ok := err.(awserr.Error); ok {
+		switch aerr.Code() {
+		case "AccessDenied":
+			return true
+		}
+	}
+
+	return false
+}
+
+// getManagedPolicyARN returns the ARN of an AWS managed policy in the partition of the region.
+func getManagedPolicyARN(region, policyName string) string {
+	return fmt.Sprintf(managedPolicyARNFormat, partitionForRegion(region), policyName)
+}
+
+// getRoleName returns the name of a role given either its name or its ARN.
+func getRoleName(role string) string {
+	roleARN, err := arn.Parse(role)
+	if err != nil {
+		return role
+	}
+
+	return roleARN.Resource[strings.LastIndex(roleARN.Resource, "/")+1:]
+}
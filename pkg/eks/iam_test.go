@@ -0,0 +1,298 @@
+package eks
+
+import (
+	"context"
+	"errors"
+	"net/url"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/pkg/eks/services/mock_services"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("ValidateNodeRole", func() {
+	var (
+		mockController *gomock.Controller
+		iamServiceMock *mock_services.MockIAMServiceInterface
+		validateOpts   *ValidateNodeRoleOpts
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		iamServiceMock = mock_services.NewMockIAMServiceInterface(mockController)
+		validateOpts = &ValidateNodeRoleOpts{
+			IAMService: iamServiceMock,
+			Config: &eksv1.EKSClusterConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-cluster",
+				},
+				Spec: eksv1.EKSClusterConfigSpec{
+					Region: "us-west-2",
+				},
+			},
+			NodeRole: "arn:aws:iam::123456789012:role/path/test-role",
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	attachedPolicies := func(partition string, policyNames ...string) *iam.ListAttachedRolePoliciesOutput {
+		output := &iam.ListAttachedRolePoliciesOutput{}
+		for _, policyName := range policyNames {
+			output.AttachedPolicies = append(output.AttachedPolicies, &iam.AttachedPolicy{
+				PolicyArn:  aws.String("arn:" + partition + ":iam::aws:policy/" + policyName),
+				PolicyName: aws.String(policyName),
+			})
+		}
+		return output
+	}
+
+	It("should accept a node role with the node policies attached", func() {
+		iamServiceMock.EXPECT().ListAttachedRolePolicies(gomock.Any(), &iam.ListAttachedRolePoliciesInput{
+			RoleName: aws.String("test-role"),
+		}).Return(attachedPolicies("aws", "AmazonEKSWorkerNodePolicy", "AmazonEC2ContainerRegistryReadOnly", "AmazonEKS_CNI_Policy"), nil)
+
+		Expect(ValidateNodeRole(context.Background(), validateOpts)).To(Succeed())
+	})
+
+	It("should look for the node policies in all pages", func() {
+		firstPage := attachedPolicies("aws", "AmazonEKSWorkerNodePolicy")
+		firstPage.IsTruncated = aws.Bool(true)
+		firstPage.Marker = aws.String("next")
+		iamServiceMock.EXPECT().ListAttachedRolePolicies(gomock.Any(), &iam.ListAttachedRolePoliciesInput{
+			RoleName: aws.String("test-role"),
+		}).Return(firstPage, nil)
+		iamServiceMock.EXPECT().ListAttachedRolePolicies(gomock.Any(), &iam.ListAttachedRolePoliciesInput{
+			RoleName: aws.String("test-role"),
+			Marker:   aws.String("next"),
+		}).Return(attachedPolicies("aws", "AmazonEC2ContainerRegistryReadOnly", "AmazonEKS_CNI_Policy"), nil)
+
+		Expect(ValidateNodeRole(context.Background(), validateOpts)).To(Succeed())
+	})
+
+	It("should list the missing policies", func() {
+		iamServiceMock.EXPECT().ListAttachedRolePolicies(gomock.Any(), gomock.Any()).Return(attachedPolicies("aws", "AmazonEKSWorkerNodePolicy"), nil)
+
+		err := ValidateNodeRole(context.Background(), validateOpts)
+		Expect(err).To(MatchError("node role [test-role] for cluster [test-cluster] does not have policies " +
+			"[arn:aws:iam::aws:policy/AmazonEC2ContainerRegistryReadOnly, arn:aws:iam::aws:policy/AmazonEKS_CNI_Policy] attached"))
+	})
+
+	It("should reject the policies of another partition", func() {
+		validateOpts.Config.Spec.Region = "us-gov-west-1"
+		iamServiceMock.EXPECT().ListAttachedRolePolicies(gomock.Any(), gomock.Any()).Return(
+			attachedPolicies("aws", "AmazonEKSWorkerNodePolicy", "AmazonEC2ContainerRegistryReadOnly", "AmazonEKS_CNI_Policy"), nil)
+
+		err := ValidateNodeRole(context.Background(), validateOpts)
+		Expect(err).To(MatchError(ContainSubstring("belong to another partition than region [us-gov-west-1]")))
+	})
+
+	DescribeTable("should expect the node policies of the region partition",
+		func(region, partition string) {
+			validateOpts.Config.Spec.Region = region
+			iamServiceMock.EXPECT().ListAttachedRolePolicies(gomock.Any(), gomock.Any()).Return(
+				attachedPolicies(partition, "AmazonEKSWorkerNodePolicy", "AmazonEC2ContainerRegistryReadOnly", "AmazonEKS_CNI_Policy"), nil)
+
+			Expect(ValidateNodeRole(context.Background(), validateOpts)).To(Succeed())
+		},
+		Entry("aws", "us-west-2", "aws"),
+		Entry("aws-us-gov", "us-gov-west-1", "aws-us-gov"),
+		Entry("aws-cn", "cn-north-1", "aws-cn"),
+	)
+
+	It("should not require the CNI policy if the vpc-cni add-on has a service account role", func() {
+		validateOpts.Config.Spec.Addons = []eksv1.Addon{
+			{
+				Name:                  "vpc-cni",
+				ServiceAccountRoleArn: aws.String("arn:aws:iam::123456789012:role/vpc-cni"),
+			},
+		}
+		iamServiceMock.EXPECT().ListAttachedRolePolicies(gomock.Any(), gomock.Any()).Return(
+			attachedPolicies("aws", "AmazonEKSWorkerNodePolicy", "AmazonEC2ContainerRegistryReadOnly"), nil)
+
+		Expect(ValidateNodeRole(context.Background(), validateOpts)).To(Succeed())
+	})
+
+	DescribeTable("should reject node roles that are not role ARNs",
+		func(nodeRole string) {
+			validateOpts.NodeRole = nodeRole
+			Expect(ValidateNodeRole(context.Background(), validateOpts)).To(MatchError(ContainSubstring("must be the ARN of an IAM role")))
+		},
+		Entry("role name", "test-role"),
+		Entry("malformed ARN", "arn:aws:iam:123456789012:role/test-role"),
+		Entry("policy ARN", "arn:aws:iam::123456789012:policy/test-policy"),
+		Entry("other service", "arn:aws:s3:::test-role"),
+	)
+
+	It("should only check the ARN without IAM service", func() {
+		validateOpts.IAMService = nil
+
+		Expect(ValidateNodeRole(context.Background(), validateOpts)).To(Succeed())
+	})
+
+	It("should not fail if listing the policies is denied", func() {
+		iamServiceMock.EXPECT().ListAttachedRolePolicies(gomock.Any(), gomock.Any()).Return(nil, awserr.New("AccessDenied", "denied", nil))
+
+		Expect(ValidateNodeRole(context.Background(), validateOpts)).To(Succeed())
+	})
+
+	It("should fail if listing the policies returns error", func() {
+		iamServiceMock.EXPECT().ListAttachedRolePolicies(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+		Expect(ValidateNodeRole(context.Background(), validateOpts)).ToNot(Succeed())
+	})
+})
+
+var _ = Describe("ValidatePermissionsBoundaryARN", func() {
+	DescribeTable("should validate the permissions boundary",
+		func(boundary string, valid bool) {
+			err := ValidatePermissionsBoundaryARN(boundary)
+			if valid {
+				Expect(err).ToNot(HaveOccurred())
+			} else {
+				Expect(err).To(HaveOccurred())
+			}
+		},
+		Entry("customer managed policy", "arn:aws:iam::123456789012:policy/boundary", true),
+		Entry("policy with path", "arn:aws-us-gov:iam::123456789012:policy/boundaries/nodes", true),
+		Entry("policy name", "boundary", false),
+		Entry("role", "arn:aws:iam::123456789012:role/boundary", false),
+		Entry("other service", "arn:aws:kms:us-west-2:123456789012:key/boundary", false),
+	)
+})
+
+var _ = Describe("GetClusterRole", func() {
+	var (
+		mockController *gomock.Controller
+		iamServiceMock *mock_services.MockIAMServiceInterface
+		getRoleOpts    *GetClusterRoleOpts
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		iamServiceMock = mock_services.NewMockIAMServiceInterface(mockController)
+		getRoleOpts = &GetClusterRoleOpts{
+			IAMService: iamServiceMock,
+			Config: &eksv1.EKSClusterConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-cluster",
+				},
+				Spec: eksv1.EKSClusterConfigSpec{
+					Region:      "us-west-2",
+					ServiceRole: aws.String("arn:aws:iam::123456789012:role/test-role"),
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	trustPolicyDocument := func(principal string) *string {
+		return aws.String(url.QueryEscape(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"Service":` +
+			principal + `},"Action":"sts:AssumeRole"}]}`))
+	}
+
+	It("should return a service role trusting the EKS principal", func() {
+		iamServiceMock.EXPECT().GetRole(gomock.Any(), &iam.GetRoleInput{
+			RoleName: aws.String("test-role"),
+		}).Return(&iam.GetRoleOutput{
+			Role: &iam.Role{
+				Arn:                      aws.String("arn:aws:iam::123456789012:role/test-role"),
+				AssumeRolePolicyDocument: trustPolicyDocument(`"eks.amazonaws.com"`),
+			},
+		}, nil)
+
+		role, err := GetClusterRole(context.Background(), getRoleOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(aws.StringValue(role.Arn)).To(Equal("arn:aws:iam::123456789012:role/test-role"))
+	})
+
+	It("should find the EKS principal in a list of principals", func() {
+		iamServiceMock.EXPECT().GetRole(gomock.Any(), gomock.Any()).Return(&iam.GetRoleOutput{
+			Role: &iam.Role{
+				AssumeRolePolicyDocument: trustPolicyDocument(`["ec2.amazonaws.com","eks.amazonaws.com"]`),
+			},
+		}, nil)
+
+		_, err := GetClusterRole(context.Background(), getRoleOpts)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should accept the EKS principal in the China partition", func() {
+		getRoleOpts.Config.Spec.Region = "cn-north-1"
+		iamServiceMock.EXPECT().GetRole(gomock.Any(), gomock.Any()).Return(&iam.GetRoleOutput{
+			Role: &iam.Role{
+				AssumeRolePolicyDocument: trustPolicyDocument(`"eks.amazonaws.com"`),
+			},
+		}, nil)
+
+		_, err := GetClusterRole(context.Background(), getRoleOpts)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should reject an EKS principal built from the partition DNS suffix", func() {
+		getRoleOpts.Config.Spec.Region = "cn-north-1"
+		iamServiceMock.EXPECT().GetRole(gomock.Any(), gomock.Any()).Return(&iam.GetRoleOutput{
+			Role: &iam.Role{
+				AssumeRolePolicyDocument: trustPolicyDocument(`"eks.amazonaws.com.cn"`),
+			},
+		}, nil)
+
+		_, err := GetClusterRole(context.Background(), getRoleOpts)
+		Expect(err).To(MatchError(ContainSubstring("allows principal [eks.amazonaws.com.cn] which is not an EKS service principal, expected [eks.amazonaws.com]")))
+	})
+
+	It("should reject a service role not trusting the EKS principal", func() {
+		iamServiceMock.EXPECT().GetRole(gomock.Any(), gomock.Any()).Return(&iam.GetRoleOutput{
+			Role: &iam.Role{
+				AssumeRolePolicyDocument: trustPolicyDocument(`"ec2.amazonaws.com"`),
+			},
+		}, nil)
+
+		_, err := GetClusterRole(context.Background(), getRoleOpts)
+		Expect(err).To(MatchError(ContainSubstring("does not allow principal [eks.amazonaws.com]")))
+	})
+
+	It("should fail if getting the role returns error", func() {
+		iamServiceMock.EXPECT().GetRole(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+		_, err := GetClusterRole(context.Background(), getRoleOpts)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("GetServiceRoleTemplate", func() {
+	DescribeTable("should trust the EKS principal and attach the managed policies of the region partition",
+		func(region, expectedPrincipal, expectedPolicyARN string) {
+			template := GetServiceRoleTemplate(region)
+			Expect(template).To(ContainSubstring("- " + expectedPrincipal + "\n"))
+			Expect(template).To(ContainSubstring("- " + expectedPolicyARN + "\n"))
+		},
+		Entry("aws", "us-west-2", "eks.amazonaws.com", "arn:aws:iam::aws:policy/AmazonEKSClusterPolicy"),
+		Entry("aws-us-gov", "us-gov-west-1", "eks.amazonaws.com", "arn:aws-us-gov:iam::aws:policy/AmazonEKSClusterPolicy"),
+		Entry("aws-cn", "cn-north-1", "eks.amazonaws.com", "arn:aws-cn:iam::aws:policy/AmazonEKSClusterPolicy"),
+	)
+})
+
+var _ = Describe("getNodeInstanceRoleTemplate", func() {
+	DescribeTable("should trust the EC2 principal and attach the managed policies of the region partition",
+		func(region, expectedPrincipal, expectedPolicyARN string) {
+			template := getNodeInstanceRoleTemplate(region)
+			Expect(template).To(ContainSubstring("Service: " + expectedPrincipal + "\n"))
+			Expect(template).To(ContainSubstring("- " + expectedPolicyARN + "\n"))
+		},
+		Entry("aws", "us-west-2", "ec2.amazonaws.com", "arn:aws:iam::aws:policy/AmazonEKSWorkerNodePolicy"),
+		Entry("aws-us-gov", "us-gov-west-1", "ec2.amazonaws.com", "arn:aws-us-gov:iam::aws:policy/AmazonEKSWorkerNodePolicy"),
+		Entry("aws-cn", "cn-north-1", "ec2.amazonaws.com.cn", "arn:aws-cn:iam::aws:policy/AmazonEKSWorkerNodePolicy"),
+	)
+})
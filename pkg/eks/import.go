@@ -0,0 +1,73 @@
+package eks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/pkg/eks/services"
+)
+
+type ImportClusterOpts struct {
+	EKSService services.EKSServiceInterface
+	EC2Service services.EC2ServiceInterface
+	Config     *eksv1.EKSClusterConfig
+}
+
+// ImportCluster returns the status of a config importing an existing cluster, such as one created by eksctl,
+// populated from the upstream cluster without creating anything. On top of the fields set by RefreshClusterStatus,
+// a launch template created by the operator for the cluster is adopted if it carries the rancher-managed-template
+// tag, along with the versions of it used by the node groups of the cluster.
+func ImportCluster(ctx context.Context, opts *ImportClusterOpts) (_ *eksv1.EKSClusterConfigStatus, err error) {
+	ctx, span := startSpan(ctx, "ImportCluster", opts.Config.Spec.DisplayName)
+	defer func() { endSpan(span, err) }()
+
+	status, err := RefreshClusterStatus(ctx, &RefreshClusterStatusOpts{
+		EKSService: opts.EKSService,
+		Config:     opts.Config,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	templateID, err := findManagedLaunchTemplate(ctx, opts.EC2Service, opts.Config)
+	if err != nil {
+		return nil, fmt.Errorf("error importing cluster [%s]: %w", opts.Config.Name, err)
+	}
+	status.ManagedLaunchTemplateID = templateID
+	status.ManagedLaunchTemplateVersions = nil
+	if templateID == "" {
+		return status, nil
+	}
+
+	nodegroups, err := ListNodegroups(ctx, &ListNodegroupsOpts{
+		EKSService: opts.EKSService,
+		Config:     opts.Config,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ngName := range nodegroups {
+		ng, err := GetNodegroupState(ctx, &GetNodegroupStateOpts{
+			EKSService:    opts.EKSService,
+			Config:        opts.Config,
+			NodegroupName: ngName,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error describing nodegroup [%s] in cluster [%s]: %w", ngName, opts.Config.Name, err)
+		}
+
+		if ng.Nodegroup == nil || ng.Nodegroup.LaunchTemplate == nil ||
+			aws.StringValue(ng.Nodegroup.LaunchTemplate.Id) != templateID {
+			continue
+		}
+		if status.ManagedLaunchTemplateVersions == nil {
+			status.ManagedLaunchTemplateVersions = map[string]string{}
+		}
+		status.ManagedLaunchTemplateVersions[ngName] = aws.StringValue(ng.Nodegroup.LaunchTemplate.Version)
+	}
+
+	return status, nil
+}
@@ -0,0 +1,167 @@
+package eks
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/pkg/eks/services/mock_services"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("ImportCluster", func() {
+	var (
+		mockController    *gomock.Controller
+		eksServiceMock    *mock_services.MockEKSServiceInterface
+		ec2ServiceMock    *mock_services.MockEC2ServiceInterface
+		importClusterOpts *ImportClusterOpts
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+		ec2ServiceMock = mock_services.NewMockEC2ServiceInterface(mockController)
+		importClusterOpts = &ImportClusterOpts{
+			EKSService: eksServiceMock,
+			EC2Service: ec2ServiceMock,
+			Config: &eksv1.EKSClusterConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-cluster",
+				},
+				Spec: eksv1.EKSClusterConfigSpec{
+					DisplayName: "test",
+					Imported:    true,
+				},
+			},
+		}
+
+		eksServiceMock.EXPECT().DescribeCluster(gomock.Any(), &eks.DescribeClusterInput{
+			Name: aws.String("test"),
+		}).Return(&eks.DescribeClusterOutput{
+			Cluster: &eks.Cluster{
+				Arn:      aws.String("arn:aws:eks:us-west-2:123456789012:cluster/test"),
+				Endpoint: aws.String("https://test.eks.amazonaws.com"),
+				Status:   aws.String(eks.ClusterStatusActive),
+				CertificateAuthority: &eks.Certificate{
+					Data: aws.String("ca-data"),
+				},
+				ResourcesVpcConfig: &eks.VpcConfigResponse{
+					VpcId:                  aws.String("vpc-1"),
+					SubnetIds:              aws.StringSlice([]string{"subnet-1", "subnet-2"}),
+					SecurityGroupIds:       aws.StringSlice([]string{"sg-1"}),
+					ClusterSecurityGroupId: aws.String("sg-cluster"),
+				},
+				EncryptionConfig: []*eks.EncryptionConfig{
+					{Provider: &eks.Provider{KeyArn: aws.String("arn:aws:kms:us-west-2:123456789012:key/test")}},
+				},
+				Identity: &eks.Identity{
+					Oidc: &eks.OIDC{Issuer: aws.String("https://oidc.eks.us-west-2.amazonaws.com/id/TEST")},
+				},
+			},
+		}, nil)
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	expectManagedLaunchTemplate := func(templates ...*ec2.LaunchTemplate) {
+		ec2ServiceMock.EXPECT().DescribeLaunchTemplates(gomock.Any(), &ec2.DescribeLaunchTemplatesInput{
+			Filters: []*ec2.Filter{
+				{
+					Name:   aws.String("tag:" + launchTemplateTagKey),
+					Values: aws.StringSlice([]string{launchTemplateTagValue}),
+				},
+				{
+					Name:   aws.String("launch-template-name"),
+					Values: aws.StringSlice([]string{"rancher-managed-lt-test"}),
+				},
+			},
+		}).Return(&ec2.DescribeLaunchTemplatesOutput{LaunchTemplates: templates}, nil)
+	}
+
+	It("should populate the status from the upstream cluster and adopt the managed launch template", func() {
+		expectManagedLaunchTemplate(&ec2.LaunchTemplate{LaunchTemplateId: aws.String("lt-managed")})
+		eksServiceMock.EXPECT().ListNodegroups(gomock.Any(), &eks.ListNodegroupsInput{
+			ClusterName: aws.String("test"),
+		}).Return(&eks.ListNodegroupsOutput{
+			Nodegroups: aws.StringSlice([]string{"ng-managed", "ng-custom", "ng-default"}),
+		}, nil)
+		eksServiceMock.EXPECT().DescribeNodegroup(gomock.Any(), &eks.DescribeNodegroupInput{
+			ClusterName:   aws.String("test"),
+			NodegroupName: aws.String("ng-managed"),
+		}).Return(&eks.DescribeNodegroupOutput{
+			Nodegroup: &eks.Nodegroup{
+				LaunchTemplate: &eks.LaunchTemplateSpecification{Id: aws.String("lt-managed"), Version: aws.String("3")},
+			},
+		}, nil)
+		eksServiceMock.EXPECT().DescribeNodegroup(gomock.Any(), &eks.DescribeNodegroupInput{
+			ClusterName:   aws.String("test"),
+			NodegroupName: aws.String("ng-custom"),
+		}).Return(&eks.DescribeNodegroupOutput{
+			Nodegroup: &eks.Nodegroup{
+				LaunchTemplate: &eks.LaunchTemplateSpecification{Id: aws.String("lt-custom"), Version: aws.String("1")},
+			},
+		}, nil)
+		eksServiceMock.EXPECT().DescribeNodegroup(gomock.Any(), &eks.DescribeNodegroupInput{
+			ClusterName:   aws.String("test"),
+			NodegroupName: aws.String("ng-default"),
+		}).Return(&eks.DescribeNodegroupOutput{
+			Nodegroup: &eks.Nodegroup{},
+		}, nil)
+		ec2ServiceMock.EXPECT().CreateLaunchTemplate(gomock.Any(), gomock.Any()).Times(0)
+
+		status, err := ImportCluster(context.Background(), importClusterOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status).To(Equal(&eksv1.EKSClusterConfigStatus{
+			VirtualNetwork:                "vpc-1",
+			Subnets:                       []string{"subnet-1", "subnet-2"},
+			SecurityGroups:                []string{"sg-1"},
+			ManagedLaunchTemplateID:       "lt-managed",
+			ManagedLaunchTemplateVersions: map[string]string{"ng-managed": "3"},
+			ClusterARN:                    "arn:aws:eks:us-west-2:123456789012:cluster/test",
+			Endpoint:                      "https://test.eks.amazonaws.com",
+			CertificateAuthorityData:      "ca-data",
+			KmsKeyARN:                     "arn:aws:kms:us-west-2:123456789012:key/test",
+			OIDCIssuer:                    "https://oidc.eks.us-west-2.amazonaws.com/id/TEST",
+			ClusterSecurityGroupID:        "sg-cluster",
+		}))
+	})
+
+	It("should not create a launch template if there is no managed launch template", func() {
+		expectManagedLaunchTemplate()
+		eksServiceMock.EXPECT().ListNodegroups(gomock.Any(), gomock.Any()).Times(0)
+		ec2ServiceMock.EXPECT().CreateLaunchTemplate(gomock.Any(), gomock.Any()).Times(0)
+
+		status, err := ImportCluster(context.Background(), importClusterOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status.ManagedLaunchTemplateID).To(BeEmpty())
+		Expect(status.ManagedLaunchTemplateVersions).To(BeNil())
+		Expect(status.Subnets).To(Equal([]string{"subnet-1", "subnet-2"}))
+		Expect(status.OIDCIssuer).To(Equal("https://oidc.eks.us-west-2.amazonaws.com/id/TEST"))
+	})
+
+	It("should fail to import the cluster if looking up the launch template fails", func() {
+		ec2ServiceMock.EXPECT().DescribeLaunchTemplates(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+		_, err := ImportCluster(context.Background(), importClusterOpts)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should fail to import the cluster if describing a nodegroup fails", func() {
+		expectManagedLaunchTemplate(&ec2.LaunchTemplate{LaunchTemplateId: aws.String("lt-managed")})
+		eksServiceMock.EXPECT().ListNodegroups(gomock.Any(), gomock.Any()).Return(&eks.ListNodegroupsOutput{
+			Nodegroups: aws.StringSlice([]string{"ng-managed"}),
+		}, nil)
+		eksServiceMock.EXPECT().DescribeNodegroup(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+		_, err := ImportCluster(context.Background(), importClusterOpts)
+		Expect(err).To(HaveOccurred())
+	})
+})
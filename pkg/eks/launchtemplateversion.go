@@ -0,0 +1,117 @@
+package eks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/rancher/eks-operator/pkg/eks/services"
+)
+
+// validateLaunchTemplateVersionCompatibility ensures the launch template version a node group is updated to exists
+// and can run with the AMI type of the node group. EKS accepts such an update and only fails the rollout once the
+// new nodes cannot be launched or never join the cluster.
+//
+// A node group created with a custom AMI has to keep getting its AMI from the launch template, while any other node
+// group must leave the AMI to EKS. The instance type of the launch template has to support the architecture of the
+// AMI type of the node group, or of the AMI of the launch template for a custom AMI.
+func validateLaunchTemplateVersionCompatibility(ctx context.Context, opts *UpdateNodegroupVersionOpts, lt *eks.LaunchTemplateSpecification) error {
+	ngName := aws.StringValue(opts.NGVersionInput.NodegroupName)
+	ltName := aws.StringValue(lt.Id)
+	if ltName == "" {
+		ltName = aws.StringValue(lt.Name)
+	}
+
+	ngState, err := opts.EKSService.DescribeNodegroup(ctx, &eks.DescribeNodegroupInput{
+		ClusterName:   opts.NGVersionInput.ClusterName,
+		NodegroupName: opts.NGVersionInput.NodegroupName,
+	})
+	if err != nil {
+		return fmt.Errorf("error describing nodegroup [%s] in cluster [%s]: %w", ngName, opts.Config.Name, err)
+	}
+	amiType := aws.StringValue(ngState.Nodegroup.AmiType)
+
+	versions, err := opts.EC2Service.DescribeLaunchTemplateVersions(ctx, &ec2.DescribeLaunchTemplateVersionsInput{
+		LaunchTemplateId:   lt.Id,
+		LaunchTemplateName: lt.Name,
+		Versions:           []*string{lt.Version},
+	})
+	if err != nil {
+		return fmt.Errorf("error describing version [%s] of launch template [%s] for nodegroup [%s]: %w",
+			aws.StringValue(lt.Version), ltName, ngName, err)
+	}
+	if len(versions.LaunchTemplateVersions) == 0 || versions.LaunchTemplateVersions[0].LaunchTemplateData == nil {
+		return fmt.Errorf("version [%s] of launch template [%s] for nodegroup [%s] does not exist", aws.StringValue(lt.Version), ltName, ngName)
+	}
+	data := versions.LaunchTemplateVersions[0].LaunchTemplateData
+
+	imageID := aws.StringValue(data.ImageId)
+	if amiType == eks.AMITypesCustom && imageID == "" {
+		return fmt.Errorf("nodegroup [%s] uses a custom AMI, version [%s] of launch template [%s] must specify an AMI",
+			ngName, aws.StringValue(lt.Version), ltName)
+	}
+	if amiType != eks.AMITypesCustom && imageID != "" {
+		return fmt.Errorf("nodegroup [%s] uses AMI type [%s], version [%s] of launch template [%s] cannot specify an AMI, create a new nodegroup to use a custom AMI",
+			ngName, amiType, aws.StringValue(lt.Version), ltName)
+	}
+
+	instanceType := aws.StringValue(data.InstanceType)
+	if instanceType == "" {
+		return nil
+	}
+
+	architecture, err := getAMIArchitecture(ctx, opts.EC2Service, amiType, imageID)
+	if err != nil {
+		return fmt.Errorf("nodegroup [%s]: %w", ngName, err)
+	}
+
+	instanceTypes, err := opts.EC2Service.DescribeInstanceTypes(ctx, &ec2.DescribeInstanceTypesInput{
+		InstanceTypes: aws.StringSlice([]string{instanceType}),
+	})
+	if err != nil {
+		return fmt.Errorf("error describing instance type [%s] of nodegroup [%s]: %w", instanceType, ngName, err)
+	}
+	for _, info := range instanceTypes.InstanceTypes {
+		if info.ProcessorInfo != nil && !supportsArchitecture(info.ProcessorInfo, architecture) {
+			return fmt.Errorf("instance type [%s] of version [%s] of launch template [%s] does not support architecture [%s] of nodegroup [%s]",
+				instanceType, aws.StringValue(lt.Version), ltName, architecture, ngName)
+		}
+	}
+
+	return nil
+}
+
+func supportsArchitecture(processor *ec2.ProcessorInfo, architecture string) bool {
+	for _, supported := range processor.SupportedArchitectures {
+		if aws.StringValue(supported) == architecture {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getAMIArchitecture returns the architecture of the AMIs of the given AMI type, or of the given custom AMI.
+func getAMIArchitecture(ctx context.Context, ec2Service services.EC2ServiceInterface, amiType, imageID string) (string, error) {
+	if amiType != eks.AMITypesCustom {
+		if strings.Contains(amiType, "ARM_64") {
+			return ec2.ArchitectureValuesArm64, nil
+		}
+		return ec2.ArchitectureValuesX8664, nil
+	}
+
+	images, err := ec2Service.DescribeImages(ctx, &ec2.DescribeImagesInput{
+		ImageIds: aws.StringSlice([]string{imageID}),
+	})
+	if err != nil {
+		return "", fmt.Errorf("error describing image [%s]: %w", imageID, err)
+	}
+	if len(images.Images) == 0 {
+		return "", fmt.Errorf("image [%s] does not exist", imageID)
+	}
+
+	return aws.StringValue(images.Images[0].Architecture), nil
+}
@@ -0,0 +1,25 @@
+package eks
+
+import (
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	clusterLogField   = "cluster"
+	regionLogField    = "region"
+	phaseLogField     = "phase"
+	operationLogField = "operation"
+	nodegroupLogField = "nodegroup"
+)
+
+// clusterLogger returns a log entry carrying the cluster, its region, its phase and the operation being run as
+// fields, so that the logs of a cluster can be queried.
+func clusterLogger(config *eksv1.EKSClusterConfig, operation string) *logrus.Entry {
+	return logrus.WithFields(logrus.Fields{
+		clusterLogField:   config.Name,
+		regionLogField:    config.Spec.Region,
+		phaseLogField:     config.Status.Phase,
+		operationLogField: operation,
+	})
+}
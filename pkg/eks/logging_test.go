@@ -0,0 +1,71 @@
+package eks
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/pkg/eks/services/mock_services"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("logging", func() {
+	var (
+		mockController *gomock.Controller
+		eksServiceMock *mock_services.MockEKSServiceInterface
+		hook           *test.Hook
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+		hook = test.NewGlobal()
+	})
+
+	AfterEach(func() {
+		logrus.StandardLogger().ReplaceHooks(make(logrus.LevelHooks))
+		mockController.Finish()
+	})
+
+	It("should log with the cluster fields", func() {
+		eksServiceMock.EXPECT().UpdateClusterVersion(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+		_, err := UpdateClusterVersion(context.Background(), &UpdateClusterVersionOpts{
+			EKSService: eksServiceMock,
+			Config: &eksv1.EKSClusterConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "c-abcde",
+				},
+				Spec: eksv1.EKSClusterConfigSpec{
+					DisplayName:       "test",
+					Region:            "us-west-2",
+					KubernetesVersion: aws.String("1.27"),
+				},
+				Status: eksv1.EKSClusterConfigStatus{
+					Phase: "active",
+				},
+			},
+			UpstreamClusterSpec: &eksv1.EKSClusterConfigSpec{
+				KubernetesVersion: aws.String("1.26"),
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(hook.Entries).To(HaveLen(1))
+		entry := hook.LastEntry()
+		Expect(entry.Level).To(Equal(logrus.InfoLevel))
+		Expect(entry.Message).To(Equal("updating kubernetes version"))
+		Expect(entry.Data).To(Equal(logrus.Fields{
+			"cluster":   "c-abcde",
+			"region":    "us-west-2",
+			"phase":     "active",
+			"operation": "UpdateClusterVersion",
+			"version":   "1.27",
+		}))
+	})
+})
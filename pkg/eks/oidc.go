@@ -0,0 +1,104 @@
+package eks
+
+import (
+	"context"
+	"crypto/sha1" // nolint:gosec // IAM identifies the CA of OIDC providers by the SHA-1 fingerprint of its certificate
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/pkg/eks/services"
+)
+
+// stsAudience is the audience of the service account tokens exchanged for IAM role credentials.
+const stsAudience = "sts.amazonaws.com"
+
+// OIDCThumbprintGetter returns the SHA-1 fingerprint of the root CA certificate serving the OIDC issuer.
+type OIDCThumbprintGetter func(ctx context.Context, issuer string) (string, error)
+
+type AssociateIAMOIDCProviderOpts struct {
+	EKSService services.EKSServiceInterface
+	IAMService services.IAMServiceInterface
+	Config     *eksv1.EKSClusterConfig
+	// GetThumbprint defaults to connecting to the issuer and fingerprinting the last certificate it presents.
+	GetThumbprint OIDCThumbprintGetter
+}
+
+// AssociateIAMOIDCProvider registers the OIDC issuer of the cluster as an IAM OIDC provider, which IAM roles for
+// service accounts trust to exchange service account tokens for credentials. Identity provider configs associated
+// through EKS only authenticate users against the cluster API and do not allow this. The provider is looked up by
+// the issuer first, so an existing one, created by the operator or not, is returned instead of creating another.
+func AssociateIAMOIDCProvider(ctx context.Context, opts *AssociateIAMOIDCProviderOpts) (_ string, err error) {
+	ctx, span := startSpan(ctx, "AssociateIAMOIDCProvider", opts.Config.Spec.DisplayName)
+	defer func() { endSpan(span, err) }()
+
+	issuer, err := GetClusterOIDCIssuer(ctx, &GetClusterStatusOpts{
+		EKSService: opts.EKSService,
+		Config:     opts.Config,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	providers, err := opts.IAMService.ListOpenIDConnectProviders(ctx, &iam.ListOpenIDConnectProvidersInput{})
+	if err != nil {
+		return "", fmt.Errorf("error listing IAM OIDC providers for cluster [%s]: %w", opts.Config.Name, err)
+	}
+	providerSuffix := ":oidc-provider/" + strings.TrimPrefix(issuer, "https://")
+	for _, provider := range providers.OpenIDConnectProviderList {
+		if providerARN := aws.StringValue(provider.Arn); strings.HasSuffix(providerARN, providerSuffix) {
+			return providerARN, nil
+		}
+	}
+
+	getThumbprint := opts.GetThumbprint
+	if getThumbprint == nil {
+		getThumbprint = getOIDCIssuerThumbprint
+	}
+	thumbprint, err := getThumbprint(ctx, issuer)
+	if err != nil {
+		return "", fmt.Errorf("error getting thumbprint of OIDC issuer [%s] for cluster [%s]: %w", issuer, opts.Config.Name, err)
+	}
+
+	output, err := opts.IAMService.CreateOpenIDConnectProvider(ctx, &iam.CreateOpenIDConnectProviderInput{
+		Url:            aws.String(issuer),
+		ClientIDList:   aws.StringSlice([]string{stsAudience}),
+		ThumbprintList: aws.StringSlice([]string{thumbprint}),
+	})
+	if err != nil {
+		return "", fmt.Errorf("error creating IAM OIDC provider for cluster [%s]: %w", opts.Config.Name, err)
+	}
+
+	return aws.StringValue(output.OpenIDConnectProviderArn), nil
+}
+
+func getOIDCIssuerThumbprint(ctx context.Context, issuer string) (string, error) {
+	issuerURL, err := url.Parse(issuer)
+	if err != nil {
+		return "", err
+	}
+	host := issuerURL.Host
+	if issuerURL.Port() == "" {
+		host = net.JoinHostPort(issuerURL.Hostname(), "443")
+	}
+
+	dialer := &tls.Dialer{Config: &tls.Config{MinVersion: tls.VersionTLS12}}
+	conn, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	certificates := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(certificates) == 0 {
+		return "", fmt.Errorf("no certificate presented by [%s]", host)
+	}
+	fingerprint := sha1.Sum(certificates[len(certificates)-1].Raw) // nolint:gosec
+	return hex.EncodeToString(fingerprint[:]), nil
+}
@@ -0,0 +1,109 @@
+package eks
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/pkg/eks/services/mock_services"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("AssociateIAMOIDCProvider", func() {
+	const (
+		issuer      = "https://oidc.eks.us-west-2.amazonaws.com/id/TEST"
+		providerARN = "arn:aws:iam::123456789012:oidc-provider/oidc.eks.us-west-2.amazonaws.com/id/TEST"
+	)
+
+	var (
+		mockController *gomock.Controller
+		eksServiceMock *mock_services.MockEKSServiceInterface
+		iamServiceMock *mock_services.MockIAMServiceInterface
+		opts           *AssociateIAMOIDCProviderOpts
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+		iamServiceMock = mock_services.NewMockIAMServiceInterface(mockController)
+		opts = &AssociateIAMOIDCProviderOpts{
+			EKSService: eksServiceMock,
+			IAMService: iamServiceMock,
+			Config: &eksv1.EKSClusterConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: eksv1.EKSClusterConfigSpec{
+					DisplayName: "test-cluster",
+				},
+			},
+			GetThumbprint: func(_ context.Context, url string) (string, error) {
+				Expect(url).To(Equal(issuer))
+				return "9e99a48a9960b14926bb7f3b02e22da2b0ab7280", nil
+			},
+		}
+		eksServiceMock.EXPECT().DescribeCluster(gomock.Any(), &eks.DescribeClusterInput{
+			Name: aws.String("test-cluster"),
+		}).Return(&eks.DescribeClusterOutput{
+			Cluster: &eks.Cluster{
+				Identity: &eks.Identity{Oidc: &eks.OIDC{Issuer: aws.String(issuer)}},
+			},
+		}, nil).AnyTimes()
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should return the provider already associated with the issuer", func() {
+		iamServiceMock.EXPECT().ListOpenIDConnectProviders(gomock.Any(), gomock.Any()).Return(&iam.ListOpenIDConnectProvidersOutput{
+			OpenIDConnectProviderList: []*iam.OpenIDConnectProviderListEntry{
+				{Arn: aws.String("arn:aws:iam::123456789012:oidc-provider/oidc.eks.us-west-2.amazonaws.com/id/OTHER")},
+				{Arn: aws.String(providerARN)},
+			},
+		}, nil)
+
+		arn, err := AssociateIAMOIDCProvider(context.Background(), opts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(arn).To(Equal(providerARN))
+	})
+
+	It("should create a provider for the issuer", func() {
+		iamServiceMock.EXPECT().ListOpenIDConnectProviders(gomock.Any(), gomock.Any()).Return(&iam.ListOpenIDConnectProvidersOutput{}, nil)
+		iamServiceMock.EXPECT().CreateOpenIDConnectProvider(gomock.Any(), &iam.CreateOpenIDConnectProviderInput{
+			Url:            aws.String(issuer),
+			ClientIDList:   aws.StringSlice([]string{"sts.amazonaws.com"}),
+			ThumbprintList: aws.StringSlice([]string{"9e99a48a9960b14926bb7f3b02e22da2b0ab7280"}),
+		}).Return(&iam.CreateOpenIDConnectProviderOutput{
+			OpenIDConnectProviderArn: aws.String(providerARN),
+		}, nil)
+
+		arn, err := AssociateIAMOIDCProvider(context.Background(), opts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(arn).To(Equal(providerARN))
+	})
+
+	It("should fail if the thumbprint cannot be retrieved", func() {
+		iamServiceMock.EXPECT().ListOpenIDConnectProviders(gomock.Any(), gomock.Any()).Return(&iam.ListOpenIDConnectProvidersOutput{}, nil)
+		opts.GetThumbprint = func(_ context.Context, _ string) (string, error) {
+			return "", errors.New("connection refused")
+		}
+
+		_, err := AssociateIAMOIDCProvider(context.Background(), opts)
+		Expect(err).To(MatchError(ContainSubstring("connection refused")))
+	})
+
+	It("should fail if creating the provider fails", func() {
+		iamServiceMock.EXPECT().ListOpenIDConnectProviders(gomock.Any(), gomock.Any()).Return(&iam.ListOpenIDConnectProvidersOutput{}, nil)
+		iamServiceMock.EXPECT().CreateOpenIDConnectProvider(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+		_, err := AssociateIAMOIDCProvider(context.Background(), opts)
+		Expect(err).To(HaveOccurred())
+	})
+})
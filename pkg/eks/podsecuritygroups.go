@@ -0,0 +1,62 @@
+package eks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/pkg/eks/services"
+)
+
+// validateSecurityGroupsForPods ensures the instance types of a node group requesting security groups for pods can
+// attach the trunk network interface the branch interfaces of the pods are created on. That is the case for nitro
+// and bare metal instance types, except for burstable instance types.
+//
+// The nodes only get a trunk interface once the vpc-cni add-on of the cluster is configured with ENABLE_POD_ENI set
+// to true, which is not managed here. When the node group uses its own launch template, the instance type is part of
+// that launch template and it is up to the user to choose a supported one.
+func validateSecurityGroupsForPods(ctx context.Context, ec2Service services.EC2ServiceInterface, ng *eksv1.NodeGroup) error {
+	if !aws.BoolValue(ng.SecurityGroupsForPods) || ng.LaunchTemplate != nil {
+		return nil
+	}
+
+	instanceTypes := []*string{ng.InstanceType}
+	if aws.BoolValue(ng.RequestSpotInstances) {
+		instanceTypes = ng.SpotInstanceTypes
+	}
+	if len(instanceTypes) == 0 || aws.StringValue(instanceTypes[0]) == "" {
+		return nil
+	}
+
+	output, err := ec2Service.DescribeInstanceTypes(ctx, &ec2.DescribeInstanceTypesInput{
+		InstanceTypes: instanceTypes,
+	})
+	if err != nil {
+		return fmt.Errorf("error describing instance types of nodegroup [%s]: %w", aws.StringValue(ng.NodegroupName), err)
+	}
+
+	var unsupported []string
+	for _, info := range output.InstanceTypes {
+		if !supportsTrunkInterface(info) {
+			unsupported = append(unsupported, aws.StringValue(info.InstanceType))
+		}
+	}
+	if len(unsupported) != 0 {
+		return fmt.Errorf("nodegroup [%s]: instance types [%s] do not support trunk network interfaces required by securityGroupsForPods, use a nitro or bare metal instance type that is not burstable",
+			aws.StringValue(ng.NodegroupName), strings.Join(unsupported, ", "))
+	}
+
+	return nil
+}
+
+func supportsTrunkInterface(info *ec2.InstanceTypeInfo) bool {
+	if aws.StringValue(info.Hypervisor) != ec2.InstanceTypeHypervisorNitro && !aws.BoolValue(info.BareMetal) {
+		return false
+	}
+
+	// families starting with t, such as trn1, are not necessarily burstable
+	return !aws.BoolValue(info.BurstablePerformanceSupported)
+}
@@ -0,0 +1,110 @@
+package eks
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/pkg/eks/services/mock_services"
+)
+
+var _ = Describe("validateSecurityGroupsForPods", func() {
+	var (
+		mockController *gomock.Controller
+		ec2ServiceMock *mock_services.MockEC2ServiceInterface
+		nodeGroup      *eksv1.NodeGroup
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		ec2ServiceMock = mock_services.NewMockEC2ServiceInterface(mockController)
+		nodeGroup = &eksv1.NodeGroup{
+			NodegroupName:         aws.String("test"),
+			InstanceType:          aws.String("m5.large"),
+			SecurityGroupsForPods: aws.Bool(true),
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should accept a supported instance type", func() {
+		ec2ServiceMock.EXPECT().DescribeInstanceTypes(gomock.Any(), &ec2.DescribeInstanceTypesInput{
+			InstanceTypes: aws.StringSlice([]string{"m5.large"}),
+		}).Return(&ec2.DescribeInstanceTypesOutput{
+			InstanceTypes: []*ec2.InstanceTypeInfo{
+				{
+					InstanceType: aws.String("m5.large"),
+					Hypervisor:   aws.String(ec2.InstanceTypeHypervisorNitro),
+				},
+			},
+		}, nil)
+
+		Expect(validateSecurityGroupsForPods(context.Background(), ec2ServiceMock, nodeGroup)).To(Succeed())
+	})
+
+	It("should reject unsupported spot instance types", func() {
+		nodeGroup.InstanceType = nil
+		nodeGroup.RequestSpotInstances = aws.Bool(true)
+		nodeGroup.SpotInstanceTypes = aws.StringSlice([]string{"m5.large", "t3.large", "m4.large"})
+		ec2ServiceMock.EXPECT().DescribeInstanceTypes(gomock.Any(), &ec2.DescribeInstanceTypesInput{
+			InstanceTypes: nodeGroup.SpotInstanceTypes,
+		}).Return(&ec2.DescribeInstanceTypesOutput{
+			InstanceTypes: []*ec2.InstanceTypeInfo{
+				{
+					InstanceType: aws.String("m5.large"),
+					Hypervisor:   aws.String(ec2.InstanceTypeHypervisorNitro),
+				},
+				{
+					InstanceType:                  aws.String("t3.large"),
+					Hypervisor:                    aws.String(ec2.InstanceTypeHypervisorNitro),
+					BurstablePerformanceSupported: aws.Bool(true),
+				},
+				{
+					InstanceType: aws.String("m4.large"),
+					Hypervisor:   aws.String(ec2.InstanceTypeHypervisorXen),
+				},
+			},
+		}, nil)
+
+		err := validateSecurityGroupsForPods(context.Background(), ec2ServiceMock, nodeGroup)
+		Expect(err).To(MatchError(ContainSubstring("instance types [t3.large, m4.large] do not support trunk network interfaces")))
+	})
+
+	It("should fail if describing the instance types fails", func() {
+		ec2ServiceMock.EXPECT().DescribeInstanceTypes(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+		Expect(validateSecurityGroupsForPods(context.Background(), ec2ServiceMock, nodeGroup)).ToNot(Succeed())
+	})
+
+	It("should skip the check if security groups for pods are not requested", func() {
+		nodeGroup.SecurityGroupsForPods = nil
+
+		Expect(validateSecurityGroupsForPods(context.Background(), ec2ServiceMock, nodeGroup)).To(Succeed())
+	})
+
+	It("should skip the check if the node group uses its own launch template", func() {
+		nodeGroup.InstanceType = nil
+		nodeGroup.LaunchTemplate = &eksv1.LaunchTemplate{ID: aws.String("test")}
+
+		Expect(validateSecurityGroupsForPods(context.Background(), ec2ServiceMock, nodeGroup)).To(Succeed())
+	})
+
+	DescribeTable("supportsTrunkInterface",
+		func(info *ec2.InstanceTypeInfo, expected bool) {
+			Expect(supportsTrunkInterface(info)).To(Equal(expected))
+		},
+		Entry("nitro", &ec2.InstanceTypeInfo{InstanceType: aws.String("c5.xlarge"), Hypervisor: aws.String(ec2.InstanceTypeHypervisorNitro)}, true),
+		Entry("bare metal", &ec2.InstanceTypeInfo{InstanceType: aws.String("m5.metal"), BareMetal: aws.Bool(true)}, true),
+		Entry("xen", &ec2.InstanceTypeInfo{InstanceType: aws.String("c4.xlarge"), Hypervisor: aws.String(ec2.InstanceTypeHypervisorXen)}, false),
+		Entry("burstable nitro", &ec2.InstanceTypeInfo{InstanceType: aws.String("t3.medium"), Hypervisor: aws.String(ec2.InstanceTypeHypervisorNitro),
+			BurstablePerformanceSupported: aws.Bool(true)}, false),
+		Entry("trainium", &ec2.InstanceTypeInfo{InstanceType: aws.String("trn1.2xlarge"), Hypervisor: aws.String(ec2.InstanceTypeHypervisorNitro)}, true),
+	)
+})
@@ -0,0 +1,110 @@
+package eks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+)
+
+// ErrPublicAccessLockout is returned when new public access sources would stop the operator from reaching the
+// cluster API through the public endpoint.
+var ErrPublicAccessLockout = errors.New("public access sources would exclude the operator")
+
+// PublicIPDetector returns the public IP address the operator reaches the cluster endpoint from.
+type PublicIPDetector func(ctx context.Context) (net.IP, error)
+
+// NewHTTPPublicIPDetector returns a detector that asks a service answering with the caller address in plain text,
+// such as https://checkip.amazonaws.com.
+func NewHTTPPublicIPDetector(url string, client *http.Client) PublicIPDetector {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return func(ctx context.Context) (net.IP, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("error detecting public IP from [%s]: %w", url, err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("error detecting public IP from [%s]: unexpected status [%s]", url, resp.Status)
+		}
+		body, err := io.ReadAll(io.LimitReader(resp.Body, 256))
+		if err != nil {
+			return nil, fmt.Errorf("error detecting public IP from [%s]: %w", url, err)
+		}
+		ip := net.ParseIP(strings.TrimSpace(string(body)))
+		if ip == nil {
+			return nil, fmt.Errorf("error detecting public IP from [%s]: invalid address [%s]", url, strings.TrimSpace(string(body)))
+		}
+		return ip, nil
+	}
+}
+
+type CheckPublicAccessLockoutOpts struct {
+	Config              *eksv1.EKSClusterConfig
+	UpstreamClusterSpec *eksv1.EKSClusterConfigSpec
+	DetectPublicIP      PublicIPDetector
+}
+
+// CheckPublicAccessLockout returns an error wrapping ErrPublicAccessLockout when the public access sources of the
+// config would exclude the public IP of the operator while the current sources of the cluster include it. Sources
+// that already exclude the operator are not reported, the operator is then expected to reach the cluster through
+// its private endpoint. The IP is only detected when the sources differ from the cluster.
+func CheckPublicAccessLockout(ctx context.Context, opts *CheckPublicAccessLockoutOpts) error {
+	if opts.DetectPublicIP == nil || opts.Config.Spec.PublicAccessSources == nil ||
+		!publicAccessSourcesChanged(opts.Config, opts.UpstreamClusterSpec) {
+		return nil
+	}
+
+	sources := filterPublicAccessSources(opts.Config.Spec.PublicAccessSources)
+	if sources == nil {
+		return nil
+	}
+
+	ip, err := opts.DetectPublicIP(ctx)
+	if err != nil {
+		return err
+	}
+
+	allowed, err := sourcesAllow(sources, ip)
+	if err != nil {
+		return err
+	}
+	if allowed {
+		return nil
+	}
+	allowedUpstream, err := sourcesAllow(filterPublicAccessSources(opts.UpstreamClusterSpec.PublicAccessSources), ip)
+	if err != nil || !allowedUpstream {
+		return nil
+	}
+
+	return fmt.Errorf("%w: public access sources [%s] of cluster [%s] do not include the operator IP [%s]",
+		ErrPublicAccessLockout, strings.Join(sources, ", "), opts.Config.Name, ip)
+}
+
+// sourcesAllow reports whether the IP is part of the given CIDRs. Empty sources leave the endpoint open.
+func sourcesAllow(sources []string, ip net.IP) (bool, error) {
+	if len(sources) == 0 {
+		return true, nil
+	}
+	for _, source := range sources {
+		_, cidr, err := net.ParseCIDR(source)
+		if err != nil {
+			return false, fmt.Errorf("invalid public access source [%s]: %w", source, err)
+		}
+		if cidr.Contains(ip) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
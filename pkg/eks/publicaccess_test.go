@@ -0,0 +1,120 @@
+package eks
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("CheckPublicAccessLockout", func() {
+	var (
+		detectorCalled bool
+		opts           *CheckPublicAccessLockoutOpts
+	)
+
+	BeforeEach(func() {
+		detectorCalled = false
+		opts = &CheckPublicAccessLockoutOpts{
+			Config: &eksv1.EKSClusterConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+			},
+			UpstreamClusterSpec: &eksv1.EKSClusterConfigSpec{
+				PublicAccessSources: []string{allOpen},
+			},
+			DetectPublicIP: func(_ context.Context) (net.IP, error) {
+				detectorCalled = true
+				return net.ParseIP("203.0.113.10"), nil
+			},
+		}
+	})
+
+	DescribeTable("should detect public access sources excluding the operator",
+		func(sources, upstreamSources []string, lockedOut bool) {
+			opts.Config.Spec.PublicAccessSources = sources
+			opts.UpstreamClusterSpec.PublicAccessSources = upstreamSources
+
+			err := CheckPublicAccessLockout(context.Background(), opts)
+			if lockedOut {
+				Expect(err).To(MatchError(ErrPublicAccessLockout))
+				Expect(err.Error()).To(ContainSubstring("do not include the operator IP [203.0.113.10]"))
+			} else {
+				Expect(err).ToNot(HaveOccurred())
+			}
+		},
+		Entry("tightened to a CIDR excluding the operator", []string{"198.51.100.0/24"}, []string{allOpen}, true),
+		Entry("tightened from a CIDR including the operator", []string{"198.51.100.0/24"}, []string{"203.0.113.0/24"}, true),
+		Entry("tightened to a CIDR including the operator", []string{"198.51.100.0/24", "203.0.113.0/28"}, []string{allOpen}, false),
+		Entry("opened to everyone", []string{allOpen}, []string{"198.51.100.0/24"}, false),
+		Entry("upstream already excludes the operator", []string{"198.51.100.0/25"}, []string{"198.51.100.0/24"}, false),
+		Entry("upstream without sources", []string{"198.51.100.0/24"}, nil, true),
+	)
+
+	It("should not detect the IP if the sources are not set", func() {
+		Expect(CheckPublicAccessLockout(context.Background(), opts)).To(Succeed())
+		Expect(detectorCalled).To(BeFalse())
+	})
+
+	It("should not detect the IP if the sources match the cluster", func() {
+		opts.Config.Spec.PublicAccessSources = []string{"198.51.100.0/24"}
+		opts.UpstreamClusterSpec.PublicAccessSources = []string{"198.51.100.0/24"}
+
+		Expect(CheckPublicAccessLockout(context.Background(), opts)).To(Succeed())
+		Expect(detectorCalled).To(BeFalse())
+	})
+
+	It("should skip the check without a detector", func() {
+		opts.DetectPublicIP = nil
+		opts.Config.Spec.PublicAccessSources = []string{"198.51.100.0/24"}
+
+		Expect(CheckPublicAccessLockout(context.Background(), opts)).To(Succeed())
+	})
+
+	It("should return detection errors", func() {
+		opts.Config.Spec.PublicAccessSources = []string{"198.51.100.0/24"}
+		opts.DetectPublicIP = func(_ context.Context) (net.IP, error) {
+			return nil, errors.New("error")
+		}
+
+		err := CheckPublicAccessLockout(context.Background(), opts)
+		Expect(err).To(HaveOccurred())
+		Expect(errors.Is(err, ErrPublicAccessLockout)).To(BeFalse())
+	})
+
+	It("should reject invalid sources", func() {
+		opts.Config.Spec.PublicAccessSources = []string{"198.51.100.1"}
+
+		Expect(CheckPublicAccessLockout(context.Background(), opts)).To(MatchError(ContainSubstring("invalid public access source [198.51.100.1]")))
+	})
+})
+
+var _ = Describe("NewHTTPPublicIPDetector", func() {
+	It("should parse the address returned by the service", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte("203.0.113.10\n"))
+		}))
+		defer server.Close()
+
+		ip, err := NewHTTPPublicIPDetector(server.URL, nil)(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ip.String()).To(Equal("203.0.113.10"))
+	})
+
+	It("should fail on an invalid answer", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte("not an ip"))
+		}))
+		defer server.Close()
+
+		_, err := NewHTTPPublicIPDetector(server.URL, nil)(context.Background())
+		Expect(err).To(MatchError(ContainSubstring("invalid address [not an ip]")))
+	})
+})
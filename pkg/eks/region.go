@@ -0,0 +1,47 @@
+package eks
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// ValidateRegion checks that the region is known to one of the default partitions, so the endpoints, service
+// principals and ARNs built for it do not fall back to the commercial partition.
+func ValidateRegion(region string) error {
+	for _, p := range endpoints.DefaultPartitions() {
+		if _, ok := p.Regions()[region]; ok {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("region [%s] is not a known AWS region", region)
+}
+
+// partitionForRegion returns the ID of the partition of the region, such as aws-cn or aws-us-gov, to build the ARNs
+// of resources in that region. The commercial partition is assumed for a region unknown to the SDK.
+func partitionForRegion(region string) string {
+	if p, ok := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), region); ok {
+		return p.ID()
+	}
+	return endpoints.AwsPartitionID
+}
+
+// servicePrincipals holds the service principals that are not <service>.amazonaws.com, by partition. Unlike endpoints,
+// principals do not follow the DNS suffix of the partition: EKS is eks.amazonaws.com in every partition, only EC2 has
+// a principal of its own in the China partition.
+var servicePrincipals = map[string]map[string]string{
+	endpoints.AwsCnPartitionID: {
+		ec2.ServiceName: "ec2.amazonaws.com.cn",
+	},
+}
+
+// getServicePrincipal returns the principal of the service in the partition of the region, to be trusted by the roles
+// that service assumes.
+func getServicePrincipal(service, region string) string {
+	if principal, ok := servicePrincipals[partitionForRegion(region)][service]; ok {
+		return principal
+	}
+	return service + ".amazonaws.com"
+}
@@ -0,0 +1,51 @@
+package eks
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ValidateRegion", func() {
+	DescribeTable("should accept known regions of every partition",
+		func(region string) {
+			Expect(ValidateRegion(region)).To(Succeed())
+		},
+		Entry("aws", "us-west-2"),
+		Entry("aws-us-gov", "us-gov-west-1"),
+		Entry("aws-cn", "cn-northwest-1"),
+	)
+
+	DescribeTable("should reject unknown regions",
+		func(region string) {
+			Expect(ValidateRegion(region)).To(MatchError(ContainSubstring("is not a known AWS region")))
+		},
+		Entry("bogus region", "us-moon-1"),
+		Entry("empty region", ""),
+	)
+})
+
+var _ = Describe("getServicePrincipal", func() {
+	DescribeTable("should return the service principal of the region partition",
+		func(service, region, expectedPrincipal string) {
+			Expect(getServicePrincipal(service, region)).To(Equal(expectedPrincipal))
+		},
+		Entry("eks in aws", "eks", "us-west-2", "eks.amazonaws.com"),
+		Entry("eks in aws-us-gov", "eks", "us-gov-west-1", "eks.amazonaws.com"),
+		Entry("eks in aws-cn", "eks", "cn-north-1", "eks.amazonaws.com"),
+		Entry("ec2 in aws", "ec2", "us-west-2", "ec2.amazonaws.com"),
+		Entry("ec2 in aws-us-gov", "ec2", "us-gov-west-1", "ec2.amazonaws.com"),
+		Entry("ec2 in aws-cn", "ec2", "cn-north-1", "ec2.amazonaws.com.cn"),
+	)
+})
+
+var _ = Describe("partitionForRegion", func() {
+	DescribeTable("should return the partition of the region",
+		func(region, expectedPartition string) {
+			Expect(partitionForRegion(region)).To(Equal(expectedPartition))
+		},
+		Entry("aws", "eu-central-1", "aws"),
+		Entry("aws-us-gov", "us-gov-east-1", "aws-us-gov"),
+		Entry("aws-cn", "cn-north-1", "aws-cn"),
+		Entry("unknown region", "moon-1", "aws"),
+	)
+})
@@ -0,0 +1,83 @@
+package eks
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+)
+
+// retriableErrorCodes are the error codes of throttled requests and of server errors the SDK does not expose
+// through a 5xx status code.
+var retriableErrorCodes = map[string]bool{
+	"Throttling":                  true,
+	"ThrottlingException":         true,
+	"RequestLimitExceeded":        true,
+	"TooManyRequestsException":    true,
+	"ServerException":             true,
+	"ServiceUnavailableException": true,
+	"InternalFailure":             true,
+}
+
+// backoff configures how retryWithBackoff retries a failing call.
+type backoff struct {
+	// maxAttempts is the number of calls made before giving up, including the first one.
+	maxAttempts int
+	// initialDelay is the longest wait before the first retry, it doubles with every retry up to maxDelay.
+	initialDelay time.Duration
+	maxDelay     time.Duration
+	// retriableCodes are the error codes retried along with server errors.
+	retriableCodes map[string]bool
+	// retryAllErrors retries every error, for calls failing until a dependency goes away on its own.
+	retryAllErrors bool
+}
+
+// defaultBackoff retries throttled requests and server errors for about a minute, on top of the few immediate
+// retries the SDK clients already make.
+var defaultBackoff = backoff{
+	maxAttempts:    6,
+	initialDelay:   2 * time.Second,
+	maxDelay:       30 * time.Second,
+	retriableCodes: retriableErrorCodes,
+}
+
+// retryWithBackoff calls fn until it succeeds, fails with an error that is not retriable or the attempts are
+// exhausted, and returns the last error. The wait between calls grows exponentially and is jittered so that
+// reconciles of several clusters throttled at the same time do not retry in lockstep.
+func retryWithBackoff(ctx context.Context, b backoff, fn func() error) error {
+	delay := b.initialDelay
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= b.maxAttempts || !b.retriable(err) {
+			return err
+		}
+
+		// wait for a random duration between half of and the full delay
+		wait := delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+		if err := sleepWithContext(ctx, wait); err != nil {
+			return err
+		}
+
+		delay *= 2
+		if delay > b.maxDelay {
+			delay = b.maxDelay
+		}
+	}
+}
+
+func (b backoff) retriable(err error) bool {
+	if b.retryAllErrors {
+		return true
+	}
+
+	var requestFailure awserr.RequestFailure
+	if errors.As(err, &requestFailure) && requestFailure.StatusCode() >= http.StatusInternalServerError {
+		return true
+	}
+
+	var awsErr awserr.Error
+	return errors.As(err, &awsErr) && b.retriableCodes[awsErr.Code()]
+}
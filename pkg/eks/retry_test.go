@@ -0,0 +1,85 @@
+package eks
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rancher/eks-operator/pkg/eks/services/mock_services"
+)
+
+var _ = Describe("retryWithBackoff", func() {
+	var (
+		mockController *gomock.Controller
+		eksServiceMock *mock_services.MockEKSServiceInterface
+		testBackoff    backoff
+		createCluster  func() error
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+		testBackoff = backoff{
+			maxAttempts:    3,
+			initialDelay:   time.Millisecond,
+			maxDelay:       2 * time.Millisecond,
+			retriableCodes: retriableErrorCodes,
+		}
+		createCluster = func() error {
+			_, err := eksServiceMock.CreateCluster(context.Background(), &eks.CreateClusterInput{Name: aws.String("test")})
+			return err
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should retry throttled calls until they succeed", func() {
+		gomock.InOrder(
+			eksServiceMock.EXPECT().CreateCluster(gomock.Any(), gomock.Any()).Return(nil, awserr.New("Throttling", "Rate exceeded", nil)),
+			eksServiceMock.EXPECT().CreateCluster(gomock.Any(), gomock.Any()).Return(nil, awserr.New("RequestLimitExceeded", "Request limit exceeded", nil)),
+			eksServiceMock.EXPECT().CreateCluster(gomock.Any(), gomock.Any()).Return(&eks.CreateClusterOutput{}, nil),
+		)
+
+		Expect(retryWithBackoff(context.Background(), testBackoff, createCluster)).To(Succeed())
+	})
+
+	It("should retry server errors", func() {
+		gomock.InOrder(
+			eksServiceMock.EXPECT().CreateCluster(gomock.Any(), gomock.Any()).Return(nil,
+				awserr.NewRequestFailure(awserr.New("BadGateway", "bad gateway", nil), 502, "request")),
+			eksServiceMock.EXPECT().CreateCluster(gomock.Any(), gomock.Any()).Return(&eks.CreateClusterOutput{}, nil),
+		)
+
+		Expect(retryWithBackoff(context.Background(), testBackoff, createCluster)).To(Succeed())
+	})
+
+	It("should give up after the maximum number of attempts", func() {
+		eksServiceMock.EXPECT().CreateCluster(gomock.Any(), gomock.Any()).Return(nil, awserr.New("Throttling", "Rate exceeded", nil)).Times(3)
+
+		err := retryWithBackoff(context.Background(), testBackoff, createCluster)
+		Expect(err).To(MatchError(ContainSubstring("Rate exceeded")))
+	})
+
+	It("should not retry errors that are not retriable", func() {
+		eksServiceMock.EXPECT().CreateCluster(gomock.Any(), gomock.Any()).Return(nil, awserr.New(eks.ErrCodeInvalidParameterException, "invalid", nil))
+
+		Expect(retryWithBackoff(context.Background(), testBackoff, createCluster)).ToNot(Succeed())
+	})
+
+	It("should stop retrying once the context is done", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		eksServiceMock.EXPECT().CreateCluster(gomock.Any(), gomock.Any()).Return(nil, awserr.New("Throttling", "Rate exceeded", nil))
+
+		err := retryWithBackoff(ctx, testBackoff, createCluster)
+		Expect(errors.Is(err, context.Canceled)).To(BeTrue())
+	})
+})
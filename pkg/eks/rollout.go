@@ -0,0 +1,240 @@
+package eks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/eks"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/pkg/eks/services"
+)
+
+const (
+	maxUnavailableLimit           = 100
+	maxUnavailablePercentageLimit = 100
+
+	// UpdateStrategySurge replaces one node at a time, the node group keeps its capacity during the rollout at the
+	// cost of the slowest rollout.
+	UpdateStrategySurge = "Surge"
+	// UpdateStrategyInPlace replaces a third of the nodes at a time, the rollout is faster but the node group runs
+	// with reduced capacity while the drained nodes are replaced.
+	UpdateStrategyInPlace = "InPlace"
+
+	inPlaceMaxUnavailablePercentage = 33
+)
+
+// GetNodegroupUpdateConfig returns the update config of a node group, either given explicitly or derived from its
+// update strategy. EKS always launches the new nodes before draining the old ones, the update config only limits
+// how many nodes are drained and replaced in parallel. Pods are evicted respecting their PodDisruptionBudgets.
+func GetNodegroupUpdateConfig(ng *eksv1.NodeGroup) *eksv1.NodeGroupUpdateConfig {
+	if ng.UpdateConfig != nil {
+		return ng.UpdateConfig
+	}
+
+	switch aws.StringValue(ng.UpdateStrategy) {
+	case UpdateStrategySurge:
+		return &eksv1.NodeGroupUpdateConfig{MaxUnavailable: aws.Int64(1)}
+	case UpdateStrategyInPlace:
+		return &eksv1.NodeGroupUpdateConfig{MaxUnavailablePercentage: aws.Int64(inPlaceMaxUnavailablePercentage)}
+	}
+
+	return nil
+}
+
+func validateUpdateConfig(ng *eksv1.NodeGroup) error {
+	if strategy := aws.StringValue(ng.UpdateStrategy); strategy != "" {
+		if strategy != UpdateStrategySurge && strategy != UpdateStrategyInPlace {
+			return fmt.Errorf("updateStrategy [%s] is invalid, must be one of %v", strategy, []string{UpdateStrategySurge, UpdateStrategyInPlace})
+		}
+		if ng.UpdateConfig != nil {
+			return fmt.Errorf("updateStrategy and updateConfig cannot both be specified")
+		}
+	}
+
+	updateConfig := ng.UpdateConfig
+	if updateConfig == nil {
+		return nil
+	}
+
+	maxUnavailable, maxUnavailablePercentage := updateConfig.MaxUnavailable, updateConfig.MaxUnavailablePercentage
+	if maxUnavailable != nil && maxUnavailablePercentage != nil {
+		return fmt.Errorf("updateConfig maxUnavailable and maxUnavailablePercentage cannot both be specified")
+	}
+	if maxUnavailable == nil && maxUnavailablePercentage == nil {
+		return fmt.Errorf("updateConfig must specify one of maxUnavailable or maxUnavailablePercentage")
+	}
+	if maxUnavailable != nil && (*maxUnavailable < 1 || *maxUnavailable > maxUnavailableLimit) {
+		return fmt.Errorf("updateConfig maxUnavailable must be between 1 and %d", maxUnavailableLimit)
+	}
+	if maxUnavailablePercentage != nil && (*maxUnavailablePercentage < 1 || *maxUnavailablePercentage > maxUnavailablePercentageLimit) {
+		return fmt.Errorf("updateConfig maxUnavailablePercentage must be between 1 and %d", maxUnavailablePercentageLimit)
+	}
+
+	return nil
+}
+
+func toEKSUpdateConfig(updateConfig *eksv1.NodeGroupUpdateConfig) *eks.NodegroupUpdateConfig {
+	if updateConfig == nil {
+		return nil
+	}
+
+	return &eks.NodegroupUpdateConfig{
+		MaxUnavailable:           updateConfig.MaxUnavailable,
+		MaxUnavailablePercentage: updateConfig.MaxUnavailablePercentage,
+	}
+}
+
+// FromEKSUpdateConfig converts the update config of an upstream node group to a node group update config.
+func FromEKSUpdateConfig(updateConfig *eks.NodegroupUpdateConfig) *eksv1.NodeGroupUpdateConfig {
+	if updateConfig == nil {
+		return nil
+	}
+
+	return &eksv1.NodeGroupUpdateConfig{
+		MaxUnavailable:           updateConfig.MaxUnavailable,
+		MaxUnavailablePercentage: updateConfig.MaxUnavailablePercentage,
+	}
+}
+
+// GetNodegroupUpdateConfigUpdate returns the update config to send to get from the upstream update config to the
+// desired one, or nil if there is nothing to update. An unset update config leaves the upstream one untouched.
+func GetNodegroupUpdateConfigUpdate(updateConfig, upstreamUpdateConfig *eksv1.NodeGroupUpdateConfig) *eks.NodegroupUpdateConfig {
+	if updateConfig == nil {
+		return nil
+	}
+	if upstreamUpdateConfig != nil &&
+		aws.Int64Value(updateConfig.MaxUnavailable) == aws.Int64Value(upstreamUpdateConfig.MaxUnavailable) &&
+		aws.Int64Value(updateConfig.MaxUnavailablePercentage) == aws.Int64Value(upstreamUpdateConfig.MaxUnavailablePercentage) {
+		return nil
+	}
+
+	return toEKSUpdateConfig(updateConfig)
+}
+
+type GetNodegroupUpdateProgressOpts struct {
+	EKSService         services.EKSServiceInterface
+	AutoScalingService services.AutoScalingServiceInterface
+	Config             *eksv1.EKSClusterConfig
+	NodegroupName      string
+}
+
+// NodegroupUpdateProgress is the progress of the update being rolled out to a node group.
+type NodegroupUpdateProgress struct {
+	NodegroupName string
+	UpdateID      string
+	UpdateType    string
+	// Phase is the status of the update, it is empty if there is no update in progress.
+	Phase        string
+	UpdatedNodes int
+	TotalNodes   int
+}
+
+// Message returns a message describing how far the rollout of the update has progressed.
+func (p *NodegroupUpdateProgress) Message() string {
+	if p.UpdateID == "" {
+		return fmt.Sprintf("nodegroup [%s] has no update in progress", p.NodegroupName)
+	}
+
+	return fmt.Sprintf("nodegroup [%s] update [%s] of type %s: %d of %d nodes updated",
+		p.NodegroupName, p.UpdateID, p.UpdateType, p.UpdatedNodes, p.TotalNodes)
+}
+
+// GetNodegroupUpdateProgress finds the update in progress on a node group and counts the nodes of its auto scaling
+// groups that already run the current launch template version of their group. A rollout that does not progress
+// between reconciles is usually blocked by a PodDisruptionBudget preventing the nodes from being drained.
+func GetNodegroupUpdateProgress(ctx context.Context, opts *GetNodegroupUpdateProgressOpts) (*NodegroupUpdateProgress, error) {
+	progress := &NodegroupUpdateProgress{NodegroupName: opts.NodegroupName}
+
+	update, err := getNodegroupUpdateInProgress(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if update == nil {
+		return progress, nil
+	}
+	progress.UpdateID = aws.StringValue(update.Id)
+	progress.UpdateType = aws.StringValue(update.Type)
+	progress.Phase = aws.StringValue(update.Status)
+
+	output, err := opts.EKSService.DescribeNodegroup(ctx, &eks.DescribeNodegroupInput{
+		ClusterName:   aws.String(opts.Config.Spec.DisplayName),
+		NodegroupName: aws.String(opts.NodegroupName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error describing nodegroup [%s] in cluster [%s]: %w", opts.NodegroupName, opts.Config.Name, err)
+	}
+	if output.Nodegroup == nil || output.Nodegroup.Resources == nil || len(output.Nodegroup.Resources.AutoScalingGroups) == 0 {
+		return progress, nil
+	}
+
+	asgNames := make([]*string, 0, len(output.Nodegroup.Resources.AutoScalingGroups))
+	for _, asg := range output.Nodegroup.Resources.AutoScalingGroups {
+		asgNames = append(asgNames, asg.Name)
+	}
+
+	asgs, err := opts.AutoScalingService.DescribeAutoScalingGroups(ctx, &autoscaling.DescribeAutoScalingGroupsInput{
+		AutoScalingGroupNames: asgNames,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error describing auto scaling groups of nodegroup [%s] in cluster [%s]: %w", opts.NodegroupName, opts.Config.Name, err)
+	}
+
+	for _, asg := range asgs.AutoScalingGroups {
+		version := getAutoScalingGroupLaunchTemplateVersion(asg)
+		for _, instance := range asg.Instances {
+			progress.TotalNodes++
+			if version != "" && instance.LaunchTemplate != nil && aws.StringValue(instance.LaunchTemplate.Version) == version {
+				progress.UpdatedNodes++
+			}
+		}
+	}
+
+	return progress, nil
+}
+
+func getNodegroupUpdateInProgress(ctx context.Context, opts *GetNodegroupUpdateProgressOpts) (*eks.Update, error) {
+	input := &eks.ListUpdatesInput{
+		Name:          aws.String(opts.Config.Spec.DisplayName),
+		NodegroupName: aws.String(opts.NodegroupName),
+	}
+	for {
+		updates, err := opts.EKSService.ListUpdates(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("error listing updates of nodegroup [%s] in cluster [%s]: %w", opts.NodegroupName, opts.Config.Name, err)
+		}
+
+		for _, updateID := range updates.UpdateIds {
+			output, err := opts.EKSService.DescribeUpdate(ctx, &eks.DescribeUpdateInput{
+				Name:          input.Name,
+				NodegroupName: input.NodegroupName,
+				UpdateId:      updateID,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("error describing update [%s] of nodegroup [%s] in cluster [%s]: %w",
+					aws.StringValue(updateID), opts.NodegroupName, opts.Config.Name, err)
+			}
+			if output.Update != nil && aws.StringValue(output.Update.Status) == eks.UpdateStatusInProgress {
+				return output.Update, nil
+			}
+		}
+
+		if updates.NextToken == nil {
+			return nil, nil
+		}
+		input.NextToken = updates.NextToken
+	}
+}
+
+func getAutoScalingGroupLaunchTemplateVersion(asg *autoscaling.Group) string {
+	if asg.LaunchTemplate != nil {
+		return aws.StringValue(asg.LaunchTemplate.Version)
+	}
+	if asg.MixedInstancesPolicy != nil && asg.MixedInstancesPolicy.LaunchTemplate != nil &&
+		asg.MixedInstancesPolicy.LaunchTemplate.LaunchTemplateSpecification != nil {
+		return aws.StringValue(asg.MixedInstancesPolicy.LaunchTemplate.LaunchTemplateSpecification.Version)
+	}
+
+	return ""
+}
@@ -0,0 +1,161 @@
+package eks
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/pkg/eks/services/mock_services"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("GetNodegroupUpdateProgress", func() {
+	var (
+		mockController         *gomock.Controller
+		eksServiceMock         *mock_services.MockEKSServiceInterface
+		autoScalingServiceMock *mock_services.MockAutoScalingServiceInterface
+		progressOpts           *GetNodegroupUpdateProgressOpts
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+		autoScalingServiceMock = mock_services.NewMockAutoScalingServiceInterface(mockController)
+		progressOpts = &GetNodegroupUpdateProgressOpts{
+			EKSService:         eksServiceMock,
+			AutoScalingService: autoScalingServiceMock,
+			Config: &eksv1.EKSClusterConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: eksv1.EKSClusterConfigSpec{
+					DisplayName: "test",
+				},
+			},
+			NodegroupName: "ng",
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should report the nodes updated by the update in progress", func() {
+		eksServiceMock.EXPECT().ListUpdates(gomock.Any(), &eks.ListUpdatesInput{
+			Name:          aws.String("test"),
+			NodegroupName: aws.String("ng"),
+		}).Return(&eks.ListUpdatesOutput{
+			UpdateIds: aws.StringSlice([]string{"done", "running"}),
+		}, nil)
+		eksServiceMock.EXPECT().DescribeUpdate(gomock.Any(), &eks.DescribeUpdateInput{
+			Name:          aws.String("test"),
+			NodegroupName: aws.String("ng"),
+			UpdateId:      aws.String("done"),
+		}).Return(&eks.DescribeUpdateOutput{
+			Update: &eks.Update{Id: aws.String("done"), Status: aws.String(eks.UpdateStatusSuccessful)},
+		}, nil)
+		eksServiceMock.EXPECT().DescribeUpdate(gomock.Any(), &eks.DescribeUpdateInput{
+			Name:          aws.String("test"),
+			NodegroupName: aws.String("ng"),
+			UpdateId:      aws.String("running"),
+		}).Return(&eks.DescribeUpdateOutput{
+			Update: &eks.Update{
+				Id:     aws.String("running"),
+				Status: aws.String(eks.UpdateStatusInProgress),
+				Type:   aws.String(eks.UpdateTypeVersionUpdate),
+			},
+		}, nil)
+		eksServiceMock.EXPECT().DescribeNodegroup(gomock.Any(), gomock.Any()).Return(&eks.DescribeNodegroupOutput{
+			Nodegroup: &eks.Nodegroup{
+				Resources: &eks.NodegroupResources{
+					AutoScalingGroups: []*eks.AutoScalingGroup{{Name: aws.String("asg")}},
+				},
+			},
+		}, nil)
+		autoScalingServiceMock.EXPECT().DescribeAutoScalingGroups(gomock.Any(), &autoscaling.DescribeAutoScalingGroupsInput{
+			AutoScalingGroupNames: aws.StringSlice([]string{"asg"}),
+		}).Return(&autoscaling.DescribeAutoScalingGroupsOutput{
+			AutoScalingGroups: []*autoscaling.Group{
+				{
+					LaunchTemplate: &autoscaling.LaunchTemplateSpecification{Version: aws.String("3")},
+					Instances: []*autoscaling.Instance{
+						{LaunchTemplate: &autoscaling.LaunchTemplateSpecification{Version: aws.String("3")}},
+						{LaunchTemplate: &autoscaling.LaunchTemplateSpecification{Version: aws.String("2")}},
+						{LaunchTemplate: &autoscaling.LaunchTemplateSpecification{Version: aws.String("2")}},
+					},
+				},
+			},
+		}, nil)
+
+		progress, err := GetNodegroupUpdateProgress(context.Background(), progressOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(progress.UpdateID).To(Equal("running"))
+		Expect(progress.UpdatedNodes).To(Equal(1))
+		Expect(progress.TotalNodes).To(Equal(3))
+		Expect(progress.Message()).To(Equal("nodegroup [ng] update [running] of type VersionUpdate: 1 of 3 nodes updated"))
+	})
+
+	It("should report no progress if there is no update in progress", func() {
+		eksServiceMock.EXPECT().ListUpdates(gomock.Any(), gomock.Any()).Return(&eks.ListUpdatesOutput{}, nil)
+
+		progress, err := GetNodegroupUpdateProgress(context.Background(), progressOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(progress.UpdateID).To(BeEmpty())
+		Expect(progress.Message()).To(Equal("nodegroup [ng] has no update in progress"))
+	})
+
+	It("should fail if listing the updates fails", func() {
+		eksServiceMock.EXPECT().ListUpdates(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+		_, err := GetNodegroupUpdateProgress(context.Background(), progressOpts)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("validateUpdateConfig", func() {
+	DescribeTable("should validate the update config",
+		func(ng *eksv1.NodeGroup, valid bool) {
+			err := validateUpdateConfig(ng)
+			if valid {
+				Expect(err).ToNot(HaveOccurred())
+			} else {
+				Expect(err).To(HaveOccurred())
+			}
+		},
+		Entry("unset", &eksv1.NodeGroup{}, true),
+		Entry("max unavailable", &eksv1.NodeGroup{UpdateConfig: &eksv1.NodeGroupUpdateConfig{MaxUnavailable: aws.Int64(2)}}, true),
+		Entry("max unavailable percentage", &eksv1.NodeGroup{UpdateConfig: &eksv1.NodeGroupUpdateConfig{MaxUnavailablePercentage: aws.Int64(50)}}, true),
+		Entry("both", &eksv1.NodeGroup{UpdateConfig: &eksv1.NodeGroupUpdateConfig{MaxUnavailable: aws.Int64(2), MaxUnavailablePercentage: aws.Int64(50)}}, false),
+		Entry("neither", &eksv1.NodeGroup{UpdateConfig: &eksv1.NodeGroupUpdateConfig{}}, false),
+		Entry("zero max unavailable", &eksv1.NodeGroup{UpdateConfig: &eksv1.NodeGroupUpdateConfig{MaxUnavailable: aws.Int64(0)}}, false),
+		Entry("percentage over 100", &eksv1.NodeGroup{UpdateConfig: &eksv1.NodeGroupUpdateConfig{MaxUnavailablePercentage: aws.Int64(101)}}, false),
+		Entry("surge strategy", &eksv1.NodeGroup{UpdateStrategy: aws.String(UpdateStrategySurge)}, true),
+		Entry("in place strategy", &eksv1.NodeGroup{UpdateStrategy: aws.String(UpdateStrategyInPlace)}, true),
+		Entry("unknown strategy", &eksv1.NodeGroup{UpdateStrategy: aws.String("BlueGreen")}, false),
+		Entry("strategy and update config", &eksv1.NodeGroup{
+			UpdateStrategy: aws.String(UpdateStrategySurge),
+			UpdateConfig:   &eksv1.NodeGroupUpdateConfig{MaxUnavailable: aws.Int64(2)},
+		}, false),
+	)
+})
+
+var _ = Describe("GetNodegroupUpdateConfig", func() {
+	DescribeTable("should map the update strategy to an update config",
+		func(ng *eksv1.NodeGroup, expected *eksv1.NodeGroupUpdateConfig) {
+			Expect(GetNodegroupUpdateConfig(ng)).To(Equal(expected))
+		},
+		Entry("unset", &eksv1.NodeGroup{}, nil),
+		Entry("surge", &eksv1.NodeGroup{UpdateStrategy: aws.String(UpdateStrategySurge)},
+			&eksv1.NodeGroupUpdateConfig{MaxUnavailable: aws.Int64(1)}),
+		Entry("in place", &eksv1.NodeGroup{UpdateStrategy: aws.String(UpdateStrategyInPlace)},
+			&eksv1.NodeGroupUpdateConfig{MaxUnavailablePercentage: aws.Int64(33)}),
+		Entry("explicit update config", &eksv1.NodeGroup{UpdateConfig: &eksv1.NodeGroupUpdateConfig{MaxUnavailable: aws.Int64(3)}},
+			&eksv1.NodeGroupUpdateConfig{MaxUnavailable: aws.Int64(3)}),
+	)
+})
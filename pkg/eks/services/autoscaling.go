@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+)
+
+type AutoScalingServiceInterface interface {
+	AttachLoadBalancerTargetGroups(ctx context.Context, input *autoscaling.AttachLoadBalancerTargetGroupsInput) (*autoscaling.AttachLoadBalancerTargetGroupsOutput, error)
+	DetachLoadBalancerTargetGroups(ctx context.Context, input *autoscaling.DetachLoadBalancerTargetGroupsInput) (*autoscaling.DetachLoadBalancerTargetGroupsOutput, error)
+	DescribeLoadBalancerTargetGroups(ctx context.Context, input *autoscaling.DescribeLoadBalancerTargetGroupsInput) (*autoscaling.DescribeLoadBalancerTargetGroupsOutput, error)
+	DescribeScalingActivities(ctx context.Context, input *autoscaling.DescribeScalingActivitiesInput) (*autoscaling.DescribeScalingActivitiesOutput, error)
+	DescribeAutoScalingGroups(ctx context.Context, input *autoscaling.DescribeAutoScalingGroupsInput) (*autoscaling.DescribeAutoScalingGroupsOutput, error)
+}
+
+type autoScalingService struct {
+	svc *autoscaling.AutoScaling
+}
+
+func NewAutoScalingService(sess *session.Session) AutoScalingServiceInterface {
+	return &autoScalingService{
+		svc: autoscaling.New(sess),
+	}
+}
+
+func (c *autoScalingService) AttachLoadBalancerTargetGroups(ctx context.Context, input *autoscaling.AttachLoadBalancerTargetGroupsInput) (*autoscaling.AttachLoadBalancerTargetGroupsOutput, error) {
+	return c.svc.AttachLoadBalancerTargetGroupsWithContext(ctx, input)
+}
+
+func (c *autoScalingService) DetachLoadBalancerTargetGroups(ctx context.Context, input *autoscaling.DetachLoadBalancerTargetGroupsInput) (*autoscaling.DetachLoadBalancerTargetGroupsOutput, error) {
+	return c.svc.DetachLoadBalancerTargetGroupsWithContext(ctx, input)
+}
+
+func (c *autoScalingService) DescribeLoadBalancerTargetGroups(ctx context.Context, input *autoscaling.DescribeLoadBalancerTargetGroupsInput) (*autoscaling.DescribeLoadBalancerTargetGroupsOutput, error) {
+	return c.svc.DescribeLoadBalancerTargetGroupsWithContext(ctx, input)
+}
+
+func (c *autoScalingService) DescribeScalingActivities(ctx context.Context, input *autoscaling.DescribeScalingActivitiesInput) (*autoscaling.DescribeScalingActivitiesOutput, error) {
+	return c.svc.DescribeScalingActivitiesWithContext(ctx, input)
+}
+
+func (c *autoScalingService) DescribeAutoScalingGroups(ctx context.Context, input *autoscaling.DescribeAutoScalingGroupsInput) (*autoscaling.DescribeAutoScalingGroupsOutput, error) {
+	return c.svc.DescribeAutoScalingGroupsWithContext(ctx, input)
+}
@@ -1,15 +1,17 @@
 package services
 
 import (
+	"context"
+
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/cloudformation"
 )
 
 type CloudFormationServiceInterface interface {
-	DescribeStacks(input *cloudformation.DescribeStacksInput) (*cloudformation.DescribeStacksOutput, error)
-	DeleteStack(input *cloudformation.DeleteStackInput) (*cloudformation.DeleteStackOutput, error)
-	CreateStack(input *cloudformation.CreateStackInput) (*cloudformation.CreateStackOutput, error)
-	DescribeStackEvents(input *cloudformation.DescribeStackEventsInput) (*cloudformation.DescribeStackEventsOutput, error)
+	DescribeStacks(ctx context.Context, input *cloudformation.DescribeStacksInput) (*cloudformation.DescribeStacksOutput, error)
+	DeleteStack(ctx context.Context, input *cloudformation.DeleteStackInput) (*cloudformation.DeleteStackOutput, error)
+	CreateStack(ctx context.Context, input *cloudformation.CreateStackInput) (*cloudformation.CreateStackOutput, error)
+	DescribeStackEvents(ctx context.Context, input *cloudformation.DescribeStackEventsInput) (*cloudformation.DescribeStackEventsOutput, error)
 }
 
 type cloudFormationService struct {
@@ -22,18 +24,18 @@ func NewCloudFormationService(sess *session.Session) CloudFormationServiceInterf
 	}
 }
 
-func (c *cloudFormationService) DescribeStacks(input *cloudformation.DescribeStacksInput) (*cloudformation.DescribeStacksOutput, error) {
-	return c.svc.DescribeStacks(input)
+func (c *cloudFormationService) DescribeStacks(ctx context.Context, input *cloudformation.DescribeStacksInput) (*cloudformation.DescribeStacksOutput, error) {
+	return c.svc.DescribeStacksWithContext(ctx, input)
 }
 
-func (c *cloudFormationService) DeleteStack(input *cloudformation.DeleteStackInput) (*cloudformation.DeleteStackOutput, error) {
-	return c.svc.DeleteStack(input)
+func (c *cloudFormationService) DeleteStack(ctx context.Context, input *cloudformation.DeleteStackInput) (*cloudformation.DeleteStackOutput, error) {
+	return c.svc.DeleteStackWithContext(ctx, input)
 }
 
-func (c *cloudFormationService) CreateStack(input *cloudformation.CreateStackInput) (*cloudformation.CreateStackOutput, error) {
-	return c.svc.CreateStack(input)
+func (c *cloudFormationService) CreateStack(ctx context.Context, input *cloudformation.CreateStackInput) (*cloudformation.CreateStackOutput, error) {
+	return c.svc.CreateStackWithContext(ctx, input)
 }
 
-func (c *cloudFormationService) DescribeStackEvents(input *cloudformation.DescribeStackEventsInput) (*cloudformation.DescribeStackEventsOutput, error) {
-	return c.svc.DescribeStackEvents(input)
+func (c *cloudFormationService) DescribeStackEvents(ctx context.Context, input *cloudformation.DescribeStackEventsInput) (*cloudformation.DescribeStackEventsOutput, error) {
+	return c.svc.DescribeStackEventsWithContext(ctx, input)
 }
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+)
+
+type CloudWatchLogsServiceInterface interface {
+	DescribeLogGroups(ctx context.Context, input *cloudwatchlogs.DescribeLogGroupsInput) (*cloudwatchlogs.DescribeLogGroupsOutput, error)
+	PutRetentionPolicy(ctx context.Context, input *cloudwatchlogs.PutRetentionPolicyInput) (*cloudwatchlogs.PutRetentionPolicyOutput, error)
+}
+
+type cloudWatchLogsService struct {
+	svc *cloudwatchlogs.CloudWatchLogs
+}
+
+func NewCloudWatchLogsService(sess *session.Session) CloudWatchLogsServiceInterface {
+	return &cloudWatchLogsService{
+		svc: cloudwatchlogs.New(sess),
+	}
+}
+
+func (c *cloudWatchLogsService) DescribeLogGroups(ctx context.Context, input *cloudwatchlogs.DescribeLogGroupsInput) (*cloudwatchlogs.DescribeLogGroupsOutput, error) {
+	return c.svc.DescribeLogGroupsWithContext(ctx, input)
+}
+
+func (c *cloudWatchLogsService) PutRetentionPolicy(ctx context.Context, input *cloudwatchlogs.PutRetentionPolicyInput) (*cloudwatchlogs.PutRetentionPolicyOutput, error) {
+	return c.svc.PutRetentionPolicyWithContext(ctx, input)
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+// assumeRoleExpiryWindow makes cached assumed role credentials refresh shortly before they expire, so that
+// requests in flight are not signed with credentials that expire mid-request.
+const assumeRoleExpiryWindow = time.Minute
+
+// CredentialsCache shares assumed role credentials between the sessions of all clusters assuming the same role with
+// the same source credentials and external ID. Cached credentials refresh themselves once they are about to expire, so
+// STS is only called once per role and credentials lifetime instead of once per cluster reconcile. It is safe for
+// concurrent use.
+type CredentialsCache struct {
+	mu             sync.Mutex
+	credentials    map[credentialsKey]*credentials.Credentials
+	newCredentials func(sess *session.Session, roleARN, externalID string) *credentials.Credentials
+}
+
+// credentialsKey identifies assumed role credentials. The source is a hash of the credentials the role is assumed
+// with, so that clusters of different credential owners never share the credentials one of them assumed.
+type credentialsKey struct {
+	source     string
+	roleARN    string
+	externalID string
+}
+
+func NewCredentialsCache() *CredentialsCache {
+	return &CredentialsCache{
+		credentials:    make(map[credentialsKey]*credentials.Credentials),
+		newCredentials: newAssumeRoleCredentials,
+	}
+}
+
+// Credentials returns the cached credentials for the role, assuming it with the given session on first use. The
+// external ID is passed when assuming the role if it is set.
+func (c *CredentialsCache) Credentials(sess *session.Session, roleARN, externalID string) (*credentials.Credentials, error) {
+	source, err := sourceCredentialsIdentity(sess)
+	if err != nil {
+		return nil, err
+	}
+	key := credentialsKey{source: source, roleARN: roleARN, externalID: externalID}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if creds, ok := c.credentials[key]; ok {
+		return creds, nil
+	}
+
+	creds := c.newCredentials(sess, roleARN, externalID)
+	c.credentials[key] = creds
+	return creds, nil
+}
+
+// Forget evicts the credentials of the role assumed with the given session, once the cluster using them is removed.
+// Other clusters sharing them assume the role again on their next use.
+func (c *CredentialsCache) Forget(sess *session.Session, roleARN, externalID string) error {
+	source, err := sourceCredentialsIdentity(sess)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.credentials, credentialsKey{source: source, roleARN: roleARN, externalID: externalID})
+	return nil
+}
+
+// SessionForRole returns a copy of the session that uses the cached credentials of the role, for example to provision
+// into the account of a customer.
+func (c *CredentialsCache) SessionForRole(sess *session.Session, roleARN, externalID string) (*session.Session, error) {
+	creds, err := c.Credentials(sess, roleARN, externalID)
+	if err != nil {
+		return nil, err
+	}
+	return sess.Copy(&aws.Config{Credentials: creds}), nil
+}
+
+func sourceCredentialsIdentity(sess *session.Session) (string, error) {
+	if sess.Config.Credentials == nil {
+		return "", fmt.Errorf("error getting source credentials to assume role: no credentials configured")
+	}
+	value, err := sess.Config.Credentials.Get()
+	if err != nil {
+		return "", fmt.Errorf("error getting source credentials to assume role: %w", err)
+	}
+
+	hash := sha256.New()
+	for _, part := range []string{value.AccessKeyID, value.SecretAccessKey, value.SessionToken} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func newAssumeRoleCredentials(sess *session.Session, roleARN, externalID string) *credentials.Credentials {
+	return stscreds.NewCredentials(sess, roleARN, func(p *stscreds.AssumeRoleProvider) {
+		p.ExpiryWindow = assumeRoleExpiryWindow
+		if externalID != "" {
+			p.ExternalID = aws.String(externalID)
+		}
+	})
+}
+
+// NewServicesForRole returns the EKS, EC2 and CloudFormation services using the cached credentials of the role assumed
+// with the given session.
+func (c *CredentialsCache) NewServicesForRole(sess *session.Session, roleARN, externalID string) (EKSServiceInterface, EC2ServiceInterface, CloudFormationServiceInterface, error) {
+	roleSess, err := c.SessionForRole(sess, roleARN, externalID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return NewEKSService(roleSess), NewEC2Service(roleSess), NewCloudFormationService(roleSess), nil
+}
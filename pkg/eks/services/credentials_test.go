@@ -0,0 +1,218 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/stretchr/testify/assert"
+)
+
+type countingProvider struct {
+	retrieved int
+}
+
+func (p *countingProvider) Retrieve() (credentials.Value, error) {
+	p.retrieved++
+	return credentials.Value{AccessKeyID: "access", SecretAccessKey: "secret"}, nil
+}
+
+func (p *countingProvider) IsExpired() bool {
+	return p.retrieved == 0
+}
+
+func TestCredentialsCacheSharesCredentialsPerRole(t *testing.T) {
+	asserts := assert.New(t)
+
+	var providers []*countingProvider
+	cache := NewCredentialsCache()
+	cache.newCredentials = func(_ *session.Session, _, _ string) *credentials.Credentials {
+		provider := &countingProvider{}
+		providers = append(providers, provider)
+		return credentials.NewCredentials(provider)
+	}
+
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String("us-east-1"),
+		Credentials: credentials.NewStaticCredentials("access", "secret", ""),
+	})
+	asserts.NoError(err)
+
+	roleARN := "arn:aws:iam::123456789012:role/test"
+	sess1, err := cache.SessionForRole(sess, roleARN, "external-id")
+	asserts.NoError(err)
+	sess2, err := cache.SessionForRole(sess, roleARN, "external-id")
+	asserts.NoError(err)
+	asserts.Same(sess1.Config.Credentials, sess2.Config.Credentials)
+
+	_, err = sess1.Config.Credentials.Get()
+	asserts.NoError(err)
+	_, err = sess2.Config.Credentials.Get()
+	asserts.NoError(err)
+	if asserts.Len(providers, 1) {
+		asserts.Equal(1, providers[0].retrieved)
+	}
+
+	otherRole, err := cache.SessionForRole(sess, "arn:aws:iam::123456789012:role/other", "external-id")
+	asserts.NoError(err)
+	asserts.NotSame(sess1.Config.Credentials, otherRole.Config.Credentials)
+
+	otherExternalID, err := cache.SessionForRole(sess, roleARN, "other-external-id")
+	asserts.NoError(err)
+	asserts.NotSame(sess1.Config.Credentials, otherExternalID.Config.Credentials)
+
+	otherSourceSess, err := session.NewSession(&aws.Config{
+		Region:      aws.String("us-east-1"),
+		Credentials: credentials.NewStaticCredentials("access", "other-secret", ""),
+	})
+	asserts.NoError(err)
+	otherSource, err := cache.SessionForRole(otherSourceSess, roleARN, "external-id")
+	asserts.NoError(err)
+	asserts.NotSame(sess1.Config.Credentials, otherSource.Config.Credentials)
+	asserts.Len(providers, 4)
+}
+
+func TestCredentialsCacheRequiresSourceCredentials(t *testing.T) {
+	asserts := assert.New(t)
+
+	cache := NewCredentialsCache()
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String("us-east-1"),
+		Credentials: credentials.NewStaticCredentials("", "", ""),
+	})
+	asserts.NoError(err)
+
+	_, err = cache.SessionForRole(sess, "arn:aws:iam::123456789012:role/test", "")
+	asserts.Error(err)
+	asserts.Empty(cache.credentials)
+}
+
+const assumeRoleResponse = `<AssumeRoleResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleResult>
+    <Credentials>
+      <AccessKeyId>assumed-access</AccessKeyId>
+      <SecretAccessKey>assumed-secret</SecretAccessKey>
+      <SessionToken>assumed-token</SessionToken>
+      <Expiration>%s</Expiration>
+    </Credentials>
+  </AssumeRoleResult>
+</AssumeRoleResponse>`
+
+func newAssumeRoleSession(t *testing.T, requests *[]url.Values) *session.Session {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		*requests = append(*requests, r.PostForm)
+		fmt.Fprintf(w, assumeRoleResponse, time.Now().Add(time.Hour).UTC().Format(time.RFC3339))
+	}))
+	t.Cleanup(server.Close)
+
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String("us-east-1"),
+		Endpoint:    aws.String(server.URL),
+		Credentials: credentials.NewStaticCredentials("access", "secret", ""),
+	})
+	assert.NoError(t, err)
+	return sess
+}
+
+func TestCredentialsCacheAssumesRoleOnce(t *testing.T) {
+	asserts := assert.New(t)
+
+	var assumeRoleRequests []url.Values
+	sess := newAssumeRoleSession(t, &assumeRoleRequests)
+
+	cache := NewCredentialsCache()
+	roleARN := "arn:aws:iam::123456789012:role/customer"
+	for i := 0; i < 3; i++ {
+		roleSess, err := cache.SessionForRole(sess, roleARN, "external-id")
+		asserts.NoError(err)
+		asserts.NotSame(sess.Config.Credentials, roleSess.Config.Credentials)
+
+		value, err := NewEKSService(roleSess).(*eksService).svc.Config.Credentials.Get()
+		asserts.NoError(err)
+		asserts.Equal("assumed-access", value.AccessKeyID)
+		asserts.Equal("assumed-secret", value.SecretAccessKey)
+		asserts.Equal("assumed-token", value.SessionToken)
+	}
+
+	if asserts.Len(assumeRoleRequests, 1) {
+		asserts.Equal("AssumeRole", assumeRoleRequests[0].Get("Action"))
+		asserts.Equal(roleARN, assumeRoleRequests[0].Get("RoleArn"))
+		asserts.Equal("external-id", assumeRoleRequests[0].Get("ExternalId"))
+	}
+}
+
+func TestCredentialsCacheWithoutExternalID(t *testing.T) {
+	asserts := assert.New(t)
+
+	var assumeRoleRequests []url.Values
+	sess := newAssumeRoleSession(t, &assumeRoleRequests)
+
+	roleSess, err := NewCredentialsCache().SessionForRole(sess, "arn:aws:iam::123456789012:role/customer", "")
+	asserts.NoError(err)
+	_, err = roleSess.Config.Credentials.Get()
+	asserts.NoError(err)
+	if asserts.Len(assumeRoleRequests, 1) {
+		_, ok := assumeRoleRequests[0]["ExternalId"]
+		asserts.False(ok)
+	}
+}
+
+func TestNewServicesForRoleAssumesRole(t *testing.T) {
+	asserts := assert.New(t)
+
+	var assumeRoleRequests []url.Values
+	sess := newAssumeRoleSession(t, &assumeRoleRequests)
+
+	roleARN := "arn:aws:iam::123456789012:role/customer"
+	eksSvc, ec2Svc, cloudFormationSvc, err := NewCredentialsCache().NewServicesForRole(sess, roleARN, "external-id")
+	asserts.NoError(err)
+
+	eksCreds := eksSvc.(*eksService).svc.Config.Credentials
+	asserts.Same(eksCreds, ec2Svc.(*ec2Service).svc.Config.Credentials)
+	asserts.Same(eksCreds, cloudFormationSvc.(*cloudFormationService).svc.Config.Credentials)
+	asserts.NotSame(sess.Config.Credentials, eksCreds)
+
+	value, err := eksCreds.Get()
+	asserts.NoError(err)
+	asserts.Equal("assumed-access", value.AccessKeyID)
+	asserts.Equal("assumed-secret", value.SecretAccessKey)
+	asserts.Equal("assumed-token", value.SessionToken)
+
+	if asserts.Len(assumeRoleRequests, 1) {
+		asserts.Equal("AssumeRole", assumeRoleRequests[0].Get("Action"))
+		asserts.Equal(roleARN, assumeRoleRequests[0].Get("RoleArn"))
+		asserts.Equal("external-id", assumeRoleRequests[0].Get("ExternalId"))
+	}
+}
+
+func TestCredentialsCacheForget(t *testing.T) {
+	asserts := assert.New(t)
+
+	var assumeRoleRequests []url.Values
+	sess := newAssumeRoleSession(t, &assumeRoleRequests)
+
+	cache := NewCredentialsCache()
+	roleARN := "arn:aws:iam::123456789012:role/customer"
+	roleSess, err := cache.SessionForRole(sess, roleARN, "external-id")
+	asserts.NoError(err)
+	_, err = cache.SessionForRole(sess, "arn:aws:iam::123456789012:role/other", "external-id")
+	asserts.NoError(err)
+
+	asserts.NoError(cache.Forget(sess, roleARN, "external-id"))
+	asserts.Len(cache.credentials, 1)
+
+	// sessions created before keep using the evicted credentials
+	_, err = roleSess.Config.Credentials.Get()
+	asserts.NoError(err)
+
+	newRoleSess, err := cache.SessionForRole(sess, roleARN, "external-id")
+	asserts.NoError(err)
+	asserts.NotSame(roleSess.Config.Credentials, newRoleSess.Config.Credentials)
+}
@@ -1,25 +1,41 @@
 package services
 
 import (
+	"context"
+
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/eks"
 )
 
 type EKSServiceInterface interface {
-	CreateCluster(input *eks.CreateClusterInput) (*eks.CreateClusterOutput, error)
-	DeleteCluster(input *eks.DeleteClusterInput) (*eks.DeleteClusterOutput, error)
-	ListClusters(input *eks.ListClustersInput) (*eks.ListClustersOutput, error)
-	DescribeCluster(input *eks.DescribeClusterInput) (*eks.DescribeClusterOutput, error)
-	UpdateClusterConfig(input *eks.UpdateClusterConfigInput) (*eks.UpdateClusterConfigOutput, error)
-	UpdateClusterVersion(input *eks.UpdateClusterVersionInput) (*eks.UpdateClusterVersionOutput, error)
-	CreateNodegroup(input *eks.CreateNodegroupInput) (*eks.CreateNodegroupOutput, error)
-	UpdateNodegroupConfig(input *eks.UpdateNodegroupConfigInput) (*eks.UpdateNodegroupConfigOutput, error)
-	ListNodegroups(input *eks.ListNodegroupsInput) (*eks.ListNodegroupsOutput, error)
-	DeleteNodegroup(input *eks.DeleteNodegroupInput) (*eks.DeleteNodegroupOutput, error)
-	DescribeNodegroup(input *eks.DescribeNodegroupInput) (*eks.DescribeNodegroupOutput, error)
-	UpdateNodegroupVersion(input *eks.UpdateNodegroupVersionInput) (*eks.UpdateNodegroupVersionOutput, error)
-	TagResource(input *eks.TagResourceInput) (*eks.TagResourceOutput, error)
-	UntagResource(input *eks.UntagResourceInput) (*eks.UntagResourceOutput, error)
+	CreateCluster(ctx context.Context, input *eks.CreateClusterInput) (*eks.CreateClusterOutput, error)
+	DeleteCluster(ctx context.Context, input *eks.DeleteClusterInput) (*eks.DeleteClusterOutput, error)
+	ListClusters(ctx context.Context, input *eks.ListClustersInput) (*eks.ListClustersOutput, error)
+	ListClustersPages(ctx context.Context, input *eks.ListClustersInput, fn func(*eks.ListClustersOutput, bool) bool) error
+	DescribeCluster(ctx context.Context, input *eks.DescribeClusterInput) (*eks.DescribeClusterOutput, error)
+	UpdateClusterConfig(ctx context.Context, input *eks.UpdateClusterConfigInput) (*eks.UpdateClusterConfigOutput, error)
+	UpdateClusterVersion(ctx context.Context, input *eks.UpdateClusterVersionInput) (*eks.UpdateClusterVersionOutput, error)
+	CreateNodegroup(ctx context.Context, input *eks.CreateNodegroupInput) (*eks.CreateNodegroupOutput, error)
+	UpdateNodegroupConfig(ctx context.Context, input *eks.UpdateNodegroupConfigInput) (*eks.UpdateNodegroupConfigOutput, error)
+	ListNodegroups(ctx context.Context, input *eks.ListNodegroupsInput) (*eks.ListNodegroupsOutput, error)
+	DeleteNodegroup(ctx context.Context, input *eks.DeleteNodegroupInput) (*eks.DeleteNodegroupOutput, error)
+	DescribeNodegroup(ctx context.Context, input *eks.DescribeNodegroupInput) (*eks.DescribeNodegroupOutput, error)
+	UpdateNodegroupVersion(ctx context.Context, input *eks.UpdateNodegroupVersionInput) (*eks.UpdateNodegroupVersionOutput, error)
+	TagResource(ctx context.Context, input *eks.TagResourceInput) (*eks.TagResourceOutput, error)
+	UntagResource(ctx context.Context, input *eks.UntagResourceInput) (*eks.UntagResourceOutput, error)
+	ListTagsForResource(ctx context.Context, input *eks.ListTagsForResourceInput) (*eks.ListTagsForResourceOutput, error)
+	CreateFargateProfile(ctx context.Context, input *eks.CreateFargateProfileInput) (*eks.CreateFargateProfileOutput, error)
+	DescribeFargateProfile(ctx context.Context, input *eks.DescribeFargateProfileInput) (*eks.DescribeFargateProfileOutput, error)
+	CreateAddon(ctx context.Context, input *eks.CreateAddonInput) (*eks.CreateAddonOutput, error)
+	DescribeAddon(ctx context.Context, input *eks.DescribeAddonInput) (*eks.DescribeAddonOutput, error)
+	UpdateAddon(ctx context.Context, input *eks.UpdateAddonInput) (*eks.UpdateAddonOutput, error)
+	DescribeAddonVersions(ctx context.Context, input *eks.DescribeAddonVersionsInput) (*eks.DescribeAddonVersionsOutput, error)
+	ListUpdates(ctx context.Context, input *eks.ListUpdatesInput) (*eks.ListUpdatesOutput, error)
+	DescribeUpdate(ctx context.Context, input *eks.DescribeUpdateInput) (*eks.DescribeUpdateOutput, error)
+	CreateAccessEntry(ctx context.Context, input *eks.CreateAccessEntryInput) (*eks.CreateAccessEntryOutput, error)
+	ListAccessEntries(ctx context.Context, input *eks.ListAccessEntriesInput) (*eks.ListAccessEntriesOutput, error)
+	AssociateAccessPolicy(ctx context.Context, input *eks.AssociateAccessPolicyInput) (*eks.AssociateAccessPolicyOutput, error)
+	DeleteAccessEntry(ctx context.Context, input *eks.DeleteAccessEntryInput) (*eks.DeleteAccessEntryOutput, error)
 }
 
 type eksService struct {
@@ -32,58 +48,114 @@ func NewEKSService(sess *session.Session) EKSServiceInterface {
 	}
 }
 
-func (c *eksService) CreateCluster(input *eks.CreateClusterInput) (*eks.CreateClusterOutput, error) {
-	return c.svc.CreateCluster(input)
+func (c *eksService) CreateCluster(ctx context.Context, input *eks.CreateClusterInput) (*eks.CreateClusterOutput, error) {
+	return c.svc.CreateClusterWithContext(ctx, input)
+}
+
+func (c *eksService) DeleteCluster(ctx context.Context, input *eks.DeleteClusterInput) (*eks.DeleteClusterOutput, error) {
+	return c.svc.DeleteClusterWithContext(ctx, input)
+}
+
+func (c *eksService) ListClusters(ctx context.Context, input *eks.ListClustersInput) (*eks.ListClustersOutput, error) {
+	return c.svc.ListClustersWithContext(ctx, input)
+}
+
+func (c *eksService) ListClustersPages(ctx context.Context, input *eks.ListClustersInput, fn func(*eks.ListClustersOutput, bool) bool) error {
+	return c.svc.ListClustersPagesWithContext(ctx, input, fn)
+}
+
+func (c *eksService) DescribeCluster(ctx context.Context, input *eks.DescribeClusterInput) (*eks.DescribeClusterOutput, error) {
+	return c.svc.DescribeClusterWithContext(ctx, input)
+}
+
+func (c *eksService) UpdateClusterConfig(ctx context.Context, input *eks.UpdateClusterConfigInput) (*eks.UpdateClusterConfigOutput, error) {
+	return c.svc.UpdateClusterConfigWithContext(ctx, input)
+}
+
+func (c *eksService) CreateNodegroup(ctx context.Context, input *eks.CreateNodegroupInput) (*eks.CreateNodegroupOutput, error) {
+	return c.svc.CreateNodegroupWithContext(ctx, input)
+}
+
+func (c *eksService) UpdateNodegroupConfig(ctx context.Context, input *eks.UpdateNodegroupConfigInput) (*eks.UpdateNodegroupConfigOutput, error) {
+	return c.svc.UpdateNodegroupConfigWithContext(ctx, input)
+}
+
+func (c *eksService) DeleteNodegroup(ctx context.Context, input *eks.DeleteNodegroupInput) (*eks.DeleteNodegroupOutput, error) {
+	return c.svc.DeleteNodegroupWithContext(ctx, input)
+}
+
+func (c *eksService) ListNodegroups(ctx context.Context, input *eks.ListNodegroupsInput) (*eks.ListNodegroupsOutput, error) {
+	return c.svc.ListNodegroupsWithContext(ctx, input)
+}
+
+func (c *eksService) DescribeNodegroup(ctx context.Context, input *eks.DescribeNodegroupInput) (*eks.DescribeNodegroupOutput, error) {
+	return c.svc.DescribeNodegroupWithContext(ctx, input)
+}
+
+func (c *eksService) UpdateClusterVersion(ctx context.Context, input *eks.UpdateClusterVersionInput) (*eks.UpdateClusterVersionOutput, error) {
+	return c.svc.UpdateClusterVersionWithContext(ctx, input)
+}
+
+func (c *eksService) TagResource(ctx context.Context, input *eks.TagResourceInput) (*eks.TagResourceOutput, error) {
+	return c.svc.TagResourceWithContext(ctx, input)
+}
+
+func (c *eksService) UntagResource(ctx context.Context, input *eks.UntagResourceInput) (*eks.UntagResourceOutput, error) {
+	return c.svc.UntagResourceWithContext(ctx, input)
+}
+
+func (c *eksService) ListTagsForResource(ctx context.Context, input *eks.ListTagsForResourceInput) (*eks.ListTagsForResourceOutput, error) {
+	return c.svc.ListTagsForResourceWithContext(ctx, input)
 }
 
-func (c *eksService) DeleteCluster(input *eks.DeleteClusterInput) (*eks.DeleteClusterOutput, error) {
-	return c.svc.DeleteCluster(input)
+func (c *eksService) UpdateNodegroupVersion(ctx context.Context, input *eks.UpdateNodegroupVersionInput) (*eks.UpdateNodegroupVersionOutput, error) {
+	return c.svc.UpdateNodegroupVersionWithContext(ctx, input)
 }
 
-func (c *eksService) ListClusters(input *eks.ListClustersInput) (*eks.ListClustersOutput, error) {
-	return c.svc.ListClusters(input)
+func (c *eksService) CreateFargateProfile(ctx context.Context, input *eks.CreateFargateProfileInput) (*eks.CreateFargateProfileOutput, error) {
+	return c.svc.CreateFargateProfileWithContext(ctx, input)
 }
 
-func (c *eksService) DescribeCluster(input *eks.DescribeClusterInput) (*eks.DescribeClusterOutput, error) {
-	return c.svc.DescribeCluster(input)
+func (c *eksService) DescribeFargateProfile(ctx context.Context, input *eks.DescribeFargateProfileInput) (*eks.DescribeFargateProfileOutput, error) {
+	return c.svc.DescribeFargateProfileWithContext(ctx, input)
 }
 
-func (c *eksService) UpdateClusterConfig(input *eks.UpdateClusterConfigInput) (*eks.UpdateClusterConfigOutput, error) {
-	return c.svc.UpdateClusterConfig(input)
+func (c *eksService) CreateAddon(ctx context.Context, input *eks.CreateAddonInput) (*eks.CreateAddonOutput, error) {
+	return c.svc.CreateAddonWithContext(ctx, input)
 }
 
-func (c *eksService) CreateNodegroup(input *eks.CreateNodegroupInput) (*eks.CreateNodegroupOutput, error) {
-	return c.svc.CreateNodegroup(input)
+func (c *eksService) DescribeAddon(ctx context.Context, input *eks.DescribeAddonInput) (*eks.DescribeAddonOutput, error) {
+	return c.svc.DescribeAddonWithContext(ctx, input)
 }
 
-func (c *eksService) UpdateNodegroupConfig(input *eks.UpdateNodegroupConfigInput) (*eks.UpdateNodegroupConfigOutput, error) {
-	return c.svc.UpdateNodegroupConfig(input)
+func (c *eksService) UpdateAddon(ctx context.Context, input *eks.UpdateAddonInput) (*eks.UpdateAddonOutput, error) {
+	return c.svc.UpdateAddonWithContext(ctx, input)
 }
 
-func (c *eksService) DeleteNodegroup(input *eks.DeleteNodegroupInput) (*eks.DeleteNodegroupOutput, error) {
-	return c.svc.DeleteNodegroup(input)
+func (c *eksService) DescribeAddonVersions(ctx context.Context, input *eks.DescribeAddonVersionsInput) (*eks.DescribeAddonVersionsOutput, error) {
+	return c.svc.DescribeAddonVersionsWithContext(ctx, input)
 }
 
-func (c *eksService) ListNodegroups(input *eks.ListNodegroupsInput) (*eks.ListNodegroupsOutput, error) {
-	return c.svc.ListNodegroups(input)
+func (c *eksService) ListUpdates(ctx context.Context, input *eks.ListUpdatesInput) (*eks.ListUpdatesOutput, error) {
+	return c.svc.ListUpdatesWithContext(ctx, input)
 }
 
-func (c *eksService) DescribeNodegroup(input *eks.DescribeNodegroupInput) (*eks.DescribeNodegroupOutput, error) {
-	return c.svc.DescribeNodegroup(input)
+func (c *eksService) DescribeUpdate(ctx context.Context, input *eks.DescribeUpdateInput) (*eks.DescribeUpdateOutput, error) {
+	return c.svc.DescribeUpdateWithContext(ctx, input)
 }
 
-func (c *eksService) UpdateClusterVersion(input *eks.UpdateClusterVersionInput) (*eks.UpdateClusterVersionOutput, error) {
-	return c.svc.UpdateClusterVersion(input)
+func (c *eksService) CreateAccessEntry(ctx context.Context, input *eks.CreateAccessEntryInput) (*eks.CreateAccessEntryOutput, error) {
+	return c.svc.CreateAccessEntryWithContext(ctx, input)
 }
 
-func (c *eksService) TagResource(input *eks.TagResourceInput) (*eks.TagResourceOutput, error) {
-	return c.svc.TagResource(input)
+func (c *eksService) ListAccessEntries(ctx context.Context, input *eks.ListAccessEntriesInput) (*eks.ListAccessEntriesOutput, error) {
+	return c.svc.ListAccessEntriesWithContext(ctx, input)
 }
 
-func (c *eksService) UntagResource(input *eks.UntagResourceInput) (*eks.UntagResourceOutput, error) {
-	return c.svc.UntagResource(input)
+func (c *eksService) AssociateAccessPolicy(ctx context.Context, input *eks.AssociateAccessPolicyInput) (*eks.AssociateAccessPolicyOutput, error) {
+	return c.svc.AssociateAccessPolicyWithContext(ctx, input)
 }
 
-func (c *eksService) UpdateNodegroupVersion(input *eks.UpdateNodegroupVersionInput) (*eks.UpdateNodegroupVersionOutput, error) {
-	return c.svc.UpdateNodegroupVersion(input)
+func (c *eksService) DeleteAccessEntry(ctx context.Context, input *eks.DeleteAccessEntryInput) (*eks.DeleteAccessEntryOutput, error) {
+	return c.svc.DeleteAccessEntryWithContext(ctx, input)
 }
@@ -1,12 +1,17 @@
 package services
 
 import (
+	"context"
+
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/iam"
 )
 
 type IAMServiceInterface interface {
-	GetRole(input *iam.GetRoleInput) (*iam.GetRoleOutput, error)
+	GetRole(ctx context.Context, input *iam.GetRoleInput) (*iam.GetRoleOutput, error)
+	ListAttachedRolePolicies(ctx context.Context, input *iam.ListAttachedRolePoliciesInput) (*iam.ListAttachedRolePoliciesOutput, error)
+	ListOpenIDConnectProviders(ctx context.Context, input *iam.ListOpenIDConnectProvidersInput) (*iam.ListOpenIDConnectProvidersOutput, error)
+	CreateOpenIDConnectProvider(ctx context.Context, input *iam.CreateOpenIDConnectProviderInput) (*iam.CreateOpenIDConnectProviderOutput, error)
 }
 
 type iamService struct {
@@ -19,6 +24,18 @@ func NewIAMService(sess *session.Session) IAMServiceInterface {
 	}
 }
 
-func (c *iamService) GetRole(input *iam.GetRoleInput) (*iam.GetRoleOutput, error) {
-	return c.svc.GetRole(input)
+func (c *iamService) GetRole(ctx context.Context, input *iam.GetRoleInput) (*iam.GetRoleOutput, error) {
+	return c.svc.GetRoleWithContext(ctx, input)
+}
+
+func (c *iamService) ListAttachedRolePolicies(ctx context.Context, input *iam.ListAttachedRolePoliciesInput) (*iam.ListAttachedRolePoliciesOutput, error) {
+	return c.svc.ListAttachedRolePoliciesWithContext(ctx, input)
+}
+
+func (c *iamService) ListOpenIDConnectProviders(ctx context.Context, input *iam.ListOpenIDConnectProvidersInput) (*iam.ListOpenIDConnectProvidersOutput, error) {
+	return c.svc.ListOpenIDConnectProvidersWithContext(ctx, input)
+}
+
+func (c *iamService) CreateOpenIDConnectProvider(ctx context.Context, input *iam.CreateOpenIDConnectProviderInput) (*iam.CreateOpenIDConnectProviderOutput, error) {
+	return c.svc.CreateOpenIDConnectProviderWithContext(ctx, input)
 }
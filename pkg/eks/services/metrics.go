@@ -0,0 +1,343 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	eksServiceLabel            = "eks"
+	ec2ServiceLabel            = "ec2"
+	cloudformationServiceLabel = "cloudformation"
+
+	successResult = "success"
+	errorResult   = "error"
+)
+
+// APIMetrics holds the metrics of the AWS API requests made by the instrumented services.
+type APIMetrics struct {
+	requests *prometheus.CounterVec
+	latency  *prometheus.HistogramVec
+}
+
+// NewAPIMetrics creates the AWS API request metrics and registers them with the registerer.
+func NewAPIMetrics(registerer prometheus.Registerer) (*APIMetrics, error) {
+	metrics := &APIMetrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "eks_operator_aws_api_requests_total",
+			Help: "Number of AWS API requests made by the operator.",
+		}, []string{"service", "operation", "result"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "eks_operator_aws_api_request_duration_seconds",
+			Help:    "Duration of the AWS API requests made by the operator, including the retries of the SDK.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"service", "operation"}),
+	}
+	for _, collector := range []prometheus.Collector{metrics.requests, metrics.latency} {
+		if err := registerer.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+	return metrics, nil
+}
+
+// observe calls the request and records its result and duration.
+func observe[T any](metrics *APIMetrics, service, operation string, request func() (T, error)) (T, error) {
+	start := time.Now()
+	output, err := request()
+	metrics.latency.WithLabelValues(service, operation).Observe(time.Since(start).Seconds())
+	result := successResult
+	if err != nil {
+		result = errorResult
+	}
+	metrics.requests.WithLabelValues(service, operation, result).Inc()
+	return output, err
+}
+
+type instrumentedEKSService struct {
+	inner   EKSServiceInterface
+	metrics *APIMetrics
+}
+
+// NewInstrumentedEKSService returns an EKS service recording the requests made through the inner service in the metrics.
+func NewInstrumentedEKSService(inner EKSServiceInterface, metrics *APIMetrics) EKSServiceInterface {
+	return &instrumentedEKSService{inner: inner, metrics: metrics}
+}
+
+func (s *instrumentedEKSService) CreateCluster(ctx context.Context, input *eks.CreateClusterInput) (*eks.CreateClusterOutput, error) {
+	return observe(s.metrics, eksServiceLabel, "CreateCluster", func() (*eks.CreateClusterOutput, error) {
+		return s.inner.CreateCluster(ctx, input)
+	})
+}
+
+func (s *instrumentedEKSService) DeleteCluster(ctx context.Context, input *eks.DeleteClusterInput) (*eks.DeleteClusterOutput, error) {
+	return observe(s.metrics, eksServiceLabel, "DeleteCluster", func() (*eks.DeleteClusterOutput, error) {
+		return s.inner.DeleteCluster(ctx, input)
+	})
+}
+
+func (s *instrumentedEKSService) ListClusters(ctx context.Context, input *eks.ListClustersInput) (*eks.ListClustersOutput, error) {
+	return observe(s.metrics, eksServiceLabel, "ListClusters", func() (*eks.ListClustersOutput, error) {
+		return s.inner.ListClusters(ctx, input)
+	})
+}
+
+func (s *instrumentedEKSService) ListClustersPages(ctx context.Context, input *eks.ListClustersInput, fn func(*eks.ListClustersOutput, bool) bool) error {
+	_, err := observe(s.metrics, eksServiceLabel, "ListClustersPages", func() (struct{}, error) {
+		return struct{}{}, s.inner.ListClustersPages(ctx, input, fn)
+	})
+	return err
+}
+
+func (s *instrumentedEKSService) DescribeCluster(ctx context.Context, input *eks.DescribeClusterInput) (*eks.DescribeClusterOutput, error) {
+	return observe(s.metrics, eksServiceLabel, "DescribeCluster", func() (*eks.DescribeClusterOutput, error) {
+		return s.inner.DescribeCluster(ctx, input)
+	})
+}
+
+func (s *instrumentedEKSService) UpdateClusterConfig(ctx context.Context, input *eks.UpdateClusterConfigInput) (*eks.UpdateClusterConfigOutput, error) {
+	return observe(s.metrics, eksServiceLabel, "UpdateClusterConfig", func() (*eks.UpdateClusterConfigOutput, error) {
+		return s.inner.UpdateClusterConfig(ctx, input)
+	})
+}
+
+func (s *instrumentedEKSService) UpdateClusterVersion(ctx context.Context, input *eks.UpdateClusterVersionInput) (*eks.UpdateClusterVersionOutput, error) {
+	return observe(s.metrics, eksServiceLabel, "UpdateClusterVersion", func() (*eks.UpdateClusterVersionOutput, error) {
+		return s.inner.UpdateClusterVersion(ctx, input)
+	})
+}
+
+func (s *instrumentedEKSService) CreateNodegroup(ctx context.Context, input *eks.CreateNodegroupInput) (*eks.CreateNodegroupOutput, error) {
+	return observe(s.metrics, eksServiceLabel, "CreateNodegroup", func() (*eks.CreateNodegroupOutput, error) {
+		return s.inner.CreateNodegroup(ctx, input)
+	})
+}
+
+func (s *instrumentedEKSService) UpdateNodegroupConfig(ctx context.Context, input *eks.UpdateNodegroupConfigInput) (*eks.UpdateNodegroupConfigOutput, error) {
+	return observe(s.metrics, eksServiceLabel, "UpdateNodegroupConfig", func() (*eks.UpdateNodegroupConfigOutput, error) {
+		return s.inner.UpdateNodegroupConfig(ctx, input)
+	})
+}
+
+func (s *instrumentedEKSService) ListNodegroups(ctx context.Context, input *eks.ListNodegroupsInput) (*eks.ListNodegroupsOutput, error) {
+	return observe(s.metrics, eksServiceLabel, "ListNodegroups", func() (*eks.ListNodegroupsOutput, error) {
+		return s.inner.ListNodegroups(ctx, input)
+	})
+}
+
+func (s *instrumentedEKSService) DeleteNodegroup(ctx context.Context, input *eks.DeleteNodegroupInput) (*eks.DeleteNodegroupOutput, error) {
+	return observe(s.metrics, eksServiceLabel, "DeleteNodegroup", func() (*eks.DeleteNodegroupOutput, error) {
+		return s.inner.DeleteNodegroup(ctx, input)
+	})
+}
+
+func (s *instrumentedEKSService) DescribeNodegroup(ctx context.Context, input *eks.DescribeNodegroupInput) (*eks.DescribeNodegroupOutput, error) {
+	return observe(s.metrics, eksServiceLabel, "DescribeNodegroup", func() (*eks.DescribeNodegroupOutput, error) {
+		return s.inner.DescribeNodegroup(ctx, input)
+	})
+}
+
+func (s *instrumentedEKSService) UpdateNodegroupVersion(ctx context.Context, input *eks.UpdateNodegroupVersionInput) (*eks.UpdateNodegroupVersionOutput, error) {
+	return observe(s.metrics, eksServiceLabel, "UpdateNodegroupVersion", func() (*eks.UpdateNodegroupVersionOutput, error) {
+		return s.inner.UpdateNodegroupVersion(ctx, input)
+	})
+}
+
+func (s *instrumentedEKSService) TagResource(ctx context.Context, input *eks.TagResourceInput) (*eks.TagResourceOutput, error) {
+	return observe(s.metrics, eksServiceLabel, "TagResource", func() (*eks.TagResourceOutput, error) {
+		return s.inner.TagResource(ctx, input)
+	})
+}
+
+func (s *instrumentedEKSService) UntagResource(ctx context.Context, input *eks.UntagResourceInput) (*eks.UntagResourceOutput, error) {
+	return observe(s.metrics, eksServiceLabel, "UntagResource", func() (*eks.UntagResourceOutput, error) {
+		return s.inner.UntagResource(ctx, input)
+	})
+}
+
+func (s *instrumentedEKSService) ListTagsForResource(ctx context.Context, input *eks.ListTagsForResourceInput) (*eks.ListTagsForResourceOutput, error) {
+	return observe(s.metrics, eksServiceLabel, "ListTagsForResource", func() (*eks.ListTagsForResourceOutput, error) {
+		return s.inner.ListTagsForResource(ctx, input)
+	})
+}
+
+func (s *instrumentedEKSService) CreateFargateProfile(ctx context.Context, input *eks.CreateFargateProfileInput) (*eks.CreateFargateProfileOutput, error) {
+	return observe(s.metrics, eksServiceLabel, "CreateFargateProfile", func() (*eks.CreateFargateProfileOutput, error) {
+		return s.inner.CreateFargateProfile(ctx, input)
+	})
+}
+
+func (s *instrumentedEKSService) DescribeFargateProfile(ctx context.Context, input *eks.DescribeFargateProfileInput) (*eks.DescribeFargateProfileOutput, error) {
+	return observe(s.metrics, eksServiceLabel, "DescribeFargateProfile", func() (*eks.DescribeFargateProfileOutput, error) {
+		return s.inner.DescribeFargateProfile(ctx, input)
+	})
+}
+
+func (s *instrumentedEKSService) CreateAddon(ctx context.Context, input *eks.CreateAddonInput) (*eks.CreateAddonOutput, error) {
+	return observe(s.metrics, eksServiceLabel, "CreateAddon", func() (*eks.CreateAddonOutput, error) {
+		return s.inner.CreateAddon(ctx, input)
+	})
+}
+
+func (s *instrumentedEKSService) DescribeAddon(ctx context.Context, input *eks.DescribeAddonInput) (*eks.DescribeAddonOutput, error) {
+	return observe(s.metrics, eksServiceLabel, "DescribeAddon", func() (*eks.DescribeAddonOutput, error) {
+		return s.inner.DescribeAddon(ctx, input)
+	})
+}
+
+func (s *instrumentedEKSService) UpdateAddon(ctx context.Context, input *eks.UpdateAddonInput) (*eks.UpdateAddonOutput, error) {
+	return observe(s.metrics, eksServiceLabel, "UpdateAddon", func() (*eks.UpdateAddonOutput, error) {
+		return s.inner.UpdateAddon(ctx, input)
+	})
+}
+
+func (s *instrumentedEKSService) DescribeAddonVersions(ctx context.Context, input *eks.DescribeAddonVersionsInput) (*eks.DescribeAddonVersionsOutput, error) {
+	return observe(s.metrics, eksServiceLabel, "DescribeAddonVersions", func() (*eks.DescribeAddonVersionsOutput, error) {
+		return s.inner.DescribeAddonVersions(ctx, input)
+	})
+}
+
+func (s *instrumentedEKSService) ListUpdates(ctx context.Context, input *eks.ListUpdatesInput) (*eks.ListUpdatesOutput, error) {
+	return observe(s.metrics, eksServiceLabel, "ListUpdates", func() (*eks.ListUpdatesOutput, error) {
+		return s.inner.ListUpdates(ctx, input)
+	})
+}
+
+func (s *instrumentedEKSService) DescribeUpdate(ctx context.Context, input *eks.DescribeUpdateInput) (*eks.DescribeUpdateOutput, error) {
+	return observe(s.metrics, eksServiceLabel, "DescribeUpdate", func() (*eks.DescribeUpdateOutput, error) {
+		return s.inner.DescribeUpdate(ctx, input)
+	})
+}
+
+func (s *instrumentedEKSService) CreateAccessEntry(ctx context.Context, input *eks.CreateAccessEntryInput) (*eks.CreateAccessEntryOutput, error) {
+	return observe(s.metrics, eksServiceLabel, "CreateAccessEntry", func() (*eks.CreateAccessEntryOutput, error) {
+		return s.inner.CreateAccessEntry(ctx, input)
+	})
+}
+
+func (s *instrumentedEKSService) ListAccessEntries(ctx context.Context, input *eks.ListAccessEntriesInput) (*eks.ListAccessEntriesOutput, error) {
+	return observe(s.metrics, eksServiceLabel, "ListAccessEntries", func() (*eks.ListAccessEntriesOutput, error) {
+		return s.inner.ListAccessEntries(ctx, input)
+	})
+}
+
+func (s *instrumentedEKSService) AssociateAccessPolicy(ctx context.Context, input *eks.AssociateAccessPolicyInput) (*eks.AssociateAccessPolicyOutput, error) {
+	return observe(s.metrics, eksServiceLabel, "AssociateAccessPolicy", func() (*eks.AssociateAccessPolicyOutput, error) {
+		return s.inner.AssociateAccessPolicy(ctx, input)
+	})
+}
+
+func (s *instrumentedEKSService) DeleteAccessEntry(ctx context.Context, input *eks.DeleteAccessEntryInput) (*eks.DeleteAccessEntryOutput, error) {
+	return observe(s.metrics, eksServiceLabel, "DeleteAccessEntry", func() (*eks.DeleteAccessEntryOutput, error) {
+		return s.inner.DeleteAccessEntry(ctx, input)
+	})
+}
+
+type instrumentedEC2Service struct {
+	inner   EC2ServiceInterface
+	metrics *APIMetrics
+}
+
+// NewInstrumentedEC2Service returns an EC2 service recording the requests made through the inner service in the metrics.
+func NewInstrumentedEC2Service(inner EC2ServiceInterface, metrics *APIMetrics) EC2ServiceInterface {
+	return &instrumentedEC2Service{inner: inner, metrics: metrics}
+}
+
+func (s *instrumentedEC2Service) CreateLaunchTemplate(ctx context.Context, input *ec2.CreateLaunchTemplateInput) (*ec2.CreateLaunchTemplateOutput, error) {
+	return observe(s.metrics, ec2ServiceLabel, "CreateLaunchTemplate", func() (*ec2.CreateLaunchTemplateOutput, error) {
+		return s.inner.CreateLaunchTemplate(ctx, input)
+	})
+}
+
+func (s *instrumentedEC2Service) DeleteLaunchTemplate(ctx context.Context, input *ec2.DeleteLaunchTemplateInput) (*ec2.DeleteLaunchTemplateOutput, error) {
+	return observe(s.metrics, ec2ServiceLabel, "DeleteLaunchTemplate", func() (*ec2.DeleteLaunchTemplateOutput, error) {
+		return s.inner.DeleteLaunchTemplate(ctx, input)
+	})
+}
+
+func (s *instrumentedEC2Service) DescribeLaunchTemplates(ctx context.Context, input *ec2.DescribeLaunchTemplatesInput) (*ec2.DescribeLaunchTemplatesOutput, error) {
+	return observe(s.metrics, ec2ServiceLabel, "DescribeLaunchTemplates", func() (*ec2.DescribeLaunchTemplatesOutput, error) {
+		return s.inner.DescribeLaunchTemplates(ctx, input)
+	})
+}
+
+func (s *instrumentedEC2Service) CreateLaunchTemplateVersion(ctx context.Context, input *ec2.CreateLaunchTemplateVersionInput) (*ec2.CreateLaunchTemplateVersionOutput, error) {
+	return observe(s.metrics, ec2ServiceLabel, "CreateLaunchTemplateVersion", func() (*ec2.CreateLaunchTemplateVersionOutput, error) {
+		return s.inner.CreateLaunchTemplateVersion(ctx, input)
+	})
+}
+
+func (s *instrumentedEC2Service) DeleteLaunchTemplateVersions(ctx context.Context, input *ec2.DeleteLaunchTemplateVersionsInput) (*ec2.DeleteLaunchTemplateVersionsOutput, error) {
+	return observe(s.metrics, ec2ServiceLabel, "DeleteLaunchTemplateVersions", func() (*ec2.DeleteLaunchTemplateVersionsOutput, error) {
+		return s.inner.DeleteLaunchTemplateVersions(ctx, input)
+	})
+}
+
+func (s *instrumentedEC2Service) DescribeLaunchTemplateVersions(ctx context.Context, input *ec2.DescribeLaunchTemplateVersionsInput) (*ec2.DescribeLaunchTemplateVersionsOutput, error) {
+	return observe(s.metrics, ec2ServiceLabel, "DescribeLaunchTemplateVersions", func() (*ec2.DescribeLaunchTemplateVersionsOutput, error) {
+		return s.inner.DescribeLaunchTemplateVersions(ctx, input)
+	})
+}
+
+func (s *instrumentedEC2Service) DescribeImages(ctx context.Context, input *ec2.DescribeImagesInput) (*ec2.DescribeImagesOutput, error) {
+	return observe(s.metrics, ec2ServiceLabel, "DescribeImages", func() (*ec2.DescribeImagesOutput, error) {
+		return s.inner.DescribeImages(ctx, input)
+	})
+}
+
+func (s *instrumentedEC2Service) DescribeInstanceTypes(ctx context.Context, input *ec2.DescribeInstanceTypesInput) (*ec2.DescribeInstanceTypesOutput, error) {
+	return observe(s.metrics, ec2ServiceLabel, "DescribeInstanceTypes", func() (*ec2.DescribeInstanceTypesOutput, error) {
+		return s.inner.DescribeInstanceTypes(ctx, input)
+	})
+}
+
+func (s *instrumentedEC2Service) DescribeSubnets(ctx context.Context, input *ec2.DescribeSubnetsInput) (*ec2.DescribeSubnetsOutput, error) {
+	return observe(s.metrics, ec2ServiceLabel, "DescribeSubnets", func() (*ec2.DescribeSubnetsOutput, error) {
+		return s.inner.DescribeSubnets(ctx, input)
+	})
+}
+
+func (s *instrumentedEC2Service) DescribeSecurityGroups(ctx context.Context, input *ec2.DescribeSecurityGroupsInput) (*ec2.DescribeSecurityGroupsOutput, error) {
+	return observe(s.metrics, ec2ServiceLabel, "DescribeSecurityGroups", func() (*ec2.DescribeSecurityGroupsOutput, error) {
+		return s.inner.DescribeSecurityGroups(ctx, input)
+	})
+}
+
+type instrumentedCloudFormationService struct {
+	inner   CloudFormationServiceInterface
+	metrics *APIMetrics
+}
+
+// NewInstrumentedCloudFormationService returns an CloudFormation service recording the requests made through the inner service in the metrics.
+func NewInstrumentedCloudFormationService(inner CloudFormationServiceInterface, metrics *APIMetrics) CloudFormationServiceInterface {
+	return &instrumentedCloudFormationService{inner: inner, metrics: metrics}
+}
+
+func (s *instrumentedCloudFormationService) DescribeStacks(ctx context.Context, input *cloudformation.DescribeStacksInput) (*cloudformation.DescribeStacksOutput, error) {
+	return observe(s.metrics, cloudformationServiceLabel, "DescribeStacks", func() (*cloudformation.DescribeStacksOutput, error) {
+		return s.inner.DescribeStacks(ctx, input)
+	})
+}
+
+func (s *instrumentedCloudFormationService) DeleteStack(ctx context.Context, input *cloudformation.DeleteStackInput) (*cloudformation.DeleteStackOutput, error) {
+	return observe(s.metrics, cloudformationServiceLabel, "DeleteStack", func() (*cloudformation.DeleteStackOutput, error) {
+		return s.inner.DeleteStack(ctx, input)
+	})
+}
+
+func (s *instrumentedCloudFormationService) CreateStack(ctx context.Context, input *cloudformation.CreateStackInput) (*cloudformation.CreateStackOutput, error) {
+	return observe(s.metrics, cloudformationServiceLabel, "CreateStack", func() (*cloudformation.CreateStackOutput, error) {
+		return s.inner.CreateStack(ctx, input)
+	})
+}
+
+func (s *instrumentedCloudFormationService) DescribeStackEvents(ctx context.Context, input *cloudformation.DescribeStackEventsInput) (*cloudformation.DescribeStackEventsOutput, error) {
+	return observe(s.metrics, cloudformationServiceLabel, "DescribeStackEvents", func() (*cloudformation.DescribeStackEventsOutput, error) {
+		return s.inner.DescribeStackEvents(ctx, input)
+	})
+}
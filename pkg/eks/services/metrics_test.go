@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeEKSService struct {
+	EKSServiceInterface
+	err error
+}
+
+func (s *fakeEKSService) DescribeCluster(_ context.Context, input *eks.DescribeClusterInput) (*eks.DescribeClusterOutput, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &eks.DescribeClusterOutput{Cluster: &eks.Cluster{Name: input.Name}}, nil
+}
+
+func TestInstrumentedEKSServiceCountsRequests(t *testing.T) {
+	asserts := assert.New(t)
+
+	registry := prometheus.NewRegistry()
+	metrics, err := NewAPIMetrics(registry)
+	asserts.NoError(err)
+
+	inner := &fakeEKSService{}
+	svc := NewInstrumentedEKSService(inner, metrics)
+
+	output, err := svc.DescribeCluster(context.Background(), &eks.DescribeClusterInput{Name: aws.String("test")})
+	asserts.NoError(err)
+	asserts.Equal("test", aws.StringValue(output.Cluster.Name))
+
+	inner.err = errors.New("error")
+	_, err = svc.DescribeCluster(context.Background(), &eks.DescribeClusterInput{Name: aws.String("test")})
+	asserts.EqualError(err, "error")
+	_, err = svc.DescribeCluster(context.Background(), &eks.DescribeClusterInput{Name: aws.String("test")})
+	asserts.Error(err)
+
+	asserts.Equal(float64(1), testutil.ToFloat64(metrics.requests.WithLabelValues("eks", "DescribeCluster", "success")))
+	asserts.Equal(float64(2), testutil.ToFloat64(metrics.requests.WithLabelValues("eks", "DescribeCluster", "error")))
+
+	families, err := registry.Gather()
+	asserts.NoError(err)
+	for _, family := range families {
+		if family.GetName() != "eks_operator_aws_api_request_duration_seconds" {
+			continue
+		}
+		asserts.Len(family.GetMetric(), 1)
+		asserts.Equal(uint64(3), family.GetMetric()[0].GetHistogram().GetSampleCount())
+		return
+	}
+	t.Fatal("latency histogram not registered")
+}
+
+func TestNewAPIMetricsFailsToRegisterTwice(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	_, err := NewAPIMetrics(registry)
+	assert.NoError(t, err)
+
+	_, err = NewAPIMetrics(registry)
+	assert.Error(t, err)
+}
@@ -0,0 +1,111 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../autoscaling.go
+
+// Package mock_services is a generated GoMock package.
+package mock_services
+
+import (
+	context "context"
+	reflect "reflect"
+
+	autoscaling "github.com/aws/aws-sdk-go/service/autoscaling"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAutoScalingServiceInterface is a mock of AutoScalingServiceInterface interface.
+type MockAutoScalingServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockAutoScalingServiceInterfaceMockRecorder
+}
+
+// MockAutoScalingServiceInterfaceMockRecorder is the mock recorder for MockAutoScalingServiceInterface.
+type MockAutoScalingServiceInterfaceMockRecorder struct {
+	mock *MockAutoScalingServiceInterface
+}
+
+// NewMockAutoScalingServiceInterface creates a new mock instance.
+func NewMockAutoScalingServiceInterface(ctrl *gomock.Controller) *MockAutoScalingServiceInterface {
+	mock := &MockAutoScalingServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockAutoScalingServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAutoScalingServiceInterface) EXPECT() *MockAutoScalingServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// AttachLoadBalancerTargetGroups mocks base method.
+func (m *MockAutoScalingServiceInterface) AttachLoadBalancerTargetGroups(ctx context.Context, input *autoscaling.AttachLoadBalancerTargetGroupsInput) (*autoscaling.AttachLoadBalancerTargetGroupsOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachLoadBalancerTargetGroups", ctx, input)
+	ret0, _ := ret[0].(*autoscaling.AttachLoadBalancerTargetGroupsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AttachLoadBalancerTargetGroups indicates an expected call of AttachLoadBalancerTargetGroups.
+func (mr *MockAutoScalingServiceInterfaceMockRecorder) AttachLoadBalancerTargetGroups(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachLoadBalancerTargetGroups", reflect.TypeOf((*MockAutoScalingServiceInterface)(nil).AttachLoadBalancerTargetGroups), ctx, input)
+}
+
+// DescribeAutoScalingGroups mocks base method.
+func (m *MockAutoScalingServiceInterface) DescribeAutoScalingGroups(ctx context.Context, input *autoscaling.DescribeAutoScalingGroupsInput) (*autoscaling.DescribeAutoScalingGroupsOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeAutoScalingGroups", ctx, input)
+	ret0, _ := ret[0].(*autoscaling.DescribeAutoScalingGroupsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeAutoScalingGroups indicates an expected call of DescribeAutoScalingGroups.
+func (mr *MockAutoScalingServiceInterfaceMockRecorder) DescribeAutoScalingGroups(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeAutoScalingGroups", reflect.TypeOf((*MockAutoScalingServiceInterface)(nil).DescribeAutoScalingGroups), ctx, input)
+}
+
+// DescribeLoadBalancerTargetGroups mocks base method.
+func (m *MockAutoScalingServiceInterface) DescribeLoadBalancerTargetGroups(ctx context.Context, input *autoscaling.DescribeLoadBalancerTargetGroupsInput) (*autoscaling.DescribeLoadBalancerTargetGroupsOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeLoadBalancerTargetGroups", ctx, input)
+	ret0, _ := ret[0].(*autoscaling.DescribeLoadBalancerTargetGroupsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeLoadBalancerTargetGroups indicates an expected call of DescribeLoadBalancerTargetGroups.
+func (mr *MockAutoScalingServiceInterfaceMockRecorder) DescribeLoadBalancerTargetGroups(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeLoadBalancerTargetGroups", reflect.TypeOf((*MockAutoScalingServiceInterface)(nil).DescribeLoadBalancerTargetGroups), ctx, input)
+}
+
+// DescribeScalingActivities mocks base method.
+func (m *MockAutoScalingServiceInterface) DescribeScalingActivities(ctx context.Context, input *autoscaling.DescribeScalingActivitiesInput) (*autoscaling.DescribeScalingActivitiesOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeScalingActivities", ctx, input)
+	ret0, _ := ret[0].(*autoscaling.DescribeScalingActivitiesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeScalingActivities indicates an expected call of DescribeScalingActivities.
+func (mr *MockAutoScalingServiceInterfaceMockRecorder) DescribeScalingActivities(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeScalingActivities", reflect.TypeOf((*MockAutoScalingServiceInterface)(nil).DescribeScalingActivities), ctx, input)
+}
+
+// DetachLoadBalancerTargetGroups mocks base method.
+func (m *MockAutoScalingServiceInterface) DetachLoadBalancerTargetGroups(ctx context.Context, input *autoscaling.DetachLoadBalancerTargetGroupsInput) (*autoscaling.DetachLoadBalancerTargetGroupsOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetachLoadBalancerTargetGroups", ctx, input)
+	ret0, _ := ret[0].(*autoscaling.DetachLoadBalancerTargetGroupsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DetachLoadBalancerTargetGroups indicates an expected call of DetachLoadBalancerTargetGroups.
+func (mr *MockAutoScalingServiceInterfaceMockRecorder) DetachLoadBalancerTargetGroups(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachLoadBalancerTargetGroups", reflect.TypeOf((*MockAutoScalingServiceInterface)(nil).DetachLoadBalancerTargetGroups), ctx, input)
+}
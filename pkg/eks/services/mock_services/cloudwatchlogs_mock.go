@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../cloudwatchlogs.go
+
+// Package mock_services is a generated GoMock package.
+package mock_services
+
+import (
+	context "context"
+	reflect "reflect"
+
+	cloudwatchlogs "github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCloudWatchLogsServiceInterface is a mock of CloudWatchLogsServiceInterface interface.
+type MockCloudWatchLogsServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockCloudWatchLogsServiceInterfaceMockRecorder
+}
+
+// MockCloudWatchLogsServiceInterfaceMockRecorder is the mock recorder for MockCloudWatchLogsServiceInterface.
+type MockCloudWatchLogsServiceInterfaceMockRecorder struct {
+	mock *MockCloudWatchLogsServiceInterface
+}
+
+// NewMockCloudWatchLogsServiceInterface creates a new mock instance.
+func NewMockCloudWatchLogsServiceInterface(ctrl *gomock.Controller) *MockCloudWatchLogsServiceInterface {
+	mock := &MockCloudWatchLogsServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockCloudWatchLogsServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCloudWatchLogsServiceInterface) EXPECT() *MockCloudWatchLogsServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// DescribeLogGroups mocks base method.
+func (m *MockCloudWatchLogsServiceInterface) DescribeLogGroups(ctx context.Context, input *cloudwatchlogs.DescribeLogGroupsInput) (*cloudwatchlogs.DescribeLogGroupsOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeLogGroups", ctx, input)
+	ret0, _ := ret[0].(*cloudwatchlogs.DescribeLogGroupsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeLogGroups indicates an expected call of DescribeLogGroups.
+func (mr *MockCloudWatchLogsServiceInterfaceMockRecorder) DescribeLogGroups(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeLogGroups", reflect.TypeOf((*MockCloudWatchLogsServiceInterface)(nil).DescribeLogGroups), ctx, input)
+}
+
+// PutRetentionPolicy mocks base method.
+func (m *MockCloudWatchLogsServiceInterface) PutRetentionPolicy(ctx context.Context, input *cloudwatchlogs.PutRetentionPolicyInput) (*cloudwatchlogs.PutRetentionPolicyOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PutRetentionPolicy", ctx, input)
+	ret0, _ := ret[0].(*cloudwatchlogs.PutRetentionPolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutRetentionPolicy indicates an expected call of PutRetentionPolicy.
+func (mr *MockCloudWatchLogsServiceInterfaceMockRecorder) PutRetentionPolicy(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutRetentionPolicy", reflect.TypeOf((*MockCloudWatchLogsServiceInterface)(nil).PutRetentionPolicy), ctx, input)
+}
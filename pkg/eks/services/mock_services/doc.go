@@ -2,6 +2,8 @@ package mock_services
 
 // Run go generate to regenerate this mock.
 //
+//go:generate ../../../../bin/mockgen -destination autoscaling_mock.go -package mock_services -source ../autoscaling.go AutoScalingServiceInterface
+//go:generate ../../../../bin/mockgen -destination cloudwatchlogs_mock.go -package mock_services -source ../cloudwatchlogs.go CloudWatchLogsServiceInterface
 //go:generate ../../../../bin/mockgen -destination cloudformation_mock.go -package mock_services -source ../cloudformation.go CloudFormationServiceInterface
 //go:generate ../../../../bin/mockgen -destination eks_mock.go -package mock_services -source ../eks.go EKSServiceInterface
 //go:generate ../../../../bin/mockgen -destination iam_mock.go -package mock_services -source ../iam.go IAMServiceInterface
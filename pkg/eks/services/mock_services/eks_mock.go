@@ -5,6 +5,7 @@
 package mock_services
 
 import (
+	context "context"
 	reflect "reflect"
 
 	eks "github.com/aws/aws-sdk-go/service/eks"
@@ -34,212 +35,421 @@ func (m *MockEKSServiceInterface) EXPECT() *MockEKSServiceInterfaceMockRecorder
 	return m.recorder
 }
 
+// AssociateAccessPolicy mocks base method.
+func (m *MockEKSServiceInterface) AssociateAccessPolicy(ctx context.Context, input *eks.AssociateAccessPolicyInput) (*eks.AssociateAccessPolicyOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssociateAccessPolicy", ctx, input)
+	ret0, _ := ret[0].(*eks.AssociateAccessPolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AssociateAccessPolicy indicates an expected call of AssociateAccessPolicy.
+func (mr *MockEKSServiceInterfaceMockRecorder) AssociateAccessPolicy(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssociateAccessPolicy", reflect.TypeOf((*MockEKSServiceInterface)(nil).AssociateAccessPolicy), ctx, input)
+}
+
+// CreateAccessEntry mocks base method.
+func (m *MockEKSServiceInterface) CreateAccessEntry(ctx context.Context, input *eks.CreateAccessEntryInput) (*eks.CreateAccessEntryOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccessEntry", ctx, input)
+	ret0, _ := ret[0].(*eks.CreateAccessEntryOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccessEntry indicates an expected call of CreateAccessEntry.
+func (mr *MockEKSServiceInterfaceMockRecorder) CreateAccessEntry(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccessEntry", reflect.TypeOf((*MockEKSServiceInterface)(nil).CreateAccessEntry), ctx, input)
+}
+
+// CreateAddon mocks base method.
+func (m *MockEKSServiceInterface) CreateAddon(ctx context.Context, input *eks.CreateAddonInput) (*eks.CreateAddonOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAddon", ctx, input)
+	ret0, _ := ret[0].(*eks.CreateAddonOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAddon indicates an expected call of CreateAddon.
+func (mr *MockEKSServiceInterfaceMockRecorder) CreateAddon(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAddon", reflect.TypeOf((*MockEKSServiceInterface)(nil).CreateAddon), ctx, input)
+}
+
 // CreateCluster mocks base method.
-func (m *MockEKSServiceInterface) CreateCluster(input *eks.CreateClusterInput) (*eks.CreateClusterOutput, error) {
+func (m *MockEKSServiceInterface) CreateCluster(ctx context.Context, input *eks.CreateClusterInput) (*eks.CreateClusterOutput, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateCluster", input)
+	ret := m.ctrl.Call(m, "CreateCluster", ctx, input)
 	ret0, _ := ret[0].(*eks.CreateClusterOutput)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateCluster indicates an expected call of CreateCluster.
-func (mr *MockEKSServiceInterfaceMockRecorder) CreateCluster(input interface{}) *gomock.Call {
+func (mr *MockEKSServiceInterfaceMockRecorder) CreateCluster(ctx, input interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCluster", reflect.TypeOf((*MockEKSServiceInterface)(nil).CreateCluster), input)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCluster", reflect.TypeOf((*MockEKSServiceInterface)(nil).CreateCluster), ctx, input)
+}
+
+// CreateFargateProfile mocks base method.
+func (m *MockEKSServiceInterface) CreateFargateProfile(ctx context.Context, input *eks.CreateFargateProfileInput) (*eks.CreateFargateProfileOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFargateProfile", ctx, input)
+	ret0, _ := ret[0].(*eks.CreateFargateProfileOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateFargateProfile indicates an expected call of CreateFargateProfile.
+func (mr *MockEKSServiceInterfaceMockRecorder) CreateFargateProfile(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFargateProfile", reflect.TypeOf((*MockEKSServiceInterface)(nil).CreateFargateProfile), ctx, input)
 }
 
 // CreateNodegroup mocks base method.
-func (m *MockEKSServiceInterface) CreateNodegroup(input *eks.CreateNodegroupInput) (*eks.CreateNodegroupOutput, error) {
+func (m *MockEKSServiceInterface) CreateNodegroup(ctx context.Context, input *eks.CreateNodegroupInput) (*eks.CreateNodegroupOutput, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateNodegroup", input)
+	ret := m.ctrl.Call(m, "CreateNodegroup", ctx, input)
 	ret0, _ := ret[0].(*eks.CreateNodegroupOutput)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateNodegroup indicates an expected call of CreateNodegroup.
-func (mr *MockEKSServiceInterfaceMockRecorder) CreateNodegroup(input interface{}) *gomock.Call {
+func (mr *MockEKSServiceInterfaceMockRecorder) CreateNodegroup(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNodegroup", reflect.TypeOf((*MockEKSServiceInterface)(nil).CreateNodegroup), ctx, input)
+}
+
+// DeleteAccessEntry mocks base method.
+func (m *MockEKSServiceInterface) DeleteAccessEntry(ctx context.Context, input *eks.DeleteAccessEntryInput) (*eks.DeleteAccessEntryOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccessEntry", ctx, input)
+	ret0, _ := ret[0].(*eks.DeleteAccessEntryOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAccessEntry indicates an expected call of DeleteAccessEntry.
+func (mr *MockEKSServiceInterfaceMockRecorder) DeleteAccessEntry(ctx, input interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNodegroup", reflect.TypeOf((*MockEKSServiceInterface)(nil).CreateNodegroup), input)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccessEntry", reflect.TypeOf((*MockEKSServiceInterface)(nil).DeleteAccessEntry), ctx, input)
 }
 
 // DeleteCluster mocks base method.
-func (m *MockEKSServiceInterface) DeleteCluster(input *eks.DeleteClusterInput) (*eks.DeleteClusterOutput, error) {
+func (m *MockEKSServiceInterface) DeleteCluster(ctx context.Context, input *eks.DeleteClusterInput) (*eks.DeleteClusterOutput, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteCluster", input)
+	ret := m.ctrl.Call(m, "DeleteCluster", ctx, input)
 	ret0, _ := ret[0].(*eks.DeleteClusterOutput)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // DeleteCluster indicates an expected call of DeleteCluster.
-func (mr *MockEKSServiceInterfaceMockRecorder) DeleteCluster(input interface{}) *gomock.Call {
+func (mr *MockEKSServiceInterfaceMockRecorder) DeleteCluster(ctx, input interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCluster", reflect.TypeOf((*MockEKSServiceInterface)(nil).DeleteCluster), input)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCluster", reflect.TypeOf((*MockEKSServiceInterface)(nil).DeleteCluster), ctx, input)
 }
 
 // DeleteNodegroup mocks base method.
-func (m *MockEKSServiceInterface) DeleteNodegroup(input *eks.DeleteNodegroupInput) (*eks.DeleteNodegroupOutput, error) {
+func (m *MockEKSServiceInterface) DeleteNodegroup(ctx context.Context, input *eks.DeleteNodegroupInput) (*eks.DeleteNodegroupOutput, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteNodegroup", input)
+	ret := m.ctrl.Call(m, "DeleteNodegroup", ctx, input)
 	ret0, _ := ret[0].(*eks.DeleteNodegroupOutput)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // DeleteNodegroup indicates an expected call of DeleteNodegroup.
-func (mr *MockEKSServiceInterfaceMockRecorder) DeleteNodegroup(input interface{}) *gomock.Call {
+func (mr *MockEKSServiceInterfaceMockRecorder) DeleteNodegroup(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNodegroup", reflect.TypeOf((*MockEKSServiceInterface)(nil).DeleteNodegroup), ctx, input)
+}
+
+// DescribeAddon mocks base method.
+func (m *MockEKSServiceInterface) DescribeAddon(ctx context.Context, input *eks.DescribeAddonInput) (*eks.DescribeAddonOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeAddon", ctx, input)
+	ret0, _ := ret[0].(*eks.DescribeAddonOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeAddon indicates an expected call of DescribeAddon.
+func (mr *MockEKSServiceInterfaceMockRecorder) DescribeAddon(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeAddon", reflect.TypeOf((*MockEKSServiceInterface)(nil).DescribeAddon), ctx, input)
+}
+
+// DescribeAddonVersions mocks base method.
+func (m *MockEKSServiceInterface) DescribeAddonVersions(ctx context.Context, input *eks.DescribeAddonVersionsInput) (*eks.DescribeAddonVersionsOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeAddonVersions", ctx, input)
+	ret0, _ := ret[0].(*eks.DescribeAddonVersionsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeAddonVersions indicates an expected call of DescribeAddonVersions.
+func (mr *MockEKSServiceInterfaceMockRecorder) DescribeAddonVersions(ctx, input interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNodegroup", reflect.TypeOf((*MockEKSServiceInterface)(nil).DeleteNodegroup), input)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeAddonVersions", reflect.TypeOf((*MockEKSServiceInterface)(nil).DescribeAddonVersions), ctx, input)
 }
 
 // DescribeCluster mocks base method.
-func (m *MockEKSServiceInterface) DescribeCluster(input *eks.DescribeClusterInput) (*eks.DescribeClusterOutput, error) {
+func (m *MockEKSServiceInterface) DescribeCluster(ctx context.Context, input *eks.DescribeClusterInput) (*eks.DescribeClusterOutput, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DescribeCluster", input)
+	ret := m.ctrl.Call(m, "DescribeCluster", ctx, input)
 	ret0, _ := ret[0].(*eks.DescribeClusterOutput)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // DescribeCluster indicates an expected call of DescribeCluster.
-func (mr *MockEKSServiceInterfaceMockRecorder) DescribeCluster(input interface{}) *gomock.Call {
+func (mr *MockEKSServiceInterfaceMockRecorder) DescribeCluster(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeCluster", reflect.TypeOf((*MockEKSServiceInterface)(nil).DescribeCluster), ctx, input)
+}
+
+// DescribeFargateProfile mocks base method.
+func (m *MockEKSServiceInterface) DescribeFargateProfile(ctx context.Context, input *eks.DescribeFargateProfileInput) (*eks.DescribeFargateProfileOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeFargateProfile", ctx, input)
+	ret0, _ := ret[0].(*eks.DescribeFargateProfileOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeFargateProfile indicates an expected call of DescribeFargateProfile.
+func (mr *MockEKSServiceInterfaceMockRecorder) DescribeFargateProfile(ctx, input interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeCluster", reflect.TypeOf((*MockEKSServiceInterface)(nil).DescribeCluster), input)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeFargateProfile", reflect.TypeOf((*MockEKSServiceInterface)(nil).DescribeFargateProfile), ctx, input)
 }
 
 // DescribeNodegroup mocks base method.
-func (m *MockEKSServiceInterface) DescribeNodegroup(input *eks.DescribeNodegroupInput) (*eks.DescribeNodegroupOutput, error) {
+func (m *MockEKSServiceInterface) DescribeNodegroup(ctx context.Context, input *eks.DescribeNodegroupInput) (*eks.DescribeNodegroupOutput, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DescribeNodegroup", input)
+	ret := m.ctrl.Call(m, "DescribeNodegroup", ctx, input)
 	ret0, _ := ret[0].(*eks.DescribeNodegroupOutput)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // DescribeNodegroup indicates an expected call of DescribeNodegroup.
-func (mr *MockEKSServiceInterfaceMockRecorder) DescribeNodegroup(input interface{}) *gomock.Call {
+func (mr *MockEKSServiceInterfaceMockRecorder) DescribeNodegroup(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeNodegroup", reflect.TypeOf((*MockEKSServiceInterface)(nil).DescribeNodegroup), ctx, input)
+}
+
+// DescribeUpdate mocks base method.
+func (m *MockEKSServiceInterface) DescribeUpdate(ctx context.Context, input *eks.DescribeUpdateInput) (*eks.DescribeUpdateOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeUpdate", ctx, input)
+	ret0, _ := ret[0].(*eks.DescribeUpdateOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeUpdate indicates an expected call of DescribeUpdate.
+func (mr *MockEKSServiceInterfaceMockRecorder) DescribeUpdate(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeUpdate", reflect.TypeOf((*MockEKSServiceInterface)(nil).DescribeUpdate), ctx, input)
+}
+
+// ListAccessEntries mocks base method.
+func (m *MockEKSServiceInterface) ListAccessEntries(ctx context.Context, input *eks.ListAccessEntriesInput) (*eks.ListAccessEntriesOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccessEntries", ctx, input)
+	ret0, _ := ret[0].(*eks.ListAccessEntriesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccessEntries indicates an expected call of ListAccessEntries.
+func (mr *MockEKSServiceInterfaceMockRecorder) ListAccessEntries(ctx, input interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeNodegroup", reflect.TypeOf((*MockEKSServiceInterface)(nil).DescribeNodegroup), input)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccessEntries", reflect.TypeOf((*MockEKSServiceInterface)(nil).ListAccessEntries), ctx, input)
 }
 
 // ListClusters mocks base method.
-func (m *MockEKSServiceInterface) ListClusters(input *eks.ListClustersInput) (*eks.ListClustersOutput, error) {
+func (m *MockEKSServiceInterface) ListClusters(ctx context.Context, input *eks.ListClustersInput) (*eks.ListClustersOutput, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListClusters", input)
+	ret := m.ctrl.Call(m, "ListClusters", ctx, input)
 	ret0, _ := ret[0].(*eks.ListClustersOutput)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListClusters indicates an expected call of ListClusters.
-func (mr *MockEKSServiceInterfaceMockRecorder) ListClusters(input interface{}) *gomock.Call {
+func (mr *MockEKSServiceInterfaceMockRecorder) ListClusters(ctx, input interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListClusters", reflect.TypeOf((*MockEKSServiceInterface)(nil).ListClusters), input)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListClusters", reflect.TypeOf((*MockEKSServiceInterface)(nil).ListClusters), ctx, input)
+}
+
+// ListClustersPages mocks base method.
+func (m *MockEKSServiceInterface) ListClustersPages(ctx context.Context, input *eks.ListClustersInput, fn func(*eks.ListClustersOutput, bool) bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListClustersPages", ctx, input, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ListClustersPages indicates an expected call of ListClustersPages.
+func (mr *MockEKSServiceInterfaceMockRecorder) ListClustersPages(ctx, input, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListClustersPages", reflect.TypeOf((*MockEKSServiceInterface)(nil).ListClustersPages), ctx, input, fn)
 }
 
 // ListNodegroups mocks base method.
-func (m *MockEKSServiceInterface) ListNodegroups(input *eks.ListNodegroupsInput) (*eks.ListNodegroupsOutput, error) {
+func (m *MockEKSServiceInterface) ListNodegroups(ctx context.Context, input *eks.ListNodegroupsInput) (*eks.ListNodegroupsOutput, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListNodegroups", input)
+	ret := m.ctrl.Call(m, "ListNodegroups", ctx, input)
 	ret0, _ := ret[0].(*eks.ListNodegroupsOutput)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListNodegroups indicates an expected call of ListNodegroups.
-func (mr *MockEKSServiceInterfaceMockRecorder) ListNodegroups(input interface{}) *gomock.Call {
+func (mr *MockEKSServiceInterfaceMockRecorder) ListNodegroups(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNodegroups", reflect.TypeOf((*MockEKSServiceInterface)(nil).ListNodegroups), ctx, input)
+}
+
+// ListTagsForResource mocks base method.
+func (m *MockEKSServiceInterface) ListTagsForResource(ctx context.Context, input *eks.ListTagsForResourceInput) (*eks.ListTagsForResourceOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTagsForResource", ctx, input)
+	ret0, _ := ret[0].(*eks.ListTagsForResourceOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTagsForResource indicates an expected call of ListTagsForResource.
+func (mr *MockEKSServiceInterfaceMockRecorder) ListTagsForResource(ctx, input interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNodegroups", reflect.TypeOf((*MockEKSServiceInterface)(nil).ListNodegroups), input)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTagsForResource", reflect.TypeOf((*MockEKSServiceInterface)(nil).ListTagsForResource), ctx, input)
+}
+
+// ListUpdates mocks base method.
+func (m *MockEKSServiceInterface) ListUpdates(ctx context.Context, input *eks.ListUpdatesInput) (*eks.ListUpdatesOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUpdates", ctx, input)
+	ret0, _ := ret[0].(*eks.ListUpdatesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUpdates indicates an expected call of ListUpdates.
+func (mr *MockEKSServiceInterfaceMockRecorder) ListUpdates(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUpdates", reflect.TypeOf((*MockEKSServiceInterface)(nil).ListUpdates), ctx, input)
 }
 
 // TagResource mocks base method.
-func (m *MockEKSServiceInterface) TagResource(input *eks.TagResourceInput) (*eks.TagResourceOutput, error) {
+func (m *MockEKSServiceInterface) TagResource(ctx context.Context, input *eks.TagResourceInput) (*eks.TagResourceOutput, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "TagResource", input)
+	ret := m.ctrl.Call(m, "TagResource", ctx, input)
 	ret0, _ := ret[0].(*eks.TagResourceOutput)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // TagResource indicates an expected call of TagResource.
-func (mr *MockEKSServiceInterfaceMockRecorder) TagResource(input interface{}) *gomock.Call {
+func (mr *MockEKSServiceInterfaceMockRecorder) TagResource(ctx, input interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TagResource", reflect.TypeOf((*MockEKSServiceInterface)(nil).TagResource), input)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TagResource", reflect.TypeOf((*MockEKSServiceInterface)(nil).TagResource), ctx, input)
 }
 
 // UntagResource mocks base method.
-func (m *MockEKSServiceInterface) UntagResource(input *eks.UntagResourceInput) (*eks.UntagResourceOutput, error) {
+func (m *MockEKSServiceInterface) UntagResource(ctx context.Context, input *eks.UntagResourceInput) (*eks.UntagResourceOutput, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UntagResource", input)
+	ret := m.ctrl.Call(m, "UntagResource", ctx, input)
 	ret0, _ := ret[0].(*eks.UntagResourceOutput)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UntagResource indicates an expected call of UntagResource.
-func (mr *MockEKSServiceInterfaceMockRecorder) UntagResource(input interface{}) *gomock.Call {
+func (mr *MockEKSServiceInterfaceMockRecorder) UntagResource(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UntagResource", reflect.TypeOf((*MockEKSServiceInterface)(nil).UntagResource), ctx, input)
+}
+
+// UpdateAddon mocks base method.
+func (m *MockEKSServiceInterface) UpdateAddon(ctx context.Context, input *eks.UpdateAddonInput) (*eks.UpdateAddonOutput, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAddon", ctx, input)
+	ret0, _ := ret[0].(*eks.UpdateAddonOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAddon indicates an expected call of UpdateAddon.
+func (mr *MockEKSServiceInterfaceMockRecorder) UpdateAddon(ctx, input interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UntagResource", reflect.TypeOf((*MockEKSServiceInterface)(nil).UntagResource), input)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAddon", reflect.TypeOf((*MockEKSServiceInterface)(nil).UpdateAddon), ctx, input)
 }
 
 // UpdateClusterConfig mocks base method.
-func (m *MockEKSServiceInterface) UpdateClusterConfig(input *eks.UpdateClusterConfigInput) (*eks.UpdateClusterConfigOutput, error) {
+func (m *MockEKSServiceInterface) UpdateClusterConfig(ctx context.Context, input *eks.UpdateClusterConfigInput) (*eks.UpdateClusterConfigOutput, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateClusterConfig", input)
+	ret := m.ctrl.Call(m, "UpdateClusterConfig", ctx, input)
 	ret0, _ := ret[0].(*eks.UpdateClusterConfigOutput)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateClusterConfig indicates an expected call of UpdateClusterConfig.
-func (mr *MockEKSServiceInterfaceMockRecorder) UpdateClusterConfig(input interface{}) *gomock.Call {
+func (mr *MockEKSServiceInterfaceMockRecorder) UpdateClusterConfig(ctx, input interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateClusterConfig", reflect.TypeOf((*MockEKSServiceInterface)(nil).UpdateClusterConfig), input)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateClusterConfig", reflect.TypeOf((*MockEKSServiceInterface)(nil).UpdateClusterConfig), ctx, input)
 }
 
 // UpdateClusterVersion mocks base method.
-func (m *MockEKSServiceInterface) UpdateClusterVersion(input *eks.UpdateClusterVersionInput) (*eks.UpdateClusterVersionOutput, error) {
+func (m *MockEKSServiceInterface) UpdateClusterVersion(ctx context.Context, input *eks.UpdateClusterVersionInput) (*eks.UpdateClusterVersionOutput, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateClusterVersion", input)
+	ret := m.ctrl.Call(m, "UpdateClusterVersion", ctx, input)
 	ret0, _ := ret[0].(*eks.UpdateClusterVersionOutput)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateClusterVersion indicates an expected call of UpdateClusterVersion.
-func (mr *MockEKSServiceInterfaceMockRecorder) UpdateClusterVersion(input interface{}) *gomock.Call {
+func (mr *MockEKSServiceInterfaceMockRecorder) UpdateClusterVersion(ctx, input interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateClusterVersion", reflect.TypeOf((*MockEKSServiceInterface)(nil).UpdateClusterVersion), input)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateClusterVersion", reflect.TypeOf((*MockEKSServiceInterface)(nil).UpdateClusterVersion), ctx, input)
 }
 
 // UpdateNodegroupConfig mocks base method.
-func (m *MockEKSServiceInterface) UpdateNodegroupConfig(input *eks.UpdateNodegroupConfigInput) (*eks.UpdateNodegroupConfigOutput, error) {
+func (m *MockEKSServiceInterface) UpdateNodegroupConfig(ctx context.Context, input *eks.UpdateNodegroupConfigInput) (*eks.UpdateNodegroupConfigOutput, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateNodegroupConfig", input)
+	ret := m.ctrl.Call(m, "UpdateNodegroupConfig", ctx, input)
 	ret0, _ := ret[0].(*eks.UpdateNodegroupConfigOutput)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateNodegroupConfig indicates an expected call of UpdateNodegroupConfig.
-func (mr *MockEKSServiceInterfaceMockRecorder) UpdateNodegroupConfig(input interface{}) *gomock.Call {
+func (mr *MockEKSServiceInterfaceMockRecorder) UpdateNodegroupConfig(ctx, input interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNodegroupConfig", reflect.TypeOf((*MockEKSServiceInterface)(nil).UpdateNodegroupConfig), input)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNodegroupConfig", reflect.TypeOf((*MockEKSServiceInterface)(nil).UpdateNodegroupConfig), ctx, input)
 }
 
 // UpdateNodegroupVersion mocks base method.
-func (m *MockEKSServiceInterface) UpdateNodegroupVersion(input *eks.UpdateNodegroupVersionInput) (*eks.UpdateNodegroupVersionOutput, error) {
+func (m *MockEKSServiceInterface) UpdateNodegroupVersion(ctx context.Context, input *eks.UpdateNodegroupVersionInput) (*eks.UpdateNodegroupVersionOutput, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateNodegroupVersion", input)
+	ret := m.ctrl.Call(m, "UpdateNodegroupVersion", ctx, input)
 	ret0, _ := ret[0].(*eks.UpdateNodegroupVersionOutput)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // UpdateNodegroupVersion indicates an expected call of UpdateNodegroupVersion.
-func (mr *MockEKSServiceInterfaceMockRecorder) UpdateNodegroupVersion(input interface{}) *gomock.Call {
+func (mr *MockEKSServiceInterfaceMockRecorder) UpdateNodegroupVersion(ctx, input interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNodegroupVersion", reflect.TypeOf((*MockEKSServiceInterface)(nil).UpdateNodegroupVersion), input)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNodegroupVersion", reflect.TypeOf((*MockEKSServiceInterface)(nil).UpdateNodegroupVersion), ctx, input)
 }
@@ -0,0 +1,157 @@
+package eks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/pkg/eks/services"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// SubnetCapacityCheckWarn logs node groups whose subnets are short of IP addresses, it is the default.
+	SubnetCapacityCheckWarn = "Warn"
+	// SubnetCapacityCheckError refuses to create or scale up node groups whose subnets are short of IP addresses.
+	SubnetCapacityCheckError = "Error"
+	// SubnetCapacityCheckDisabled skips the check.
+	SubnetCapacityCheckDisabled = "Disabled"
+
+	// defaultExpectedPodsPerNode is the pod density assumed for node groups that do not set expectedPodsPerNode.
+	defaultExpectedPodsPerNode = 17
+)
+
+// SubnetCapacity is the number of IP addresses left in a subnet.
+type SubnetCapacity struct {
+	SubnetID     string
+	AvailableIPs int64
+	RequiredIPs  int64
+}
+
+// NodegroupSubnetCapacityReport compares the IP addresses left in the subnets of a node group with the addresses
+// the node group needs at its maximum size.
+type NodegroupSubnetCapacityReport struct {
+	NodegroupName string
+	Subnets       []SubnetCapacity
+}
+
+// Sufficient returns whether every subnet of the node group has enough IP addresses left.
+func (r *NodegroupSubnetCapacityReport) Sufficient() bool {
+	for _, subnet := range r.Subnets {
+		if subnet.AvailableIPs < subnet.RequiredIPs {
+			return false
+		}
+	}
+	return true
+}
+
+// Message returns a message listing the subnets that are short of IP addresses.
+func (r *NodegroupSubnetCapacityReport) Message() string {
+	var short []string
+	for _, subnet := range r.Subnets {
+		if subnet.AvailableIPs < subnet.RequiredIPs {
+			short = append(short, fmt.Sprintf("%s has %d available IPs, %d required", subnet.SubnetID, subnet.AvailableIPs, subnet.RequiredIPs))
+		}
+	}
+	if len(short) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("nodegroup [%s]: subnets are short of IP addresses: %s", r.NodegroupName, strings.Join(short, "; "))
+}
+
+type CheckNodegroupSubnetCapacityOpts struct {
+	EC2Service services.EC2ServiceInterface
+	Config     *eksv1.EKSClusterConfig
+	NodeGroup  *eksv1.NodeGroup
+	Subnets    []string
+	// RunningNodes is the number of nodes the node group already runs, their addresses are already taken.
+	RunningNodes int64
+}
+
+// CheckNodegroupSubnetCapacity makes sure the subnets of a node group have enough IP addresses left for it to reach
+// its maximum size, on top of the nodes it already runs. With the vpc-cni every pod takes an address of the subnet of
+// its node, on top of the address of the node itself, and the warm pool of the plugin holds more. A node group running
+// out of addresses fails to scale with an "insufficient free addresses" error that is hard to trace back, so it is
+// reported beforehand. The nodes are assumed to be balanced over the subnets, as the auto scaling group of the node
+// group spreads them across zones. Depending on subnetCapacityCheck a shortage is logged or returned as an error.
+func CheckNodegroupSubnetCapacity(ctx context.Context, opts *CheckNodegroupSubnetCapacityOpts) error {
+	if err := ValidateSubnetCapacityCheck(opts.Config); err != nil {
+		return err
+	}
+	mode := aws.StringValue(opts.Config.Spec.SubnetCapacityCheck)
+	if mode == "" {
+		mode = SubnetCapacityCheckWarn
+	}
+	if mode == SubnetCapacityCheckDisabled {
+		return nil
+	}
+	if len(opts.Subnets) == 0 {
+		return nil
+	}
+
+	report, err := getNodegroupSubnetCapacity(ctx, opts)
+	if err != nil {
+		return err
+	}
+	if report.Sufficient() {
+		return nil
+	}
+	if mode == SubnetCapacityCheckError {
+		return errors.New(report.Message())
+	}
+	logrus.Warnf("cluster [%s] %s", opts.Config.Name, report.Message())
+	return nil
+}
+
+// ValidateSubnetCapacityCheck ensures the subnet capacity check of the cluster, if set, is a supported mode.
+func ValidateSubnetCapacityCheck(config *eksv1.EKSClusterConfig) error {
+	switch mode := aws.StringValue(config.Spec.SubnetCapacityCheck); mode {
+	case "", SubnetCapacityCheckWarn, SubnetCapacityCheckError, SubnetCapacityCheckDisabled:
+		return nil
+	default:
+		return fmt.Errorf("subnetCapacityCheck [%s] for cluster [%s] must be one of [%s, %s, %s]", mode, config.Name,
+			SubnetCapacityCheckWarn, SubnetCapacityCheckError, SubnetCapacityCheckDisabled)
+	}
+}
+
+func getNodegroupSubnetCapacity(ctx context.Context, opts *CheckNodegroupSubnetCapacityOpts) (*NodegroupSubnetCapacityReport, error) {
+	ngName := aws.StringValue(opts.NodeGroup.NodegroupName)
+	output, err := opts.EC2Service.DescribeSubnets(ctx, &ec2.DescribeSubnetsInput{
+		SubnetIds: aws.StringSlice(opts.Subnets),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error describing subnets of nodegroup [%s]: %w", ngName, err)
+	}
+
+	podsPerNode := int64(defaultExpectedPodsPerNode)
+	if opts.NodeGroup.ExpectedPodsPerNode != nil {
+		podsPerNode = aws.Int64Value(opts.NodeGroup.ExpectedPodsPerNode)
+	}
+	report := &NodegroupSubnetCapacityReport{
+		NodegroupName: ngName,
+	}
+	subnetCount := int64(len(output.Subnets))
+	if subnetCount == 0 {
+		return report, nil
+	}
+	newNodes := aws.Int64Value(opts.NodeGroup.MaxSize) - opts.RunningNodes
+	if newNodes < 0 {
+		newNodes = 0
+	}
+	// round up, the subnets that receive an extra node need the addresses for it
+	nodesPerSubnet := (newNodes + subnetCount - 1) / subnetCount
+	requiredPerSubnet := nodesPerSubnet * (1 + podsPerNode)
+	for _, subnet := range output.Subnets {
+		report.Subnets = append(report.Subnets, SubnetCapacity{
+			SubnetID:     aws.StringValue(subnet.SubnetId),
+			AvailableIPs: aws.Int64Value(subnet.AvailableIpAddressCount),
+			RequiredIPs:  requiredPerSubnet,
+		})
+	}
+
+	return report, nil
+}
@@ -0,0 +1,136 @@
+package eks
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/pkg/eks/services/mock_services"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("CheckNodegroupSubnetCapacity", func() {
+	var (
+		mockController *gomock.Controller
+		ec2ServiceMock *mock_services.MockEC2ServiceInterface
+		opts           *CheckNodegroupSubnetCapacityOpts
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		ec2ServiceMock = mock_services.NewMockEC2ServiceInterface(mockController)
+		opts = &CheckNodegroupSubnetCapacityOpts{
+			EC2Service: ec2ServiceMock,
+			Config: &eksv1.EKSClusterConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: eksv1.EKSClusterConfigSpec{
+					SubnetCapacityCheck: aws.String(SubnetCapacityCheckError),
+				},
+			},
+			NodeGroup: &eksv1.NodeGroup{
+				NodegroupName:       aws.String("ng1"),
+				MaxSize:             aws.Int64(5),
+				ExpectedPodsPerNode: aws.Int64(29),
+			},
+			Subnets: []string{"subnet-a", "subnet-b"},
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	expectSubnets := func(availableA, availableB int64) {
+		ec2ServiceMock.EXPECT().DescribeSubnets(gomock.Any(), &ec2.DescribeSubnetsInput{
+			SubnetIds: aws.StringSlice([]string{"subnet-a", "subnet-b"}),
+		}).Return(&ec2.DescribeSubnetsOutput{
+			Subnets: []*ec2.Subnet{
+				{SubnetId: aws.String("subnet-a"), AvailableIpAddressCount: aws.Int64(availableA)},
+				{SubnetId: aws.String("subnet-b"), AvailableIpAddressCount: aws.Int64(availableB)},
+			},
+		}, nil)
+	}
+
+	It("should accept subnets with enough IP addresses", func() {
+		// 3 nodes per subnet with 30 addresses each
+		expectSubnets(90, 250)
+
+		Expect(CheckNodegroupSubnetCapacity(context.Background(), opts)).To(Succeed())
+	})
+
+	It("should reject subnets short of IP addresses", func() {
+		expectSubnets(89, 250)
+
+		err := CheckNodegroupSubnetCapacity(context.Background(), opts)
+		Expect(err).To(MatchError("nodegroup [ng1]: subnets are short of IP addresses: subnet-a has 89 available IPs, 90 required"))
+	})
+
+	It("should only count the nodes that are not running yet", func() {
+		opts.RunningNodes = 3
+		// 1 new node per subnet
+		expectSubnets(30, 30)
+
+		Expect(CheckNodegroupSubnetCapacity(context.Background(), opts)).To(Succeed())
+	})
+
+	It("should assume the default pod density", func() {
+		opts.NodeGroup.ExpectedPodsPerNode = nil
+		expectSubnets(3*(1+defaultExpectedPodsPerNode)-1, 250)
+
+		Expect(CheckNodegroupSubnetCapacity(context.Background(), opts)).ToNot(Succeed())
+	})
+
+	It("should only warn by default", func() {
+		opts.Config.Spec.SubnetCapacityCheck = nil
+		expectSubnets(10, 10)
+
+		Expect(CheckNodegroupSubnetCapacity(context.Background(), opts)).To(Succeed())
+	})
+
+	It("should skip the check when disabled", func() {
+		opts.Config.Spec.SubnetCapacityCheck = aws.String(SubnetCapacityCheckDisabled)
+
+		Expect(CheckNodegroupSubnetCapacity(context.Background(), opts)).To(Succeed())
+	})
+
+	It("should reject an unknown mode", func() {
+		opts.Config.Spec.SubnetCapacityCheck = aws.String("Strict")
+
+		Expect(CheckNodegroupSubnetCapacity(context.Background(), opts)).To(MatchError(ContainSubstring("subnetCapacityCheck [Strict] for cluster [test] must be one of")))
+	})
+
+	It("should fail if describing the subnets fails", func() {
+		ec2ServiceMock.EXPECT().DescribeSubnets(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+		Expect(CheckNodegroupSubnetCapacity(context.Background(), opts)).ToNot(Succeed())
+	})
+})
+
+var _ = Describe("ValidateSubnetCapacityCheck", func() {
+	DescribeTable("should validate the subnet capacity check",
+		func(mode *string, valid bool) {
+			err := ValidateSubnetCapacityCheck(&eksv1.EKSClusterConfig{
+				Spec: eksv1.EKSClusterConfigSpec{
+					SubnetCapacityCheck: mode,
+				},
+			})
+			if valid {
+				Expect(err).ToNot(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(ContainSubstring("must be one of")))
+			}
+		},
+		Entry("not set", nil, true),
+		Entry("Warn", aws.String(SubnetCapacityCheckWarn), true),
+		Entry("Error", aws.String(SubnetCapacityCheckError), true),
+		Entry("Disabled", aws.String(SubnetCapacityCheckDisabled), true),
+		Entry("unknown mode", aws.String("warn"), false),
+	)
+})
@@ -0,0 +1,223 @@
+package eks
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/utils"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	maxTagKeyLength    = 128
+	maxTagValueLength  = 256
+	reservedTagPrefix  = "aws:"
+	tagReplacementRune = '_'
+
+	// kubernetesClusterTagPrefix is the prefix of the ownership tags Kubernetes components apply to the resources of
+	// a cluster.
+	kubernetesClusterTagPrefix = "kubernetes.io/cluster/"
+)
+
+// systemTagPrefixes are the prefixes of the tags AWS and Kubernetes apply to resources on their own. They are left
+// out of the tag diffs, untagging them fails or is undone, which would otherwise make every reconcile update the tags.
+var systemTagPrefixes = []string{reservedTagPrefix, kubernetesClusterTagPrefix}
+
+var tagCharactersRegex = regexp.MustCompile(`^[\p{L}\p{Z}\p{N}_.:/=+\-@]*$`)
+
+// ValidateTags checks the cluster and node group tags against the limits AWS enforces on every taggable resource.
+// When normalizeTags is enabled the tags are not rejected, the changes that will be applied to them are logged instead.
+// Cluster and node group tags with the prefix of the Kubernetes ownership tags are rejected either way, they are left
+// out of the tag diffs and would never be applied. Resource and volume tags are set through the launch template and
+// can carry them.
+func ValidateTags(config *eksv1.EKSClusterConfig) error {
+	errs := kubernetesClusterTagErrors(config.Spec.Tags)
+	for _, ng := range config.Spec.NodeGroups {
+		for _, err := range kubernetesClusterTagErrors(aws.StringValueMap(ng.Tags)) {
+			errs = append(errs, fmt.Sprintf("nodegroup [%s]: %s", aws.StringValue(ng.NodegroupName), err))
+		}
+	}
+	if len(errs) != 0 {
+		return fmt.Errorf("invalid tags for cluster [%s]: %s", config.Name, strings.Join(errs, "; "))
+	}
+
+	if aws.BoolValue(config.Spec.NormalizeTags) {
+		logTagChanges(config.Name, "cluster", config.Spec.Tags)
+		for _, ng := range config.Spec.NodeGroups {
+			name := aws.StringValue(ng.NodegroupName)
+			logTagChanges(config.Name, fmt.Sprintf("nodegroup [%s]", name), aws.StringValueMap(ng.Tags))
+			logTagChanges(config.Name, fmt.Sprintf("nodegroup [%s] resource", name), aws.StringValueMap(ng.ResourceTags))
+			logTagChanges(config.Name, fmt.Sprintf("nodegroup [%s] volume", name), aws.StringValueMap(ng.VolumeTags))
+		}
+		return nil
+	}
+
+	errs = validateTagMap(config.Spec.Tags)
+	for _, ng := range config.Spec.NodeGroups {
+		for _, tags := range []map[string]*string{ng.Tags, ng.ResourceTags, ng.VolumeTags} {
+			for _, err := range validateTagMap(aws.StringValueMap(tags)) {
+				errs = append(errs, fmt.Sprintf("nodegroup [%s]: %s", aws.StringValue(ng.NodegroupName), err))
+			}
+		}
+	}
+	if len(errs) != 0 {
+		return fmt.Errorf("invalid tags for cluster [%s]: %s; enable normalizeTags to have them adjusted automatically",
+			config.Name, strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// GetNormalizedTags returns the tags that are sent to AWS for the given cluster. They are returned unchanged unless
+// normalizeTags is enabled.
+func GetNormalizedTags(config *eksv1.EKSClusterConfig, tags map[string]string) map[string]string {
+	if !aws.BoolValue(config.Spec.NormalizeTags) || tags == nil {
+		return tags
+	}
+	normalized, _ := normalizeTags(tags)
+	return normalized
+}
+
+// GetNormalizedNodeGroup returns a copy of the node group whose tags, resource tags and volume tags are normalized
+// the same way as the cluster tags, so that they compare equal to what was applied in AWS.
+func GetNormalizedNodeGroup(config *eksv1.EKSClusterConfig, ng eksv1.NodeGroup) eksv1.NodeGroup {
+	if !aws.BoolValue(config.Spec.NormalizeTags) {
+		return ng
+	}
+	normalizePointerMap := func(tags map[string]*string) map[string]*string {
+		if tags == nil {
+			return nil
+		}
+		return aws.StringMap(GetNormalizedTags(config, aws.StringValueMap(tags)))
+	}
+	ng.Tags = normalizePointerMap(ng.Tags)
+	ng.ResourceTags = normalizePointerMap(ng.ResourceTags)
+	ng.VolumeTags = normalizePointerMap(ng.VolumeTags)
+	return ng
+}
+
+func validateTagMap(tags map[string]string) []string {
+	errs := []string{}
+	for _, key := range sortedKeys(tags) {
+		value := tags[key]
+		switch {
+		case key == "" || len([]rune(key)) > maxTagKeyLength:
+			errs = append(errs, fmt.Sprintf("tag key [%s] must be between 1 and %d characters", key, maxTagKeyLength))
+		case strings.HasPrefix(strings.ToLower(key), reservedTagPrefix):
+			errs = append(errs, fmt.Sprintf("tag key [%s] cannot start with the reserved prefix [%s]", key, reservedTagPrefix))
+		case !tagCharactersRegex.MatchString(key):
+			errs = append(errs, fmt.Sprintf("tag key [%s] contains characters that are not allowed", key))
+		}
+		switch {
+		case len([]rune(value)) > maxTagValueLength:
+			errs = append(errs, fmt.Sprintf("value of tag [%s] must be at most %d characters", key, maxTagValueLength))
+		case !tagCharactersRegex.MatchString(value):
+			errs = append(errs, fmt.Sprintf("value of tag [%s] contains characters that are not allowed", key))
+		}
+	}
+	return errs
+}
+
+func kubernetesClusterTagErrors(tags map[string]string) []string {
+	var errs []string
+	for _, key := range sortedKeys(tags) {
+		if strings.HasPrefix(strings.ToLower(key), kubernetesClusterTagPrefix) {
+			errs = append(errs, fmt.Sprintf("tag key [%s] cannot start with the prefix [%s] of the Kubernetes ownership tags", key, kubernetesClusterTagPrefix))
+		}
+	}
+	return errs
+}
+
+// normalizeTags trims surrounding whitespace, replaces disallowed characters, strips the reserved aws: prefix and
+// truncates keys and values to the AWS limits. Keys that end up empty are dropped and, if two keys end up the same,
+// the first one in sorted order wins. The returned changes describe every tag that was altered.
+func normalizeTags(tags map[string]string) (map[string]string, []string) {
+	normalized := make(map[string]string, len(tags))
+	changes := []string{}
+	for _, key := range sortedKeys(tags) {
+		value := tags[key]
+		newKey := normalizeTagString(key, maxTagKeyLength)
+		for strings.HasPrefix(strings.ToLower(newKey), reservedTagPrefix) {
+			newKey = strings.TrimSpace(newKey[len(reservedTagPrefix):])
+		}
+		newValue := normalizeTagString(value, maxTagValueLength)
+		_, exists := normalized[newKey]
+
+		switch {
+		case newKey == "":
+			changes = append(changes, fmt.Sprintf("dropped tag [%s] with an empty key", key))
+			continue
+		case exists:
+			changes = append(changes, fmt.Sprintf("dropped tag [%s] which conflicts with tag [%s]", key, newKey))
+			continue
+		case newKey != key:
+			changes = append(changes, fmt.Sprintf("changed tag key [%s] to [%s]", key, newKey))
+		}
+		if newValue != value {
+			changes = append(changes, fmt.Sprintf("changed value of tag [%s] from [%s] to [%s]", newKey, value, newValue))
+		}
+		normalized[newKey] = newValue
+	}
+	return normalized, changes
+}
+
+func normalizeTagString(s string, maxLength int) string {
+	s = strings.Map(func(r rune) rune {
+		if tagCharactersRegex.MatchString(string(r)) {
+			return r
+		}
+		return tagReplacementRune
+	}, strings.TrimSpace(s))
+	if runes := []rune(s); len(runes) > maxLength {
+		s = strings.TrimSpace(string(runes[:maxLength]))
+	}
+	return s
+}
+
+func logTagChanges(clusterName, resource string, tags map[string]string) {
+	_, changes := normalizeTags(tags)
+	for _, change := range changes {
+		logrus.Infof("normalizing %s tags of cluster [%s]: %s", resource, clusterName, change)
+	}
+}
+
+// diffTags returns the tags to add or change and the keys of the tags to remove on a resource to match the desired
+// tags, ignoring system tags.
+func diffTags(tags, upstreamTags map[string]string) (map[string]*string, []*string) {
+	return utils.DiffTags(withoutSystemTags(tags), withoutSystemTags(upstreamTags))
+}
+
+func withoutSystemTags(tags map[string]string) map[string]string {
+	if tags == nil {
+		return nil
+	}
+	filtered := make(map[string]string, len(tags))
+	for key, value := range tags {
+		if !isSystemTag(key) {
+			filtered[key] = value
+		}
+	}
+	return filtered
+}
+
+func isSystemTag(key string) bool {
+	for _, prefix := range systemTagPrefixes {
+		if strings.HasPrefix(strings.ToLower(key), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,187 @@
+package eks
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("ValidateTags", func() {
+	var config *eksv1.EKSClusterConfig
+
+	BeforeEach(func() {
+		config = &eksv1.EKSClusterConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test",
+			},
+			Spec: eksv1.EKSClusterConfigSpec{
+				Tags: map[string]string{"team": "platform", "cost-center": "a1/b2"},
+				NodeGroups: []eksv1.NodeGroup{
+					{
+						NodegroupName: aws.String("ng1"),
+						Tags:          aws.StringMap(map[string]string{"owner": "john.doe@example.com"}),
+						ResourceTags:  aws.StringMap(map[string]string{"Name": "worker"}),
+					},
+				},
+			},
+		}
+	})
+
+	It("should accept valid tags", func() {
+		Expect(ValidateTags(config)).To(Succeed())
+	})
+
+	DescribeTable("should reject invalid cluster tags in strict mode",
+		func(key, value, expectedError string) {
+			config.Spec.Tags[key] = value
+			Expect(ValidateTags(config)).To(MatchError(ContainSubstring(expectedError)))
+		},
+		Entry("empty key", "", "value", "tag key [] must be between 1 and 128 characters"),
+		Entry("long key", strings.Repeat("k", 129), "value", "must be between 1 and 128 characters"),
+		Entry("reserved prefix", "aws:team", "value", "tag key [aws:team] cannot start with the reserved prefix [aws:]"),
+		Entry("invalid key characters", "team#1", "value", "tag key [team#1] contains characters that are not allowed"),
+		Entry("long value", "key", strings.Repeat("v", 257), "value of tag [key] must be at most 256 characters"),
+		Entry("invalid value characters", "key", "a,b", "value of tag [key] contains characters that are not allowed"),
+		Entry("ownership tag", "kubernetes.io/cluster/test", "owned", "tag key [kubernetes.io/cluster/test] cannot start with the prefix [kubernetes.io/cluster/]"),
+	)
+
+	It("should reject invalid node group tags in strict mode", func() {
+		config.Spec.NodeGroups[0].VolumeTags = aws.StringMap(map[string]string{"disk*": "data"})
+
+		Expect(ValidateTags(config)).To(MatchError(ContainSubstring("nodegroup [ng1]: tag key [disk*] contains characters that are not allowed")))
+	})
+
+	It("should accept invalid tags when normalization is enabled", func() {
+		config.Spec.NormalizeTags = aws.Bool(true)
+		config.Spec.Tags["aws:team#1"] = "a,b"
+
+		Expect(ValidateTags(config)).To(Succeed())
+	})
+
+	It("should reject Kubernetes ownership tags when normalization is enabled", func() {
+		config.Spec.NormalizeTags = aws.Bool(true)
+		config.Spec.NodeGroups[0].Tags["Kubernetes.io/cluster/other"] = aws.String("owned")
+
+		Expect(ValidateTags(config)).To(MatchError(ContainSubstring(
+			"nodegroup [ng1]: tag key [Kubernetes.io/cluster/other] cannot start with the prefix [kubernetes.io/cluster/]")))
+	})
+
+	It("should accept Kubernetes ownership tags in node group resource tags", func() {
+		config.Spec.NodeGroups[0].ResourceTags["kubernetes.io/cluster/test"] = aws.String("owned")
+
+		Expect(ValidateTags(config)).To(Succeed())
+	})
+})
+
+var _ = Describe("normalizeTags", func() {
+	It("should trim, replace, strip the reserved prefix and truncate", func() {
+		normalized, changes := normalizeTags(map[string]string{
+			" team ":                 " platform ",
+			"cost#center":            "a,b",
+			"aws:owner":              "ops",
+			strings.Repeat("k", 130): strings.Repeat("v", 300),
+			"valid":                  "value",
+		})
+
+		Expect(normalized).To(Equal(map[string]string{
+			"team":                   "platform",
+			"cost_center":            "a_b",
+			"owner":                  "ops",
+			strings.Repeat("k", 128): strings.Repeat("v", 256),
+			"valid":                  "value",
+		}))
+		Expect(changes).To(HaveLen(7))
+		Expect(changes).To(ContainElement("changed tag key [aws:owner] to [owner]"))
+		Expect(changes).To(ContainElement("changed value of tag [cost_center] from [a,b] to [a_b]"))
+	})
+
+	It("should drop empty and conflicting keys", func() {
+		normalized, changes := normalizeTags(map[string]string{
+			"   ":       "empty",
+			"team#":     "first",
+			"team_":     "second",
+			"aws:":      "reserved",
+			"unchanged": "value",
+		})
+
+		Expect(normalized).To(Equal(map[string]string{
+			"team_":     "first",
+			"unchanged": "value",
+		}))
+		Expect(changes).To(ConsistOf(
+			"dropped tag [   ] with an empty key",
+			"dropped tag [aws:] with an empty key",
+			"changed tag key [team#] to [team_]",
+			"dropped tag [team_] which conflicts with tag [team_]",
+		))
+	})
+
+	It("should leave valid tags unchanged", func() {
+		tags := map[string]string{"team": "platform", "path": "a/b:c=d+e-f@g"}
+
+		normalized, changes := normalizeTags(tags)
+		Expect(normalized).To(Equal(tags))
+		Expect(changes).To(BeEmpty())
+	})
+})
+
+var _ = Describe("GetNormalizedNodeGroup", func() {
+	var (
+		config    *eksv1.EKSClusterConfig
+		nodeGroup eksv1.NodeGroup
+	)
+
+	BeforeEach(func() {
+		config = &eksv1.EKSClusterConfig{}
+		nodeGroup = eksv1.NodeGroup{
+			NodegroupName: aws.String("ng1"),
+			Tags:          aws.StringMap(map[string]string{"team ": "platform"}),
+			ResourceTags:  aws.StringMap(map[string]string{"Name": "a,b"}),
+		}
+	})
+
+	It("should not change the node group in strict mode", func() {
+		Expect(GetNormalizedNodeGroup(config, nodeGroup)).To(Equal(nodeGroup))
+		Expect(GetNormalizedTags(config, map[string]string{"team ": "platform"})).To(Equal(map[string]string{"team ": "platform"}))
+	})
+
+	It("should normalize all node group tags without modifying the original", func() {
+		config.Spec.NormalizeTags = aws.Bool(true)
+
+		normalized := GetNormalizedNodeGroup(config, nodeGroup)
+		Expect(aws.StringValueMap(normalized.Tags)).To(Equal(map[string]string{"team": "platform"}))
+		Expect(aws.StringValueMap(normalized.ResourceTags)).To(Equal(map[string]string{"Name": "a_b"}))
+		Expect(normalized.VolumeTags).To(BeNil())
+		Expect(aws.StringValueMap(nodeGroup.Tags)).To(Equal(map[string]string{"team ": "platform"}))
+	})
+})
+
+var _ = Describe("tag diff", func() {
+	upstreamTags := map[string]string{
+		"team":                               "a",
+		"stale":                              "b",
+		"aws:cloudformation:stack-name":      "test-stack",
+		"AWS:eks:cluster-name":               "test",
+		"kubernetes.io/cluster/test-cluster": "owned",
+	}
+
+	It("should never untag system tags", func() {
+		_, untags := diffTags(map[string]string{"team": "a"}, upstreamTags)
+		Expect(untags).To(Equal([]*string{aws.String("stale")}))
+		_, untags = diffTags(map[string]string{}, upstreamTags)
+		Expect(untags).To(ConsistOf(aws.String("team"), aws.String("stale")))
+	})
+
+	It("should never tag system tags", func() {
+		tags := map[string]string{
+			"team":                          "c",
+			"aws:cloudformation:stack-name": "other-stack",
+		}
+		updateTags, _ := diffTags(tags, upstreamTags)
+		Expect(updateTags).To(Equal(map[string]*string{"team": aws.String("c")}))
+	})
+})
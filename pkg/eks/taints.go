@@ -0,0 +1,100 @@
+package eks
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eks"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+)
+
+// taintID identifies a taint the same way kubernetes does, a key can be used once per effect.
+type taintID struct {
+	key    string
+	effect string
+}
+
+func validateTaints(taints []eksv1.Taint) error {
+	for _, taint := range taints {
+		if aws.StringValue(taint.Key) == "" {
+			return fmt.Errorf("taint key cannot be empty")
+		}
+
+		valid := false
+		for _, effect := range eks.TaintEffect_Values() {
+			if aws.StringValue(taint.Effect) == effect {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return fmt.Errorf("taint [%s] has invalid effect [%s], must be one of %v",
+				aws.StringValue(taint.Key), aws.StringValue(taint.Effect), eks.TaintEffect_Values())
+		}
+	}
+
+	return nil
+}
+
+func toEKSTaints(taints []eksv1.Taint) []*eks.Taint {
+	if taints == nil {
+		return nil
+	}
+
+	eksTaints := make([]*eks.Taint, 0, len(taints))
+	for _, taint := range taints {
+		eksTaints = append(eksTaints, &eks.Taint{
+			Key:    taint.Key,
+			Value:  taint.Value,
+			Effect: taint.Effect,
+		})
+	}
+
+	return eksTaints
+}
+
+// FromEKSTaints converts the taints of an upstream node group to node group taints.
+func FromEKSTaints(eksTaints []*eks.Taint) []eksv1.Taint {
+	if eksTaints == nil {
+		return nil
+	}
+
+	taints := make([]eksv1.Taint, 0, len(eksTaints))
+	for _, taint := range eksTaints {
+		taints = append(taints, eksv1.Taint{
+			Key:    taint.Key,
+			Value:  taint.Value,
+			Effect: taint.Effect,
+		})
+	}
+
+	return taints
+}
+
+// getTaintsToUpdate returns the taints to add or update and the taints to remove to get from the upstream taints
+// to the desired taints. Changing the effect of a key removes the taint with the previous effect.
+func getTaintsToUpdate(taints, upstreamTaints []eksv1.Taint) ([]*eks.Taint, []*eks.Taint) {
+	upstreamValues := make(map[taintID]string, len(upstreamTaints))
+	for _, taint := range upstreamTaints {
+		upstreamValues[taintID{aws.StringValue(taint.Key), aws.StringValue(taint.Effect)}] = aws.StringValue(taint.Value)
+	}
+
+	desired := make(map[taintID]bool, len(taints))
+	var addOrUpdateTaints []*eks.Taint
+	for _, taint := range taints {
+		id := taintID{aws.StringValue(taint.Key), aws.StringValue(taint.Effect)}
+		desired[id] = true
+		if value, ok := upstreamValues[id]; !ok || value != aws.StringValue(taint.Value) {
+			addOrUpdateTaints = append(addOrUpdateTaints, toEKSTaints([]eksv1.Taint{taint})...)
+		}
+	}
+
+	var removeTaints []*eks.Taint
+	for _, taint := range upstreamTaints {
+		if !desired[taintID{aws.StringValue(taint.Key), aws.StringValue(taint.Effect)}] {
+			removeTaints = append(removeTaints, toEKSTaints([]eksv1.Taint{taint})...)
+		}
+	}
+
+	return addOrUpdateTaints, removeTaints
+}
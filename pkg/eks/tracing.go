@@ -0,0 +1,44 @@
+package eks
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	clusterNameAttribute = "eks.cluster.name"
+	operationAttribute   = "eks.operation"
+	outcomeAttribute     = "eks.outcome"
+)
+
+var tracer trace.Tracer = trace.NewNoopTracerProvider().Tracer("")
+
+// SetTracer sets the tracer used to create spans around cluster and node group operations. Spans are not recorded
+// until a tracer is set.
+func SetTracer(t trace.Tracer) {
+	if t == nil {
+		t = trace.NewNoopTracerProvider().Tracer("")
+	}
+	tracer = t
+}
+
+func startSpan(ctx context.Context, operation, clusterName string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, operation, trace.WithAttributes(
+		attribute.String(clusterNameAttribute, clusterName),
+		attribute.String(operationAttribute, operation),
+	))
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.SetAttributes(attribute.String(outcomeAttribute, "error"))
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else {
+		span.SetAttributes(attribute.String(outcomeAttribute, "success"))
+	}
+	span.End()
+}
@@ -0,0 +1,69 @@
+package eks
+
+import (
+	"context"
+	"errors"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/pkg/eks/services/mock_services"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+var _ = Describe("tracing", func() {
+	var (
+		mockController *gomock.Controller
+		eksServiceMock *mock_services.MockEKSServiceInterface
+		spanRecorder   *tracetest.SpanRecorder
+		config         *eksv1.EKSClusterConfig
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+		spanRecorder = tracetest.NewSpanRecorder()
+		SetTracer(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder)).Tracer("test"))
+		config = &eksv1.EKSClusterConfig{
+			Spec: eksv1.EKSClusterConfigSpec{
+				DisplayName: "test",
+			},
+		}
+	})
+
+	AfterEach(func() {
+		SetTracer(nil)
+		mockController.Finish()
+	})
+
+	It("should start and end a span for an operation", func() {
+		eksServiceMock.EXPECT().DeleteCluster(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+		Expect(DeleteCluster(context.Background(), &DeleteClusterOptions{EKSService: eksServiceMock, Config: config})).To(Succeed())
+
+		Expect(spanRecorder.Started()).To(HaveLen(1))
+		Expect(spanRecorder.Ended()).To(HaveLen(1))
+		span := spanRecorder.Ended()[0]
+		Expect(span.Name()).To(Equal("DeleteCluster"))
+		Expect(span.Attributes()).To(ContainElements(
+			attribute.String(clusterNameAttribute, "test"),
+			attribute.String(operationAttribute, "DeleteCluster"),
+			attribute.String(outcomeAttribute, "success"),
+		))
+	})
+
+	It("should record the error of a failed operation", func() {
+		eksServiceMock.EXPECT().DeleteCluster(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+		Expect(DeleteCluster(context.Background(), &DeleteClusterOptions{EKSService: eksServiceMock, Config: config})).ToNot(Succeed())
+
+		Expect(spanRecorder.Ended()).To(HaveLen(1))
+		span := spanRecorder.Ended()[0]
+		Expect(span.Attributes()).To(ContainElement(attribute.String(outcomeAttribute, "error")))
+		Expect(span.Status().Code).To(Equal(codes.Error))
+	})
+})
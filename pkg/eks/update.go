@@ -1,18 +1,28 @@
 package eks
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
 	"github.com/aws/aws-sdk-go/service/eks"
 	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
 	"github.com/rancher/eks-operator/pkg/eks/services"
 	"github.com/rancher/eks-operator/utils"
-	"github.com/sirupsen/logrus"
 )
 
 const (
 	allOpen = "0.0.0.0/0"
+
+	clusterLogGroupNameFormat = "/aws/eks/%s/cluster"
+
+	// networkFieldsSourceProvided is the networking source of clusters created in subnets given in the spec.
+	networkFieldsSourceProvided = "provided"
 )
 
 type UpdateClusterVersionOpts struct {
@@ -21,16 +31,32 @@ type UpdateClusterVersionOpts struct {
 	UpstreamClusterSpec *eksv1.EKSClusterConfigSpec
 }
 
-func UpdateClusterVersion(opts *UpdateClusterVersionOpts) (bool, error) {
+func UpdateClusterVersion(ctx context.Context, opts *UpdateClusterVersionOpts) (_ bool, err error) {
+	ctx, span := startSpan(ctx, "UpdateClusterVersion", opts.Config.Spec.DisplayName)
+	defer func() { endSpan(span, err) }()
+
 	updated := false
 	if aws.StringValue(opts.UpstreamClusterSpec.KubernetesVersion) != aws.StringValue(opts.Config.Spec.KubernetesVersion) {
-		logrus.Infof("updating kubernetes version for cluster [%s]", opts.Config.Name)
-		_, err := opts.EKSService.UpdateClusterVersion(&eks.UpdateClusterVersionInput{
+		if err := validateKubernetesVersion(aws.StringValue(opts.Config.Spec.KubernetesVersion)); err != nil {
+			return updated, fmt.Errorf("error updating cluster [%s] kubernetes version: %w", opts.Config.Name, err)
+		}
+		if upstreamVersion := aws.StringValue(opts.UpstreamClusterSpec.KubernetesVersion); upstreamVersion != "" {
+			if err := validateKubernetesVersionUpgrade(upstreamVersion, aws.StringValue(opts.Config.Spec.KubernetesVersion)); err != nil {
+				return updated, fmt.Errorf("error updating cluster [%s] kubernetes version: %w", opts.Config.Name, err)
+			}
+		}
+		if err := validateAddonsCompatibility(ctx, opts.EKSService, opts.Config, aws.StringValue(opts.Config.Spec.KubernetesVersion)); err != nil {
+			return updated, fmt.Errorf("error updating cluster [%s] kubernetes version: %w", opts.Config.Name, err)
+		}
+		clusterLogger(opts.Config, "UpdateClusterVersion").
+			WithField("version", aws.StringValue(opts.Config.Spec.KubernetesVersion)).
+			Info("updating kubernetes version")
+		_, err := opts.EKSService.UpdateClusterVersion(ctx, &eks.UpdateClusterVersionInput{
 			Name:    aws.String(opts.Config.Spec.DisplayName),
 			Version: opts.Config.Spec.KubernetesVersion,
 		})
 		if err != nil {
-			return updated, fmt.Errorf("error updating cluster [%s] kubernetes version: %w", opts.Config.Name, err)
+			return updated, fmt.Errorf("error updating cluster [%s] kubernetes version: %w", opts.Config.Name, AsUpdateConflict(err))
 		}
 		updated = true
 	}
@@ -46,10 +72,21 @@ type UpdateResourceTagsOpts struct {
 	ResourceARN  string
 }
 
-func UpdateResourceTags(opts *UpdateResourceTagsOpts) (bool, error) {
+func UpdateResourceTags(ctx context.Context, opts *UpdateResourceTagsOpts) (bool, error) {
 	updated := false
-	if updateTags := utils.GetKeyValuesToUpdate(opts.Tags, opts.UpstreamTags); updateTags != nil {
+	upstreamTags := opts.UpstreamTags
+	if tagsDiffer(opts.Tags, upstreamTags) {
+		var err error
+		upstreamTags, err = getResourceTags(ctx, opts.EKSService, opts.ResourceARN)
+		if err != nil {
+			return false, fmt.Errorf("error listing tags for cluster [%s]: %w", opts.ClusterName, err)
+		}
+	}
+
+	updateTags, updateUntags := diffTags(opts.Tags, upstreamTags)
+	if updateTags != nil {
 		_, err := opts.EKSService.TagResource(
+			ctx,
 			&eks.TagResourceInput{
 				ResourceArn: aws.String(opts.ResourceARN),
 				Tags:        updateTags,
@@ -60,8 +97,9 @@ func UpdateResourceTags(opts *UpdateResourceTagsOpts) (bool, error) {
 		updated = true
 	}
 
-	if updateUntags := utils.GetKeysToDelete(opts.Tags, opts.UpstreamTags); updateUntags != nil {
+	if updateUntags != nil {
 		_, err := opts.EKSService.UntagResource(
+			ctx,
 			&eks.UntagResourceInput{
 				ResourceArn: aws.String(opts.ResourceARN),
 				TagKeys:     updateUntags,
@@ -75,21 +113,115 @@ func UpdateResourceTags(opts *UpdateResourceTagsOpts) (bool, error) {
 	return updated, nil
 }
 
+// getResourceTags reads the current tags of a resource. The tags of a described cluster or node group may be stale
+// right after the resource is tagged, so a detected tag diff is confirmed against a fresh read before any update is
+// sent. A read that is still stale only causes an idempotent tag update, the next reconcile confirms the result.
+func getResourceTags(ctx context.Context, eksService services.EKSServiceInterface, resourceARN string) (map[string]string, error) {
+	output, err := eksService.ListTagsForResource(ctx, &eks.ListTagsForResourceInput{
+		ResourceArn: aws.String(resourceARN),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return aws.StringValueMap(output.Tags), nil
+}
+
+func tagsDiffer(tags, upstreamTags map[string]string) bool {
+	updateTags, updateUntags := diffTags(tags, upstreamTags)
+	return updateTags != nil || updateUntags != nil
+}
+
+type UpdateNodegroupTagsOpts struct {
+	EKSService        services.EKSServiceInterface
+	Config            *eksv1.EKSClusterConfig
+	NodeGroup         *eksv1.NodeGroup
+	UpstreamNodeGroup *eksv1.NodeGroup
+	NodegroupARN      string
+}
+
+// UpdateNodegroupTags reconciles the tags of the managed node group resource. The resource tags of the node group are
+// propagated to the node group itself so that they are not only set on its instances, the node group tags take
+// precedence over resource tags with the same key. The tags are left alone if the node group tags are not set.
+func UpdateNodegroupTags(ctx context.Context, opts *UpdateNodegroupTagsOpts) (_ bool, err error) {
+	ctx, span := startSpan(ctx, "UpdateNodegroupTags", opts.Config.Spec.DisplayName)
+	defer func() { endSpan(span, err) }()
+
+	if opts.NodeGroup.Tags == nil {
+		return false, nil
+	}
+
+	name := aws.StringValue(opts.NodeGroup.NodegroupName)
+	tags := aws.StringValueMap(opts.NodeGroup.ResourceTags)
+	for key, value := range aws.StringValueMap(opts.NodeGroup.Tags) {
+		tags[key] = value
+	}
+	tags = GetNormalizedTags(opts.Config, tags)
+
+	upstreamTags := aws.StringValueMap(opts.UpstreamNodeGroup.Tags)
+	if tagsDiffer(tags, upstreamTags) {
+		upstreamTags, err = getResourceTags(ctx, opts.EKSService, opts.NodegroupARN)
+		if err != nil {
+			return false, fmt.Errorf("error listing tags for nodegroup [%s] in cluster [%s]: %w", name, opts.Config.Spec.DisplayName, err)
+		}
+	}
+
+	updated := false
+	updateTags, updateUntags := diffTags(tags, upstreamTags)
+	if updateTags != nil {
+		_, err := opts.EKSService.TagResource(ctx, &eks.TagResourceInput{
+			ResourceArn: aws.String(opts.NodegroupARN),
+			Tags:        updateTags,
+		})
+		if err != nil {
+			return false, fmt.Errorf("error tagging nodegroup [%s] in cluster [%s]: %w", name, opts.Config.Spec.DisplayName, err)
+		}
+		updated = true
+	}
+
+	if updateUntags != nil {
+		_, err := opts.EKSService.UntagResource(ctx, &eks.UntagResourceInput{
+			ResourceArn: aws.String(opts.NodegroupARN),
+			TagKeys:     updateUntags,
+		})
+		if err != nil {
+			return false, fmt.Errorf("error untagging nodegroup [%s] in cluster [%s]: %w", name, opts.Config.Spec.DisplayName, err)
+		}
+		updated = true
+	}
+
+	return updated, nil
+}
+
 type UpdateLoggingTypesOpts struct {
 	EKSService          services.EKSServiceInterface
 	Config              *eksv1.EKSClusterConfig
 	UpstreamClusterSpec *eksv1.EKSClusterConfigSpec
 }
 
-func UpdateClusterLoggingTypes(opts *UpdateLoggingTypesOpts) (bool, error) {
+// UpdateClusterLoggingTypes updates the control plane log types of the cluster to match the logging types of the
+// config. Nil logging types leave the logging of the cluster untouched, while empty logging types disable all the log
+// types enabled upstream.
+func UpdateClusterLoggingTypes(ctx context.Context, opts *UpdateLoggingTypesOpts) (_ bool, err error) {
+	ctx, span := startSpan(ctx, "UpdateClusterLoggingTypes", opts.Config.Spec.DisplayName)
+	defer func() { endSpan(span, err) }()
+
+	if err := ValidateLoggingTypes(opts.Config); err != nil {
+		return false, err
+	}
+
 	updated := false
 	if loggingTypesUpdate := getLoggingTypesUpdate(opts.Config.Spec.LoggingTypes, opts.UpstreamClusterSpec.LoggingTypes); loggingTypesUpdate != nil {
-		_, err := opts.EKSService.UpdateClusterConfig(
-			&eks.UpdateClusterConfigInput{
-				Name:    aws.String(opts.Config.Spec.DisplayName),
-				Logging: loggingTypesUpdate,
-			},
-		)
+		err := retryWithBackoff(ctx, defaultBackoff, func() error {
+			_, err := opts.EKSService.UpdateClusterConfig(
+				ctx,
+				&eks.UpdateClusterConfigInput{
+					Name:    aws.String(opts.Config.Spec.DisplayName),
+					Logging: loggingTypesUpdate,
+				},
+			)
+			return AsUpdateConflict(err)
+		})
 		if err != nil {
 			return false, fmt.Errorf("error updating cluster [%s] logging types: %w", opts.Config.Name, err)
 		}
@@ -99,29 +231,122 @@ func UpdateClusterLoggingTypes(opts *UpdateLoggingTypesOpts) (bool, error) {
 	return updated, nil
 }
 
+// ErrLogGroupNotFound is returned when the retention of the control plane logs cannot be set yet, because EKS only
+// creates the log group of a cluster once the first logs are written.
+var ErrLogGroupNotFound = errors.New("log group not found")
+
+type UpdateClusterLogRetentionOpts struct {
+	CloudWatchLogsService services.CloudWatchLogsServiceInterface
+	Config                *eksv1.EKSClusterConfig
+}
+
+// UpdateClusterLogRetention sets the retention period of the cluster's control plane log group. EKS creates the
+// log group when logging is enabled but never sets its retention, so logs would otherwise be kept forever. Nothing is
+// done unless logging types are set in the spec, as nil leaves logging to EKS and an empty list disables it. The
+// retention applied last is recorded in the status of the config, so the log group is only looked up when it changes.
+// An error wrapping ErrLogGroupNotFound is returned while the log group does not exist yet.
+func UpdateClusterLogRetention(ctx context.Context, opts *UpdateClusterLogRetentionOpts) (_ bool, err error) {
+	ctx, span := startSpan(ctx, "UpdateClusterLogRetention", opts.Config.Spec.DisplayName)
+	defer func() { endSpan(span, err) }()
+
+	if opts.Config.Spec.LogRetentionDays == nil || len(opts.Config.Spec.LoggingTypes) == 0 {
+		return false, nil
+	}
+
+	if err := ValidateLogRetention(opts.Config); err != nil {
+		return false, err
+	}
+
+	retentionDays := aws.Int64Value(opts.Config.Spec.LogRetentionDays)
+	if opts.Config.Status.LogRetentionDays == retentionDays {
+		return false, nil
+	}
+
+	logGroupName := fmt.Sprintf(clusterLogGroupNameFormat, opts.Config.Spec.DisplayName)
+	output, err := opts.CloudWatchLogsService.DescribeLogGroups(ctx, &cloudwatchlogs.DescribeLogGroupsInput{
+		LogGroupNamePrefix: aws.String(logGroupName),
+	})
+	if err != nil {
+		return false, fmt.Errorf("error describing log group for cluster [%s]: %w", opts.Config.Name, err)
+	}
+
+	for _, logGroup := range output.LogGroups {
+		if aws.StringValue(logGroup.LogGroupName) != logGroupName {
+			continue
+		}
+		if aws.Int64Value(logGroup.RetentionInDays) == retentionDays {
+			opts.Config.Status.LogRetentionDays = retentionDays
+			return false, nil
+		}
+
+		clusterLogger(opts.Config, "UpdateClusterLogRetention").
+			WithField("retentionInDays", retentionDays).
+			Info("updating log retention")
+		_, err := opts.CloudWatchLogsService.PutRetentionPolicy(ctx, &cloudwatchlogs.PutRetentionPolicyInput{
+			LogGroupName:    aws.String(logGroupName),
+			RetentionInDays: aws.Int64(retentionDays),
+		})
+		if err != nil {
+			return false, fmt.Errorf("error updating log retention for cluster [%s]: %w", opts.Config.Name, err)
+		}
+		opts.Config.Status.LogRetentionDays = retentionDays
+		return true, nil
+	}
+
+	return false, fmt.Errorf("%w: retention of log group [%s] of cluster [%s] will be set once it is created",
+		ErrLogGroupNotFound, logGroupName, opts.Config.Name)
+}
+
+// ValidateLogRetention ensures the log retention of the cluster, if set, is a period CloudWatch Logs supports.
+func ValidateLogRetention(config *eksv1.EKSClusterConfig) error {
+	if config.Spec.LogRetentionDays == nil {
+		return nil
+	}
+
+	if retentionDays := aws.Int64Value(config.Spec.LogRetentionDays); !validLogRetentionDays(retentionDays) {
+		return fmt.Errorf("invalid log retention days [%d] for cluster [%s]", retentionDays, config.Name)
+	}
+
+	return nil
+}
+
+func validLogRetentionDays(days int64) bool {
+	switch days {
+	case 1, 3, 5, 7, 14, 30, 60, 90, 120, 150, 180, 365, 400, 545, 731, 1096, 1827, 2192, 2557, 2922, 3288, 3653:
+		return true
+	}
+
+	return false
+}
+
 type UpdateClusterAccessOpts struct {
 	EKSService          services.EKSServiceInterface
 	Config              *eksv1.EKSClusterConfig
 	UpstreamClusterSpec *eksv1.EKSClusterConfigSpec
 }
 
-func UpdateClusterAccess(opts *UpdateClusterAccessOpts) (bool, error) {
+func UpdateClusterAccess(ctx context.Context, opts *UpdateClusterAccessOpts) (_ bool, err error) {
+	ctx, span := startSpan(ctx, "UpdateClusterAccess", opts.Config.Spec.DisplayName)
+	defer func() { endSpan(span, err) }()
+
 	updated := false
 
-	publicAccessUpdate := opts.Config.Spec.PublicAccess != nil && aws.BoolValue(opts.UpstreamClusterSpec.PublicAccess) != aws.BoolValue(opts.Config.Spec.PublicAccess)
-	privateAccessUpdate := opts.Config.Spec.PrivateAccess != nil && aws.BoolValue(opts.UpstreamClusterSpec.PrivateAccess) != aws.BoolValue(opts.Config.Spec.PrivateAccess)
-	if publicAccessUpdate || privateAccessUpdate {
+	if endpointAccessChanged(opts.Config, opts.UpstreamClusterSpec) {
 		// public and private access updates need to be sent together. When they are sent one at a time
 		// the request may be denied due to having both public and private access disabled.
-		_, err := opts.EKSService.UpdateClusterConfig(
-			&eks.UpdateClusterConfigInput{
-				Name: aws.String(opts.Config.Spec.DisplayName),
-				ResourcesVpcConfig: &eks.VpcConfigRequest{
-					EndpointPublicAccess:  opts.Config.Spec.PublicAccess,
-					EndpointPrivateAccess: opts.Config.Spec.PrivateAccess,
+		err := retryWithBackoff(ctx, defaultBackoff, func() error {
+			_, err := opts.EKSService.UpdateClusterConfig(
+				ctx,
+				&eks.UpdateClusterConfigInput{
+					Name: aws.String(opts.Config.Spec.DisplayName),
+					ResourcesVpcConfig: &eks.VpcConfigRequest{
+						EndpointPublicAccess:  opts.Config.Spec.PublicAccess,
+						EndpointPrivateAccess: opts.Config.Spec.PrivateAccess,
+					},
 				},
-			},
-		)
+			)
+			return AsUpdateConflict(err)
+		})
 		if err != nil {
 			return false, fmt.Errorf("error updating cluster [%s] public/private access: %w", opts.Config.Name, err)
 		}
@@ -137,21 +362,25 @@ type UpdateClusterPublicAccessSourcesOpts struct {
 	UpstreamClusterSpec *eksv1.EKSClusterConfigSpec
 }
 
-func UpdateClusterPublicAccessSources(opts *UpdateClusterPublicAccessSourcesOpts) (bool, error) {
+func UpdateClusterPublicAccessSources(ctx context.Context, opts *UpdateClusterPublicAccessSourcesOpts) (_ bool, err error) {
+	ctx, span := startSpan(ctx, "UpdateClusterPublicAccessSources", opts.Config.Spec.DisplayName)
+	defer func() { endSpan(span, err) }()
+
 	updated := false
 	// check public access CIDRs for update (public access sources)
-
-	filteredSpecPublicAccessSources := filterPublicAccessSources(opts.Config.Spec.PublicAccessSources)
-	filteredUpstreamPublicAccessSources := filterPublicAccessSources(opts.UpstreamClusterSpec.PublicAccessSources)
-	if !utils.CompareStringSliceElements(filteredSpecPublicAccessSources, filteredUpstreamPublicAccessSources) {
-		_, err := opts.EKSService.UpdateClusterConfig(
-			&eks.UpdateClusterConfigInput{
-				Name: aws.String(opts.Config.Spec.DisplayName),
-				ResourcesVpcConfig: &eks.VpcConfigRequest{
-					PublicAccessCidrs: getPublicAccessCidrs(opts.Config.Spec.PublicAccessSources),
+	if publicAccessSourcesChanged(opts.Config, opts.UpstreamClusterSpec) {
+		err := retryWithBackoff(ctx, defaultBackoff, func() error {
+			_, err := opts.EKSService.UpdateClusterConfig(
+				ctx,
+				&eks.UpdateClusterConfigInput{
+					Name: aws.String(opts.Config.Spec.DisplayName),
+					ResourcesVpcConfig: &eks.VpcConfigRequest{
+						PublicAccessCidrs: getPublicAccessCidrs(opts.Config.Spec.PublicAccessSources),
+					},
 				},
-			},
-		)
+			)
+			return AsUpdateConflict(err)
+		})
 		if err != nil {
 			return false, fmt.Errorf("error updating cluster [%s] public access sources: %w", opts.Config.Name, err)
 		}
@@ -162,29 +391,552 @@ func UpdateClusterPublicAccessSources(opts *UpdateClusterPublicAccessSourcesOpts
 	return updated, nil
 }
 
+type UpdateClusterVpcAccessOpts struct {
+	EKSService          services.EKSServiceInterface
+	Config              *eksv1.EKSClusterConfig
+	UpstreamClusterSpec *eksv1.EKSClusterConfigSpec
+}
+
+// UpdateClusterVpcAccess updates the public and private endpoint access and the public access sources of the cluster
+// in a single request. EKS only allows one cluster config update in progress at a time, so updating them with
+// UpdateClusterAccess and UpdateClusterPublicAccessSources in a row fails until the first update is done. The public
+// access sources are left alone if they are not set in the spec.
+func UpdateClusterVpcAccess(ctx context.Context, opts *UpdateClusterVpcAccessOpts) (_ bool, err error) {
+	ctx, span := startSpan(ctx, "UpdateClusterVpcAccess", opts.Config.Spec.DisplayName)
+	defer func() { endSpan(span, err) }()
+
+	vpcConfig := &eks.VpcConfigRequest{}
+	updated := false
+	if endpointAccessChanged(opts.Config, opts.UpstreamClusterSpec) {
+		vpcConfig.EndpointPublicAccess = opts.Config.Spec.PublicAccess
+		vpcConfig.EndpointPrivateAccess = opts.Config.Spec.PrivateAccess
+		updated = true
+	}
+	if opts.Config.Spec.PublicAccessSources != nil && publicAccessSourcesChanged(opts.Config, opts.UpstreamClusterSpec) {
+		vpcConfig.PublicAccessCidrs = getPublicAccessCidrs(opts.Config.Spec.PublicAccessSources)
+		updated = true
+	}
+	if !updated {
+		return false, nil
+	}
+
+	err = retryWithBackoff(ctx, defaultBackoff, func() error {
+		_, err := opts.EKSService.UpdateClusterConfig(ctx, &eks.UpdateClusterConfigInput{
+			Name:               aws.String(opts.Config.Spec.DisplayName),
+			ResourcesVpcConfig: vpcConfig,
+		})
+		return AsUpdateConflict(err)
+	})
+	if err != nil {
+		return false, fmt.Errorf("error updating cluster [%s] endpoint access: %w", opts.Config.Name, err)
+	}
+
+	return true, nil
+}
+
+// endpointAccessChanged returns whether the public or private endpoint access of the spec differs from the cluster.
+// Both have to be sent together, when they are sent one at a time the request may be denied due to having both
+// public and private access disabled.
+func endpointAccessChanged(config *eksv1.EKSClusterConfig, upstreamSpec *eksv1.EKSClusterConfigSpec) bool {
+	publicAccessUpdate := config.Spec.PublicAccess != nil && aws.BoolValue(upstreamSpec.PublicAccess) != aws.BoolValue(config.Spec.PublicAccess)
+	privateAccessUpdate := config.Spec.PrivateAccess != nil && aws.BoolValue(upstreamSpec.PrivateAccess) != aws.BoolValue(config.Spec.PrivateAccess)
+	return publicAccessUpdate || privateAccessUpdate
+}
+
+func publicAccessSourcesChanged(config *eksv1.EKSClusterConfig, upstreamSpec *eksv1.EKSClusterConfigSpec) bool {
+	return !utils.CompareStringSliceElements(filterPublicAccessSources(config.Spec.PublicAccessSources),
+		filterPublicAccessSources(upstreamSpec.PublicAccessSources))
+}
+
+type UpdateClusterVpcConfigOpts struct {
+	EKSService          services.EKSServiceInterface
+	Config              *eksv1.EKSClusterConfig
+	UpstreamClusterSpec *eksv1.EKSClusterConfigSpec
+}
+
+// UpdateClusterVpcConfig updates the additional security groups of the cluster. The security groups are the ones of
+// the spec when the networking of the cluster was provided, and the ones of the status when it was generated by the
+// operator. The subnets of a cluster cannot be changed once it is created, so changing them returns an error.
+func UpdateClusterVpcConfig(ctx context.Context, opts *UpdateClusterVpcConfigOpts) (_ bool, err error) {
+	ctx, span := startSpan(ctx, "UpdateClusterVpcConfig", opts.Config.Spec.DisplayName)
+	defer func() { endSpan(span, err) }()
+
+	if len(opts.Config.Spec.Subnets) != 0 && !utils.CompareStringSliceElements(opts.Config.Spec.Subnets, opts.UpstreamClusterSpec.Subnets) {
+		return false, fmt.Errorf("subnets of cluster [%s] cannot be changed from [%s] to [%s] after creation, a new cluster has to be created instead",
+			opts.Config.Name, strings.Join(opts.UpstreamClusterSpec.Subnets, ", "), strings.Join(opts.Config.Spec.Subnets, ", "))
+	}
+
+	securityGroups := opts.Config.Status.SecurityGroups
+	if opts.Config.Status.NetworkFieldsSource == networkFieldsSourceProvided {
+		securityGroups = opts.Config.Spec.SecurityGroups
+	}
+	if securityGroups == nil || utils.CompareStringSliceElements(securityGroups, opts.UpstreamClusterSpec.SecurityGroups) {
+		return false, nil
+	}
+
+	err = retryWithBackoff(ctx, defaultBackoff, func() error {
+		_, err := opts.EKSService.UpdateClusterConfig(ctx, &eks.UpdateClusterConfigInput{
+			Name: aws.String(opts.Config.Spec.DisplayName),
+			ResourcesVpcConfig: &eks.VpcConfigRequest{
+				SecurityGroupIds: aws.StringSlice(securityGroups),
+			},
+		})
+		return AsUpdateConflict(err)
+	})
+	if err != nil {
+		return false, fmt.Errorf("error updating cluster [%s] security groups: %w", opts.Config.Name, err)
+	}
+
+	return true, nil
+}
+
 type UpdateNodegroupVersionOpts struct {
-	EKSService     services.EKSServiceInterface
-	EC2Service     services.EC2ServiceInterface
-	Config         *eksv1.EKSClusterConfig
-	NodeGroup      *eksv1.NodeGroup
-	NGVersionInput *eks.UpdateNodegroupVersionInput
-	LTVersions     map[string]string
+	EKSService      services.EKSServiceInterface
+	EC2Service      services.EC2ServiceInterface
+	Config          *eksv1.EKSClusterConfig
+	NodeGroup       *eksv1.NodeGroup
+	NGVersionInput  *eks.UpdateNodegroupVersionInput
+	UpstreamVersion *string
+	LTVersions      map[string]string
+	// Force upgrades the node group even if pods cannot be drained because of a pod disruption budget.
+	Force bool
 }
 
-func UpdateNodegroupVersion(opts *UpdateNodegroupVersionOpts) error {
-	if _, err := opts.EKSService.UpdateNodegroupVersion(opts.NGVersionInput); err != nil {
+// UpdateNodegroupVersion rolls out a new kubernetes version and/or launch template version to a node group. The
+// kubernetes version is only sent when it differs from the upstream version of the node group, nothing is updated
+// if neither is left to change.
+func UpdateNodegroupVersion(ctx context.Context, opts *UpdateNodegroupVersionOpts) (_ bool, err error) {
+	ctx, span := startSpan(ctx, "UpdateNodegroupVersion", opts.Config.Spec.DisplayName)
+	defer func() { endSpan(span, err) }()
+
+	if opts.NGVersionInput == nil {
+		return false, nil
+	}
+
+	ngVersionInput := *opts.NGVersionInput
+	if ngVersionInput.Version != nil && aws.StringValue(ngVersionInput.Version) == aws.StringValue(opts.UpstreamVersion) {
+		ngVersionInput.Version = nil
+	}
+	if ngVersionInput.Version == nil && ngVersionInput.ReleaseVersion == nil && ngVersionInput.LaunchTemplate == nil {
+		return false, nil
+	}
+	if opts.Force {
+		ngVersionInput.Force = aws.Bool(true)
+	}
+
+	err = validateNodegroupVersionInput(opts.NodeGroup, &ngVersionInput)
+	if err == nil {
+		err = validateManagedLaunchTemplateVersion(opts.Config.Status.ManagedLaunchTemplateID, ngVersionInput.LaunchTemplate)
+	}
+	if err == nil && ngVersionInput.LaunchTemplate != nil {
+		err = validateLaunchTemplateVersionCompatibility(ctx, opts, ngVersionInput.LaunchTemplate)
+	}
+	if err == nil {
+		_, err = opts.EKSService.UpdateNodegroupVersion(ctx, &ngVersionInput)
+	}
+	if err != nil {
 		if version, ok := opts.LTVersions[aws.StringValue(opts.NodeGroup.NodegroupName)]; ok {
 			// If there was an error updating the node group and a Rancher-managed launch template version was created,
 			// then the version that caused the issue needs to be deleted to prevent bad versions from piling up.
-			DeleteLaunchTemplateVersions(opts.EC2Service, opts.Config.Status.ManagedLaunchTemplateID, []*string{aws.String(version)})
+			DeleteLaunchTemplateVersions(ctx, opts.EC2Service, opts.Config.Status.ManagedLaunchTemplateID, []*string{aws.String(version)})
 		}
-		return err
+		return false, AsUpdateConflict(err)
+	}
+
+	return true, nil
+}
+
+// validateNodegroupVersionInput ensures a node group running a custom AMI is only rolled out through its launch
+// template. EKS rejects updates that set a kubernetes or release version together with a launch template that
+// uses a custom AMI.
+func validateNodegroupVersionInput(ng *eksv1.NodeGroup, input *eks.UpdateNodegroupVersionInput) error {
+	if ng == nil || input == nil || input.LaunchTemplate == nil || aws.StringValue(ng.ImageID) == "" {
+		return nil
+	}
+
+	if input.Version != nil || input.ReleaseVersion != nil {
+		return fmt.Errorf("nodegroup [%s] uses a custom AMI, kubernetes version and release version cannot be updated along with the launch template",
+			aws.StringValue(ng.NodegroupName))
 	}
 
 	return nil
 }
 
+// ValidateNodegroupUpdate returns an error for changes to an existing node group that EKS cannot apply in place. The
+// capacity type is chosen from requestSpotInstances when the node group is created and UpdateNodegroupConfig cannot
+// change it, so the node group has to be recreated instead.
+func ValidateNodegroupUpdate(config *eksv1.EKSClusterConfig, ng, upstreamNg eksv1.NodeGroup) error {
+	if ng.RequestSpotInstances == nil || upstreamNg.RequestSpotInstances == nil {
+		return nil
+	}
+
+	capacityType := getCapacityType(ng.RequestSpotInstances)
+	upstreamCapacityType := getCapacityType(upstreamNg.RequestSpotInstances)
+	if capacityType != upstreamCapacityType {
+		return fmt.Errorf("capacity type of nodegroup [%s] in cluster [%s] cannot be changed from [%s] to [%s] after creation, "+
+			"the nodegroup has to be deleted and created again with requestSpotInstances set to [%t] instead",
+			aws.StringValue(ng.NodegroupName), config.Name, upstreamCapacityType, capacityType, aws.BoolValue(ng.RequestSpotInstances))
+	}
+
+	return nil
+}
+
+type UpdateNodegroupLabelsOpts struct {
+	EKSService     services.EKSServiceInterface
+	Config         *eksv1.EKSClusterConfig
+	NodeGroup      *eksv1.NodeGroup
+	UpstreamLabels map[string]*string
+}
+
+func UpdateNodegroupLabels(ctx context.Context, opts *UpdateNodegroupLabelsOpts) (_ bool, err error) {
+	ctx, span := startSpan(ctx, "UpdateNodegroupLabels", opts.Config.Spec.DisplayName)
+	defer func() { endSpan(span, err) }()
+
+	if opts.NodeGroup.Labels == nil {
+		return false, nil
+	}
+
+	labels := aws.StringValueMap(opts.NodeGroup.Labels)
+	upstreamLabels := aws.StringValueMap(opts.UpstreamLabels)
+	addOrUpdateLabels := utils.GetKeyValuesToUpdate(labels, upstreamLabels)
+	removeLabels := utils.GetKeysToDelete(labels, upstreamLabels)
+	if addOrUpdateLabels == nil && removeLabels == nil {
+		return false, nil
+	}
+
+	_, err = opts.EKSService.UpdateNodegroupConfig(ctx, &eks.UpdateNodegroupConfigInput{
+		ClusterName:   aws.String(opts.Config.Spec.DisplayName),
+		NodegroupName: opts.NodeGroup.NodegroupName,
+		Labels: &eks.UpdateLabelsPayload{
+			AddOrUpdateLabels: addOrUpdateLabels,
+			RemoveLabels:      removeLabels,
+		},
+	})
+	if err != nil {
+		return false, fmt.Errorf("error updating labels for nodegroup [%s] in cluster [%s]: %w", aws.StringValue(opts.NodeGroup.NodegroupName), opts.Config.Name, AsUpdateConflict(err))
+	}
+
+	return true, nil
+}
+
+type UpdateNodegroupScalingOpts struct {
+	EKSService        services.EKSServiceInterface
+	Config            *eksv1.EKSClusterConfig
+	NodeGroup         *eksv1.NodeGroup
+	UpstreamNodeGroup *eksv1.NodeGroup
+}
+
+// UpdateNodegroupScaling updates the desired, min and max sizes of a node group that differ from the upstream node
+// group. Sizes that are not set keep their upstream value and are checked along with the new ones, so that lowering
+// maxSize below the current desired size is refused with the node group name rather than by EKS. With
+// ignoreDesiredSizeDrift set, a desired size changed by the cluster autoscaler is kept as long as the min and max size
+// and the desired size of the spec are unchanged since they were last applied. The applied desired size is recorded in
+// the status of the config.
+func UpdateNodegroupScaling(ctx context.Context, opts *UpdateNodegroupScalingOpts) (_ bool, err error) {
+	ctx, span := startSpan(ctx, "UpdateNodegroupScaling", opts.Config.Spec.DisplayName)
+	defer func() { endSpan(span, err) }()
+
+	ngName := aws.StringValue(opts.NodeGroup.NodegroupName)
+	scalingConfig := &eks.NodegroupScalingConfig{}
+	sizes := eksv1.NodeGroup{
+		DesiredSize: opts.UpstreamNodeGroup.DesiredSize,
+		MinSize:     opts.UpstreamNodeGroup.MinSize,
+		MaxSize:     opts.UpstreamNodeGroup.MaxSize,
+	}
+	// node groups without a recorded desired size predate the record, their desired size is taken as applied
+	appliedDesiredSize, recorded := opts.Config.Status.NodegroupDesiredSizes[ngName]
+	desiredSizeChanged := recorded && appliedDesiredSize != aws.Int64Value(opts.NodeGroup.DesiredSize)
+	drift := getNodegroupScalingDrift(opts.NodeGroup, opts.UpstreamNodeGroup)
+	keepDesiredSize := aws.BoolValue(opts.NodeGroup.IgnoreDesiredSizeDrift) && drift.desiredSizeOnly() && !desiredSizeChanged
+	updated := false
+	if opts.NodeGroup.DesiredSize != nil && !keepDesiredSize && aws.Int64Value(opts.NodeGroup.DesiredSize) != aws.Int64Value(opts.UpstreamNodeGroup.DesiredSize) {
+		scalingConfig.DesiredSize = opts.NodeGroup.DesiredSize
+		sizes.DesiredSize = opts.NodeGroup.DesiredSize
+		updated = true
+	}
+	if opts.NodeGroup.MinSize != nil && aws.Int64Value(opts.NodeGroup.MinSize) != aws.Int64Value(opts.UpstreamNodeGroup.MinSize) {
+		scalingConfig.MinSize = opts.NodeGroup.MinSize
+		sizes.MinSize = opts.NodeGroup.MinSize
+		updated = true
+	}
+	if opts.NodeGroup.MaxSize != nil && aws.Int64Value(opts.NodeGroup.MaxSize) != aws.Int64Value(opts.UpstreamNodeGroup.MaxSize) {
+		scalingConfig.MaxSize = opts.NodeGroup.MaxSize
+		sizes.MaxSize = opts.NodeGroup.MaxSize
+		updated = true
+	}
+	if !updated {
+		return false, nil
+	}
+	if err := validateScalingConfig(&sizes); err != nil {
+		return false, fmt.Errorf("error validating scaling config for nodegroup [%s] in cluster [%s]: %w", ngName, opts.Config.Name, err)
+	}
+
+	_, err = opts.EKSService.UpdateNodegroupConfig(ctx, &eks.UpdateNodegroupConfigInput{
+		ClusterName:   aws.String(opts.Config.Spec.DisplayName),
+		NodegroupName: opts.NodeGroup.NodegroupName,
+		ScalingConfig: scalingConfig,
+	})
+	if err != nil {
+		return false, fmt.Errorf("error updating scaling config for nodegroup [%s] in cluster [%s]: %w", ngName, opts.Config.Name, AsUpdateConflict(err))
+	}
+	if scalingConfig.DesiredSize != nil {
+		if opts.Config.Status.NodegroupDesiredSizes == nil {
+			opts.Config.Status.NodegroupDesiredSizes = make(map[string]int64)
+		}
+		opts.Config.Status.NodegroupDesiredSizes[ngName] = aws.Int64Value(scalingConfig.DesiredSize)
+	}
+
+	return true, nil
+}
+
+// nodegroupScalingDrift describes how the live scaling config of a node group differs from the configured one.
+// Deltas are the live value minus the configured value and are zero for fields that are not configured.
+type nodegroupScalingDrift struct {
+	desiredSizeDelta int64
+	minSizeDelta     int64
+	maxSizeDelta     int64
+}
+
+// minMaxChanged reports whether the min or max size differ from the configured ones, which is never caused by the
+// cluster autoscaler.
+func (d *nodegroupScalingDrift) minMaxChanged() bool {
+	return d.minSizeDelta != 0 || d.maxSizeDelta != 0
+}
+
+// desiredSizeOnly reports whether only the desired size drifted, which is what the cluster autoscaler does when it
+// scales a node group within its min and max size.
+func (d *nodegroupScalingDrift) desiredSizeOnly() bool {
+	return d.desiredSizeDelta != 0 && !d.minMaxChanged()
+}
+
+func getNodegroupScalingDrift(ng, upstreamNg *eksv1.NodeGroup) *nodegroupScalingDrift {
+	return &nodegroupScalingDrift{
+		desiredSizeDelta: scalingDelta(upstreamNg.DesiredSize, ng.DesiredSize),
+		minSizeDelta:     scalingDelta(upstreamNg.MinSize, ng.MinSize),
+		maxSizeDelta:     scalingDelta(upstreamNg.MaxSize, ng.MaxSize),
+	}
+}
+
+func scalingDelta(live, configured *int64) int64 {
+	if configured == nil {
+		return 0
+	}
+	return aws.Int64Value(live) - aws.Int64Value(configured)
+}
+
+type UpdateNodegroupTaintsOpts struct {
+	EKSService     services.EKSServiceInterface
+	Config         *eksv1.EKSClusterConfig
+	NodeGroup      *eksv1.NodeGroup
+	UpstreamTaints []eksv1.Taint
+}
+
+func UpdateNodegroupTaints(ctx context.Context, opts *UpdateNodegroupTaintsOpts) (_ bool, err error) {
+	ctx, span := startSpan(ctx, "UpdateNodegroupTaints", opts.Config.Spec.DisplayName)
+	defer func() { endSpan(span, err) }()
+
+	if opts.NodeGroup.Taints == nil {
+		return false, nil
+	}
+
+	ngName := aws.StringValue(opts.NodeGroup.NodegroupName)
+	if err := validateTaints(opts.NodeGroup.Taints); err != nil {
+		return false, fmt.Errorf("error validating taints for nodegroup [%s] in cluster [%s]: %w", ngName, opts.Config.Name, err)
+	}
+
+	addOrUpdateTaints, removeTaints := getTaintsToUpdate(opts.NodeGroup.Taints, opts.UpstreamTaints)
+	if addOrUpdateTaints == nil && removeTaints == nil {
+		return false, nil
+	}
+
+	_, err = opts.EKSService.UpdateNodegroupConfig(ctx, &eks.UpdateNodegroupConfigInput{
+		ClusterName:   aws.String(opts.Config.Spec.DisplayName),
+		NodegroupName: opts.NodeGroup.NodegroupName,
+		Taints: &eks.UpdateTaintsPayload{
+			AddOrUpdateTaints: addOrUpdateTaints,
+			RemoveTaints:      removeTaints,
+		},
+	})
+	if err != nil {
+		return false, fmt.Errorf("error updating taints for nodegroup [%s] in cluster [%s]: %w", ngName, opts.Config.Name, AsUpdateConflict(err))
+	}
+
+	return true, nil
+}
+
+type UpdateNodegroupUpdateConfigOpts struct {
+	EKSService           services.EKSServiceInterface
+	Config               *eksv1.EKSClusterConfig
+	NodeGroup            *eksv1.NodeGroup
+	UpstreamUpdateConfig *eksv1.NodeGroupUpdateConfig
+}
+
+// UpdateNodegroupUpdateConfig updates how many nodes of a node group are replaced in parallel during a rollout, when
+// the update config or update strategy of the node group differs from the upstream update config.
+func UpdateNodegroupUpdateConfig(ctx context.Context, opts *UpdateNodegroupUpdateConfigOpts) (_ bool, err error) {
+	ctx, span := startSpan(ctx, "UpdateNodegroupUpdateConfig", opts.Config.Spec.DisplayName)
+	defer func() { endSpan(span, err) }()
+
+	ngName := aws.StringValue(opts.NodeGroup.NodegroupName)
+	if err := validateUpdateConfig(opts.NodeGroup); err != nil {
+		return false, fmt.Errorf("error validating update config for nodegroup [%s] in cluster [%s]: %w", ngName, opts.Config.Name, err)
+	}
+
+	updateConfig := GetNodegroupUpdateConfigUpdate(GetNodegroupUpdateConfig(opts.NodeGroup), opts.UpstreamUpdateConfig)
+	if updateConfig == nil {
+		return false, nil
+	}
+
+	_, err = opts.EKSService.UpdateNodegroupConfig(ctx, &eks.UpdateNodegroupConfigInput{
+		ClusterName:   aws.String(opts.Config.Spec.DisplayName),
+		NodegroupName: opts.NodeGroup.NodegroupName,
+		UpdateConfig:  updateConfig,
+	})
+	if err != nil {
+		return false, fmt.Errorf("error updating update config for nodegroup [%s] in cluster [%s]: %w", ngName, opts.Config.Name, AsUpdateConflict(err))
+	}
+
+	return true, nil
+}
+
+type UpdateNodegroupTargetGroupsOpts struct {
+	EKSService         services.EKSServiceInterface
+	AutoScalingService services.AutoScalingServiceInterface
+	Config             *eksv1.EKSClusterConfig
+	NodeGroup          *eksv1.NodeGroup
+}
+
+// UpdateNodegroupTargetGroups attaches the node group's target groups to the auto scaling groups backing it, and
+// detaches the ones removed from the node group since. The target groups attached are recorded in the status of the
+// config, so the auto scaling groups are only looked up when the target groups of the node group change, and target
+// groups attached outside of the operator are never detached.
+func UpdateNodegroupTargetGroups(ctx context.Context, opts *UpdateNodegroupTargetGroupsOpts) (_ bool, err error) {
+	ctx, span := startSpan(ctx, "UpdateNodegroupTargetGroups", opts.Config.Spec.DisplayName)
+	defer func() { endSpan(span, err) }()
+
+	ngName := aws.StringValue(opts.NodeGroup.NodegroupName)
+	appliedTargetGroupARNs := opts.Config.Status.NodegroupTargetGroupARNs[ngName]
+	if utils.CompareStringSliceElements(opts.NodeGroup.TargetGroupARNs, appliedTargetGroupARNs) {
+		return false, nil
+	}
+
+	if err := ValidateTargetGroupARNs(*opts.NodeGroup); err != nil {
+		return false, fmt.Errorf("error validating target groups for cluster [%s]: %w", opts.Config.Name, err)
+	}
+
+	targetGroupARNs := make(map[string]bool, len(opts.NodeGroup.TargetGroupARNs))
+	for _, tgARN := range opts.NodeGroup.TargetGroupARNs {
+		targetGroupARNs[tgARN] = true
+	}
+	var removedTargetGroupARNs []string
+	for _, tgARN := range appliedTargetGroupARNs {
+		if !targetGroupARNs[tgARN] {
+			removedTargetGroupARNs = append(removedTargetGroupARNs, tgARN)
+		}
+	}
+
+	ngOutput, err := opts.EKSService.DescribeNodegroup(ctx, &eks.DescribeNodegroupInput{
+		ClusterName:   aws.String(opts.Config.Spec.DisplayName),
+		NodegroupName: opts.NodeGroup.NodegroupName,
+	})
+	if err != nil {
+		return false, fmt.Errorf("error describing nodegroup [%s] in cluster [%s]: %w", ngName, opts.Config.Name, err)
+	}
+	if ngOutput.Nodegroup == nil || ngOutput.Nodegroup.Resources == nil {
+		return false, nil
+	}
+
+	updated := false
+	for _, asg := range ngOutput.Nodegroup.Resources.AutoScalingGroups {
+		tgOutput, err := opts.AutoScalingService.DescribeLoadBalancerTargetGroups(ctx, &autoscaling.DescribeLoadBalancerTargetGroupsInput{
+			AutoScalingGroupName: asg.Name,
+		})
+		if err != nil {
+			return updated, fmt.Errorf("error describing target groups of nodegroup [%s] in cluster [%s]: %w", ngName, opts.Config.Name, err)
+		}
+
+		attached := make(map[string]bool, len(tgOutput.LoadBalancerTargetGroups))
+		for _, tg := range tgOutput.LoadBalancerTargetGroups {
+			attached[aws.StringValue(tg.LoadBalancerTargetGroupARN)] = true
+		}
+
+		var toAttach, toDetach []string
+		for _, tgARN := range opts.NodeGroup.TargetGroupARNs {
+			if !attached[tgARN] {
+				toAttach = append(toAttach, tgARN)
+			}
+		}
+		for _, tgARN := range removedTargetGroupARNs {
+			if attached[tgARN] {
+				toDetach = append(toDetach, tgARN)
+			}
+		}
+
+		if len(toAttach) != 0 {
+			clusterLogger(opts.Config, "UpdateNodegroupTargetGroups").
+				WithField(nodegroupLogField, ngName).
+				Info("attaching target groups")
+			_, err = opts.AutoScalingService.AttachLoadBalancerTargetGroups(ctx, &autoscaling.AttachLoadBalancerTargetGroupsInput{
+				AutoScalingGroupName: asg.Name,
+				TargetGroupARNs:      aws.StringSlice(toAttach),
+			})
+			if err != nil {
+				return updated, fmt.Errorf("error attaching target groups to nodegroup [%s] in cluster [%s]: %w", ngName, opts.Config.Name, err)
+			}
+			updated = true
+		}
+
+		if len(toDetach) != 0 {
+			clusterLogger(opts.Config, "UpdateNodegroupTargetGroups").
+				WithField(nodegroupLogField, ngName).
+				Info("detaching target groups")
+			_, err = opts.AutoScalingService.DetachLoadBalancerTargetGroups(ctx, &autoscaling.DetachLoadBalancerTargetGroupsInput{
+				AutoScalingGroupName: asg.Name,
+				TargetGroupARNs:      aws.StringSlice(toDetach),
+			})
+			if err != nil {
+				return updated, fmt.Errorf("error detaching target groups from nodegroup [%s] in cluster [%s]: %w", ngName, opts.Config.Name, err)
+			}
+			updated = true
+		}
+	}
+
+	if len(opts.NodeGroup.TargetGroupARNs) == 0 {
+		delete(opts.Config.Status.NodegroupTargetGroupARNs, ngName)
+		return updated, nil
+	}
+	if opts.Config.Status.NodegroupTargetGroupARNs == nil {
+		opts.Config.Status.NodegroupTargetGroupARNs = make(map[string][]string)
+	}
+	opts.Config.Status.NodegroupTargetGroupARNs[ngName] = append([]string(nil), opts.NodeGroup.TargetGroupARNs...)
+
+	return updated, nil
+}
+
+// ValidateTargetGroupARNs ensures the target groups of the node group are given by their elastic load balancing ARN.
+func ValidateTargetGroupARNs(group eksv1.NodeGroup) error {
+	for _, tgARN := range group.TargetGroupARNs {
+		parsed, err := arn.Parse(tgARN)
+		if err != nil {
+			return fmt.Errorf("nodegroup [%s]: invalid target group ARN [%s]: %w", aws.StringValue(group.NodegroupName), tgARN, err)
+		}
+		if parsed.Service != "elasticloadbalancing" || !strings.HasPrefix(parsed.Resource, "targetgroup/") {
+			return fmt.Errorf("nodegroup [%s]: invalid target group ARN [%s]: not an elastic load balancing target group",
+				aws.StringValue(group.NodegroupName), tgARN)
+		}
+	}
+
+	return nil
+}
+
+// getLoggingTypesUpdate returns the logging update enabling and disabling log types to get from the upstream logging
+// types to the desired ones, or nil if there is nothing to update. Nil logging types are left as they are upstream,
+// as opposed to empty logging types which disable every upstream log type.
 func getLoggingTypesUpdate(loggingTypes []string, upstreamLoggingTypes []string) *eks.Logging {
+	if loggingTypes == nil {
+		return nil
+	}
+
 	loggingUpdate := &eks.Logging{}
 
 	if loggingTypesToDisable := getLoggingTypesToDisable(loggingTypes, upstreamLoggingTypes); loggingTypesToDisable != nil {
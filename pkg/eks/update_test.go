@@ -1,9 +1,14 @@
 package eks
 
 import (
+	"context"
 	"errors"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/eks"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo/v2"
@@ -31,11 +36,11 @@ var _ = Describe("UpdateClusterVersion", func() {
 				},
 				Spec: eksv1.EKSClusterConfigSpec{
 					DisplayName:       "test-cluster",
-					KubernetesVersion: aws.String("test1"),
+					KubernetesVersion: aws.String("1.27"),
 				},
 			},
 			UpstreamClusterSpec: &eksv1.EKSClusterConfigSpec{
-				KubernetesVersion: aws.String("test2"),
+				KubernetesVersion: aws.String("1.26"),
 			},
 		}
 	})
@@ -46,29 +51,73 @@ var _ = Describe("UpdateClusterVersion", func() {
 
 	It("should update cluster version", func() {
 		eksServiceMock.EXPECT().UpdateClusterVersion(
+			gomock.Any(),
 			&eks.UpdateClusterVersionInput{
 				Name:    aws.String(updateClusterVersionOptions.Config.Spec.DisplayName),
 				Version: updateClusterVersionOptions.Config.Spec.KubernetesVersion,
 			},
 		).Return(nil, nil)
-		updated, err := UpdateClusterVersion(updateClusterVersionOptions)
+		updated, err := UpdateClusterVersion(context.Background(), updateClusterVersionOptions)
 		Expect(updated).To(BeTrue())
 		Expect(err).NotTo(HaveOccurred())
 	})
 
 	It("should not update cluster version if version didn't change", func() {
-		updateClusterVersionOptions.UpstreamClusterSpec.KubernetesVersion = aws.String("test1")
-		updated, err := UpdateClusterVersion(updateClusterVersionOptions)
+		updateClusterVersionOptions.UpstreamClusterSpec.KubernetesVersion = aws.String("1.27")
+		updated, err := UpdateClusterVersion(context.Background(), updateClusterVersionOptions)
 		Expect(updated).To(BeFalse())
 		Expect(err).NotTo(HaveOccurred())
 	})
 
 	It("should return error if update cluster version failed", func() {
-		eksServiceMock.EXPECT().UpdateClusterVersion(gomock.Any()).Return(nil, errors.New("error updating cluster version"))
-		updated, err := UpdateClusterVersion(updateClusterVersionOptions)
+		eksServiceMock.EXPECT().UpdateClusterVersion(gomock.Any(), gomock.Any()).Return(nil, errors.New("error updating cluster version"))
+		updated, err := UpdateClusterVersion(context.Background(), updateClusterVersionOptions)
 		Expect(updated).To(BeFalse())
 		Expect(err).To(HaveOccurred())
 	})
+
+	It("should not update cluster version if an add-on is not compatible with it", func() {
+		updateClusterVersionOptions.Config.Spec.Addons = []eksv1.Addon{
+			{
+				Name:    "vpc-cni",
+				Version: aws.String("v1.10.4-eksbuild.1"),
+			},
+		}
+		eksServiceMock.EXPECT().DescribeAddonVersions(gomock.Any(), gomock.Any()).Return(&eks.DescribeAddonVersionsOutput{
+			Addons: []*eks.AddonInfo{
+				{
+					AddonName: aws.String("vpc-cni"),
+					AddonVersions: []*eks.AddonVersionInfo{
+						{AddonVersion: aws.String("v1.12.6-eksbuild.2")},
+					},
+				},
+			},
+		}, nil)
+		updated, err := UpdateClusterVersion(context.Background(), updateClusterVersionOptions)
+		Expect(updated).To(BeFalse())
+		Expect(err).To(MatchError(ContainSubstring("vpc-cni [v1.10.4-eksbuild.1] must be updated to a compatible version")))
+	})
+
+	It("should not update cluster version if the version is malformed", func() {
+		updateClusterVersionOptions.Config.Spec.KubernetesVersion = aws.String("1.27.3")
+		updated, err := UpdateClusterVersion(context.Background(), updateClusterVersionOptions)
+		Expect(updated).To(BeFalse())
+		Expect(err).To(MatchError(ContainSubstring("invalid kubernetes version [1.27.3]")))
+	})
+
+	It("should not downgrade cluster version", func() {
+		updateClusterVersionOptions.UpstreamClusterSpec.KubernetesVersion = aws.String("1.29")
+		updated, err := UpdateClusterVersion(context.Background(), updateClusterVersionOptions)
+		Expect(updated).To(BeFalse())
+		Expect(err).To(MatchError(ContainSubstring("kubernetes version cannot be downgraded from 1.29 to 1.27")))
+	})
+
+	It("should not skip minor versions when updating cluster version", func() {
+		updateClusterVersionOptions.Config.Spec.KubernetesVersion = aws.String("1.29")
+		updated, err := UpdateClusterVersion(context.Background(), updateClusterVersionOptions)
+		Expect(updated).To(BeFalse())
+		Expect(err).To(MatchError(ContainSubstring("upgrade from 1.26 to 1.27 first")))
+	})
 })
 
 var _ = Describe("UpdateResourceTags", func() {
@@ -100,8 +149,35 @@ var _ = Describe("UpdateResourceTags", func() {
 		mockController.Finish()
 	})
 
+	expectTagReads := func(tags map[string]string) {
+		eksServiceMock.EXPECT().ListTagsForResource(
+			gomock.Any(),
+			&eks.ListTagsForResourceInput{
+				ResourceArn: aws.String(updateResourceTagsOpts.ResourceARN),
+			},
+		).Return(&eks.ListTagsForResourceOutput{Tags: aws.StringMap(tags)}, nil)
+	}
+
+	It("should not untag system tags", func() {
+		updateResourceTagsOpts.UpstreamTags = map[string]string{
+			"test1":                              "test1",
+			"test2":                              "changed",
+			"aws:cloudformation:stack-name":      "test-stack",
+			"kubernetes.io/cluster/test-cluster": "owned",
+		}
+		eksServiceMock.EXPECT().ListTagsForResource(gomock.Any(), gomock.Any()).Times(0)
+		eksServiceMock.EXPECT().TagResource(gomock.Any(), gomock.Any()).Times(0)
+		eksServiceMock.EXPECT().UntagResource(gomock.Any(), gomock.Any()).Times(0)
+
+		updated, err := UpdateResourceTags(context.Background(), updateResourceTagsOpts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+
 	It("should update cluster tags", func() {
+		expectTagReads(updateResourceTagsOpts.UpstreamTags)
 		eksServiceMock.EXPECT().TagResource(
+			gomock.Any(),
 			&eks.TagResourceInput{
 				ResourceArn: aws.String(updateResourceTagsOpts.ResourceARN),
 				Tags: map[string]*string{
@@ -110,12 +186,13 @@ var _ = Describe("UpdateResourceTags", func() {
 			},
 		).Return(nil, nil)
 		eksServiceMock.EXPECT().UntagResource(
+			gomock.Any(),
 			&eks.UntagResourceInput{
 				ResourceArn: aws.String(updateResourceTagsOpts.ResourceARN),
 				TagKeys:     []*string{aws.String("test3")},
 			},
 		).Return(nil, nil)
-		updated, err := UpdateResourceTags(updateResourceTagsOpts)
+		updated, err := UpdateResourceTags(context.Background(), updateResourceTagsOpts)
 		Expect(updated).To(BeTrue())
 		Expect(err).NotTo(HaveOccurred())
 	})
@@ -125,7 +202,9 @@ var _ = Describe("UpdateResourceTags", func() {
 			"test1": "test1",
 			"test2": "test2",
 		}
+		expectTagReads(updateResourceTagsOpts.UpstreamTags)
 		eksServiceMock.EXPECT().TagResource(
+			gomock.Any(),
 			&eks.TagResourceInput{
 				ResourceArn: aws.String(updateResourceTagsOpts.ResourceARN),
 				Tags: map[string]*string{
@@ -133,7 +212,7 @@ var _ = Describe("UpdateResourceTags", func() {
 				},
 			},
 		).Return(nil, nil)
-		updated, err := UpdateResourceTags(updateResourceTagsOpts)
+		updated, err := UpdateResourceTags(context.Background(), updateResourceTagsOpts)
 		Expect(updated).To(BeTrue())
 		Expect(err).NotTo(HaveOccurred())
 	})
@@ -143,13 +222,15 @@ var _ = Describe("UpdateResourceTags", func() {
 			"test1": "test1",
 			"test2": "test2",
 		}
+		expectTagReads(updateResourceTagsOpts.UpstreamTags)
 		eksServiceMock.EXPECT().UntagResource(
+			gomock.Any(),
 			&eks.UntagResourceInput{
 				ResourceArn: aws.String(updateResourceTagsOpts.ResourceARN),
 				TagKeys:     []*string{aws.String("test3")},
 			},
 		).Return(nil, nil)
-		updated, err := UpdateResourceTags(updateResourceTagsOpts)
+		updated, err := UpdateResourceTags(context.Background(), updateResourceTagsOpts)
 		Expect(updated).To(BeTrue())
 		Expect(err).NotTo(HaveOccurred())
 	})
@@ -163,27 +244,173 @@ var _ = Describe("UpdateResourceTags", func() {
 			"test1": "test1",
 			"test2": "test2",
 		}
-		updated, err := UpdateResourceTags(updateResourceTagsOpts)
+		updated, err := UpdateResourceTags(context.Background(), updateResourceTagsOpts)
 		Expect(updated).To(BeFalse())
 		Expect(err).NotTo(HaveOccurred())
 	})
 
 	It("should return error if update cluster tags failed", func() {
-		eksServiceMock.EXPECT().TagResource(gomock.Any()).Return(nil, errors.New("error tagging resource"))
-		updated, err := UpdateResourceTags(updateResourceTagsOpts)
+		expectTagReads(updateResourceTagsOpts.UpstreamTags)
+		eksServiceMock.EXPECT().TagResource(gomock.Any(), gomock.Any()).Return(nil, errors.New("error tagging resource"))
+		updated, err := UpdateResourceTags(context.Background(), updateResourceTagsOpts)
 		Expect(updated).To(BeFalse())
 		Expect(err).To(HaveOccurred())
 	})
 
 	It("should return error if untag cluster tags failed", func() {
-		eksServiceMock.EXPECT().TagResource(gomock.Any()).Return(nil, nil)
-		eksServiceMock.EXPECT().UntagResource(gomock.Any()).Return(nil, errors.New("error untagging resource"))
-		updated, err := UpdateResourceTags(updateResourceTagsOpts)
+		expectTagReads(updateResourceTagsOpts.UpstreamTags)
+		eksServiceMock.EXPECT().TagResource(gomock.Any(), gomock.Any()).Return(nil, nil)
+		eksServiceMock.EXPECT().UntagResource(gomock.Any(), gomock.Any()).Return(nil, errors.New("error untagging resource"))
+		updated, err := UpdateResourceTags(context.Background(), updateResourceTagsOpts)
+		Expect(updated).To(BeFalse())
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should not update cluster tags if a fresh tag read is consistent with stale upstream tags", func() {
+		eksServiceMock.EXPECT().ListTagsForResource(gomock.Any(), gomock.Any()).Return(
+			&eks.ListTagsForResourceOutput{Tags: aws.StringMap(updateResourceTagsOpts.Tags)}, nil)
+		updated, err := UpdateResourceTags(context.Background(), updateResourceTagsOpts)
+		Expect(updated).To(BeFalse())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should return error if listing tags failed", func() {
+		eksServiceMock.EXPECT().ListTagsForResource(gomock.Any(), gomock.Any()).Return(nil, errors.New("error listing tags"))
+		updated, err := UpdateResourceTags(context.Background(), updateResourceTagsOpts)
 		Expect(updated).To(BeFalse())
 		Expect(err).To(HaveOccurred())
 	})
 })
 
+var _ = Describe("UpdateNodegroupTags", func() {
+	var (
+		mockController *gomock.Controller
+		eksServiceMock *mock_services.MockEKSServiceInterface
+		opts           *UpdateNodegroupTagsOpts
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+		opts = &UpdateNodegroupTagsOpts{
+			EKSService: eksServiceMock,
+			Config: &eksv1.EKSClusterConfig{
+				Spec: eksv1.EKSClusterConfigSpec{
+					DisplayName: "test",
+				},
+			},
+			NodeGroup: &eksv1.NodeGroup{
+				NodegroupName: aws.String("ng1"),
+				Tags:          aws.StringMap(map[string]string{"test1": "test1"}),
+				ResourceTags:  aws.StringMap(map[string]string{"test2": "test2", "test1": "overridden"}),
+			},
+			UpstreamNodeGroup: &eksv1.NodeGroup{
+				Tags: aws.StringMap(map[string]string{"test1": "test1", "test2": "test2"}),
+			},
+			NodegroupARN: "test-nodegroup-arn",
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	expectTagReads := func(tags map[string]string) {
+		eksServiceMock.EXPECT().ListTagsForResource(
+			gomock.Any(),
+			&eks.ListTagsForResourceInput{
+				ResourceArn: aws.String(opts.NodegroupARN),
+			},
+		).Return(&eks.ListTagsForResourceOutput{Tags: aws.StringMap(tags)}, nil)
+	}
+
+	It("should not update the node group tags if they didn't change", func() {
+		updated, err := UpdateNodegroupTags(context.Background(), opts)
+		Expect(updated).To(BeFalse())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should only add the added resource tags", func() {
+		opts.NodeGroup.ResourceTags["test3"] = aws.String("test3")
+		expectTagReads(aws.StringValueMap(opts.UpstreamNodeGroup.Tags))
+		eksServiceMock.EXPECT().TagResource(
+			gomock.Any(),
+			&eks.TagResourceInput{
+				ResourceArn: aws.String(opts.NodegroupARN),
+				Tags: map[string]*string{
+					"test3": aws.String("test3"),
+				},
+			},
+		).Return(nil, nil)
+
+		updated, err := UpdateNodegroupTags(context.Background(), opts)
+		Expect(updated).To(BeTrue())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should only remove the removed resource tags", func() {
+		delete(opts.NodeGroup.ResourceTags, "test2")
+		expectTagReads(aws.StringValueMap(opts.UpstreamNodeGroup.Tags))
+		eksServiceMock.EXPECT().UntagResource(
+			gomock.Any(),
+			&eks.UntagResourceInput{
+				ResourceArn: aws.String(opts.NodegroupARN),
+				TagKeys:     []*string{aws.String("test2")},
+			},
+		).Return(nil, nil)
+
+		updated, err := UpdateNodegroupTags(context.Background(), opts)
+		Expect(updated).To(BeTrue())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should add and remove tags in the same update", func() {
+		opts.NodeGroup.ResourceTags = aws.StringMap(map[string]string{"test3": "test3"})
+		expectTagReads(aws.StringValueMap(opts.UpstreamNodeGroup.Tags))
+		eksServiceMock.EXPECT().TagResource(gomock.Any(), &eks.TagResourceInput{
+			ResourceArn: aws.String(opts.NodegroupARN),
+			Tags:        map[string]*string{"test3": aws.String("test3")},
+		}).Return(nil, nil)
+		eksServiceMock.EXPECT().UntagResource(gomock.Any(), &eks.UntagResourceInput{
+			ResourceArn: aws.String(opts.NodegroupARN),
+			TagKeys:     []*string{aws.String("test2")},
+		}).Return(nil, nil)
+
+		updated, err := UpdateNodegroupTags(context.Background(), opts)
+		Expect(updated).To(BeTrue())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should leave the tags alone if the node group tags are not set", func() {
+		opts.NodeGroup.Tags = nil
+		opts.NodeGroup.ResourceTags = nil
+
+		updated, err := UpdateNodegroupTags(context.Background(), opts)
+		Expect(updated).To(BeFalse())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should return error if tagging the node group failed", func() {
+		opts.NodeGroup.ResourceTags["test3"] = aws.String("test3")
+		expectTagReads(aws.StringValueMap(opts.UpstreamNodeGroup.Tags))
+		eksServiceMock.EXPECT().TagResource(gomock.Any(), gomock.Any()).Return(nil, errors.New("error tagging resource"))
+
+		updated, err := UpdateNodegroupTags(context.Background(), opts)
+		Expect(updated).To(BeFalse())
+		Expect(err).To(MatchError(ContainSubstring("error tagging nodegroup [ng1] in cluster [test]")))
+	})
+
+	It("should return error if untagging the node group failed", func() {
+		delete(opts.NodeGroup.ResourceTags, "test2")
+		expectTagReads(aws.StringValueMap(opts.UpstreamNodeGroup.Tags))
+		eksServiceMock.EXPECT().UntagResource(gomock.Any(), gomock.Any()).Return(nil, errors.New("error untagging resource"))
+
+		updated, err := UpdateNodegroupTags(context.Background(), opts)
+		Expect(updated).To(BeFalse())
+		Expect(err).To(MatchError(ContainSubstring("error untagging nodegroup [ng1] in cluster [test]")))
+	})
+})
+
 var _ = Describe("UpdateLoggingTypes", func() {
 	var (
 		mockController         *gomock.Controller
@@ -198,11 +425,11 @@ var _ = Describe("UpdateLoggingTypes", func() {
 			EKSService: eksServiceMock,
 			Config: &eksv1.EKSClusterConfig{
 				Spec: eksv1.EKSClusterConfigSpec{
-					LoggingTypes: []string{"test1", "test2", "test3-enabled"},
+					LoggingTypes: []string{"api", "audit", "scheduler"},
 				},
 			},
 			UpstreamClusterSpec: &eksv1.EKSClusterConfigSpec{
-				LoggingTypes: []string{"test1", "test2", "disabled"},
+				LoggingTypes: []string{"api", "audit", "authenticator"},
 			},
 		}
 	})
@@ -213,42 +440,244 @@ var _ = Describe("UpdateLoggingTypes", func() {
 
 	It("should update cluster logging types", func() {
 		eksServiceMock.EXPECT().UpdateClusterConfig(
+			gomock.Any(),
 			&eks.UpdateClusterConfigInput{
 				Name: aws.String(updateLoggingTypesOpts.Config.Spec.DisplayName),
 				Logging: &eks.Logging{
 					ClusterLogging: []*eks.LogSetup{
 						{
 							Enabled: aws.Bool(false),
-							Types:   []*string{aws.String("disabled")},
+							Types:   []*string{aws.String("authenticator")},
 						},
 						{
 							Enabled: aws.Bool(true),
-							Types:   []*string{aws.String("test3-enabled")},
+							Types:   []*string{aws.String("scheduler")},
 						},
 					},
 				},
 			},
 		).Return(nil, nil)
-		updated, err := UpdateClusterLoggingTypes(updateLoggingTypesOpts)
+		updated, err := UpdateClusterLoggingTypes(context.Background(), updateLoggingTypesOpts)
 		Expect(updated).To(BeTrue())
 		Expect(err).NotTo(HaveOccurred())
 	})
 
 	It("should not update cluster logging types if logging types didn't change", func() {
-		updateLoggingTypesOpts.UpstreamClusterSpec.LoggingTypes = []string{"test1", "test2", "test3-enabled"}
-		updated, err := UpdateClusterLoggingTypes(updateLoggingTypesOpts)
+		updateLoggingTypesOpts.UpstreamClusterSpec.LoggingTypes = []string{"api", "audit", "scheduler"}
+		updated, err := UpdateClusterLoggingTypes(context.Background(), updateLoggingTypesOpts)
+		Expect(updated).To(BeFalse())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should not update cluster logging types if logging types are nil", func() {
+		updateLoggingTypesOpts.Config.Spec.LoggingTypes = nil
+		eksServiceMock.EXPECT().UpdateClusterConfig(gomock.Any(), gomock.Any()).Times(0)
+
+		updated, err := UpdateClusterLoggingTypes(context.Background(), updateLoggingTypesOpts)
+		Expect(updated).To(BeFalse())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should disable all upstream logging types if logging types are empty", func() {
+		updateLoggingTypesOpts.Config.Spec.LoggingTypes = []string{}
+		eksServiceMock.EXPECT().UpdateClusterConfig(gomock.Any(), &eks.UpdateClusterConfigInput{
+			Name: aws.String(updateLoggingTypesOpts.Config.Spec.DisplayName),
+			Logging: &eks.Logging{
+				ClusterLogging: []*eks.LogSetup{
+					{
+						Enabled: aws.Bool(false),
+						Types:   aws.StringSlice([]string{"api", "audit", "authenticator"}),
+					},
+				},
+			},
+		}).Return(nil, nil)
+
+		updated, err := UpdateClusterLoggingTypes(context.Background(), updateLoggingTypesOpts)
+		Expect(updated).To(BeTrue())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should not update cluster logging types if logging types are empty and none are enabled upstream", func() {
+		updateLoggingTypesOpts.Config.Spec.LoggingTypes = []string{}
+		updateLoggingTypesOpts.UpstreamClusterSpec.LoggingTypes = []string{}
+		eksServiceMock.EXPECT().UpdateClusterConfig(gomock.Any(), gomock.Any()).Times(0)
+
+		updated, err := UpdateClusterLoggingTypes(context.Background(), updateLoggingTypesOpts)
 		Expect(updated).To(BeFalse())
 		Expect(err).NotTo(HaveOccurred())
 	})
 
+	It("should only enable the logging types that are not enabled upstream", func() {
+		updateLoggingTypesOpts.Config.Spec.LoggingTypes = []string{"api", "audit", "authenticator", "scheduler"}
+		eksServiceMock.EXPECT().UpdateClusterConfig(gomock.Any(), &eks.UpdateClusterConfigInput{
+			Name: aws.String(updateLoggingTypesOpts.Config.Spec.DisplayName),
+			Logging: &eks.Logging{
+				ClusterLogging: []*eks.LogSetup{
+					{
+						Enabled: aws.Bool(true),
+						Types:   aws.StringSlice([]string{"scheduler"}),
+					},
+				},
+			},
+		}).Return(nil, nil)
+
+		updated, err := UpdateClusterLoggingTypes(context.Background(), updateLoggingTypesOpts)
+		Expect(updated).To(BeTrue())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should fail to update cluster logging types with invalid logging types", func() {
+		updateLoggingTypesOpts.Config.Spec.LoggingTypes = []string{"api", "apiserver", "controllermanager"}
+		eksServiceMock.EXPECT().UpdateClusterConfig(gomock.Any(), gomock.Any()).Times(0)
+
+		updated, err := UpdateClusterLoggingTypes(context.Background(), updateLoggingTypesOpts)
+		Expect(updated).To(BeFalse())
+		Expect(err).To(MatchError(ContainSubstring("invalid logging types [apiserver controllermanager]")))
+	})
+
 	It("should return error if update cluster logging types failed", func() {
-		eksServiceMock.EXPECT().UpdateClusterConfig(gomock.Any()).Return(nil, errors.New("error updating cluster config"))
-		updated, err := UpdateClusterLoggingTypes(updateLoggingTypesOpts)
+		eksServiceMock.EXPECT().UpdateClusterConfig(gomock.Any(), gomock.Any()).Return(nil, errors.New("error updating cluster config"))
+		updated, err := UpdateClusterLoggingTypes(context.Background(), updateLoggingTypesOpts)
+		Expect(updated).To(BeFalse())
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("UpdateClusterLogRetention", func() {
+	var (
+		mockController                *gomock.Controller
+		cloudWatchLogsServiceMock     *mock_services.MockCloudWatchLogsServiceInterface
+		updateClusterLogRetentionOpts *UpdateClusterLogRetentionOpts
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		cloudWatchLogsServiceMock = mock_services.NewMockCloudWatchLogsServiceInterface(mockController)
+		updateClusterLogRetentionOpts = &UpdateClusterLogRetentionOpts{
+			CloudWatchLogsService: cloudWatchLogsServiceMock,
+			Config: &eksv1.EKSClusterConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-cluster",
+				},
+				Spec: eksv1.EKSClusterConfigSpec{
+					DisplayName:      "test",
+					LoggingTypes:     []string{"audit"},
+					LogRetentionDays: aws.Int64(30),
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should set log retention", func() {
+		cloudWatchLogsServiceMock.EXPECT().DescribeLogGroups(gomock.Any(), &cloudwatchlogs.DescribeLogGroupsInput{
+			LogGroupNamePrefix: aws.String("/aws/eks/test/cluster"),
+		}).Return(&cloudwatchlogs.DescribeLogGroupsOutput{
+			LogGroups: []*cloudwatchlogs.LogGroup{
+				{LogGroupName: aws.String("/aws/eks/test/cluster")},
+			},
+		}, nil)
+		cloudWatchLogsServiceMock.EXPECT().PutRetentionPolicy(gomock.Any(), &cloudwatchlogs.PutRetentionPolicyInput{
+			LogGroupName:    aws.String("/aws/eks/test/cluster"),
+			RetentionInDays: aws.Int64(30),
+		}).Return(nil, nil)
+
+		updated, err := UpdateClusterLogRetention(context.Background(), updateClusterLogRetentionOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeTrue())
+		Expect(updateClusterLogRetentionOpts.Config.Status.LogRetentionDays).To(Equal(int64(30)))
+	})
+
+	It("should not update log retention if it is already set", func() {
+		cloudWatchLogsServiceMock.EXPECT().DescribeLogGroups(gomock.Any(), gomock.Any()).Return(&cloudwatchlogs.DescribeLogGroupsOutput{
+			LogGroups: []*cloudwatchlogs.LogGroup{
+				{LogGroupName: aws.String("/aws/eks/test/cluster"), RetentionInDays: aws.Int64(30)},
+			},
+		}, nil)
+
+		updated, err := UpdateClusterLogRetention(context.Background(), updateClusterLogRetentionOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeFalse())
+		Expect(updateClusterLogRetentionOpts.Config.Status.LogRetentionDays).To(Equal(int64(30)))
+	})
+
+	It("should not describe the log group if the retention was applied already", func() {
+		updateClusterLogRetentionOpts.Config.Status.LogRetentionDays = 30
+		cloudWatchLogsServiceMock.EXPECT().DescribeLogGroups(gomock.Any(), gomock.Any()).Times(0)
+
+		updated, err := UpdateClusterLogRetention(context.Background(), updateClusterLogRetentionOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+
+	It("should update log retention if it changed since it was applied", func() {
+		updateClusterLogRetentionOpts.Config.Status.LogRetentionDays = 7
+		cloudWatchLogsServiceMock.EXPECT().DescribeLogGroups(gomock.Any(), gomock.Any()).Return(&cloudwatchlogs.DescribeLogGroupsOutput{
+			LogGroups: []*cloudwatchlogs.LogGroup{
+				{LogGroupName: aws.String("/aws/eks/test/cluster"), RetentionInDays: aws.Int64(7)},
+			},
+		}, nil)
+		cloudWatchLogsServiceMock.EXPECT().PutRetentionPolicy(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+		updated, err := UpdateClusterLogRetention(context.Background(), updateClusterLogRetentionOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeTrue())
+		Expect(updateClusterLogRetentionOpts.Config.Status.LogRetentionDays).To(Equal(int64(30)))
+	})
+
+	It("should return ErrLogGroupNotFound if the log group is not created yet", func() {
+		cloudWatchLogsServiceMock.EXPECT().DescribeLogGroups(gomock.Any(), gomock.Any()).Return(&cloudwatchlogs.DescribeLogGroupsOutput{
+			LogGroups: []*cloudwatchlogs.LogGroup{
+				{LogGroupName: aws.String("/aws/eks/test/cluster-other"), RetentionInDays: aws.Int64(7)},
+			},
+		}, nil)
+		cloudWatchLogsServiceMock.EXPECT().PutRetentionPolicy(gomock.Any(), gomock.Any()).Times(0)
+
+		updated, err := UpdateClusterLogRetention(context.Background(), updateClusterLogRetentionOpts)
+		Expect(err).To(MatchError(ErrLogGroupNotFound))
 		Expect(updated).To(BeFalse())
+	})
+
+	It("should not update log retention if logging is disabled", func() {
+		updateClusterLogRetentionOpts.Config.Spec.LoggingTypes = nil
+
+		updated, err := UpdateClusterLogRetention(context.Background(), updateClusterLogRetentionOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+
+	It("should fail if log retention is invalid", func() {
+		updateClusterLogRetentionOpts.Config.Spec.LogRetentionDays = aws.Int64(2)
+
+		_, err := UpdateClusterLogRetention(context.Background(), updateClusterLogRetentionOpts)
 		Expect(err).To(HaveOccurred())
 	})
 })
 
+var _ = Describe("ValidateLogRetention", func() {
+	DescribeTable("should validate the log retention",
+		func(retentionDays *int64, valid bool) {
+			err := ValidateLogRetention(&eksv1.EKSClusterConfig{
+				Spec: eksv1.EKSClusterConfigSpec{
+					LogRetentionDays: retentionDays,
+				},
+			})
+			if valid {
+				Expect(err).ToNot(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(ContainSubstring("invalid log retention days")))
+			}
+		},
+		Entry("not set", nil, true),
+		Entry("supported period", aws.Int64(14), true),
+		Entry("unsupported period", aws.Int64(2), false),
+		Entry("zero", aws.Int64(0), false),
+	)
+})
+
 var _ = Describe("UpdateClusterAccess", func() {
 	var (
 		mockController          *gomock.Controller
@@ -280,6 +709,7 @@ var _ = Describe("UpdateClusterAccess", func() {
 
 	It("should update cluster access", func() {
 		eksServiceMock.EXPECT().UpdateClusterConfig(
+			gomock.Any(),
 			&eks.UpdateClusterConfigInput{
 				Name: aws.String(updateClusterAccessOpts.Config.Spec.DisplayName),
 				ResourcesVpcConfig: &eks.VpcConfigRequest{
@@ -288,7 +718,7 @@ var _ = Describe("UpdateClusterAccess", func() {
 				},
 			},
 		).Return(nil, nil)
-		updated, err := UpdateClusterAccess(updateClusterAccessOpts)
+		updated, err := UpdateClusterAccess(context.Background(), updateClusterAccessOpts)
 		Expect(updated).To(BeTrue())
 		Expect(err).NotTo(HaveOccurred())
 	})
@@ -296,14 +726,14 @@ var _ = Describe("UpdateClusterAccess", func() {
 	It("should not update cluster access if access didn't change", func() {
 		updateClusterAccessOpts.UpstreamClusterSpec.PrivateAccess = aws.Bool(true)
 		updateClusterAccessOpts.UpstreamClusterSpec.PublicAccess = aws.Bool(true)
-		updated, err := UpdateClusterAccess(updateClusterAccessOpts)
+		updated, err := UpdateClusterAccess(context.Background(), updateClusterAccessOpts)
 		Expect(updated).To(BeFalse())
 		Expect(err).NotTo(HaveOccurred())
 	})
 
 	It("should return error if update cluster access failed", func() {
-		eksServiceMock.EXPECT().UpdateClusterConfig(gomock.Any()).Return(nil, errors.New("error updating cluster config"))
-		updated, err := UpdateClusterAccess(updateClusterAccessOpts)
+		eksServiceMock.EXPECT().UpdateClusterConfig(gomock.Any(), gomock.Any()).Return(nil, errors.New("error updating cluster config"))
+		updated, err := UpdateClusterAccess(context.Background(), updateClusterAccessOpts)
 		Expect(updated).To(BeFalse())
 		Expect(err).To(HaveOccurred())
 	})
@@ -338,6 +768,7 @@ var _ = Describe("UpdateClusterPublicAccessSources", func() {
 
 	It("should update cluster public access sources", func() {
 		eksServiceMock.EXPECT().UpdateClusterConfig(
+			gomock.Any(),
 			&eks.UpdateClusterConfigInput{
 				Name: aws.String(updateClusterPublicAccessSourcesOpts.Config.Spec.DisplayName),
 				ResourcesVpcConfig: &eks.VpcConfigRequest{
@@ -345,50 +776,51 @@ var _ = Describe("UpdateClusterPublicAccessSources", func() {
 				},
 			},
 		).Return(nil, nil)
-		updated, err := UpdateClusterPublicAccessSources(updateClusterPublicAccessSourcesOpts)
+		updated, err := UpdateClusterPublicAccessSources(context.Background(), updateClusterPublicAccessSourcesOpts)
 		Expect(updated).To(BeTrue())
 		Expect(err).NotTo(HaveOccurred())
 	})
 
 	It("should not update cluster public access sources if public access sources didn't change", func() {
 		updateClusterPublicAccessSourcesOpts.UpstreamClusterSpec.PublicAccessSources = []string{"test1", "test2"}
-		updated, err := UpdateClusterPublicAccessSources(updateClusterPublicAccessSourcesOpts)
+		updated, err := UpdateClusterPublicAccessSources(context.Background(), updateClusterPublicAccessSourcesOpts)
 		Expect(updated).To(BeFalse())
 		Expect(err).NotTo(HaveOccurred())
 	})
 
 	It("should return error if update cluster public access sources failed", func() {
-		eksServiceMock.EXPECT().UpdateClusterConfig(gomock.Any()).Return(nil, errors.New("error updating cluster config"))
-		updated, err := UpdateClusterPublicAccessSources(updateClusterPublicAccessSourcesOpts)
+		eksServiceMock.EXPECT().UpdateClusterConfig(gomock.Any(), gomock.Any()).Return(nil, errors.New("error updating cluster config"))
+		updated, err := UpdateClusterPublicAccessSources(context.Background(), updateClusterPublicAccessSourcesOpts)
 		Expect(updated).To(BeFalse())
 		Expect(err).To(HaveOccurred())
 	})
 })
 
-var _ = Describe("UpdateNodegroupVersion", func() {
+var _ = Describe("UpdateClusterVpcAccess", func() {
 	var (
 		mockController             *gomock.Controller
 		eksServiceMock             *mock_services.MockEKSServiceInterface
-		ec2ServiceMock             *mock_services.MockEC2ServiceInterface
-		updateNodegroupVersionOpts *UpdateNodegroupVersionOpts
+		updateClusterVpcAccessOpts *UpdateClusterVpcAccessOpts
 	)
 
 	BeforeEach(func() {
 		mockController = gomock.NewController(GinkgoT())
 		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
-		ec2ServiceMock = mock_services.NewMockEC2ServiceInterface(mockController)
-		updateNodegroupVersionOpts = &UpdateNodegroupVersionOpts{
+		updateClusterVpcAccessOpts = &UpdateClusterVpcAccessOpts{
 			EKSService: eksServiceMock,
-			EC2Service: ec2ServiceMock,
 			Config: &eksv1.EKSClusterConfig{
-				Status: eksv1.EKSClusterConfigStatus{
-					ManagedLaunchTemplateID: "test",
+				Spec: eksv1.EKSClusterConfigSpec{
+					DisplayName:         "test",
+					PublicAccess:        aws.Bool(true),
+					PrivateAccess:       aws.Bool(true),
+					PublicAccessSources: []string{"10.0.0.0/16"},
 				},
 			},
-			NodeGroup: &eksv1.NodeGroup{
-				NodegroupName: aws.String("test"),
+			UpstreamClusterSpec: &eksv1.EKSClusterConfigSpec{
+				PublicAccess:        aws.Bool(true),
+				PrivateAccess:       aws.Bool(false),
+				PublicAccessSources: []string{"0.0.0.0/0"},
 			},
-			LTVersions: map[string]string{"test": "test"},
 		}
 	})
 
@@ -396,14 +828,1136 @@ var _ = Describe("UpdateNodegroupVersion", func() {
 		mockController.Finish()
 	})
 
-	It("should update node group version", func() {
-		eksServiceMock.EXPECT().UpdateNodegroupVersion(updateNodegroupVersionOpts.NGVersionInput).Return(nil, nil)
-		Expect(UpdateNodegroupVersion(updateNodegroupVersionOpts)).To(Succeed())
+	It("should update the endpoint access and the public access sources in a single call", func() {
+		eksServiceMock.EXPECT().UpdateClusterConfig(gomock.Any(), &eks.UpdateClusterConfigInput{
+			Name: aws.String("test"),
+			ResourcesVpcConfig: &eks.VpcConfigRequest{
+				EndpointPublicAccess:  aws.Bool(true),
+				EndpointPrivateAccess: aws.Bool(true),
+				PublicAccessCidrs:     aws.StringSlice([]string{"10.0.0.0/16"}),
+			},
+		}).Return(nil, nil).Times(1)
+
+		updated, err := UpdateClusterVpcAccess(context.Background(), updateClusterVpcAccessOpts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(BeTrue())
 	})
 
-	It("should delete launch template version if update fails", func() {
-		eksServiceMock.EXPECT().UpdateNodegroupVersion(updateNodegroupVersionOpts.NGVersionInput).Return(nil, errors.New("error"))
-		ec2ServiceMock.EXPECT().DeleteLaunchTemplateVersions(gomock.Any()).Return(nil, nil)
-		Expect(UpdateNodegroupVersion(updateNodegroupVersionOpts)).To(HaveOccurred())
+	It("should only update the endpoint access if the public access sources didn't change", func() {
+		updateClusterVpcAccessOpts.UpstreamClusterSpec.PublicAccessSources = []string{"10.0.0.0/16"}
+		eksServiceMock.EXPECT().UpdateClusterConfig(gomock.Any(), &eks.UpdateClusterConfigInput{
+			Name: aws.String("test"),
+			ResourcesVpcConfig: &eks.VpcConfigRequest{
+				EndpointPublicAccess:  aws.Bool(true),
+				EndpointPrivateAccess: aws.Bool(true),
+			},
+		}).Return(nil, nil)
+
+		updated, err := UpdateClusterVpcAccess(context.Background(), updateClusterVpcAccessOpts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(BeTrue())
+	})
+
+	It("should only update the public access sources if the endpoint access didn't change", func() {
+		updateClusterVpcAccessOpts.UpstreamClusterSpec.PrivateAccess = aws.Bool(true)
+		eksServiceMock.EXPECT().UpdateClusterConfig(gomock.Any(), &eks.UpdateClusterConfigInput{
+			Name: aws.String("test"),
+			ResourcesVpcConfig: &eks.VpcConfigRequest{
+				PublicAccessCidrs: aws.StringSlice([]string{"10.0.0.0/16"}),
+			},
+		}).Return(nil, nil)
+
+		updated, err := UpdateClusterVpcAccess(context.Background(), updateClusterVpcAccessOpts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(BeTrue())
+	})
+
+	It("should leave the public access sources alone if they are not set", func() {
+		updateClusterVpcAccessOpts.UpstreamClusterSpec.PrivateAccess = aws.Bool(true)
+		updateClusterVpcAccessOpts.Config.Spec.PublicAccessSources = nil
+
+		updated, err := UpdateClusterVpcAccess(context.Background(), updateClusterVpcAccessOpts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+
+	It("should return error if the update failed", func() {
+		eksServiceMock.EXPECT().UpdateClusterConfig(gomock.Any(), gomock.Any()).Return(nil, errors.New("error updating cluster config"))
+
+		updated, err := UpdateClusterVpcAccess(context.Background(), updateClusterVpcAccessOpts)
+		Expect(err).To(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+
+	It("should return a retriable error if another update is in progress", func() {
+		eksServiceMock.EXPECT().UpdateClusterConfig(gomock.Any(), gomock.Any()).Return(nil,
+			awserr.New(eks.ErrCodeResourceInUseException, "Cluster already has an update in progress", nil))
+
+		updated, err := UpdateClusterVpcAccess(context.Background(), updateClusterVpcAccessOpts)
+		Expect(err).To(MatchError(ErrUpdateInProgress))
+		Expect(IsRetriableUpdateConflict(err)).To(BeTrue())
+		Expect(updated).To(BeFalse())
+	})
+})
+
+var _ = Describe("UpdateClusterVpcConfig", func() {
+	var (
+		mockController             *gomock.Controller
+		eksServiceMock             *mock_services.MockEKSServiceInterface
+		updateClusterVpcConfigOpts *UpdateClusterVpcConfigOpts
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+		updateClusterVpcConfigOpts = &UpdateClusterVpcConfigOpts{
+			EKSService: eksServiceMock,
+			Config: &eksv1.EKSClusterConfig{
+				Spec: eksv1.EKSClusterConfigSpec{
+					DisplayName:    "test",
+					Subnets:        []string{"subnet1", "subnet2"},
+					SecurityGroups: []string{"sg1", "sg2"},
+				},
+				Status: eksv1.EKSClusterConfigStatus{
+					NetworkFieldsSource: "provided",
+					SecurityGroups:      []string{"sg1"},
+				},
+			},
+			UpstreamClusterSpec: &eksv1.EKSClusterConfigSpec{
+				Subnets:        []string{"subnet2", "subnet1"},
+				SecurityGroups: []string{"sg1"},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should add security groups", func() {
+		eksServiceMock.EXPECT().UpdateClusterConfig(gomock.Any(), &eks.UpdateClusterConfigInput{
+			Name: aws.String("test"),
+			ResourcesVpcConfig: &eks.VpcConfigRequest{
+				SecurityGroupIds: aws.StringSlice([]string{"sg1", "sg2"}),
+			},
+		}).Return(nil, nil)
+
+		updated, err := UpdateClusterVpcConfig(context.Background(), updateClusterVpcConfigOpts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(BeTrue())
+	})
+
+	It("should remove security groups", func() {
+		updateClusterVpcConfigOpts.Config.Spec.SecurityGroups = []string{"sg2"}
+		updateClusterVpcConfigOpts.UpstreamClusterSpec.SecurityGroups = []string{"sg1", "sg2"}
+		eksServiceMock.EXPECT().UpdateClusterConfig(gomock.Any(), &eks.UpdateClusterConfigInput{
+			Name: aws.String("test"),
+			ResourcesVpcConfig: &eks.VpcConfigRequest{
+				SecurityGroupIds: aws.StringSlice([]string{"sg2"}),
+			},
+		}).Return(nil, nil)
+
+		updated, err := UpdateClusterVpcConfig(context.Background(), updateClusterVpcConfigOpts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(BeTrue())
+	})
+
+	It("should restore the security groups of generated networking", func() {
+		updateClusterVpcConfigOpts.Config.Spec.Subnets = nil
+		updateClusterVpcConfigOpts.Config.Spec.SecurityGroups = nil
+		updateClusterVpcConfigOpts.Config.Status.NetworkFieldsSource = "generated"
+		updateClusterVpcConfigOpts.UpstreamClusterSpec.SecurityGroups = nil
+		eksServiceMock.EXPECT().UpdateClusterConfig(gomock.Any(), &eks.UpdateClusterConfigInput{
+			Name: aws.String("test"),
+			ResourcesVpcConfig: &eks.VpcConfigRequest{
+				SecurityGroupIds: aws.StringSlice([]string{"sg1"}),
+			},
+		}).Return(nil, nil)
+
+		updated, err := UpdateClusterVpcConfig(context.Background(), updateClusterVpcConfigOpts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(BeTrue())
+	})
+
+	It("should not update the security groups if they didn't change", func() {
+		updateClusterVpcConfigOpts.UpstreamClusterSpec.SecurityGroups = []string{"sg2", "sg1"}
+
+		updated, err := UpdateClusterVpcConfig(context.Background(), updateClusterVpcConfigOpts)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+
+	It("should reject subnet changes", func() {
+		updateClusterVpcConfigOpts.Config.Spec.Subnets = []string{"subnet1", "subnet3"}
+		eksServiceMock.EXPECT().UpdateClusterConfig(gomock.Any(), gomock.Any()).Times(0)
+
+		updated, err := UpdateClusterVpcConfig(context.Background(), updateClusterVpcConfigOpts)
+		Expect(err).To(MatchError(ContainSubstring("cannot be changed from [subnet2, subnet1] to [subnet1, subnet3] after creation")))
+		Expect(updated).To(BeFalse())
+	})
+
+	It("should return error if updating the security groups failed", func() {
+		eksServiceMock.EXPECT().UpdateClusterConfig(gomock.Any(), gomock.Any()).Return(nil, errors.New("error updating cluster config"))
+
+		updated, err := UpdateClusterVpcConfig(context.Background(), updateClusterVpcConfigOpts)
+		Expect(err).To(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+})
+
+var _ = Describe("UpdateNodegroupVersion", func() {
+	var (
+		mockController             *gomock.Controller
+		eksServiceMock             *mock_services.MockEKSServiceInterface
+		ec2ServiceMock             *mock_services.MockEC2ServiceInterface
+		updateNodegroupVersionOpts *UpdateNodegroupVersionOpts
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+		ec2ServiceMock = mock_services.NewMockEC2ServiceInterface(mockController)
+		updateNodegroupVersionOpts = &UpdateNodegroupVersionOpts{
+			EKSService: eksServiceMock,
+			EC2Service: ec2ServiceMock,
+			Config: &eksv1.EKSClusterConfig{
+				Status: eksv1.EKSClusterConfigStatus{
+					ManagedLaunchTemplateID: "test",
+				},
+			},
+			NodeGroup: &eksv1.NodeGroup{
+				NodegroupName: aws.String("test"),
+			},
+			NGVersionInput: &eks.UpdateNodegroupVersionInput{
+				ClusterName:   aws.String("test"),
+				NodegroupName: aws.String("test"),
+				Version:       aws.String("1.27"),
+			},
+			UpstreamVersion: aws.String("1.26"),
+			LTVersions:      map[string]string{"test": "test"},
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	expectLaunchTemplateVersion := func(amiType string, data *ec2.ResponseLaunchTemplateData) {
+		eksServiceMock.EXPECT().DescribeNodegroup(gomock.Any(), &eks.DescribeNodegroupInput{
+			ClusterName:   aws.String("test"),
+			NodegroupName: aws.String("test"),
+		}).Return(&eks.DescribeNodegroupOutput{
+			Nodegroup: &eks.Nodegroup{AmiType: aws.String(amiType)},
+		}, nil)
+		ec2ServiceMock.EXPECT().DescribeLaunchTemplateVersions(gomock.Any(), &ec2.DescribeLaunchTemplateVersionsInput{
+			LaunchTemplateId: aws.String("test"),
+			Versions:         aws.StringSlice([]string{"3"}),
+		}).Return(&ec2.DescribeLaunchTemplateVersionsOutput{
+			LaunchTemplateVersions: []*ec2.LaunchTemplateVersion{{LaunchTemplateData: data}},
+		}, nil)
+	}
+
+	expectInstanceType := func(instanceType string, architectures ...string) {
+		ec2ServiceMock.EXPECT().DescribeInstanceTypes(gomock.Any(), &ec2.DescribeInstanceTypesInput{
+			InstanceTypes: aws.StringSlice([]string{instanceType}),
+		}).Return(&ec2.DescribeInstanceTypesOutput{
+			InstanceTypes: []*ec2.InstanceTypeInfo{
+				{
+					InstanceType:  aws.String(instanceType),
+					ProcessorInfo: &ec2.ProcessorInfo{SupportedArchitectures: aws.StringSlice(architectures)},
+				},
+			},
+		}, nil)
+	}
+
+	It("should update node group version", func() {
+		eksServiceMock.EXPECT().UpdateNodegroupVersion(gomock.Any(), updateNodegroupVersionOpts.NGVersionInput).Return(nil, nil)
+		updated, err := UpdateNodegroupVersion(context.Background(), updateNodegroupVersionOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeTrue())
+	})
+
+	It("should not update node group version if it is unchanged", func() {
+		updateNodegroupVersionOpts.UpstreamVersion = aws.String("1.27")
+		updated, err := UpdateNodegroupVersion(context.Background(), updateNodegroupVersionOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+
+	It("should only update the launch template if the node group version is unchanged", func() {
+		updateNodegroupVersionOpts.UpstreamVersion = aws.String("1.27")
+		updateNodegroupVersionOpts.NGVersionInput.LaunchTemplate = &eks.LaunchTemplateSpecification{
+			Id:      aws.String("test"),
+			Version: aws.String("3"),
+		}
+		expectLaunchTemplateVersion(eks.AMITypesAl2X8664, &ec2.ResponseLaunchTemplateData{InstanceType: aws.String("m5.large")})
+		expectInstanceType("m5.large", ec2.ArchitectureTypeX8664)
+		eksServiceMock.EXPECT().UpdateNodegroupVersion(gomock.Any(), &eks.UpdateNodegroupVersionInput{
+			ClusterName:   aws.String("test"),
+			NodegroupName: aws.String("test"),
+			LaunchTemplate: &eks.LaunchTemplateSpecification{
+				Id:      aws.String("test"),
+				Version: aws.String("3"),
+			},
+		}).Return(nil, nil)
+		updated, err := UpdateNodegroupVersion(context.Background(), updateNodegroupVersionOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeTrue())
+	})
+
+	It("should force the node group version update", func() {
+		updateNodegroupVersionOpts.Force = true
+		eksServiceMock.EXPECT().UpdateNodegroupVersion(gomock.Any(), &eks.UpdateNodegroupVersionInput{
+			ClusterName:   aws.String("test"),
+			NodegroupName: aws.String("test"),
+			Version:       aws.String("1.27"),
+			Force:         aws.Bool(true),
+		}).Return(nil, nil)
+		updated, err := UpdateNodegroupVersion(context.Background(), updateNodegroupVersionOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeTrue())
+		Expect(updateNodegroupVersionOpts.NGVersionInput.Force).To(BeNil())
+	})
+
+	It("should delete launch template version if update fails", func() {
+		eksServiceMock.EXPECT().UpdateNodegroupVersion(gomock.Any(), updateNodegroupVersionOpts.NGVersionInput).Return(nil, errors.New("error"))
+		ec2ServiceMock.EXPECT().DeleteLaunchTemplateVersions(gomock.Any(), gomock.Any()).Return(nil, nil)
+		updated, err := UpdateNodegroupVersion(context.Background(), updateNodegroupVersionOpts)
+		Expect(err).To(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+
+	It("should fail to update node group to the placeholder version of the managed launch template", func() {
+		updateNodegroupVersionOpts.NGVersionInput = &eks.UpdateNodegroupVersionInput{
+			ClusterName:   aws.String("test"),
+			NodegroupName: aws.String("test"),
+			LaunchTemplate: &eks.LaunchTemplateSpecification{
+				Id:      aws.String("test"),
+				Version: aws.String("1"),
+			},
+		}
+		ec2ServiceMock.EXPECT().DeleteLaunchTemplateVersions(gomock.Any(), gomock.Any()).Return(nil, nil)
+		_, err := UpdateNodegroupVersion(context.Background(), updateNodegroupVersionOpts)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should roll out a new AMI through the launch template only", func() {
+		updateNodegroupVersionOpts.NodeGroup.ImageID = aws.String("ami-new")
+		updateNodegroupVersionOpts.NGVersionInput = &eks.UpdateNodegroupVersionInput{
+			ClusterName:   aws.String("test"),
+			NodegroupName: aws.String("test"),
+			LaunchTemplate: &eks.LaunchTemplateSpecification{
+				Id:      aws.String("test"),
+				Version: aws.String("3"),
+			},
+		}
+		expectLaunchTemplateVersion(eks.AMITypesCustom, &ec2.ResponseLaunchTemplateData{
+			ImageId:      aws.String("ami-new"),
+			InstanceType: aws.String("m6g.large"),
+		})
+		ec2ServiceMock.EXPECT().DescribeImages(gomock.Any(), &ec2.DescribeImagesInput{
+			ImageIds: aws.StringSlice([]string{"ami-new"}),
+		}).Return(&ec2.DescribeImagesOutput{
+			Images: []*ec2.Image{{Architecture: aws.String(ec2.ArchitectureValuesArm64)}},
+		}, nil)
+		expectInstanceType("m6g.large", ec2.ArchitectureTypeArm64)
+		eksServiceMock.EXPECT().UpdateNodegroupVersion(gomock.Any(), &eks.UpdateNodegroupVersionInput{
+			ClusterName:   aws.String("test"),
+			NodegroupName: aws.String("test"),
+			LaunchTemplate: &eks.LaunchTemplateSpecification{
+				Id:      aws.String("test"),
+				Version: aws.String("3"),
+			},
+		}).Return(nil, nil)
+		updated, err := UpdateNodegroupVersion(context.Background(), updateNodegroupVersionOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeTrue())
+	})
+
+	Context("with a launch template version", func() {
+		BeforeEach(func() {
+			updateNodegroupVersionOpts.NGVersionInput = &eks.UpdateNodegroupVersionInput{
+				ClusterName:   aws.String("test"),
+				NodegroupName: aws.String("test"),
+				LaunchTemplate: &eks.LaunchTemplateSpecification{
+					Id:      aws.String("test"),
+					Version: aws.String("3"),
+				},
+			}
+		})
+
+		It("should fail if the instance type does not support the architecture of the AMI type", func() {
+			expectLaunchTemplateVersion(eks.AMITypesAl2Arm64, &ec2.ResponseLaunchTemplateData{InstanceType: aws.String("m5.large")})
+			expectInstanceType("m5.large", ec2.ArchitectureTypeI386, ec2.ArchitectureTypeX8664)
+			ec2ServiceMock.EXPECT().DeleteLaunchTemplateVersions(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+			updated, err := UpdateNodegroupVersion(context.Background(), updateNodegroupVersionOpts)
+			Expect(err).To(MatchError(ContainSubstring("instance type [m5.large] of version [3] of launch template [test] does not support architecture [arm64]")))
+			Expect(updated).To(BeFalse())
+		})
+
+		It("should fail if the launch template version sets an AMI for a node group without a custom AMI", func() {
+			expectLaunchTemplateVersion(eks.AMITypesAl2X8664, &ec2.ResponseLaunchTemplateData{ImageId: aws.String("ami-new")})
+			ec2ServiceMock.EXPECT().DeleteLaunchTemplateVersions(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+			_, err := UpdateNodegroupVersion(context.Background(), updateNodegroupVersionOpts)
+			Expect(err).To(MatchError(ContainSubstring("cannot specify an AMI")))
+		})
+
+		It("should fail if the launch template version does not exist", func() {
+			eksServiceMock.EXPECT().DescribeNodegroup(gomock.Any(), gomock.Any()).Return(&eks.DescribeNodegroupOutput{
+				Nodegroup: &eks.Nodegroup{AmiType: aws.String(eks.AMITypesAl2X8664)},
+			}, nil)
+			ec2ServiceMock.EXPECT().DescribeLaunchTemplateVersions(gomock.Any(), gomock.Any()).Return(&ec2.DescribeLaunchTemplateVersionsOutput{}, nil)
+			ec2ServiceMock.EXPECT().DeleteLaunchTemplateVersions(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+			_, err := UpdateNodegroupVersion(context.Background(), updateNodegroupVersionOpts)
+			Expect(err).To(MatchError(ContainSubstring("version [3] of launch template [test] for nodegroup [test] does not exist")))
+		})
+	})
+
+	It("should fail to roll out a new AMI if the kubernetes version is also set", func() {
+		updateNodegroupVersionOpts.NodeGroup.ImageID = aws.String("ami-new")
+		updateNodegroupVersionOpts.NGVersionInput = &eks.UpdateNodegroupVersionInput{
+			ClusterName:   aws.String("test"),
+			NodegroupName: aws.String("test"),
+			Version:       aws.String("1.27"),
+			LaunchTemplate: &eks.LaunchTemplateSpecification{
+				Id:      aws.String("test"),
+				Version: aws.String("3"),
+			},
+		}
+		ec2ServiceMock.EXPECT().DeleteLaunchTemplateVersions(gomock.Any(), gomock.Any()).Return(nil, nil)
+		_, err := UpdateNodegroupVersion(context.Background(), updateNodegroupVersionOpts)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should fail to roll out a new AMI if the release version is also set", func() {
+		updateNodegroupVersionOpts.NodeGroup.ImageID = aws.String("ami-new")
+		updateNodegroupVersionOpts.NGVersionInput = &eks.UpdateNodegroupVersionInput{
+			ClusterName:    aws.String("test"),
+			NodegroupName:  aws.String("test"),
+			ReleaseVersion: aws.String("1.27.1-20230703"),
+			LaunchTemplate: &eks.LaunchTemplateSpecification{
+				Id:      aws.String("test"),
+				Version: aws.String("3"),
+			},
+		}
+		ec2ServiceMock.EXPECT().DeleteLaunchTemplateVersions(gomock.Any(), gomock.Any()).Return(nil, nil)
+		_, err := UpdateNodegroupVersion(context.Background(), updateNodegroupVersionOpts)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("UpdateNodegroupScaling", func() {
+	var (
+		mockController             *gomock.Controller
+		eksServiceMock             *mock_services.MockEKSServiceInterface
+		updateNodegroupScalingOpts *UpdateNodegroupScalingOpts
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+		updateNodegroupScalingOpts = &UpdateNodegroupScalingOpts{
+			EKSService: eksServiceMock,
+			Config: &eksv1.EKSClusterConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-cluster",
+				},
+				Spec: eksv1.EKSClusterConfigSpec{
+					DisplayName: "test",
+				},
+			},
+			NodeGroup: &eksv1.NodeGroup{
+				NodegroupName: aws.String("test"),
+				DesiredSize:   aws.Int64(2),
+				MinSize:       aws.Int64(1),
+				MaxSize:       aws.Int64(3),
+			},
+			UpstreamNodeGroup: &eksv1.NodeGroup{
+				NodegroupName: aws.String("test"),
+				DesiredSize:   aws.Int64(2),
+				MinSize:       aws.Int64(1),
+				MaxSize:       aws.Int64(3),
+			},
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should not update the scaling config if it didn't change", func() {
+		updated, err := UpdateNodegroupScaling(context.Background(), updateNodegroupScalingOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+
+	It("should only update the desired size and record it", func() {
+		updateNodegroupScalingOpts.NodeGroup.DesiredSize = aws.Int64(3)
+		eksServiceMock.EXPECT().UpdateNodegroupConfig(gomock.Any(), &eks.UpdateNodegroupConfigInput{
+			ClusterName:   aws.String("test"),
+			NodegroupName: aws.String("test"),
+			ScalingConfig: &eks.NodegroupScalingConfig{
+				DesiredSize: aws.Int64(3),
+			},
+		}).Return(nil, nil)
+
+		updated, err := UpdateNodegroupScaling(context.Background(), updateNodegroupScalingOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeTrue())
+		Expect(updateNodegroupScalingOpts.Config.Status.NodegroupDesiredSizes).To(Equal(map[string]int64{"test": 3}))
+	})
+
+	It("should keep the upstream desired size with ignoreDesiredSizeDrift", func() {
+		updateNodegroupScalingOpts.NodeGroup.IgnoreDesiredSizeDrift = aws.Bool(true)
+		updateNodegroupScalingOpts.UpstreamNodeGroup.DesiredSize = aws.Int64(3)
+
+		updated, err := UpdateNodegroupScaling(context.Background(), updateNodegroupScalingOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+
+	It("should keep the upstream desired size with ignoreDesiredSizeDrift while the spec matches the applied size", func() {
+		updateNodegroupScalingOpts.NodeGroup.IgnoreDesiredSizeDrift = aws.Bool(true)
+		updateNodegroupScalingOpts.Config.Status.NodegroupDesiredSizes = map[string]int64{"test": 2}
+		updateNodegroupScalingOpts.UpstreamNodeGroup.DesiredSize = aws.Int64(3)
+
+		updated, err := UpdateNodegroupScaling(context.Background(), updateNodegroupScalingOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+
+	It("should apply a desired size changed in the spec with ignoreDesiredSizeDrift", func() {
+		updateNodegroupScalingOpts.NodeGroup.IgnoreDesiredSizeDrift = aws.Bool(true)
+		updateNodegroupScalingOpts.Config.Status.NodegroupDesiredSizes = map[string]int64{"test": 1}
+		updateNodegroupScalingOpts.UpstreamNodeGroup.DesiredSize = aws.Int64(3)
+		eksServiceMock.EXPECT().UpdateNodegroupConfig(gomock.Any(), &eks.UpdateNodegroupConfigInput{
+			ClusterName:   aws.String("test"),
+			NodegroupName: aws.String("test"),
+			ScalingConfig: &eks.NodegroupScalingConfig{
+				DesiredSize: aws.Int64(2),
+			},
+		}).Return(nil, nil)
+
+		updated, err := UpdateNodegroupScaling(context.Background(), updateNodegroupScalingOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeTrue())
+		Expect(updateNodegroupScalingOpts.Config.Status.NodegroupDesiredSizes).To(Equal(map[string]int64{"test": 2}))
+	})
+
+	It("should apply the desired size with ignoreDesiredSizeDrift when the max size changes", func() {
+		updateNodegroupScalingOpts.NodeGroup.IgnoreDesiredSizeDrift = aws.Bool(true)
+		updateNodegroupScalingOpts.NodeGroup.MaxSize = aws.Int64(4)
+		updateNodegroupScalingOpts.UpstreamNodeGroup.DesiredSize = aws.Int64(3)
+		eksServiceMock.EXPECT().UpdateNodegroupConfig(gomock.Any(), &eks.UpdateNodegroupConfigInput{
+			ClusterName:   aws.String("test"),
+			NodegroupName: aws.String("test"),
+			ScalingConfig: &eks.NodegroupScalingConfig{
+				DesiredSize: aws.Int64(2),
+				MaxSize:     aws.Int64(4),
+			},
+		}).Return(nil, nil)
+
+		updated, err := UpdateNodegroupScaling(context.Background(), updateNodegroupScalingOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeTrue())
+	})
+
+	It("should refuse a min size greater than the max size", func() {
+		updateNodegroupScalingOpts.NodeGroup.MinSize = aws.Int64(4)
+
+		updated, err := UpdateNodegroupScaling(context.Background(), updateNodegroupScalingOpts)
+		Expect(err).To(MatchError(ContainSubstring("minSize [4] cannot be greater than maxSize [3]")))
+		Expect(updated).To(BeFalse())
+	})
+
+	It("should refuse a max size below the upstream desired size", func() {
+		updateNodegroupScalingOpts.NodeGroup.DesiredSize = nil
+		updateNodegroupScalingOpts.NodeGroup.MaxSize = aws.Int64(1)
+
+		updated, err := UpdateNodegroupScaling(context.Background(), updateNodegroupScalingOpts)
+		Expect(err).To(MatchError(ContainSubstring("desiredSize [2] cannot be greater than maxSize [1]")))
+		Expect(updated).To(BeFalse())
+	})
+
+	It("should return an error if updating the scaling config fails", func() {
+		updateNodegroupScalingOpts.NodeGroup.MaxSize = aws.Int64(5)
+		eksServiceMock.EXPECT().UpdateNodegroupConfig(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+		updated, err := UpdateNodegroupScaling(context.Background(), updateNodegroupScalingOpts)
+		Expect(err).To(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+})
+
+var _ = Describe("getNodegroupScalingDrift", func() {
+	var nodeGroup *eksv1.NodeGroup
+
+	BeforeEach(func() {
+		nodeGroup = &eksv1.NodeGroup{
+			NodegroupName: aws.String("test"),
+			MinSize:       aws.Int64(1),
+			MaxSize:       aws.Int64(5),
+			DesiredSize:   aws.Int64(2),
+		}
+	})
+
+	It("should report desired size drift from the autoscaler", func() {
+		drift := getNodegroupScalingDrift(nodeGroup, &eksv1.NodeGroup{
+			MinSize:     aws.Int64(1),
+			MaxSize:     aws.Int64(5),
+			DesiredSize: aws.Int64(4),
+		})
+		Expect(drift).To(Equal(&nodegroupScalingDrift{desiredSizeDelta: 2}))
+		Expect(drift.desiredSizeOnly()).To(BeTrue())
+		Expect(drift.minMaxChanged()).To(BeFalse())
+	})
+
+	It("should report min and max drift from a manual change", func() {
+		drift := getNodegroupScalingDrift(nodeGroup, &eksv1.NodeGroup{
+			MinSize:     aws.Int64(2),
+			MaxSize:     aws.Int64(3),
+			DesiredSize: aws.Int64(2),
+		})
+		Expect(drift).To(Equal(&nodegroupScalingDrift{minSizeDelta: 1, maxSizeDelta: -2}))
+		Expect(drift.desiredSizeOnly()).To(BeFalse())
+		Expect(drift.minMaxChanged()).To(BeTrue())
+	})
+
+	It("should not report drift of sizes that are not configured", func() {
+		drift := getNodegroupScalingDrift(&eksv1.NodeGroup{}, &eksv1.NodeGroup{
+			MinSize:     aws.Int64(2),
+			MaxSize:     aws.Int64(3),
+			DesiredSize: aws.Int64(2),
+		})
+		Expect(drift).To(Equal(&nodegroupScalingDrift{}))
+	})
+})
+
+var _ = Describe("ValidateNodegroupUpdate", func() {
+	config := &eksv1.EKSClusterConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+		},
+	}
+
+	It("should allow node groups with an unchanged capacity type", func() {
+		ng := eksv1.NodeGroup{NodegroupName: aws.String("ng1"), RequestSpotInstances: aws.Bool(true)}
+		upstreamNg := eksv1.NodeGroup{NodegroupName: aws.String("ng1"), RequestSpotInstances: aws.Bool(true)}
+		Expect(ValidateNodegroupUpdate(config, ng, upstreamNg)).To(Succeed())
+	})
+
+	It("should allow node groups that don't set requestSpotInstances", func() {
+		ng := eksv1.NodeGroup{NodegroupName: aws.String("ng1")}
+		upstreamNg := eksv1.NodeGroup{NodegroupName: aws.String("ng1"), RequestSpotInstances: aws.Bool(true)}
+		Expect(ValidateNodegroupUpdate(config, ng, upstreamNg)).To(Succeed())
+	})
+
+	It("should return error if the node group is switched to spot instances", func() {
+		ng := eksv1.NodeGroup{NodegroupName: aws.String("ng1"), RequestSpotInstances: aws.Bool(true)}
+		upstreamNg := eksv1.NodeGroup{NodegroupName: aws.String("ng1"), RequestSpotInstances: aws.Bool(false)}
+		err := ValidateNodegroupUpdate(config, ng, upstreamNg)
+		Expect(err).To(MatchError(ContainSubstring("cannot be changed from [ON_DEMAND] to [SPOT]")))
+		Expect(err).To(MatchError(ContainSubstring("requestSpotInstances set to [true]")))
+	})
+
+	It("should return error if the node group is switched to on demand instances", func() {
+		ng := eksv1.NodeGroup{NodegroupName: aws.String("ng1"), RequestSpotInstances: aws.Bool(false)}
+		upstreamNg := eksv1.NodeGroup{NodegroupName: aws.String("ng1"), RequestSpotInstances: aws.Bool(true)}
+		Expect(ValidateNodegroupUpdate(config, ng, upstreamNg)).To(MatchError(ContainSubstring("cannot be changed from [SPOT] to [ON_DEMAND]")))
+	})
+})
+
+var _ = Describe("UpdateNodegroupLabels", func() {
+	var (
+		mockController            *gomock.Controller
+		eksServiceMock            *mock_services.MockEKSServiceInterface
+		updateNodegroupLabelsOpts *UpdateNodegroupLabelsOpts
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+		updateNodegroupLabelsOpts = &UpdateNodegroupLabelsOpts{
+			EKSService: eksServiceMock,
+			Config: &eksv1.EKSClusterConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-cluster",
+				},
+				Spec: eksv1.EKSClusterConfigSpec{
+					DisplayName: "test",
+				},
+			},
+			NodeGroup: &eksv1.NodeGroup{
+				NodegroupName: aws.String("test"),
+				Labels:        aws.StringMap(map[string]string{"a": "1", "b": "2"}),
+			},
+			UpstreamLabels: aws.StringMap(map[string]string{"a": "1", "b": "2"}),
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should not update labels if they didn't change", func() {
+		updated, err := UpdateNodegroupLabels(context.Background(), updateNodegroupLabelsOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+
+	It("should add labels", func() {
+		updateNodegroupLabelsOpts.NodeGroup.Labels = aws.StringMap(map[string]string{"a": "1", "b": "2", "c": "3"})
+		eksServiceMock.EXPECT().UpdateNodegroupConfig(gomock.Any(), &eks.UpdateNodegroupConfigInput{
+			ClusterName:   aws.String("test"),
+			NodegroupName: aws.String("test"),
+			Labels: &eks.UpdateLabelsPayload{
+				AddOrUpdateLabels: aws.StringMap(map[string]string{"c": "3"}),
+			},
+		}).Return(nil, nil)
+
+		updated, err := UpdateNodegroupLabels(context.Background(), updateNodegroupLabelsOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeTrue())
+	})
+
+	It("should remove labels", func() {
+		updateNodegroupLabelsOpts.NodeGroup.Labels = aws.StringMap(map[string]string{"a": "1"})
+		eksServiceMock.EXPECT().UpdateNodegroupConfig(gomock.Any(), &eks.UpdateNodegroupConfigInput{
+			ClusterName:   aws.String("test"),
+			NodegroupName: aws.String("test"),
+			Labels: &eks.UpdateLabelsPayload{
+				RemoveLabels: aws.StringSlice([]string{"b"}),
+			},
+		}).Return(nil, nil)
+
+		updated, err := UpdateNodegroupLabels(context.Background(), updateNodegroupLabelsOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeTrue())
+	})
+
+	It("should return error if updating labels failed", func() {
+		updateNodegroupLabelsOpts.NodeGroup.Labels = aws.StringMap(map[string]string{"a": "2"})
+		eksServiceMock.EXPECT().UpdateNodegroupConfig(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+		updated, err := UpdateNodegroupLabels(context.Background(), updateNodegroupLabelsOpts)
+		Expect(err).To(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+})
+
+var _ = Describe("UpdateNodegroupTaints", func() {
+	var (
+		mockController            *gomock.Controller
+		eksServiceMock            *mock_services.MockEKSServiceInterface
+		updateNodegroupTaintsOpts *UpdateNodegroupTaintsOpts
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+		updateNodegroupTaintsOpts = &UpdateNodegroupTaintsOpts{
+			EKSService: eksServiceMock,
+			Config: &eksv1.EKSClusterConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-cluster",
+				},
+				Spec: eksv1.EKSClusterConfigSpec{
+					DisplayName: "test",
+				},
+			},
+			NodeGroup: &eksv1.NodeGroup{
+				NodegroupName: aws.String("test"),
+				Taints: []eksv1.Taint{
+					{Key: aws.String("dedicated"), Value: aws.String("gpu"), Effect: aws.String(eks.TaintEffectNoSchedule)},
+				},
+			},
+			UpstreamTaints: []eksv1.Taint{
+				{Key: aws.String("dedicated"), Value: aws.String("gpu"), Effect: aws.String(eks.TaintEffectNoSchedule)},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should not update taints if they didn't change", func() {
+		updated, err := UpdateNodegroupTaints(context.Background(), updateNodegroupTaintsOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+
+	It("should update the value of a taint", func() {
+		updateNodegroupTaintsOpts.NodeGroup.Taints[0].Value = aws.String("cpu")
+		eksServiceMock.EXPECT().UpdateNodegroupConfig(gomock.Any(), &eks.UpdateNodegroupConfigInput{
+			ClusterName:   aws.String("test"),
+			NodegroupName: aws.String("test"),
+			Taints: &eks.UpdateTaintsPayload{
+				AddOrUpdateTaints: []*eks.Taint{
+					{Key: aws.String("dedicated"), Value: aws.String("cpu"), Effect: aws.String(eks.TaintEffectNoSchedule)},
+				},
+			},
+		}).Return(nil, nil)
+
+		updated, err := UpdateNodegroupTaints(context.Background(), updateNodegroupTaintsOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeTrue())
+	})
+
+	It("should replace the taint if the effect changed for the same key", func() {
+		updateNodegroupTaintsOpts.NodeGroup.Taints[0].Effect = aws.String(eks.TaintEffectNoExecute)
+		eksServiceMock.EXPECT().UpdateNodegroupConfig(gomock.Any(), &eks.UpdateNodegroupConfigInput{
+			ClusterName:   aws.String("test"),
+			NodegroupName: aws.String("test"),
+			Taints: &eks.UpdateTaintsPayload{
+				AddOrUpdateTaints: []*eks.Taint{
+					{Key: aws.String("dedicated"), Value: aws.String("gpu"), Effect: aws.String(eks.TaintEffectNoExecute)},
+				},
+				RemoveTaints: []*eks.Taint{
+					{Key: aws.String("dedicated"), Value: aws.String("gpu"), Effect: aws.String(eks.TaintEffectNoSchedule)},
+				},
+			},
+		}).Return(nil, nil)
+
+		updated, err := UpdateNodegroupTaints(context.Background(), updateNodegroupTaintsOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeTrue())
+	})
+
+	It("should remove taints", func() {
+		updateNodegroupTaintsOpts.NodeGroup.Taints = []eksv1.Taint{}
+		eksServiceMock.EXPECT().UpdateNodegroupConfig(gomock.Any(), &eks.UpdateNodegroupConfigInput{
+			ClusterName:   aws.String("test"),
+			NodegroupName: aws.String("test"),
+			Taints: &eks.UpdateTaintsPayload{
+				RemoveTaints: []*eks.Taint{
+					{Key: aws.String("dedicated"), Value: aws.String("gpu"), Effect: aws.String(eks.TaintEffectNoSchedule)},
+				},
+			},
+		}).Return(nil, nil)
+
+		updated, err := UpdateNodegroupTaints(context.Background(), updateNodegroupTaintsOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeTrue())
+	})
+
+	It("should reject taints with an invalid effect", func() {
+		updateNodegroupTaintsOpts.NodeGroup.Taints[0].Effect = aws.String("NoSchedule")
+
+		updated, err := UpdateNodegroupTaints(context.Background(), updateNodegroupTaintsOpts)
+		Expect(err).To(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+
+	It("should return error if updating taints failed", func() {
+		updateNodegroupTaintsOpts.NodeGroup.Taints[0].Value = aws.String("cpu")
+		eksServiceMock.EXPECT().UpdateNodegroupConfig(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+		updated, err := UpdateNodegroupTaints(context.Background(), updateNodegroupTaintsOpts)
+		Expect(err).To(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+})
+
+var _ = Describe("UpdateNodegroupUpdateConfig", func() {
+	var (
+		mockController                  *gomock.Controller
+		eksServiceMock                  *mock_services.MockEKSServiceInterface
+		updateNodegroupUpdateConfigOpts *UpdateNodegroupUpdateConfigOpts
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+		updateNodegroupUpdateConfigOpts = &UpdateNodegroupUpdateConfigOpts{
+			EKSService: eksServiceMock,
+			Config: &eksv1.EKSClusterConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-cluster",
+				},
+				Spec: eksv1.EKSClusterConfigSpec{
+					DisplayName: "test",
+				},
+			},
+			NodeGroup: &eksv1.NodeGroup{
+				NodegroupName: aws.String("test"),
+				UpdateConfig:  &eksv1.NodeGroupUpdateConfig{MaxUnavailable: aws.Int64(1)},
+			},
+			UpstreamUpdateConfig: &eksv1.NodeGroupUpdateConfig{MaxUnavailable: aws.Int64(1)},
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should not update the update config if it didn't change", func() {
+		updated, err := UpdateNodegroupUpdateConfig(context.Background(), updateNodegroupUpdateConfigOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+
+	It("should not update the update config if it is not set", func() {
+		updateNodegroupUpdateConfigOpts.NodeGroup.UpdateConfig = nil
+
+		updated, err := UpdateNodegroupUpdateConfig(context.Background(), updateNodegroupUpdateConfigOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+
+	It("should switch from max unavailable to max unavailable percentage", func() {
+		updateNodegroupUpdateConfigOpts.NodeGroup.UpdateConfig = &eksv1.NodeGroupUpdateConfig{MaxUnavailablePercentage: aws.Int64(25)}
+		eksServiceMock.EXPECT().UpdateNodegroupConfig(gomock.Any(), &eks.UpdateNodegroupConfigInput{
+			ClusterName:   aws.String("test"),
+			NodegroupName: aws.String("test"),
+			UpdateConfig: &eks.NodegroupUpdateConfig{
+				MaxUnavailablePercentage: aws.Int64(25),
+			},
+		}).Return(nil, nil)
+
+		updated, err := UpdateNodegroupUpdateConfig(context.Background(), updateNodegroupUpdateConfigOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeTrue())
+	})
+
+	It("should update the update config derived from the update strategy", func() {
+		updateNodegroupUpdateConfigOpts.NodeGroup.UpdateConfig = nil
+		updateNodegroupUpdateConfigOpts.NodeGroup.UpdateStrategy = aws.String(UpdateStrategyInPlace)
+		eksServiceMock.EXPECT().UpdateNodegroupConfig(gomock.Any(), &eks.UpdateNodegroupConfigInput{
+			ClusterName:   aws.String("test"),
+			NodegroupName: aws.String("test"),
+			UpdateConfig: &eks.NodegroupUpdateConfig{
+				MaxUnavailablePercentage: aws.Int64(33),
+			},
+		}).Return(nil, nil)
+
+		updated, err := UpdateNodegroupUpdateConfig(context.Background(), updateNodegroupUpdateConfigOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeTrue())
+	})
+
+	It("should fail to update the update config if both fields are set", func() {
+		updateNodegroupUpdateConfigOpts.NodeGroup.UpdateConfig.MaxUnavailablePercentage = aws.Int64(25)
+
+		updated, err := UpdateNodegroupUpdateConfig(context.Background(), updateNodegroupUpdateConfigOpts)
+		Expect(err).To(MatchError(ContainSubstring("cannot both be specified")))
+		Expect(updated).To(BeFalse())
+	})
+
+	It("should fail to update the update config if neither field is set", func() {
+		updateNodegroupUpdateConfigOpts.NodeGroup.UpdateConfig = &eksv1.NodeGroupUpdateConfig{}
+
+		updated, err := UpdateNodegroupUpdateConfig(context.Background(), updateNodegroupUpdateConfigOpts)
+		Expect(err).To(MatchError(ContainSubstring("must specify one of")))
+		Expect(updated).To(BeFalse())
+	})
+
+	It("should fail to update the update config if UpdateNodegroupConfig returns error", func() {
+		updateNodegroupUpdateConfigOpts.NodeGroup.UpdateConfig.MaxUnavailable = aws.Int64(2)
+		eksServiceMock.EXPECT().UpdateNodegroupConfig(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+		updated, err := UpdateNodegroupUpdateConfig(context.Background(), updateNodegroupUpdateConfigOpts)
+		Expect(err).To(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+})
+
+var _ = Describe("UpdateNodegroupTargetGroups", func() {
+	var (
+		mockController                  *gomock.Controller
+		eksServiceMock                  *mock_services.MockEKSServiceInterface
+		autoScalingServiceMock          *mock_services.MockAutoScalingServiceInterface
+		updateNodegroupTargetGroupsOpts *UpdateNodegroupTargetGroupsOpts
+		targetGroupARN                  = "arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/test/0123456789abcdef"
+		attachedTargetGroupARN          = "arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/attached/0123456789abcdef"
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		eksServiceMock = mock_services.NewMockEKSServiceInterface(mockController)
+		autoScalingServiceMock = mock_services.NewMockAutoScalingServiceInterface(mockController)
+		updateNodegroupTargetGroupsOpts = &UpdateNodegroupTargetGroupsOpts{
+			EKSService:         eksServiceMock,
+			AutoScalingService: autoScalingServiceMock,
+			Config: &eksv1.EKSClusterConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-cluster",
+				},
+				Spec: eksv1.EKSClusterConfigSpec{
+					DisplayName: "test",
+				},
+			},
+			NodeGroup: &eksv1.NodeGroup{
+				NodegroupName:   aws.String("test"),
+				TargetGroupARNs: []string{targetGroupARN, attachedTargetGroupARN},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should attach missing target groups to the node group auto scaling groups", func() {
+		eksServiceMock.EXPECT().DescribeNodegroup(gomock.Any(), &eks.DescribeNodegroupInput{
+			ClusterName:   aws.String("test"),
+			NodegroupName: aws.String("test"),
+		}).Return(&eks.DescribeNodegroupOutput{
+			Nodegroup: &eks.Nodegroup{
+				Resources: &eks.NodegroupResources{
+					AutoScalingGroups: []*eks.AutoScalingGroup{{Name: aws.String("test-asg")}},
+				},
+			},
+		}, nil)
+		autoScalingServiceMock.EXPECT().DescribeLoadBalancerTargetGroups(gomock.Any(), &autoscaling.DescribeLoadBalancerTargetGroupsInput{
+			AutoScalingGroupName: aws.String("test-asg"),
+		}).Return(&autoscaling.DescribeLoadBalancerTargetGroupsOutput{
+			LoadBalancerTargetGroups: []*autoscaling.LoadBalancerTargetGroupState{
+				{LoadBalancerTargetGroupARN: aws.String(attachedTargetGroupARN)},
+			},
+		}, nil)
+		autoScalingServiceMock.EXPECT().AttachLoadBalancerTargetGroups(gomock.Any(), &autoscaling.AttachLoadBalancerTargetGroupsInput{
+			AutoScalingGroupName: aws.String("test-asg"),
+			TargetGroupARNs:      aws.StringSlice([]string{targetGroupARN}),
+		}).Return(nil, nil)
+
+		updated, err := UpdateNodegroupTargetGroups(context.Background(), updateNodegroupTargetGroupsOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeTrue())
+		Expect(updateNodegroupTargetGroupsOpts.Config.Status.NodegroupTargetGroupARNs).To(Equal(map[string][]string{
+			"test": {targetGroupARN, attachedTargetGroupARN},
+		}))
+	})
+
+	It("should not look up the auto scaling groups if the target groups were attached already", func() {
+		updateNodegroupTargetGroupsOpts.Config.Status.NodegroupTargetGroupARNs = map[string][]string{
+			"test": {attachedTargetGroupARN, targetGroupARN},
+		}
+		eksServiceMock.EXPECT().DescribeNodegroup(gomock.Any(), gomock.Any()).Times(0)
+
+		updated, err := UpdateNodegroupTargetGroups(context.Background(), updateNodegroupTargetGroupsOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+
+	It("should detach the target groups removed from the node group", func() {
+		externalTargetGroupARN := "arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/external/0123456789abcdef"
+		updateNodegroupTargetGroupsOpts.NodeGroup.TargetGroupARNs = []string{attachedTargetGroupARN}
+		updateNodegroupTargetGroupsOpts.Config.Status.NodegroupTargetGroupARNs = map[string][]string{
+			"test": {targetGroupARN, attachedTargetGroupARN},
+		}
+		eksServiceMock.EXPECT().DescribeNodegroup(gomock.Any(), gomock.Any()).Return(&eks.DescribeNodegroupOutput{
+			Nodegroup: &eks.Nodegroup{
+				Resources: &eks.NodegroupResources{
+					AutoScalingGroups: []*eks.AutoScalingGroup{{Name: aws.String("test-asg")}},
+				},
+			},
+		}, nil)
+		autoScalingServiceMock.EXPECT().DescribeLoadBalancerTargetGroups(gomock.Any(), gomock.Any()).Return(&autoscaling.DescribeLoadBalancerTargetGroupsOutput{
+			LoadBalancerTargetGroups: []*autoscaling.LoadBalancerTargetGroupState{
+				{LoadBalancerTargetGroupARN: aws.String(targetGroupARN)},
+				{LoadBalancerTargetGroupARN: aws.String(attachedTargetGroupARN)},
+				{LoadBalancerTargetGroupARN: aws.String(externalTargetGroupARN)},
+			},
+		}, nil)
+		autoScalingServiceMock.EXPECT().DetachLoadBalancerTargetGroups(gomock.Any(), &autoscaling.DetachLoadBalancerTargetGroupsInput{
+			AutoScalingGroupName: aws.String("test-asg"),
+			TargetGroupARNs:      aws.StringSlice([]string{targetGroupARN}),
+		}).Return(nil, nil)
+
+		updated, err := UpdateNodegroupTargetGroups(context.Background(), updateNodegroupTargetGroupsOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeTrue())
+		Expect(updateNodegroupTargetGroupsOpts.Config.Status.NodegroupTargetGroupARNs).To(Equal(map[string][]string{
+			"test": {attachedTargetGroupARN},
+		}))
+	})
+
+	It("should detach every target group once they are all removed from the node group", func() {
+		updateNodegroupTargetGroupsOpts.NodeGroup.TargetGroupARNs = nil
+		updateNodegroupTargetGroupsOpts.Config.Status.NodegroupTargetGroupARNs = map[string][]string{
+			"test": {targetGroupARN},
+		}
+		eksServiceMock.EXPECT().DescribeNodegroup(gomock.Any(), gomock.Any()).Return(&eks.DescribeNodegroupOutput{
+			Nodegroup: &eks.Nodegroup{
+				Resources: &eks.NodegroupResources{
+					AutoScalingGroups: []*eks.AutoScalingGroup{{Name: aws.String("test-asg")}},
+				},
+			},
+		}, nil)
+		autoScalingServiceMock.EXPECT().DescribeLoadBalancerTargetGroups(gomock.Any(), gomock.Any()).Return(&autoscaling.DescribeLoadBalancerTargetGroupsOutput{
+			LoadBalancerTargetGroups: []*autoscaling.LoadBalancerTargetGroupState{
+				{LoadBalancerTargetGroupARN: aws.String(targetGroupARN)},
+			},
+		}, nil)
+		autoScalingServiceMock.EXPECT().DetachLoadBalancerTargetGroups(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+		updated, err := UpdateNodegroupTargetGroups(context.Background(), updateNodegroupTargetGroupsOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeTrue())
+		Expect(updateNodegroupTargetGroupsOpts.Config.Status.NodegroupTargetGroupARNs).To(BeEmpty())
+	})
+
+	It("should not update if all target groups are attached", func() {
+		eksServiceMock.EXPECT().DescribeNodegroup(gomock.Any(), gomock.Any()).Return(&eks.DescribeNodegroupOutput{
+			Nodegroup: &eks.Nodegroup{
+				Resources: &eks.NodegroupResources{
+					AutoScalingGroups: []*eks.AutoScalingGroup{{Name: aws.String("test-asg")}},
+				},
+			},
+		}, nil)
+		autoScalingServiceMock.EXPECT().DescribeLoadBalancerTargetGroups(gomock.Any(), gomock.Any()).Return(&autoscaling.DescribeLoadBalancerTargetGroupsOutput{
+			LoadBalancerTargetGroups: []*autoscaling.LoadBalancerTargetGroupState{
+				{LoadBalancerTargetGroupARN: aws.String(targetGroupARN)},
+				{LoadBalancerTargetGroupARN: aws.String(attachedTargetGroupARN)},
+			},
+		}, nil)
+
+		updated, err := UpdateNodegroupTargetGroups(context.Background(), updateNodegroupTargetGroupsOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+
+	It("should not update if no target groups are set", func() {
+		updateNodegroupTargetGroupsOpts.NodeGroup.TargetGroupARNs = nil
+
+		updated, err := UpdateNodegroupTargetGroups(context.Background(), updateNodegroupTargetGroupsOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+
+	It("should fail if a target group ARN is malformed", func() {
+		updateNodegroupTargetGroupsOpts.NodeGroup.TargetGroupARNs = []string{"test"}
+
+		updated, err := UpdateNodegroupTargetGroups(context.Background(), updateNodegroupTargetGroupsOpts)
+		Expect(err).To(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+
+	It("should fail if an ARN is not a target group", func() {
+		updateNodegroupTargetGroupsOpts.NodeGroup.TargetGroupARNs = []string{"arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/test/0123456789abcdef"}
+
+		updated, err := UpdateNodegroupTargetGroups(context.Background(), updateNodegroupTargetGroupsOpts)
+		Expect(err).To(HaveOccurred())
+		Expect(updated).To(BeFalse())
+	})
+
+	It("should fail if attaching target groups fails", func() {
+		eksServiceMock.EXPECT().DescribeNodegroup(gomock.Any(), gomock.Any()).Return(&eks.DescribeNodegroupOutput{
+			Nodegroup: &eks.Nodegroup{
+				Resources: &eks.NodegroupResources{
+					AutoScalingGroups: []*eks.AutoScalingGroup{{Name: aws.String("test-asg")}},
+				},
+			},
+		}, nil)
+		autoScalingServiceMock.EXPECT().DescribeLoadBalancerTargetGroups(gomock.Any(), gomock.Any()).Return(&autoscaling.DescribeLoadBalancerTargetGroupsOutput{}, nil)
+		autoScalingServiceMock.EXPECT().AttachLoadBalancerTargetGroups(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+		updated, err := UpdateNodegroupTargetGroups(context.Background(), updateNodegroupTargetGroupsOpts)
+		Expect(err).To(HaveOccurred())
+		Expect(updated).To(BeFalse())
 	})
 })
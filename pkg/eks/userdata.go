@@ -0,0 +1,120 @@
+package eks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eks"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+)
+
+const (
+	userDataBoundary  = "//"
+	bootstrapScript   = "/etc/eks/bootstrap.sh"
+	multipartMIMEType = "multipart/mixed"
+)
+
+// kubeletTaintEffects maps the EKS taint effects to the ones the kubelet registers the node with.
+var kubeletTaintEffects = map[string]string{
+	eks.TaintEffectNoSchedule:       "NoSchedule",
+	eks.TaintEffectNoExecute:        "NoExecute",
+	eks.TaintEffectPreferNoSchedule: "PreferNoSchedule",
+}
+
+// GetNodeUserData returns the user data of the node group instances before it is base64 encoded. It is the user data
+// of the node group if set, otherwise it is rendered from the bootstrap fields, if any. Only the user data of AMI
+// families that EKS merges with its own has to be a multipart/mixed MIME document.
+func GetNodeUserData(clusterName string, group eksv1.NodeGroup) (string, error) {
+	if userData := aws.StringValue(group.UserData); userData != "" {
+		if usesMultipartUserData(&group) && !strings.Contains(userData, "Content-Type: "+multipartMIMEType) {
+			return "", fmt.Errorf("userdata for nodegroup [%s] is not of mime time multipart/mixed", aws.StringValue(group.NodegroupName))
+		}
+		return userData, nil
+	}
+	if !hasBootstrapFields(group) {
+		return "", nil
+	}
+	return renderNodeUserData(clusterName, group), nil
+}
+
+// ValidateNodeBootstrapConfig checks that the bootstrap fields of a node group can be rendered into its user data.
+// EKS bootstraps the nodes of node groups using its own AMIs, so the fields require a custom image and cannot be
+// combined with user data or a launch template that is not managed by the operator.
+func ValidateNodeBootstrapConfig(group eksv1.NodeGroup) error {
+	if !hasBootstrapFields(group) {
+		return nil
+	}
+	name := aws.StringValue(group.NodegroupName)
+	switch {
+	case aws.StringValue(group.UserData) != "":
+		return fmt.Errorf("nodegroup [%s]: bootstrapExtraArgs and kubeletExtraArgs cannot be set along with userData", name)
+	case group.LaunchTemplate != nil:
+		return fmt.Errorf("nodegroup [%s]: bootstrapExtraArgs and kubeletExtraArgs cannot be set along with a launch template", name)
+	case aws.StringValue(group.ImageID) == "":
+		return fmt.Errorf("nodegroup [%s]: bootstrapExtraArgs and kubeletExtraArgs require a custom imageId", name)
+	case getNodegroupAMIFamily(&group) != AMIFamilyAmazonLinux2:
+		return fmt.Errorf("nodegroup [%s]: bootstrapExtraArgs and kubeletExtraArgs are only supported for amiFamily [%s]", name, AMIFamilyAmazonLinux2)
+	}
+	return nil
+}
+
+func hasBootstrapFields(group eksv1.NodeGroup) bool {
+	return aws.StringValue(group.BootstrapExtraArgs) != "" || aws.StringValue(group.KubeletExtraArgs) != ""
+}
+
+// renderNodeUserData renders a multipart/mixed MIME document running the EKS bootstrap script with the bootstrap
+// and kubelet arguments of the node group. The labels and taints of the node group are passed to the kubelet as EKS
+// leaves registering them to the user data of node groups using a custom AMI.
+func renderNodeUserData(clusterName string, group eksv1.NodeGroup) string {
+	kubeletArgs := getKubeletNodeArgs(group)
+	if extraArgs := aws.StringValue(group.KubeletExtraArgs); extraArgs != "" {
+		kubeletArgs = append(kubeletArgs, extraArgs)
+	}
+
+	command := []string{bootstrapScript, shellQuote(clusterName)}
+	if extraArgs := aws.StringValue(group.BootstrapExtraArgs); extraArgs != "" {
+		command = append(command, extraArgs)
+	}
+	if len(kubeletArgs) != 0 {
+		command = append(command, "--kubelet-extra-args", shellQuote(strings.Join(kubeletArgs, " ")))
+	}
+
+	var b strings.Builder
+	b.WriteString("MIME-Version: 1.0\n")
+	fmt.Fprintf(&b, "Content-Type: %s; boundary=%q\n\n", multipartMIMEType, userDataBoundary)
+	fmt.Fprintf(&b, "--%s\n", userDataBoundary)
+	b.WriteString("Content-Type: text/x-shellscript; charset=\"us-ascii\"\n\n")
+	b.WriteString("#!/bin/bash\nset -ex\n")
+	b.WriteString(strings.Join(command, " ") + "\n\n")
+	fmt.Fprintf(&b, "--%s--\n", userDataBoundary)
+	return b.String()
+}
+
+// getKubeletNodeArgs returns the kubelet arguments registering the node with the labels and taints of the node group.
+func getKubeletNodeArgs(group eksv1.NodeGroup) []string {
+	var args []string
+	labels := make([]string, 0, len(group.Labels))
+	for key, value := range group.Labels {
+		labels = append(labels, fmt.Sprintf("%s=%s", key, aws.StringValue(value)))
+	}
+	if len(labels) != 0 {
+		sort.Strings(labels)
+		args = append(args, "--node-labels="+strings.Join(labels, ","))
+	}
+
+	taints := make([]string, 0, len(group.Taints))
+	for _, taint := range group.Taints {
+		taints = append(taints, fmt.Sprintf("%s=%s:%s", aws.StringValue(taint.Key), aws.StringValue(taint.Value),
+			kubeletTaintEffects[aws.StringValue(taint.Effect)]))
+	}
+	if len(taints) != 0 {
+		args = append(args, "--register-with-taints="+strings.Join(taints, ","))
+	}
+	return args
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
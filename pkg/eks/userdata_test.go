@@ -0,0 +1,175 @@
+package eks
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+)
+
+// parseShellScriptPart parses the user data as a multipart/mixed MIME document and returns its only part.
+func parseShellScriptPart(userData string) (string, string) {
+	msg, err := mail.ReadMessage(strings.NewReader(userData))
+	Expect(err).ToNot(HaveOccurred())
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	Expect(err).ToNot(HaveOccurred())
+	Expect(mediaType).To(Equal("multipart/mixed"))
+
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	part, err := reader.NextPart()
+	Expect(err).ToNot(HaveOccurred())
+	body, err := io.ReadAll(part)
+	Expect(err).ToNot(HaveOccurred())
+	contentType := part.Header.Get("Content-Type")
+
+	_, err = reader.NextPart()
+	Expect(err).To(Equal(io.EOF))
+	return contentType, string(body)
+}
+
+var _ = Describe("GetNodeUserData", func() {
+	var group eksv1.NodeGroup
+
+	BeforeEach(func() {
+		group = eksv1.NodeGroup{
+			NodegroupName: aws.String("ng1"),
+			ImageID:       aws.String("ami-123"),
+			Labels: map[string]*string{
+				"zone": aws.String("a"),
+				"app":  aws.String("web"),
+			},
+			Taints: []eksv1.Taint{
+				{Key: aws.String("dedicated"), Value: aws.String("web"), Effect: aws.String("NO_SCHEDULE")},
+				{Key: aws.String("spot"), Value: aws.String("true"), Effect: aws.String("PREFER_NO_SCHEDULE")},
+			},
+			BootstrapExtraArgs: aws.String("--container-runtime containerd"),
+			KubeletExtraArgs:   aws.String("--max-pods=58"),
+		}
+	})
+
+	It("should render the bootstrap script from the bootstrap fields", func() {
+		userData, err := GetNodeUserData("my-cluster", group)
+		Expect(err).ToNot(HaveOccurred())
+
+		contentType, script := parseShellScriptPart(userData)
+		Expect(contentType).To(HavePrefix("text/x-shellscript"))
+		Expect(script).To(HavePrefix("#!/bin/bash\n"))
+		Expect(script).To(ContainSubstring("/etc/eks/bootstrap.sh 'my-cluster' --container-runtime containerd --kubelet-extra-args " +
+			"'--node-labels=app=web,zone=a --register-with-taints=dedicated=web:NoSchedule,spot=true:PreferNoSchedule --max-pods=58'\n"))
+	})
+
+	It("should only pass the kubelet arguments that are set", func() {
+		group.Labels = nil
+		group.Taints = nil
+		group.BootstrapExtraArgs = nil
+
+		userData, err := GetNodeUserData("my-cluster", group)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, script := parseShellScriptPart(userData)
+		Expect(script).To(ContainSubstring("/etc/eks/bootstrap.sh 'my-cluster' --kubelet-extra-args '--max-pods=58'\n"))
+	})
+
+	It("should quote the cluster name", func() {
+		group.KubeletExtraArgs = nil
+
+		userData, err := GetNodeUserData("it's", group)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, script := parseShellScriptPart(userData)
+		Expect(script).To(ContainSubstring(`/etc/eks/bootstrap.sh 'it'"'"'s'`))
+	})
+
+	It("should return the user data of the node group if set", func() {
+		group.BootstrapExtraArgs = nil
+		group.KubeletExtraArgs = nil
+		group.UserData = aws.String("MIME-Version: 1.0\nContent-Type: multipart/mixed; boundary=\"==BOUNDARY==\"\n\n--==BOUNDARY==--\n")
+
+		userData, err := GetNodeUserData("my-cluster", group)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(userData).To(Equal(aws.StringValue(group.UserData)))
+	})
+
+	It("should return no user data without bootstrap fields", func() {
+		group.BootstrapExtraArgs = nil
+		group.KubeletExtraArgs = nil
+
+		userData, err := GetNodeUserData("my-cluster", group)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(userData).To(BeEmpty())
+	})
+
+	It("should fail if the user data is not multipart/mixed", func() {
+		group.UserData = aws.String("#!/bin/bash")
+
+		_, err := GetNodeUserData("my-cluster", group)
+		Expect(err).To(MatchError(ContainSubstring("is not of mime time multipart/mixed")))
+	})
+
+	It("should return the PowerShell user data of a windows node group", func() {
+		group.AMIFamily = aws.String(AMIFamilyWindowsCore2022)
+		group.UserData = aws.String("<powershell>\nWrite-Output ready\n</powershell>")
+
+		userData, err := GetNodeUserData("my-cluster", group)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(userData).To(Equal("<powershell>\nWrite-Output ready\n</powershell>"))
+	})
+
+	It("should return the TOML user data of a bottlerocket node group", func() {
+		group.AMIFamily = aws.String(AMIFamilyBottlerocket)
+		group.UserData = aws.String("[settings.kubernetes]\nmax-pods = 58\n")
+
+		userData, err := GetNodeUserData("my-cluster", group)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(userData).To(Equal("[settings.kubernetes]\nmax-pods = 58\n"))
+	})
+})
+
+var _ = Describe("ValidateNodeBootstrapConfig", func() {
+	var group eksv1.NodeGroup
+
+	BeforeEach(func() {
+		group = eksv1.NodeGroup{
+			NodegroupName:    aws.String("ng1"),
+			ImageID:          aws.String("ami-123"),
+			KubeletExtraArgs: aws.String("--max-pods=58"),
+		}
+	})
+
+	It("should accept bootstrap fields with a custom image", func() {
+		Expect(ValidateNodeBootstrapConfig(group)).To(Succeed())
+	})
+
+	It("should accept node groups without bootstrap fields", func() {
+		group.KubeletExtraArgs = nil
+		group.ImageID = nil
+		group.UserData = aws.String("userdata")
+		Expect(ValidateNodeBootstrapConfig(group)).To(Succeed())
+	})
+
+	It("should require a custom image", func() {
+		group.ImageID = nil
+		Expect(ValidateNodeBootstrapConfig(group)).To(MatchError(ContainSubstring("require a custom imageId")))
+	})
+
+	It("should refuse bootstrap fields along with user data", func() {
+		group.UserData = aws.String("userdata")
+		Expect(ValidateNodeBootstrapConfig(group)).To(MatchError(ContainSubstring("along with userData")))
+	})
+
+	It("should refuse bootstrap fields along with a launch template", func() {
+		group.LaunchTemplate = &eksv1.LaunchTemplate{ID: aws.String("lt-123")}
+		Expect(ValidateNodeBootstrapConfig(group)).To(MatchError(ContainSubstring("along with a launch template")))
+	})
+
+	It("should refuse bootstrap fields for bottlerocket node groups", func() {
+		group.AMIFamily = aws.String(AMIFamilyBottlerocket)
+		Expect(ValidateNodeBootstrapConfig(group)).To(MatchError(ContainSubstring("only supported for amiFamily [AmazonLinux2]")))
+	})
+})
@@ -0,0 +1,42 @@
+package eks
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/blang/semver"
+)
+
+// kubernetesVersionFormat is the format EKS expects kubernetes versions in, major and minor only.
+var kubernetesVersionFormat = regexp.MustCompile(`^\d+\.\d+$`)
+
+func validateKubernetesVersion(version string) error {
+	if !kubernetesVersionFormat.MatchString(version) {
+		return fmt.Errorf("invalid kubernetes version [%s], expected a major and minor version such as 1.27", version)
+	}
+
+	return nil
+}
+
+// validateKubernetesVersionUpgrade ensures the kubernetes version of a cluster is upgraded one minor version at a
+// time, EKS does not allow downgrades nor skipping minor versions.
+func validateKubernetesVersionUpgrade(upstreamVersion, version string) error {
+	from, err := semver.Parse(fmt.Sprintf("%s.0", upstreamVersion))
+	if err != nil {
+		return fmt.Errorf("invalid upstream kubernetes version [%s]: %w", upstreamVersion, err)
+	}
+	to, err := semver.Parse(fmt.Sprintf("%s.0", version))
+	if err != nil {
+		return fmt.Errorf("invalid kubernetes version [%s]: %w", version, err)
+	}
+
+	if to.LT(from) {
+		return fmt.Errorf("kubernetes version cannot be downgraded from %s to %s", upstreamVersion, version)
+	}
+	if to.Major != from.Major || to.Minor > from.Minor+1 {
+		return fmt.Errorf("kubernetes version can only be upgraded one minor version at a time, upgrade from %s to %d.%d first",
+			upstreamVersion, from.Major, from.Minor+1)
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package eks
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("validateKubernetesVersion", func() {
+	DescribeTable("should accept major and minor versions",
+		func(version string) {
+			Expect(validateKubernetesVersion(version)).To(Succeed())
+		},
+		Entry("1.27", "1.27"),
+		Entry("1.30", "1.30"),
+	)
+
+	DescribeTable("should reject other versions",
+		func(version string) {
+			Expect(validateKubernetesVersion(version)).To(MatchError(ContainSubstring("invalid kubernetes version [" + version + "]")))
+		},
+		Entry("patch version", "1.27.3"),
+		Entry("prefixed version", "v1.27"),
+		Entry("latest", "latest"),
+		Entry("empty", ""),
+	)
+})
+
+var _ = Describe("validateKubernetesVersionUpgrade", func() {
+	DescribeTable("should validate the upgrade",
+		func(upstreamVersion, version, expectedError string) {
+			err := validateKubernetesVersionUpgrade(upstreamVersion, version)
+			if expectedError == "" {
+				Expect(err).ToNot(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(ContainSubstring(expectedError)))
+			}
+		},
+		Entry("single minor version", "1.27", "1.28", ""),
+		Entry("single minor version with two digits", "1.29", "1.30", ""),
+		Entry("same version", "1.27", "1.27", ""),
+		Entry("downgrade", "1.29", "1.26", "kubernetes version cannot be downgraded from 1.29 to 1.26"),
+		Entry("skipped minor versions", "1.27", "1.30", "upgrade from 1.27 to 1.28 first"),
+		Entry("major version", "1.27", "2.0", "upgrade from 1.27 to 1.28 first"),
+	)
+})
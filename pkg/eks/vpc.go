@@ -0,0 +1,205 @@
+package eks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/pkg/eks/services"
+	"github.com/rancher/eks-operator/templates"
+)
+
+const (
+	// minClusterAvailabilityZones is the number of availability zones EKS requires the subnets of a cluster to span.
+	minClusterAvailabilityZones = 2
+
+	// EC2 fails describing resources that do not exist instead of leaving them out of the output.
+	subnetNotFoundErrorCode        = "InvalidSubnetID.NotFound"
+	securityGroupNotFoundErrorCode = "InvalidGroup.NotFound"
+)
+
+type ValidateVpcResourcesOpts struct {
+	EC2Service services.EC2ServiceInterface
+	Config     *eksv1.EKSClusterConfig
+}
+
+// ValidateVpcResources checks the subnets and security groups given in the spec to create the cluster in an existing
+// VPC, and returns the ID of that VPC. The subnets have to exist, belong to the same VPC and span at least two
+// availability zones, and the security groups have to belong to the VPC of the subnets.
+func ValidateVpcResources(ctx context.Context, opts *ValidateVpcResourcesOpts) (string, error) {
+	subnetIDs := opts.Config.Spec.Subnets
+	subnets, err := opts.EC2Service.DescribeSubnets(ctx, &ec2.DescribeSubnetsInput{
+		SubnetIds: aws.StringSlice(subnetIDs),
+	})
+	if missing, ok := notFoundIDs(err, subnetNotFoundErrorCode, subnetIDs); ok {
+		return "", fmt.Errorf("subnets [%s] of cluster [%s] do not exist", strings.Join(missing, ", "), opts.Config.Name)
+	}
+	if err != nil {
+		return "", fmt.Errorf("error describing subnets [%s] of cluster [%s]: %w", strings.Join(subnetIDs, ", "), opts.Config.Name, err)
+	}
+
+	found := make(map[string]bool, len(subnets.Subnets))
+	vpcSubnets := map[string][]string{}
+	zones := map[string]bool{}
+	for _, subnet := range subnets.Subnets {
+		subnetID := aws.StringValue(subnet.SubnetId)
+		found[subnetID] = true
+		vpcID := aws.StringValue(subnet.VpcId)
+		vpcSubnets[vpcID] = append(vpcSubnets[vpcID], subnetID)
+		zones[aws.StringValue(subnet.AvailabilityZone)] = true
+	}
+	if missing := missingIDs(subnetIDs, found); len(missing) != 0 {
+		return "", fmt.Errorf("subnets [%s] of cluster [%s] do not exist", strings.Join(missing, ", "), opts.Config.Name)
+	}
+	if len(vpcSubnets) > 1 {
+		return "", fmt.Errorf("subnets of cluster [%s] must belong to the same VPC, found %s", opts.Config.Name, formatVpcResources(vpcSubnets))
+	}
+	if len(zones) < minClusterAvailabilityZones {
+		return "", fmt.Errorf("subnets [%s] of cluster [%s] must span at least %d availability zones",
+			strings.Join(subnetIDs, ", "), opts.Config.Name, minClusterAvailabilityZones)
+	}
+
+	var vpcID string
+	for id := range vpcSubnets {
+		vpcID = id
+	}
+
+	securityGroupIDs := opts.Config.Spec.SecurityGroups
+	if len(securityGroupIDs) == 0 {
+		return vpcID, nil
+	}
+
+	securityGroups, err := opts.EC2Service.DescribeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{
+		GroupIds: aws.StringSlice(securityGroupIDs),
+	})
+	if missing, ok := notFoundIDs(err, securityGroupNotFoundErrorCode, securityGroupIDs); ok {
+		return "", fmt.Errorf("security groups [%s] of cluster [%s] do not exist", strings.Join(missing, ", "), opts.Config.Name)
+	}
+	if err != nil {
+		return "", fmt.Errorf("error describing security groups [%s] of cluster [%s]: %w", strings.Join(securityGroupIDs, ", "), opts.Config.Name, err)
+	}
+
+	found = make(map[string]bool, len(securityGroups.SecurityGroups))
+	var otherVpc []string
+	for _, securityGroup := range securityGroups.SecurityGroups {
+		groupID := aws.StringValue(securityGroup.GroupId)
+		found[groupID] = true
+		if aws.StringValue(securityGroup.VpcId) != vpcID {
+			otherVpc = append(otherVpc, groupID)
+		}
+	}
+	if missing := missingIDs(securityGroupIDs, found); len(missing) != 0 {
+		return "", fmt.Errorf("security groups [%s] of cluster [%s] do not exist", strings.Join(missing, ", "), opts.Config.Name)
+	}
+	if len(otherVpc) != 0 {
+		return "", fmt.Errorf("security groups [%s] of cluster [%s] do not belong to VPC [%s] of the subnets",
+			strings.Join(otherVpc, ", "), opts.Config.Name, vpcID)
+	}
+
+	return vpcID, nil
+}
+
+// GetVpcStackName returns the name of the stack CreateVpcStack creates the networking of the cluster with.
+func GetVpcStackName(displayName string) string {
+	return fmt.Sprintf(vpcStackNameFormat, displayName)
+}
+
+type CreateVpcStackOptions struct {
+	CloudFormationService services.CloudFormationServiceInterface
+	Config                *eksv1.EKSClusterConfig
+}
+
+// CreateVpcStack creates the VPC, subnets and security group of a cluster with no subnets in its spec from the VPC
+// template, and returns the status of the config with the networking fields set from the outputs of the stack.
+func CreateVpcStack(ctx context.Context, opts *CreateVpcStackOptions) (*eksv1.EKSClusterConfigStatus, error) {
+	stack, err := CreateStack(ctx, &CreateStackOptions{
+		CloudFormationService: opts.CloudFormationService,
+		StackName:             GetVpcStackName(opts.Config.Spec.DisplayName),
+		DisplayName:           opts.Config.Spec.DisplayName,
+		TemplateBody:          templates.VpcTemplate,
+		Capabilities:          []string{},
+		Parameters:            []*cloudformation.Parameter{},
+		Tags:                  GetNormalizedTags(opts.Config, opts.Config.Spec.Tags),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating stack with VPC template: %w", err)
+	}
+
+	outputs := GetOutputsFromStack(stack.Stacks[0])
+	if outputs["SubnetIds"] == "" {
+		return nil, fmt.Errorf("no subnet ids were returned by the VPC stack of cluster [%s]", opts.Config.Name)
+	}
+
+	status := opts.Config.Status.DeepCopy()
+	status.VirtualNetwork = outputs["VpcId"]
+	status.Subnets = strings.Split(outputs["SubnetIds"], ",")
+	status.SecurityGroups = nil
+	if outputs["SecurityGroups"] != "" {
+		status.SecurityGroups = strings.Split(outputs["SecurityGroups"], ",")
+	}
+	status.NetworkFieldsSource = "generated"
+
+	return status, nil
+}
+
+// notFoundIDs returns the IDs named in the message of a not found error with the given code, or all the IDs if the
+// message names none of them.
+func notFoundIDs(err error, code string, ids []string) ([]string, bool) {
+	var aerr awserr.Error
+	if !errors.As(err, &aerr) || aerr.Code() != code {
+		return nil, false
+	}
+
+	named := map[string]bool{}
+	for _, word := range strings.FieldsFunc(aerr.Message(), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-'
+	}) {
+		named[word] = true
+	}
+
+	var missing []string
+	for _, id := range ids {
+		if named[id] {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) == 0 {
+		return ids, true
+	}
+
+	return missing, true
+}
+
+func missingIDs(ids []string, found map[string]bool) []string {
+	var missing []string
+	for _, id := range ids {
+		if !found[id] {
+			missing = append(missing, id)
+		}
+	}
+
+	return missing
+}
+
+func formatVpcResources(vpcResources map[string][]string) string {
+	vpcIDs := make([]string, 0, len(vpcResources))
+	for vpcID := range vpcResources {
+		vpcIDs = append(vpcIDs, vpcID)
+	}
+	sort.Strings(vpcIDs)
+
+	formatted := make([]string, 0, len(vpcIDs))
+	for _, vpcID := range vpcIDs {
+		formatted = append(formatted, fmt.Sprintf("[%s] in VPC [%s]", strings.Join(vpcResources[vpcID], ", "), vpcID))
+	}
+
+	return strings.Join(formatted, ", ")
+}
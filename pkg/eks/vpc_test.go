@@ -0,0 +1,239 @@
+package eks
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	eksv1 "github.com/rancher/eks-operator/pkg/apis/eks.cattle.io/v1"
+	"github.com/rancher/eks-operator/pkg/eks/services/mock_services"
+	"github.com/rancher/eks-operator/templates"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("ValidateVpcResources", func() {
+	var (
+		mockController *gomock.Controller
+		ec2ServiceMock *mock_services.MockEC2ServiceInterface
+		validateOpts   *ValidateVpcResourcesOpts
+	)
+
+	subnet := func(id, vpcID, zone string) *ec2.Subnet {
+		return &ec2.Subnet{SubnetId: aws.String(id), VpcId: aws.String(vpcID), AvailabilityZone: aws.String(zone)}
+	}
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		ec2ServiceMock = mock_services.NewMockEC2ServiceInterface(mockController)
+		validateOpts = &ValidateVpcResourcesOpts{
+			EC2Service: ec2ServiceMock,
+			Config: &eksv1.EKSClusterConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-cluster",
+				},
+				Spec: eksv1.EKSClusterConfigSpec{
+					Subnets:        []string{"subnet-1", "subnet-2"},
+					SecurityGroups: []string{"sg-1"},
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	It("should return the VPC of valid subnets and security groups", func() {
+		ec2ServiceMock.EXPECT().DescribeSubnets(gomock.Any(), &ec2.DescribeSubnetsInput{
+			SubnetIds: aws.StringSlice([]string{"subnet-1", "subnet-2"}),
+		}).Return(&ec2.DescribeSubnetsOutput{
+			Subnets: []*ec2.Subnet{subnet("subnet-1", "vpc-1", "us-west-2a"), subnet("subnet-2", "vpc-1", "us-west-2b")},
+		}, nil)
+		ec2ServiceMock.EXPECT().DescribeSecurityGroups(gomock.Any(), &ec2.DescribeSecurityGroupsInput{
+			GroupIds: aws.StringSlice([]string{"sg-1"}),
+		}).Return(&ec2.DescribeSecurityGroupsOutput{
+			SecurityGroups: []*ec2.SecurityGroup{{GroupId: aws.String("sg-1"), VpcId: aws.String("vpc-1")}},
+		}, nil)
+
+		vpcID, err := ValidateVpcResources(context.Background(), validateOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(vpcID).To(Equal("vpc-1"))
+	})
+
+	It("should not describe security groups if none are given", func() {
+		validateOpts.Config.Spec.SecurityGroups = nil
+		ec2ServiceMock.EXPECT().DescribeSubnets(gomock.Any(), gomock.Any()).Return(&ec2.DescribeSubnetsOutput{
+			Subnets: []*ec2.Subnet{subnet("subnet-1", "vpc-1", "us-west-2a"), subnet("subnet-2", "vpc-1", "us-west-2b")},
+		}, nil)
+		ec2ServiceMock.EXPECT().DescribeSecurityGroups(gomock.Any(), gomock.Any()).Times(0)
+
+		vpcID, err := ValidateVpcResources(context.Background(), validateOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(vpcID).To(Equal("vpc-1"))
+	})
+
+	It("should reject subnets in a single availability zone", func() {
+		ec2ServiceMock.EXPECT().DescribeSubnets(gomock.Any(), gomock.Any()).Return(&ec2.DescribeSubnetsOutput{
+			Subnets: []*ec2.Subnet{subnet("subnet-1", "vpc-1", "us-west-2a"), subnet("subnet-2", "vpc-1", "us-west-2a")},
+		}, nil)
+		ec2ServiceMock.EXPECT().DescribeSecurityGroups(gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := ValidateVpcResources(context.Background(), validateOpts)
+		Expect(err).To(MatchError("subnets [subnet-1, subnet-2] of cluster [test-cluster] must span at least 2 availability zones"))
+	})
+
+	It("should reject subnets in different VPCs", func() {
+		ec2ServiceMock.EXPECT().DescribeSubnets(gomock.Any(), gomock.Any()).Return(&ec2.DescribeSubnetsOutput{
+			Subnets: []*ec2.Subnet{subnet("subnet-1", "vpc-1", "us-west-2a"), subnet("subnet-2", "vpc-2", "us-west-2b")},
+		}, nil)
+
+		_, err := ValidateVpcResources(context.Background(), validateOpts)
+		Expect(err).To(MatchError("subnets of cluster [test-cluster] must belong to the same VPC, found [subnet-1] in VPC [vpc-1], [subnet-2] in VPC [vpc-2]"))
+	})
+
+	It("should reject security groups in another VPC than the subnets", func() {
+		ec2ServiceMock.EXPECT().DescribeSubnets(gomock.Any(), gomock.Any()).Return(&ec2.DescribeSubnetsOutput{
+			Subnets: []*ec2.Subnet{subnet("subnet-1", "vpc-1", "us-west-2a"), subnet("subnet-2", "vpc-1", "us-west-2b")},
+		}, nil)
+		ec2ServiceMock.EXPECT().DescribeSecurityGroups(gomock.Any(), gomock.Any()).Return(&ec2.DescribeSecurityGroupsOutput{
+			SecurityGroups: []*ec2.SecurityGroup{{GroupId: aws.String("sg-1"), VpcId: aws.String("vpc-2")}},
+		}, nil)
+
+		_, err := ValidateVpcResources(context.Background(), validateOpts)
+		Expect(err).To(MatchError("security groups [sg-1] of cluster [test-cluster] do not belong to VPC [vpc-1] of the subnets"))
+	})
+
+	It("should reject subnets that do not exist", func() {
+		ec2ServiceMock.EXPECT().DescribeSubnets(gomock.Any(), gomock.Any()).Return(nil,
+			awserr.New("InvalidSubnetID.NotFound", "The subnet ID 'subnet-2' does not exist", nil))
+
+		_, err := ValidateVpcResources(context.Background(), validateOpts)
+		Expect(err).To(MatchError("subnets [subnet-2] of cluster [test-cluster] do not exist"))
+	})
+
+	It("should reject security groups that do not exist", func() {
+		ec2ServiceMock.EXPECT().DescribeSubnets(gomock.Any(), gomock.Any()).Return(&ec2.DescribeSubnetsOutput{
+			Subnets: []*ec2.Subnet{subnet("subnet-1", "vpc-1", "us-west-2a"), subnet("subnet-2", "vpc-1", "us-west-2b")},
+		}, nil)
+		ec2ServiceMock.EXPECT().DescribeSecurityGroups(gomock.Any(), gomock.Any()).Return(nil,
+			awserr.New("InvalidGroup.NotFound", "The security group 'sg-1' does not exist", nil))
+
+		_, err := ValidateVpcResources(context.Background(), validateOpts)
+		Expect(err).To(MatchError("security groups [sg-1] of cluster [test-cluster] do not exist"))
+	})
+
+	It("should only reject the subnets named by the not found error", func() {
+		validateOpts.Config.Spec.Subnets = []string{"subnet-1", "subnet-10", "subnet-2"}
+		ec2ServiceMock.EXPECT().DescribeSubnets(gomock.Any(), gomock.Any()).Return(nil,
+			awserr.New("InvalidSubnetID.NotFound", "The subnet IDs 'subnet-10, subnet-2' do not exist", nil))
+
+		_, err := ValidateVpcResources(context.Background(), validateOpts)
+		Expect(err).To(MatchError("subnets [subnet-10, subnet-2] of cluster [test-cluster] do not exist"))
+	})
+
+	It("should reject all subnets if the not found error does not name them", func() {
+		ec2ServiceMock.EXPECT().DescribeSubnets(gomock.Any(), gomock.Any()).Return(nil,
+			awserr.New("InvalidSubnetID.NotFound", "The subnet ID does not exist", nil))
+
+		_, err := ValidateVpcResources(context.Background(), validateOpts)
+		Expect(err).To(MatchError("subnets [subnet-1, subnet-2] of cluster [test-cluster] do not exist"))
+	})
+
+	It("should fail if DescribeSubnets returns error", func() {
+		ec2ServiceMock.EXPECT().DescribeSubnets(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+		_, err := ValidateVpcResources(context.Background(), validateOpts)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("CreateVpcStack", func() {
+	var (
+		mockController             *gomock.Controller
+		cloudFormationsServiceMock *mock_services.MockCloudFormationServiceInterface
+		createVpcStackOpts         *CreateVpcStackOptions
+	)
+
+	BeforeEach(func() {
+		mockController = gomock.NewController(GinkgoT())
+		cloudFormationsServiceMock = mock_services.NewMockCloudFormationServiceInterface(mockController)
+		createVpcStackOpts = &CreateVpcStackOptions{
+			CloudFormationService: cloudFormationsServiceMock,
+			Config: &eksv1.EKSClusterConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-cluster",
+				},
+				Spec: eksv1.EKSClusterConfigSpec{
+					DisplayName: "test",
+				},
+				Status: eksv1.EKSClusterConfigStatus{
+					Phase: "creating",
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		mockController.Finish()
+	})
+
+	expectVpcStack := func(outputs ...*cloudformation.Output) {
+		cloudFormationsServiceMock.EXPECT().CreateStack(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, input *cloudformation.CreateStackInput) (*cloudformation.CreateStackOutput, error) {
+				Expect(aws.StringValue(input.StackName)).To(Equal("test-eks-vpc"))
+				Expect(aws.StringValue(input.TemplateBody)).To(Equal(templates.VpcTemplate))
+				return &cloudformation.CreateStackOutput{}, nil
+			})
+		cloudFormationsServiceMock.EXPECT().DescribeStacks(gomock.Any(), &cloudformation.DescribeStacksInput{
+			StackName: aws.String("test-eks-vpc"),
+		}).Return(&cloudformation.DescribeStacksOutput{
+			Stacks: []*cloudformation.Stack{
+				{
+					StackStatus: aws.String(createCompleteStatus),
+					Outputs:     outputs,
+				},
+			},
+		}, nil)
+	}
+
+	It("should set the networking fields of the status from the stack outputs", func() {
+		expectVpcStack(
+			&cloudformation.Output{OutputKey: aws.String("VpcId"), OutputValue: aws.String("vpc-1")},
+			&cloudformation.Output{OutputKey: aws.String("SubnetIds"), OutputValue: aws.String("subnet-1,subnet-2,subnet-3")},
+			&cloudformation.Output{OutputKey: aws.String("SecurityGroups"), OutputValue: aws.String("sg-1")},
+		)
+
+		status, err := CreateVpcStack(context.Background(), createVpcStackOpts)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(status).To(Equal(&eksv1.EKSClusterConfigStatus{
+			Phase:               "creating",
+			VirtualNetwork:      "vpc-1",
+			Subnets:             []string{"subnet-1", "subnet-2", "subnet-3"},
+			SecurityGroups:      []string{"sg-1"},
+			NetworkFieldsSource: "generated",
+		}))
+		Expect(createVpcStackOpts.Config.Status.Subnets).To(BeEmpty())
+	})
+
+	It("should fail if the stack returns no subnets", func() {
+		expectVpcStack(
+			&cloudformation.Output{OutputKey: aws.String("VpcId"), OutputValue: aws.String("vpc-1")},
+		)
+
+		_, err := CreateVpcStack(context.Background(), createVpcStackOpts)
+		Expect(err).To(MatchError(ContainSubstring("no subnet ids were returned")))
+	})
+
+	It("should fail if creating the stack fails", func() {
+		cloudFormationsServiceMock.EXPECT().CreateStack(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+		_, err := CreateVpcStack(context.Background(), createVpcStackOpts)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -1,6 +1,7 @@
 package templates
 
 // These are the CloudFormation templates used for EKS clusters, when making edits here ensure the whitespace is correct.
+// The role templates are formatted with the service principal of the role and the partition of the region.
 
 const (
 	VpcTemplate = `---
@@ -182,6 +183,12 @@ Resources:
       SubnetId: !Ref Subnet03
       RouteTableId: !Ref RouteTable
 
+  ControlPlaneSecurityGroup:
+    Type: AWS::EC2::SecurityGroup
+    Properties:
+      GroupDescription: Cluster communication with worker nodes
+      VpcId: !Ref VPC
+
 Outputs:
 
   SubnetIds:
@@ -192,6 +199,10 @@ Outputs:
       - !Join [ ",", [ !Ref Subnet01, !Ref Subnet02, !Ref Subnet03 ] ]
       - !Join [ ",", [ !Ref Subnet01, !Ref Subnet02 ] ]
 
+  SecurityGroups:
+    Description: Security group for the cluster control plane communication with worker nodes
+    Value: !Join [ ",", [ !Ref ControlPlaneSecurityGroup ] ]
+
   VpcId:
     Description: The VPC Id
     Value: !Ref VPC
@@ -200,6 +211,16 @@ Outputs:
 AWSTemplateFormatVersion: 2010-09-09
 Description: Amazon EKS - Node Group
 
+Parameters:
+
+  PermissionsBoundary:
+    Type: String
+    Default: ""
+    Description: The ARN of the policy used as permissions boundary of the node instance role
+
+Conditions:
+
+  HasPermissionsBoundary: !Not [!Equals [!Ref PermissionsBoundary, ""]]
 
 Resources:
 
@@ -211,13 +232,14 @@ Resources:
         Statement:
           - Effect: Allow
             Principal:
-              Service: %s
+              Service: %[1]s
             Action: sts:AssumeRole
       Path: "/"
       ManagedPolicyArns:
-        - arn:aws:iam::aws:policy/AmazonEKSWorkerNodePolicy
-        - arn:aws:iam::aws:policy/AmazonEKS_CNI_Policy
-        - arn:aws:iam::aws:policy/AmazonEC2ContainerRegistryReadOnly
+        - arn:%[2]s:iam::aws:policy/AmazonEKSWorkerNodePolicy
+        - arn:%[2]s:iam::aws:policy/AmazonEKS_CNI_Policy
+        - arn:%[2]s:iam::aws:policy/AmazonEC2ContainerRegistryReadOnly
+      PermissionsBoundary: !If [HasPermissionsBoundary, !Ref PermissionsBoundary, !Ref "AWS::NoValue"]
 
 Outputs:
 
@@ -241,12 +263,12 @@ Resources:
         - Effect: Allow
           Principal:
             Service:
-            - eks.amazonaws.com
+            - %[1]s
           Action:
           - sts:AssumeRole
       ManagedPolicyArns:
-        - arn:aws:iam::aws:policy/AmazonEKSServicePolicy
-        - arn:aws:iam::aws:policy/AmazonEKSClusterPolicy
+        - arn:%[2]s:iam::aws:policy/AmazonEKSServicePolicy
+        - arn:%[2]s:iam::aws:policy/AmazonEKSClusterPolicy
 
 Outputs:
 
@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"sort"
+
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
 )
@@ -46,6 +48,33 @@ func GetKeysToDelete(tags map[string]string, upstreamTags map[string]string) []*
 	return updateUntags
 }
 
+// DiffTags returns the tags to add or change and the keys of the tags to remove to get from the upstream tags to the
+// desired tags. Either result is nil if there is nothing to do. The keys to remove are sorted.
+func DiffTags(desired, upstream map[string]string) (upsert map[string]*string, remove []*string) {
+	for key, value := range desired {
+		if upstreamValue, ok := upstream[key]; ok && upstreamValue == value {
+			continue
+		}
+		if upsert == nil {
+			upsert = make(map[string]*string)
+		}
+		upsert[key] = aws.String(value)
+	}
+
+	var removeKeys []string
+	for key := range upstream {
+		if _, ok := desired[key]; !ok {
+			removeKeys = append(removeKeys, key)
+		}
+	}
+	if len(removeKeys) != 0 {
+		sort.Strings(removeKeys)
+		remove = aws.StringSlice(removeKeys)
+	}
+
+	return upsert, remove
+}
+
 // MergeMaps will add all keys and values from map2 to map1.
 func MergeMaps(map1, map2 map[string]string) map[string]string {
 	if map1 == nil {
@@ -82,10 +111,18 @@ func ValuesFromMap(m map[string]string) []string {
 }
 
 func GetInstanceTags(templateTags []*ec2.LaunchTemplateTagSpecification) map[string]*string {
+	return getTagsForResourceType(templateTags, ec2.ResourceTypeInstance)
+}
+
+func GetVolumeTags(templateTags []*ec2.LaunchTemplateTagSpecification) map[string]*string {
+	return getTagsForResourceType(templateTags, ec2.ResourceTypeVolume)
+}
+
+func getTagsForResourceType(templateTags []*ec2.LaunchTemplateTagSpecification, resourceType string) map[string]*string {
 	tags := make(map[string]*string)
 
 	for _, tag := range templateTags {
-		if aws.StringValue(tag.ResourceType) == ec2.ResourceTypeInstance {
+		if aws.StringValue(tag.ResourceType) == resourceType {
 			for _, t := range tag.Tags {
 				tags[aws.StringValue(t.Key)] = t.Value
 			}
@@ -95,17 +132,33 @@ func GetInstanceTags(templateTags []*ec2.LaunchTemplateTagSpecification) map[str
 }
 
 func CreateTagSpecs(instanceTags map[string]*string) []*ec2.LaunchTemplateTagSpecificationRequest {
-	if len(instanceTags) == 0 {
+	return createTagSpecsForResourceType(instanceTags, ec2.ResourceTypeInstance)
+}
+
+// CreateVolumeTagSpecs returns tag specifications for the EBS volumes attached to the instances launched from a
+// launch template, such as tags used by backup tooling to select volumes.
+func CreateVolumeTagSpecs(volumeTags map[string]*string) []*ec2.LaunchTemplateTagSpecificationRequest {
+	return createTagSpecsForResourceType(volumeTags, ec2.ResourceTypeVolume)
+}
+
+func createTagSpecsForResourceType(resourceTags map[string]*string, resourceType string) []*ec2.LaunchTemplateTagSpecificationRequest {
+	if len(resourceTags) == 0 {
 		return nil
 	}
 
-	tags := make([]*ec2.Tag, 0)
-	for key, value := range instanceTags {
-		tags = append(tags, &ec2.Tag{Key: aws.String(key), Value: value})
+	keys := make([]string, 0, len(resourceTags))
+	for key := range resourceTags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	tags := make([]*ec2.Tag, 0, len(keys))
+	for _, key := range keys {
+		tags = append(tags, &ec2.Tag{Key: aws.String(key), Value: resourceTags[key]})
 	}
 	return []*ec2.LaunchTemplateTagSpecificationRequest{
 		{
-			ResourceType: aws.String(ec2.ResourceTypeInstance),
+			ResourceType: aws.String(resourceType),
 			Tags:         tags,
 		},
 	}
@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffTags(t *testing.T) {
+	testCases := []struct {
+		name           string
+		desired        map[string]string
+		upstream       map[string]string
+		expectedUpsert map[string]*string
+		expectedRemove []*string
+	}{
+		{
+			name:     "no changes",
+			desired:  map[string]string{"a": "1", "b": "2"},
+			upstream: map[string]string{"a": "1", "b": "2"},
+		},
+		{
+			name:     "both empty",
+			desired:  nil,
+			upstream: map[string]string{},
+		},
+		{
+			name:           "add only",
+			desired:        map[string]string{"a": "1", "b": "2"},
+			upstream:       map[string]string{"a": "1"},
+			expectedUpsert: map[string]*string{"b": aws.String("2")},
+		},
+		{
+			name:           "add to empty upstream",
+			desired:        map[string]string{"a": "1"},
+			upstream:       nil,
+			expectedUpsert: map[string]*string{"a": aws.String("1")},
+		},
+		{
+			name:           "change only",
+			desired:        map[string]string{"a": "1", "b": "3"},
+			upstream:       map[string]string{"a": "1", "b": "2"},
+			expectedUpsert: map[string]*string{"b": aws.String("3")},
+		},
+		{
+			name:           "change to empty value",
+			desired:        map[string]string{"a": ""},
+			upstream:       map[string]string{"a": "1"},
+			expectedUpsert: map[string]*string{"a": aws.String("")},
+		},
+		{
+			name:           "remove only",
+			desired:        map[string]string{"a": "1"},
+			upstream:       map[string]string{"a": "1", "c": "3", "b": "2"},
+			expectedRemove: aws.StringSlice([]string{"b", "c"}),
+		},
+		{
+			name:           "mixed",
+			desired:        map[string]string{"a": "1", "b": "3", "d": "4"},
+			upstream:       map[string]string{"a": "1", "b": "2", "c": "3"},
+			expectedUpsert: map[string]*string{"b": aws.String("3"), "d": aws.String("4")},
+			expectedRemove: aws.StringSlice([]string{"c"}),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			upsert, remove := DiffTags(tc.desired, tc.upstream)
+			assert.Equal(t, tc.expectedUpsert, upsert)
+			assert.Equal(t, tc.expectedRemove, remove)
+		})
+	}
+}